@@ -15,8 +15,11 @@ import (
 	"github.com/theapemachine/a2a-go/pkg/a2a"
 	"github.com/theapemachine/a2a-go/pkg/ai"
 	"github.com/theapemachine/a2a-go/pkg/catalog"
+	"github.com/theapemachine/a2a-go/pkg/memory"
 	"github.com/theapemachine/a2a-go/pkg/provider"
+	"github.com/theapemachine/a2a-go/pkg/push"
 	"github.com/theapemachine/a2a-go/pkg/service"
+	"github.com/theapemachine/a2a-go/pkg/stores"
 	"github.com/theapemachine/a2a-go/pkg/stores/s3"
 )
 
@@ -89,9 +92,7 @@ var (
 				try++
 			}
 
-			card := a2a.NewAgentCardFromConfig(configFlag)
-			tm, err := ai.NewTaskManager(
-				card,
+			options := []ai.TaskManagerOption{
 				ai.WithTaskStore(s3.NewStore(
 					s3.NewConn(
 						s3.WithClient(minioClient),
@@ -100,7 +101,20 @@ var (
 				ai.WithProvider(provider.NewOpenAIProvider(
 					provider.WithOpenAIClient(),
 				)),
-			)
+			}
+
+			if v.GetString("memory.embedder") != "" {
+				store, err := newMemoryStore(cmd.Context(), v)
+				if err != nil {
+					log.Error("failed to create memory store", "error", err)
+					return err
+				}
+
+				options = append(options, ai.WithMemoryStore(store))
+			}
+
+			card := a2a.NewAgentCardFromConfig(configFlag)
+			tm, err := ai.NewTaskManager(card, options...)
 
 			if err != nil {
 				log.Error("failed to create task manager", "error", err)
@@ -122,7 +136,22 @@ var (
 				return err
 			}
 
-			return service.NewAgentServer(agent).Start()
+			serverOptions := []service.A2AServerOption{
+				service.WithPushReceiptStore(stores.NewInMemoryPushReceiptStore(v.GetInt("push.receipts.limit"))),
+			}
+
+			pushSigner, err := push.SignerFromConfig()
+
+			if err != nil {
+				log.Error("failed to load push signing key", "error", err)
+				return err
+			}
+
+			if pushSigner != nil {
+				serverOptions = append(serverOptions, service.WithPushSigner(pushSigner))
+			}
+
+			return service.NewAgentServer(agent, serverOptions...).Start()
 		},
 	}
 )
@@ -133,6 +162,53 @@ func init() {
 	agentCmd.PersistentFlags().StringVarP(&configFlag, "config", "c", "", "Configuration to use")
 }
 
+/*
+newMemoryStore bootstraps the unified memory store from the memory section
+of the config, with the embedder selected by name. Qdrant and Neo4j are used
+when their URLs are configured, in-memory stores otherwise.
+*/
+func newMemoryStore(ctx context.Context, v *viper.Viper) (*memory.UnifiedMemory, error) {
+	embedder, err := memory.GetEmbedder(v.GetString("memory.embedder"), memory.EmbedderOptions{
+		Model:   v.GetString("memory.model"),
+		BaseURL: v.GetString("memory.base_url"),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		vector memory.VectorStore = memory.NewInMemoryVectorStore()
+		graph  memory.GraphStore  = memory.NewInMemoryGraphStore()
+	)
+
+	if url := v.GetString("memory.qdrant.url"); url != "" {
+		vector = memory.NewQdrantVectorStore(url, v.GetString("memory.qdrant.collection"), embedder)
+	}
+
+	if url := v.GetString("memory.neo4j.url"); url != "" {
+		graph = memory.NewNeo4jGraphStore(url, v.GetString("memory.neo4j.user"), os.Getenv("NEO4J_PASSWORD"))
+	}
+
+	if err := memory.CheckDimensions(ctx, embedder, vector); err != nil {
+		return nil, err
+	}
+
+	store := memory.NewUnifiedStore(
+		embedder, vector, graph, memory.WithEmbeddingFallback(v.GetBool("memory.embedding_fallback")),
+	)
+
+	if interval := v.GetDuration("memory.sweep_interval"); interval > 0 {
+		go memory.SweepEvery(ctx, interval, store)
+	}
+
+	if interval := v.GetDuration("memory.reembed_interval"); v.GetBool("memory.embedding_fallback") && interval > 0 {
+		go store.ReembedEvery(ctx, interval)
+	}
+
+	return store, nil
+}
+
 var longServe = `
 Serve an A2A agent or MCP server with various configurations.
 
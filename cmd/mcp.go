@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	"github.com/theapemachine/a2a-go/pkg/provider"
 	"github.com/theapemachine/a2a-go/pkg/service/sse"
 	"github.com/theapemachine/a2a-go/pkg/tools"
 )
@@ -32,10 +33,21 @@ var (
 				return fmt.Errorf("failed to acquire tool definition: %w", err)
 			}
 
+			if capability, ok := tools.CapabilityForTool(configFlag); ok {
+				for _, prereqErr := range tools.CheckPrerequisites(capability.Name) {
+					log.Warn("tool prerequisite not met", "tool", configFlag, "error", prereqErr)
+				}
+			}
+
 			switch configFlag {
 			case "browser":
 				browserToolHandlerInstance := &tools.BrowserTool{}
 				stdio.AddTool(*toolDefinition, browserToolHandlerInstance.Handle)
+			case "web_summarize":
+				webSummarizeToolHandlerInstance := tools.NewWebSummarizer(
+					provider.NewSummarizer(provider.NewOpenAIProvider(provider.WithOpenAIClient()), "gpt-4o-mini"),
+				)
+				stdio.AddTool(*toolDefinition, webSummarizeToolHandlerInstance.Handle)
 			case "docker":
 				dockerToolHandlerInstance := &tools.DockerTool{}
 				stdio.AddTool(*toolDefinition, dockerToolHandlerInstance.Handle)
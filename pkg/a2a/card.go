@@ -1,7 +1,9 @@
 package a2a
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -81,6 +83,53 @@ type AgentCard struct {
 	DefaultOutputModes []string `json:"defaultOutputModes,omitempty"`
 	// Skills is the list of specific skills offered by the agent
 	Skills []AgentSkill `json:"skills"`
+	// Extensions advertises non-standard capabilities, such as supported memory
+	// backends, maximum context size, or pricing. It is omitted when empty so
+	// strict A2A consumers never see it.
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+/*
+SetExtension sets a custom extension value on the agent card.
+*/
+func (card *AgentCard) SetExtension(key string, value any) {
+	if card.Extensions == nil {
+		card.Extensions = make(map[string]any)
+	}
+
+	card.Extensions[key] = value
+}
+
+/*
+Extension reads a typed extension value from the agent card. Values that
+came over the wire are decoded into generic JSON types, so they are
+converted into T through a JSON round-trip. It reports false when the key
+is missing or the value cannot be converted.
+*/
+func Extension[T any](card *AgentCard, key string) (T, bool) {
+	var out T
+
+	value, ok := card.Extensions[key]
+
+	if !ok {
+		return out, false
+	}
+
+	if typed, ok := value.(T); ok {
+		return typed, true
+	}
+
+	buf, err := json.Marshal(value)
+
+	if err != nil {
+		return out, false
+	}
+
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return out, false
+	}
+
+	return out, true
 }
 
 func NewAgentCardFromConfig(key string) *AgentCard {
@@ -115,7 +164,8 @@ func NewAgentCardFromConfig(key string) *AgentCard {
 			Schemes:     v.GetStringSlice(fmt.Sprintf("agent.%s.authentication.schemes", key)),
 			Credentials: utils.Ptr(v.GetString(fmt.Sprintf("agent.%s.authentication.credentials", key))),
 		},
-		Skills: skills,
+		Skills:     skills,
+		Extensions: v.GetStringMap(fmt.Sprintf("agent.%s.extensions", key)),
 	}
 }
 
@@ -215,5 +265,18 @@ func (card *AgentCard) String() string {
 		}
 	}
 
+	// Extensions Section
+	if len(card.Extensions) > 0 {
+		sb.WriteString("\n" + sectionStyle.Render("Extensions") + "\n")
+		keys := make([]string, 0, len(card.Extensions))
+		for k := range card.Extensions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString(bullet + labelStyle.Render(k+": ") + valueStyle.Render(fmt.Sprintf("%v", card.Extensions[k])) + "\n")
+		}
+	}
+
 	return sb.String()
 }
@@ -6,10 +6,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
 
 	"github.com/charmbracelet/log"
 	"github.com/gofiber/fiber/v3"
 	fiberClient "github.com/gofiber/fiber/v3/client"
+	"github.com/gorilla/websocket"
 	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
 )
 
@@ -19,22 +24,51 @@ Client represents an A2A protocol client.
 type Client struct {
 	baseURL string
 	conn    *fiberClient.Client
+	lastID  atomic.Int64
+	nextID  func() jsonrpc.ID
 }
 
+type ClientOption func(*Client)
+
 /*
-NewClient creates a new A2A client.
+NewClient creates a new A2A client. Requests carry numeric ids counting up
+from 1, unless WithIDGenerator says otherwise.
 */
-func NewClient(baseURL string) *Client {
-	return &Client{
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	client := &Client{
 		baseURL: baseURL,
 		conn:    fiberClient.New().SetBaseURL(baseURL),
 	}
+
+	client.nextID = func() jsonrpc.ID {
+		return jsonrpc.NumberID(client.lastID.Add(1))
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+/*
+WithIDGenerator sets how request ids are made, for servers that expect
+string ids or ids in a particular format.
+*/
+func WithIDGenerator(next func() jsonrpc.ID) ClientOption {
+	return func(client *Client) {
+		client.nextID = next
+	}
 }
 
 /*
 doRequest is a helper method to send a JSON-RPC request and return a jsonrpc.Response.
 */
 func (client *Client) doRequest(req jsonrpc.Request) (jsonrpc.Response, error) {
+	if req.ID.IsZero() {
+		req.ID = client.nextID()
+	}
+
 	res, err := client.conn.Post(
 		"/rpc",
 		fiberClient.Config{
@@ -52,6 +86,20 @@ func (client *Client) doRequest(req jsonrpc.Request) (jsonrpc.Response, error) {
 	fm := fiber.Map{}
 	res.JSON(&fm)
 
+	var envelope jsonrpc.MessageIdentifier
+
+	if err := res.JSON(&envelope); err != nil {
+		return jsonrpc.Response{}, fmt.Errorf("failed to decode response id: %w", err)
+	}
+
+	// A null id is what a server answers with when it could not read the
+	// request, in which case the error it carries says more than a mismatch.
+	if !envelope.ID.IsZero() && !envelope.ID.Equal(req.ID) {
+		return jsonrpc.Response{}, fmt.Errorf(
+			"response id %s does not match request id %s", envelope.ID, req.ID,
+		)
+	}
+
 	// Parse error if present
 	var jsonErr *jsonrpc.Error
 	if errMap, ok := fm["error"].(map[string]any); ok {
@@ -63,7 +111,8 @@ func (client *Client) doRequest(req jsonrpc.Request) (jsonrpc.Response, error) {
 
 	jsonResp := jsonrpc.Response{
 		Message: jsonrpc.Message{
-			JSONRPC: "2.0",
+			MessageIdentifier: envelope,
+			JSONRPC:           "2.0",
 		},
 		Result: fm["result"],
 		Error:  jsonErr,
@@ -72,6 +121,107 @@ func (client *Client) doRequest(req jsonrpc.Request) (jsonrpc.Response, error) {
 	return jsonResp, nil
 }
 
+/*
+CallBatch sends the requests to the server in a single batch and returns
+their responses in the order of the requests. Requests without an id are
+sent as notifications, which the server does not answer, so they have no
+response in the result.
+*/
+func (client *Client) CallBatch(reqs []jsonrpc.Request) ([]jsonrpc.Response, error) {
+	var calls []jsonrpc.ID
+
+	reqs = slices.Clone(reqs)
+
+	for i := range reqs {
+		reqs[i].JSONRPC = "2.0"
+
+		if !reqs[i].IsNotification() {
+			calls = append(calls, reqs[i].ID)
+		}
+	}
+
+	res, err := client.conn.Post(
+		"/rpc",
+		fiberClient.Config{
+			Header: map[string]string{
+				"Content-Type": "application/json",
+			},
+			Body: reqs,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body := bytes.TrimSpace(res.Body())
+
+	if len(calls) == 0 || len(body) == 0 {
+		return nil, nil
+	}
+
+	// A server that cannot read the batch answers with a single error
+	if !jsonrpc.IsBatch(body) {
+		var single jsonrpc.Response
+
+		if err := json.Unmarshal(body, &single); err != nil {
+			return nil, fmt.Errorf("failed to decode batch response: %w", err)
+		}
+
+		if single.Error != nil {
+			return nil, fmt.Errorf("A2A error: %s (code: %d)", single.Error.Message, single.Error.Code)
+		}
+
+		return nil, fmt.Errorf("expected a batch response, got a single response")
+	}
+
+	var received []jsonrpc.Response
+
+	if err := json.Unmarshal(body, &received); err != nil {
+		return nil, fmt.Errorf("failed to decode batch response: %w", err)
+	}
+
+	// Servers may answer in any order, the ids tie responses to requests
+	responses := make([]jsonrpc.Response, 0, len(calls))
+
+	for _, id := range calls {
+		idx := slices.IndexFunc(received, func(response jsonrpc.Response) bool {
+			return response.ID.Equal(id)
+		})
+
+		if idx < 0 {
+			return nil, fmt.Errorf("no response for request id %s", id)
+		}
+
+		responses = append(responses, received[idx])
+	}
+
+	return responses, nil
+}
+
+/*
+FetchAgentCard retrieves the agent card from the well-known endpoint.
+*/
+func (client *Client) FetchAgentCard() (*AgentCard, error) {
+	res, err := client.conn.Get("/.well-known/agent.json")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() < 200 || res.StatusCode() >= 300 {
+		return nil, fmt.Errorf("agent card request returned status %d", res.StatusCode())
+	}
+
+	var card AgentCard
+
+	if err := res.JSON(&card); err != nil {
+		return nil, fmt.Errorf("failed to decode agent card: %w", err)
+	}
+
+	return &card, nil
+}
+
 /*
 SendTask sends a task message to the agent.
 */
@@ -124,6 +274,38 @@ func (client *Client) GetTask(params TaskQueryParams) (jsonrpc.Response, error)
 	return client.doRequest(req)
 }
 
+/*
+DiffTask fetches what changed in a task since the revision of the params,
+taken from a task or diff fetched before.
+*/
+func (client *Client) DiffTask(params TaskDiffParams) (jsonrpc.Response, error) {
+	req := jsonrpc.Request{
+		Message: jsonrpc.Message{
+			JSONRPC: "2.0",
+		},
+		Method: "tasks/diff",
+		Params: params,
+	}
+
+	return client.doRequest(req)
+}
+
+/*
+WaitTask blocks until a task reaches a terminal state, or the timeout of the
+params elapses, and returns the final task.
+*/
+func (client *Client) WaitTask(params TaskWaitParams) (jsonrpc.Response, error) {
+	req := jsonrpc.Request{
+		Message: jsonrpc.Message{
+			JSONRPC: "2.0",
+		},
+		Method: "tasks/wait",
+		Params: params,
+	}
+
+	return client.doRequest(req)
+}
+
 /*
 CancelTask cancels a task.
 */
@@ -139,6 +321,84 @@ func (client *Client) CancelTask(params TaskIDParams) (jsonrpc.Response, error)
 	return client.doRequest(req)
 }
 
+/*
+CancelTaskWithReason cancels a task and records why it was canceled.
+*/
+func (client *Client) CancelTaskWithReason(params TaskCancelParams) (jsonrpc.Response, error) {
+	req := jsonrpc.Request{
+		Message: jsonrpc.Message{
+			JSONRPC: "2.0",
+		},
+		Method: "tasks/cancel",
+		Params: params,
+	}
+
+	return client.doRequest(req)
+}
+
+/*
+SetTaskPushNotification sets the webhook the agent posts the status updates
+of a task to.
+*/
+func (client *Client) SetTaskPushNotification(params TaskPushNotificationConfig) (jsonrpc.Response, error) {
+	req := jsonrpc.Request{
+		Message: jsonrpc.Message{
+			JSONRPC: "2.0",
+		},
+		Method: "tasks/pushNotification/set",
+		Params: params,
+	}
+
+	return client.doRequest(req)
+}
+
+/*
+GetTaskPushNotification retrieves the push notification config of a task.
+*/
+func (client *Client) GetTaskPushNotification(params TaskIDParams) (jsonrpc.Response, error) {
+	req := jsonrpc.Request{
+		Message: jsonrpc.Message{
+			JSONRPC: "2.0",
+		},
+		Method: "tasks/pushNotification/get",
+		Params: params,
+	}
+
+	return client.doRequest(req)
+}
+
+/*
+DeleteTaskPushNotification removes the push notification config of a task,
+which stops its push notifications.
+*/
+func (client *Client) DeleteTaskPushNotification(params TaskIDParams) (jsonrpc.Response, error) {
+	req := jsonrpc.Request{
+		Message: jsonrpc.Message{
+			JSONRPC: "2.0",
+		},
+		Method: "tasks/pushNotification/delete",
+		Params: params,
+	}
+
+	return client.doRequest(req)
+}
+
+/*
+GetTaskPushNotificationStatus retrieves the delivery receipts of the push
+notifications of a task.
+*/
+func (client *Client) GetTaskPushNotificationStatus(params TaskIDParams) (jsonrpc.Response, error) {
+	req := jsonrpc.Request{
+		Message: jsonrpc.Message{
+			JSONRPC: "2.0",
+		},
+		Method: "tasks/pushNotification/status",
+		Params: params,
+	}
+
+	return client.doRequest(req)
+}
+
 /*
 SendTaskStreaming sends a task message and streams the response.
 */
@@ -153,6 +413,8 @@ func (client *Client) SendTaskStreaming(
 		Params: params,
 	}
 
+	req.ID = client.nextID()
+
 	res, err := client.conn.Post(
 		"/rpc",
 		fiberClient.Config{
@@ -194,3 +456,76 @@ func (client *Client) SendTaskStreaming(
 
 	return nil
 }
+
+/*
+SendTaskStreamingWS sends a task message over the WebSocket transport of the
+server, for environments that cannot use SSE, and streams the status and
+artifact updates to eventChan until the final status arrives.
+*/
+func (client *Client) SendTaskStreamingWS(
+	params TaskSendParams, eventChan chan<- any,
+) error {
+	wsURL, err := url.Parse(client.baseURL)
+
+	if err != nil {
+		return fmt.Errorf("invalid base url: %w", err)
+	}
+
+	switch wsURL.Scheme {
+	case "https", "wss":
+		wsURL.Scheme = "wss"
+	default:
+		wsURL.Scheme = "ws"
+	}
+
+	wsURL.Path = strings.TrimSuffix(wsURL.Path, "/") + "/ws"
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
+
+	if err != nil {
+		return fmt.Errorf("failed to connect websocket: %w", err)
+	}
+
+	defer conn.Close()
+
+	req := jsonrpc.Request{
+		Message: jsonrpc.Message{
+			JSONRPC: "2.0",
+		},
+		Method: "tasks/sendSubscribe",
+		Params: params,
+	}
+
+	req.ID = client.nextID()
+
+	if err := conn.WriteJSON(req); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	for {
+		var event SendTaskStreamingResponse
+
+		if err := conn.ReadJSON(&event); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+
+		if !event.ID.Equal(req.ID) {
+			continue
+		}
+
+		if event.Error != nil {
+			return fmt.Errorf("A2A error: %s (code: %d)", event.Error.Message, event.Error.Code)
+		}
+
+		eventChan <- event.Result
+
+		if update, ok := event.Result.(map[string]any); ok && update["final"] == true {
+			_ = conn.WriteMessage(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			)
+
+			return nil
+		}
+	}
+}
@@ -2,11 +2,14 @@ package a2a
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
 )
 
 func TestSendTaskStreaming(t *testing.T) {
@@ -28,3 +31,167 @@ func TestSendTaskStreaming(t *testing.T) {
 		So(<-ch, ShouldResemble, map[string]any{"step": float64(2)})
 	})
 }
+
+func TestFetchAgentCard(t *testing.T) {
+	Convey("Given a server publishing an agent card with a custom extension", t, func() {
+		card := &AgentCard{Name: "extended", URL: "http://localhost", Skills: []AgentSkill{}}
+		card.SetExtension("memory", map[string]any{"backends": []string{"qdrant", "neo4j"}, "maxContext": 128000})
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Assertions only work on the test goroutine, so a request for
+			// any other path fails the fetch instead.
+			if r.URL.Path != "/.well-known/agent.json" {
+				http.NotFound(w, r)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(card)
+		}))
+		defer srv.Close()
+
+		Convey("When the card is fetched", func() {
+			fetched, err := NewClient(srv.URL).FetchAgentCard()
+			So(err, ShouldBeNil)
+
+			Convey("Then the extension should survive the round-trip", func() {
+				type memoryExtension struct {
+					Backends   []string `json:"backends"`
+					MaxContext int      `json:"maxContext"`
+				}
+
+				ext, ok := Extension[memoryExtension](fetched, "memory")
+				So(ok, ShouldBeTrue)
+				So(ext.Backends, ShouldResemble, []string{"qdrant", "neo4j"})
+				So(ext.MaxContext, ShouldEqual, 128000)
+			})
+		})
+	})
+
+	Convey("Given an agent card without extensions", t, func() {
+		buf, err := json.Marshal(&AgentCard{Name: "plain"})
+		So(err, ShouldBeNil)
+
+		Convey("Then the extensions field should be omitted", func() {
+			So(string(buf), ShouldNotContainSubstring, "extensions")
+
+			_, ok := Extension[string](&AgentCard{}, "missing")
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+func TestClientRequestIDs(t *testing.T) {
+	Convey("Given an RPC server that echoes request ids", t, func() {
+		var ids []string
+		respondWith := ""
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req jsonrpc.Request
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			ids = append(ids, req.ID.String())
+
+			id := req.ID.String()
+			if respondWith != "" {
+				id = respondWith
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + id + `,"result":{"ok":true}}`))
+		}))
+		defer srv.Close()
+
+		Convey("When requests are sent with the default numeric ids", func() {
+			client := NewClient(srv.URL)
+
+			first, err := client.GetTask(TaskQueryParams{TaskIDParams: TaskIDParams{ID: "task-1"}})
+			So(err, ShouldBeNil)
+			second, err := client.CancelTask(TaskIDParams{ID: "task-1"})
+			So(err, ShouldBeNil)
+
+			Convey("Then each request should carry its own number, echoed in its response", func() {
+				So(ids, ShouldResemble, []string{"1", "2"})
+				So(first.ID.Equal(jsonrpc.NumberID(1)), ShouldBeTrue)
+				So(second.ID.Equal(jsonrpc.NumberID(2)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When requests are sent with string ids", func() {
+			client := NewClient(srv.URL, WithIDGenerator(func() jsonrpc.ID {
+				return jsonrpc.StringID("req-1")
+			}))
+
+			res, err := client.GetTask(TaskQueryParams{TaskIDParams: TaskIDParams{ID: "task-1"}})
+
+			Convey("Then the string id should be sent and correlated", func() {
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{`"req-1"`})
+				So(res.ID.Equal(jsonrpc.StringID("req-1")), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the server answers with the same id as the other type", func() {
+			respondWith = `"1"`
+			_, err := NewClient(srv.URL).GetTask(TaskQueryParams{TaskIDParams: TaskIDParams{ID: "task-1"}})
+
+			Convey("Then the response should not be correlated", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "does not match")
+			})
+		})
+	})
+}
+
+func TestClientCallBatch(t *testing.T) {
+	Convey("Given an RPC server that answers batches", t, func() {
+		var notified []string
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			responses, rpcErr := jsonrpc.ServeBatch(body, func(request jsonrpc.Request) jsonrpc.Response {
+				switch request.Method {
+				case "tasks/get":
+					return jsonrpc.Response{Result: map[string]any{"id": "task-1"}}
+				case "log":
+					notified = append(notified, request.Method)
+					return jsonrpc.Response{}
+				}
+
+				return jsonrpc.Response{Error: &jsonrpc.Error{Code: -32601, Message: "Method not found"}}
+			})
+			if rpcErr != nil {
+				http.Error(w, rpcErr.Message, http.StatusBadRequest)
+				return
+			}
+
+			// Answer in reverse, the client must put them back in order
+			slices.Reverse(responses)
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(responses)
+		}))
+		defer srv.Close()
+
+		Convey("When a valid call, an unknown method and a notification are sent together", func() {
+			responses, err := NewClient(srv.URL).CallBatch([]jsonrpc.Request{
+				{Message: jsonrpc.Message{MessageIdentifier: jsonrpc.MessageIdentifier{ID: jsonrpc.NumberID(1)}}, Method: "tasks/get"},
+				{Message: jsonrpc.Message{MessageIdentifier: jsonrpc.MessageIdentifier{ID: jsonrpc.NumberID(2)}}, Method: "tasks/unknown"},
+				{Method: "log"},
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then the calls should get their responses in request order", func() {
+				So(responses, ShouldHaveLength, 2)
+				So(responses[0].ID.Equal(jsonrpc.NumberID(1)), ShouldBeTrue)
+				So(responses[0].Result, ShouldResemble, map[string]any{"id": "task-1"})
+				So(responses[1].ID.Equal(jsonrpc.NumberID(2)), ShouldBeTrue)
+				So(responses[1].Error.Code, ShouldEqual, -32601)
+			})
+
+			Convey("Then the notification should reach the server without a response", func() {
+				So(notified, ShouldResemble, []string{"log"})
+			})
+		})
+	})
+}
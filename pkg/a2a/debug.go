@@ -0,0 +1,43 @@
+package a2a
+
+import "time"
+
+/*
+DebugEventKind classifies an entry in a task's debug log.
+*/
+type DebugEventKind string
+
+const (
+	DebugEventProvider   DebugEventKind = "provider"
+	DebugEventToolCall   DebugEventKind = "tool_call"
+	DebugEventTransition DebugEventKind = "state_transition"
+)
+
+/*
+DebugEvent is a single breadcrumb in a task's debug log, recording what
+happened while the task ran and, where it applies, how long it took.
+*/
+type DebugEvent struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Kind      DebugEventKind `json:"kind"`
+	Message   string         `json:"message"`
+	Duration  time.Duration  `json:"duration,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+}
+
+/*
+AddDebugEvent appends an event to the task's debug log, dropping the oldest
+entries once the log holds more than limit events. A limit of zero or less
+leaves the log unbounded.
+*/
+func (task *Task) AddDebugEvent(event DebugEvent, limit int) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	task.Debug = append(task.Debug, event)
+
+	if limit > 0 && len(task.Debug) > limit {
+		task.Debug = append([]DebugEvent(nil), task.Debug[len(task.Debug)-limit:]...)
+	}
+}
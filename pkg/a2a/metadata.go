@@ -0,0 +1,51 @@
+package a2a
+
+/*
+MergeMetadata deep-merges src into dst and returns the result. Keys that
+only exist in src are added, keys present in both are overwritten by src,
+and when both sides hold a nested map the two maps are merged recursively
+instead of the src map replacing the dst map. A nil value in src removes
+the key from dst, which is the only way to drop a key under this policy.
+A nil dst is allocated, and nested maps taken from src are copied so dst
+never aliases src.
+*/
+func MergeMetadata(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+
+	for key, value := range src {
+		if value == nil {
+			delete(dst, key)
+			continue
+		}
+
+		srcMap, srcIsMap := value.(map[string]any)
+
+		if !srcIsMap {
+			dst[key] = value
+			continue
+		}
+
+		dstMap, dstIsMap := dst[key].(map[string]any)
+
+		if !dstIsMap {
+			dstMap = nil
+		}
+
+		dst[key] = MergeMetadata(dstMap, srcMap)
+	}
+
+	return dst
+}
+
+/*
+MergeMetadata deep-merges the given metadata into the task's metadata.
+*/
+func (task *Task) MergeMetadata(metadata map[string]any) {
+	if len(metadata) == 0 {
+		return
+	}
+
+	task.Metadata = MergeMetadata(task.Metadata, metadata)
+}
@@ -0,0 +1,68 @@
+package a2a
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMergeMetadata(t *testing.T) {
+	Convey("Given task metadata carrying a schema", t, func() {
+		dst := map[string]any{
+			"schema": map[string]any{"type": "object"},
+			"limits": map[string]any{"tokens": 100, "nested": map[string]any{"a": 1}},
+		}
+
+		Convey("When a later update only sets usage", func() {
+			merged := MergeMetadata(dst, map[string]any{
+				"usage": map[string]any{"total_tokens": 42},
+			})
+
+			Convey("Then the schema should survive", func() {
+				So(merged["schema"], ShouldResemble, map[string]any{"type": "object"})
+				So(merged["usage"], ShouldResemble, map[string]any{"total_tokens": 42})
+			})
+		})
+
+		Convey("When an update carries a partial nested map", func() {
+			merged := MergeMetadata(dst, map[string]any{
+				"limits": map[string]any{"seconds": 30, "nested": map[string]any{"b": 2}},
+			})
+
+			Convey("Then the nested maps should merge rather than clobber", func() {
+				So(merged["limits"], ShouldResemble, map[string]any{
+					"tokens":  100,
+					"seconds": 30,
+					"nested":  map[string]any{"a": 1, "b": 2},
+				})
+			})
+		})
+
+		Convey("When an update sets a key to nil", func() {
+			merged := MergeMetadata(dst, map[string]any{"schema": nil})
+
+			Convey("Then the key should be removed", func() {
+				So(merged, ShouldNotContainKey, "schema")
+			})
+		})
+
+		Convey("When an update overwrites an existing key", func() {
+			merged := MergeMetadata(dst, map[string]any{"schema": "none"})
+
+			Convey("Then the new value should win", func() {
+				So(merged["schema"], ShouldEqual, "none")
+			})
+		})
+	})
+
+	Convey("Given a nil destination", t, func() {
+		src := map[string]any{"priority": map[string]any{"level": "high"}}
+		merged := MergeMetadata(nil, src)
+
+		Convey("Then it should be allocated without aliasing the source", func() {
+			So(merged, ShouldResemble, src)
+			merged["priority"].(map[string]any)["level"] = "low"
+			So(src["priority"].(map[string]any)["level"], ShouldEqual, "high")
+		})
+	})
+}
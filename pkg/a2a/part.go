@@ -1,6 +1,26 @@
 package a2a
 
-import "encoding/base64"
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+/*
+MaxFilePartSize caps the size of a file read into a part by
+NewFilePartFromPath, as the whole file travels base64-encoded inside the
+request.
+*/
+var MaxFilePartSize int64 = 10 << 20
+
+/*
+ErrFileTooLarge is returned when a file exceeds MaxFilePartSize.
+*/
+var ErrFileTooLarge = errors.New("file too large")
 
 /*
 Part is a discriminated union over Text, File and Data parts.  We keep it
@@ -55,3 +75,37 @@ func NewFilePart(name string, mimeType string, data []byte) Part {
 		},
 	}
 }
+
+/*
+NewFilePartFromPath reads the file at path into a file part, named after the
+file and typed by its extension, or by its content when the extension is
+unknown.
+*/
+func NewFilePartFromPath(path string) (Part, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Part{}, err
+	}
+
+	if info.IsDir() {
+		return Part{}, fmt.Errorf("%s is a directory", path)
+	}
+
+	if info.Size() > MaxFilePartSize {
+		return Part{}, fmt.Errorf(
+			"%w: %s is %d bytes, the maximum is %d", ErrFileTooLarge, path, info.Size(), MaxFilePartSize,
+		)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Part{}, err
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	return NewFilePart(filepath.Base(path), mimeType, data), nil
+}
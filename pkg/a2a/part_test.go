@@ -0,0 +1,80 @@
+package a2a
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewFilePartFromPath(t *testing.T) {
+	Convey("Given a PDF on disk", t, func() {
+		content := []byte("%PDF-1.4\nquarterly report\n")
+		path := filepath.Join(t.TempDir(), "report.pdf")
+		So(os.WriteFile(path, content, 0o600), ShouldBeNil)
+
+		Convey("When it is read into a part", func() {
+			part, err := NewFilePartFromPath(path)
+
+			Convey("Then it should be a file part with the name, mime type and content", func() {
+				So(err, ShouldBeNil)
+				So(part.Type, ShouldEqual, PartTypeFile)
+				So(*part.File.Name, ShouldEqual, "report.pdf")
+				So(*part.File.MimeType, ShouldEqual, "application/pdf")
+				So(part.File.Data, ShouldEqual, base64.StdEncoding.EncodeToString(content))
+			})
+		})
+
+		Convey("When a file has no known extension", func() {
+			png := filepath.Join(t.TempDir(), "screenshot")
+			So(os.WriteFile(png, []byte("\x89PNG\r\n\x1a\n0000"), 0o600), ShouldBeNil)
+
+			part, err := NewFilePartFromPath(png)
+
+			Convey("Then the mime type should be detected from its content", func() {
+				So(err, ShouldBeNil)
+				So(*part.File.MimeType, ShouldEqual, "image/png")
+			})
+		})
+
+		Convey("When it is larger than the size cap", func() {
+			defer func(limit int64) { MaxFilePartSize = limit }(MaxFilePartSize)
+			MaxFilePartSize = 8
+
+			_, err := NewFilePartFromPath(path)
+
+			Convey("Then it should be rejected", func() {
+				So(errors.Is(err, ErrFileTooLarge), ShouldBeTrue)
+			})
+		})
+
+		Convey("When files are attached to send params", func() {
+			notes := filepath.Join(t.TempDir(), "notes.txt")
+			So(os.WriteFile(notes, []byte("remember the milk"), 0o600), ShouldBeNil)
+
+			params := TaskSendParams{ID: "t1", Message: *NewTextMessage("user", "summarize this PDF.")}
+			err := params.AttachFiles(path, notes)
+
+			Convey("Then the message should carry the text followed by the files", func() {
+				So(err, ShouldBeNil)
+				So(params.Message.Parts, ShouldHaveLength, 3)
+				So(params.Message.Parts[0].Text, ShouldEqual, "summarize this PDF.")
+				So(*params.Message.Parts[1].File.Name, ShouldEqual, "report.pdf")
+				So(*params.Message.Parts[2].File.Name, ShouldEqual, "notes.txt")
+			})
+		})
+
+		Convey("When one of the files does not exist", func() {
+			params := TaskSendParams{ID: "t1", Message: *NewTextMessage("user", "hi")}
+			err := params.AttachFiles(path, filepath.Join(t.TempDir(), "missing.pdf"))
+
+			Convey("Then nothing should be attached", func() {
+				So(err, ShouldNotBeNil)
+				So(params.Message.Parts, ShouldHaveLength, 1)
+			})
+		})
+	})
+}
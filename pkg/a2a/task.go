@@ -25,6 +25,11 @@ type Task struct {
 	History   []Message      `json:"history,omitempty"`
 	Artifacts []Artifact     `json:"artifacts,omitempty"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
+	Debug     []DebugEvent   `json:"debug,omitempty"`
+	Usage     *Usage         `json:"usage,omitempty"`
+	// Revision increases by one with every write of the task, so clients can
+	// ask tasks/diff for what changed since the revision they fetched.
+	Revision int64 `json:"revision,omitempty"`
 }
 
 func (task *Task) Validate() bool {
@@ -109,6 +114,13 @@ func (task *Task) LastMessage() *Message {
 	return &task.History[len(task.History)-1]
 }
 
+/*
+Messages returns the conversation of the task, oldest message first.
+*/
+func (task *Task) Messages() []Message {
+	return task.History
+}
+
 func (task *Task) AddMessage(role, name, text string) {
 	task.History = append(task.History, Message{
 		Role:     role,
@@ -149,6 +161,20 @@ type TaskArtifactUpdateEvent struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+/*
+TaskToolCallEvent is emitted while a tool call is being streamed from the
+model, before the tool is executed. PartialArgs holds the arguments
+accumulated so far, and Done marks the event carrying the complete call.
+*/
+type TaskToolCallEvent struct {
+	ID          string         `json:"id"`
+	ToolCallID  string         `json:"toolCallId,omitempty"`
+	ToolName    string         `json:"toolName"`
+	PartialArgs string         `json:"partialArgs"`
+	Done        bool           `json:"done"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
 // TaskHistory represents the history of a task
 type TaskHistory struct {
 	// MessageHistory is the list of messages in chronological order
@@ -163,6 +189,31 @@ type TaskSendParams struct {
 	PushNotification *PushNotificationConfig `json:"pushNotification,omitempty"`
 	HistoryLength    *int                    `json:"historyLength,omitempty"`
 	Metadata         map[string]any          `json:"metadata,omitempty"`
+	// DryRun captures tool calls as a plan instead of executing them
+	DryRun bool `json:"dryRun,omitempty"`
+	// Model requests a specific model instead of the provider's default
+	Model string `json:"model,omitempty"`
+}
+
+/*
+AttachFiles reads the files at paths into file parts of the message, so a
+client can send "summarize this PDF." with the PDF attached. Nothing is
+attached when any of the files cannot be read or is too large.
+*/
+func (params *TaskSendParams) AttachFiles(paths ...string) error {
+	parts := make([]Part, 0, len(paths))
+
+	for _, path := range paths {
+		part, err := NewFilePartFromPath(path)
+		if err != nil {
+			return err
+		}
+
+		parts = append(parts, part)
+	}
+
+	params.Message.Parts = append(params.Message.Parts, parts...)
+	return nil
 }
 
 // TaskIDParams represents the base parameters for task ID-based operations
@@ -171,10 +222,45 @@ type TaskIDParams struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// TaskCancelParams represents the parameters for canceling a task
+type TaskCancelParams struct {
+	TaskIDParams
+	Reason string `json:"reason,omitempty"`
+}
+
 // TaskQueryParams represents the parameters for querying task information
 type TaskQueryParams struct {
 	TaskIDParams
 	HistoryLength *int `json:"historyLength,omitempty"`
+	IncludeDebug  bool `json:"includeDebug,omitempty"`
+}
+
+// TaskWaitParams represents the parameters for waiting on a task to finish.
+// Timeout is in seconds, a zero timeout leaves the default to the server.
+type TaskWaitParams struct {
+	TaskQueryParams
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// TaskDiffParams represents the parameters for fetching what changed in a
+// task since the revision a client fetched before.
+type TaskDiffParams struct {
+	TaskIDParams
+	Revision int64 `json:"revision"`
+}
+
+// TaskDiff holds what changed in a task since a revision: the history
+// messages and artifacts added or changed since, and the status when it
+// changed. Reset is set when the revision is unknown, for example because it
+// is too old, in which case the diff holds the whole task.
+type TaskDiff struct {
+	ID        string      `json:"id"`
+	Revision  int64       `json:"revision"`
+	Since     int64       `json:"since"`
+	Reset     bool        `json:"reset,omitempty"`
+	Status    *TaskStatus `json:"status,omitempty"`
+	History   []Message   `json:"history,omitempty"`
+	Artifacts []Artifact  `json:"artifacts,omitempty"`
 }
 
 // PushNotificationConfig represents the configuration for push notifications
@@ -190,6 +276,30 @@ type TaskPushNotificationConfig struct {
 	PushNotificationConfig PushNotificationConfig `json:"pushNotificationConfig"`
 }
 
+// PushNotificationAttempt is a single POST of a push notification, with the
+// status code the webhook answered or the error that kept it from answering.
+type PushNotificationAttempt struct {
+	Timestamp  time.Time `json:"timestamp"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// PushNotificationReceipt records the delivery of the notification of a
+// status transition, every attempt included.
+type PushNotificationReceipt struct {
+	State     TaskState                 `json:"state"`
+	Final     bool                      `json:"final"`
+	Delivered bool                      `json:"delivered"`
+	Attempts  []PushNotificationAttempt `json:"attempts"`
+}
+
+// TaskPushNotificationStatus lists the push notification receipts of a task,
+// oldest first. It is the result of tasks/pushNotification/status.
+type TaskPushNotificationStatus struct {
+	ID       string                    `json:"id"`
+	Receipts []PushNotificationReceipt `json:"receipts"`
+}
+
 // SendTaskRequest represents a request to send a task message
 type SendTaskRequest struct {
 	jsonrpc.Request
@@ -207,8 +317,8 @@ type GetTaskRequest struct {
 // CancelTaskRequest represents a request to cancel a task
 type CancelTaskRequest struct {
 	jsonrpc.Request
-	Method string       `json:"method"`
-	Params TaskIDParams `json:"params"`
+	Method string           `json:"method"`
+	Params TaskCancelParams `json:"params"`
 }
 
 // SetTaskPushNotificationRequest represents a request to set task notifications
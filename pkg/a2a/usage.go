@@ -0,0 +1,28 @@
+package a2a
+
+/*
+Usage counts the tokens a task consumed across every model call made for
+it, so it can be billed or budgeted.
+*/
+type Usage struct {
+	PromptTokens     int64 `json:"promptTokens"`
+	CompletionTokens int64 `json:"completionTokens"`
+	TotalTokens      int64 `json:"totalTokens"`
+}
+
+/*
+AddUsage adds the tokens of one model call to the task's usage.
+*/
+func (task *Task) AddUsage(promptTokens, completionTokens int64) {
+	if promptTokens == 0 && completionTokens == 0 {
+		return
+	}
+
+	if task.Usage == nil {
+		task.Usage = &Usage{}
+	}
+
+	task.Usage.PromptTokens += promptTokens
+	task.Usage.CompletionTokens += completionTokens
+	task.Usage.TotalTokens += promptTokens + completionTokens
+}
@@ -1,6 +1,8 @@
 package ai
 
 import (
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -93,3 +95,34 @@ func WithAuthService(authService *auth.Service) AgentOption {
 		agent.authService = authService
 	}
 }
+
+/*
+Requester identifies the caller behind a request by the subject of its
+token. It returns an empty string when no auth service is configured or the
+request does not authenticate.
+*/
+func (agent *Agent) Requester(req *http.Request) string {
+	if agent.authService == nil {
+		return ""
+	}
+
+	subject, err := agent.authService.Subject(req)
+	if err != nil {
+		return ""
+	}
+
+	return subject
+}
+
+/*
+Authenticate checks the credentials of an incoming request against the
+agent's auth service. It fails when no auth service is configured, so
+anything gated behind it stays closed by default.
+*/
+func (agent *Agent) Authenticate(req *http.Request) error {
+	if agent.authService == nil {
+		return fmt.Errorf("no auth service configured")
+	}
+
+	return agent.authService.AuthenticateRequest(req)
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+pendingApproval is a gated tool call parked until the client approves or
+denies it with a follow-up tasks/send.
+*/
+type pendingApproval struct {
+	task     a2a.Task
+	decision chan bool
+}
+
+/*
+approvalFor returns the ToolApprovalFunc used for a single task run. Calls
+to gated tools move the task to input-required with the pending call in its
+metadata, persist it, and block until a decision arrives through SendTask
+or the context ends. It returns nil when no tools require approval.
+*/
+func (manager *TaskManager) approvalFor(task *a2a.Task) provider.ToolApprovalFunc {
+	if len(manager.approvalTools) == 0 {
+		return nil
+	}
+
+	return func(ctx context.Context, toolName string, args map[string]any) (bool, error) {
+		if !manager.approvalTools[toolName] {
+			return true, nil
+		}
+
+		if task.Metadata == nil {
+			task.Metadata = make(map[string]any)
+		}
+
+		task.Metadata["pendingToolCall"] = map[string]any{
+			"name":      toolName,
+			"arguments": args,
+		}
+
+		manager.toStatus(task, a2a.TaskStateInputReq, a2a.NewTextMessage(
+			manager.agent.Name,
+			fmt.Sprintf("the %s tool requires approval, reply \"approve\" or \"deny\"", toolName),
+		))
+
+		pending := &pendingApproval{
+			task:     snapshotTask(task),
+			decision: make(chan bool, 1),
+		}
+
+		manager.approvalMu.Lock()
+		if manager.pendingApprovals == nil {
+			manager.pendingApprovals = make(map[string]*pendingApproval)
+		}
+		manager.pendingApprovals[task.ID] = pending
+		manager.approvalMu.Unlock()
+
+		defer func() {
+			manager.approvalMu.Lock()
+			if manager.pendingApprovals[task.ID] == pending {
+				delete(manager.pendingApprovals, task.ID)
+			}
+			manager.approvalMu.Unlock()
+		}()
+
+		if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+			log.Error("failed to persist task awaiting tool approval", "task_id", task.ID, "error", updErr)
+		}
+
+		select {
+		case approved := <-pending.decision:
+			// A nil value removes the key when the store merges metadata.
+			task.Metadata["pendingToolCall"] = nil
+			manager.toStatus(task, a2a.TaskStateWorking, a2a.NewTextMessage(
+				manager.agent.Name, approvalMessage(toolName, approved),
+			))
+			return approved, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+/*
+resolveApproval delivers the decision carried by a follow-up tasks/send to
+the tool call waiting on it. It reports false when the task has no pending
+tool call, in which case the message is handled as a regular task message.
+*/
+func (manager *TaskManager) resolveApproval(params a2a.TaskSendParams) (*a2a.Task, bool) {
+	manager.approvalMu.Lock()
+	pending, ok := manager.pendingApprovals[params.ID]
+	if ok {
+		delete(manager.pendingApprovals, params.ID)
+	}
+	manager.approvalMu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	approved := isApproval(params)
+	pending.decision <- approved
+
+	toolName := ""
+	if call, ok := pending.task.Metadata["pendingToolCall"].(map[string]any); ok {
+		toolName, _ = call["name"].(string)
+	}
+
+	task := pending.task
+	delete(task.Metadata, "pendingToolCall")
+	task.ToStatus(a2a.TaskStateWorking, a2a.NewTextMessage(
+		manager.agent.Name, approvalMessage(toolName, approved),
+	))
+
+	return &task, true
+}
+
+/*
+isApproval reads the decision from a follow-up message. An explicit
+"approved" metadata flag wins, otherwise the message text is matched.
+*/
+func isApproval(params a2a.TaskSendParams) bool {
+	if approved, ok := params.Metadata["approved"].(bool); ok {
+		return approved
+	}
+
+	switch strings.ToLower(strings.TrimSpace(params.Message.String())) {
+	case "approve", "approved", "yes", "y":
+		return true
+	}
+
+	return false
+}
+
+func approvalMessage(toolName string, approved bool) string {
+	if approved {
+		return fmt.Sprintf("the %s tool call was approved, resuming task", toolName)
+	}
+
+	return fmt.Sprintf("the %s tool call was denied, resuming task", toolName)
+}
+
+/*
+snapshotTask copies a task so it can be handed to another caller while the
+original keeps being mutated by the running provider.
+*/
+func snapshotTask(task *a2a.Task) a2a.Task {
+	snapshot := *task
+	snapshot.History = append([]a2a.Message(nil), task.History...)
+	snapshot.Artifacts = append([]a2a.Artifact(nil), task.Artifacts...)
+	snapshot.Metadata = make(map[string]any, len(task.Metadata))
+
+	for k, v := range task.Metadata {
+		snapshot.Metadata[k] = v
+	}
+
+	return snapshot
+}
@@ -0,0 +1,144 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestApprovalFor(t *testing.T) {
+	Convey("Given a TaskManager gating the docker tool behind approval", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentApproval"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for approval testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		inputRequired := make(chan struct{}, 1)
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+			updateFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+				if task.Status.State == a2a.TaskStateInputReq {
+					inputRequired <- struct{}{}
+				}
+				return nil
+			},
+		}
+
+		var executed atomic.Bool
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response)
+			go func() {
+				defer close(ch)
+				approved, err := params.ToolApproval(ctx, "docker", map[string]any{"cmd": "rm -rf /tmp/scratch"})
+				if err != nil {
+					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: errors.ErrInternal.Code, Message: err.Error()}}
+					return
+				}
+				if approved {
+					executed.Store(true)
+				}
+				ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+					Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage(agentCard.Name, "done")},
+				}}
+			}()
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(
+			agentCard,
+			WithTaskStore(store),
+			WithProvider(prov),
+			WithApprovalRequired("docker"),
+		)
+		So(initErr, ShouldBeNil)
+
+		type sendResult struct {
+			task *a2a.Task
+			err  *errors.RpcError
+		}
+
+		results := make(chan sendResult, 1)
+		go func() {
+			task, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-approval",
+				Message: *a2a.NewTextMessage("user", "clean up the scratch directory"),
+			})
+			results <- sendResult{task: task, err: err}
+		}()
+
+		select {
+		case <-inputRequired:
+		case <-time.After(2 * time.Second):
+			t.Fatal("task never reached input-required")
+		}
+
+		Convey("When the pending call is approved with a follow-up tasks/send", func() {
+			followUp, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-approval",
+				Message: *a2a.NewTextMessage("user", "approve"),
+			})
+			So(err, ShouldBeNil)
+			So(followUp.Status.State, ShouldEqual, a2a.TaskStateWorking)
+			So(followUp.Metadata, ShouldNotContainKey, "pendingToolCall")
+
+			result := <-results
+
+			Convey("Then the tool should run and the task should complete", func() {
+				So(result.err, ShouldBeNil)
+				So(executed.Load(), ShouldBeTrue)
+				So(result.task.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+			})
+		})
+
+		Convey("When the pending call is denied with a follow-up tasks/send", func() {
+			_, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-approval",
+				Message: *a2a.NewTextMessage("user", "deny"),
+			})
+			So(err, ShouldBeNil)
+
+			result := <-results
+
+			Convey("Then the tool should not run and the loop should resume", func() {
+				So(result.err, ShouldBeNil)
+				So(executed.Load(), ShouldBeFalse)
+				So(result.task.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+			})
+		})
+	})
+}
+
+func TestIsApproval(t *testing.T) {
+	Convey("Given follow-up messages for a pending tool call", t, func() {
+		Convey("Then approving replies should approve", func() {
+			So(isApproval(a2a.TaskSendParams{Message: *a2a.NewTextMessage("user", " Approve ")}), ShouldBeTrue)
+			So(isApproval(a2a.TaskSendParams{Message: *a2a.NewTextMessage("user", "yes")}), ShouldBeTrue)
+		})
+
+		Convey("Then any other reply should deny", func() {
+			So(isApproval(a2a.TaskSendParams{Message: *a2a.NewTextMessage("user", "no way")}), ShouldBeFalse)
+		})
+
+		Convey("Then the approved metadata flag should take precedence", func() {
+			So(isApproval(a2a.TaskSendParams{
+				Message:  *a2a.NewTextMessage("user", "approve"),
+				Metadata: map[string]any{"approved": false},
+			}), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+errCanceledByRequest is the cause of the context of a provider run that
+was stopped by CancelTask.
+*/
+var errCanceledByRequest = fmt.Errorf("task canceled")
+
+/*
+providerRun is a provider run in flight, which CancelTask can stop.
+*/
+type providerRun struct {
+	cancel context.CancelCauseFunc
+}
+
+/*
+startRun derives the context a provider run for the task works under,
+bounded by the task duration, and registers it so CancelTask can stop the
+run. The returned function releases the context and the registration, and
+must be called once the run is over.
+*/
+func (manager *TaskManager) startRun(ctx context.Context, taskID string) (context.Context, context.CancelFunc) {
+	runCtx, cancelRun := context.WithCancelCause(ctx)
+	run := &providerRun{cancel: cancelRun}
+
+	manager.runsMu.Lock()
+
+	if manager.runs == nil {
+		manager.runs = make(map[string]*providerRun)
+	}
+
+	manager.runs[taskID] = run
+	manager.runsMu.Unlock()
+
+	deadlineCtx, cancel := manager.withTaskDeadline(runCtx)
+
+	return deadlineCtx, func() {
+		cancel()
+		cancelRun(nil)
+
+		manager.runsMu.Lock()
+		defer manager.runsMu.Unlock()
+
+		// A newer run of the same task may have taken the slot already
+		if manager.runs[taskID] == run {
+			delete(manager.runs, taskID)
+		}
+	}
+}
+
+/*
+stopRun cancels the provider run of the task, if there is one, and reports
+whether there was.
+*/
+func (manager *TaskManager) stopRun(taskID string) bool {
+	manager.runsMu.Lock()
+	run, ok := manager.runs[taskID]
+	manager.runsMu.Unlock()
+
+	if ok {
+		run.cancel(errCanceledByRequest)
+	}
+
+	return ok
+}
+
+/*
+canceledByRequest reports whether a provider run stopped because the task
+was canceled.
+*/
+func canceledByRequest(run context.Context) bool {
+	return context.Cause(run) == errCanceledByRequest
+}
+
+/*
+cancelRun moves a task whose run was stopped by CancelTask to the canceled
+state, before the output streamed so far is written, so that write does
+not undo the cancellation in the task store.
+*/
+func (manager *TaskManager) cancelRun(task *a2a.Task) {
+	if task.Status.State == a2a.TaskStateCanceled {
+		return
+	}
+
+	manager.toStatus(task, a2a.TaskStateCanceled, a2a.NewTextMessage(manager.agent.Name, "task canceled"))
+}
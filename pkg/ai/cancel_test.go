@@ -0,0 +1,102 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestCancelRunningTask(t *testing.T) {
+	Convey("Given a task streaming from a provider that keeps generating", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentCancelRun"}
+		stopped := make(chan struct{})
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response)
+
+			go func() {
+				defer close(stopped)
+				defer close(ch)
+
+				for i := 0; ; i++ {
+					select {
+					case <-ctx.Done():
+						return
+					case ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+						ID:       params.Task.ID,
+						Artifact: a2a.Artifact{Index: i, Parts: []a2a.Part{a2a.NewTextPart(fmt.Sprintf("token %d", i))}},
+					}}:
+						time.Sleep(10 * time.Millisecond)
+					}
+				}
+			}()
+
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov))
+		So(initErr, ShouldBeNil)
+
+		task := a2a.NewTask(card.Name)
+		task.History = append(task.History, *a2a.NewTextMessage("user", "write forever"))
+
+		out, err := manager.StreamTask(context.Background(), task)
+		So(err, ShouldBeNil)
+
+		<-out
+		<-out
+
+		Convey("When the task is canceled", func() {
+			So(manager.CancelTask(context.Background(), task.ID), ShouldBeNil)
+
+			var (
+				last   jsonrpc.Response
+				closed bool
+			)
+
+			timeout := time.After(time.Second)
+
+		Drain:
+			for {
+				select {
+				case chunk, ok := <-out:
+					if !ok {
+						closed = true
+						break Drain
+					}
+
+					last = chunk
+				case <-timeout:
+					break Drain
+				}
+			}
+
+			Convey("Then the output channel should close promptly", func() {
+				So(closed, ShouldBeTrue)
+			})
+
+			Convey("Then the provider should have stopped generating", func() {
+				select {
+				case <-stopped:
+				case <-time.After(time.Second):
+					So("provider still generating", ShouldBeEmpty)
+				}
+			})
+
+			Convey("Then the task should end in the canceled state", func() {
+				status, ok := last.Result.(a2a.TaskStatusUpdateResult)
+				So(ok, ShouldBeTrue)
+				So(status.Final, ShouldBeTrue)
+				So(status.Status.State, ShouldEqual, a2a.TaskStateCanceled)
+				So(task.Status.State, ShouldEqual, a2a.TaskStateCanceled)
+			})
+		})
+	})
+}
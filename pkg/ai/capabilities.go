@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+applyCapabilities narrows the provider call down to the features the
+provider supports for the requested model, rather than letting the provider
+reject the call at runtime. Tools and streaming are switched off, image
+parts are left out for models without vision, and a schema is turned into
+an instruction for providers without a JSON mode.
+
+The history is only rewritten for the duration of the call. The returned
+function puts the original history back, keeping anything appended to it in
+the meantime. Calling it more than once has no further effect.
+*/
+func (manager *TaskManager) applyCapabilities(
+	task *a2a.Task, prvdr provider.Interface, params *provider.ProviderParams,
+) func() {
+	caps := provider.CapabilitiesFor(prvdr, params.Model)
+
+	if !caps.Tools && len(params.Tools) > 0 {
+		log.Info("provider does not support tools, omitting them", "model", params.Model, "tools", len(params.Tools))
+		params.Tools = nil
+	}
+
+	if !caps.Streaming && params.Stream {
+		log.Info("provider does not support streaming, falling back to a single response", "model", params.Model)
+		params.Stream = false
+	}
+
+	history, changed := gateHistory(task.History, caps)
+
+	if !changed {
+		return func() {}
+	}
+
+	original := task.History
+	task.History = history
+	restored := false
+
+	return func() {
+		if restored {
+			return
+		}
+
+		restored = true
+		task.History = append(original, task.History[len(history):]...)
+	}
+}
+
+/*
+gateHistory returns a copy of the history without what the capabilities rule
+out, and whether anything had to change.
+*/
+func gateHistory(history []a2a.Message, caps provider.ProviderCapabilities) ([]a2a.Message, bool) {
+	out := make([]a2a.Message, len(history))
+	copy(out, history)
+	changed := false
+
+	if !caps.Vision {
+		for i, msg := range out {
+			parts := make([]a2a.Part, 0, len(msg.Parts))
+
+			for _, part := range msg.Parts {
+				if isImagePart(part) {
+					continue
+				}
+				parts = append(parts, part)
+			}
+
+			if len(parts) != len(msg.Parts) {
+				log.Info("provider does not support vision, skipping image parts", "skipped", len(msg.Parts)-len(parts))
+				out[i].Parts = parts
+				changed = true
+			}
+		}
+	}
+
+	if !caps.JSONMode && len(out) > 0 {
+		last := &out[len(out)-1]
+
+		if schema, ok := last.Metadata["schema"]; ok && schema != nil {
+			buf, err := json.Marshal(schema)
+
+			if err == nil {
+				log.Info("provider has no JSON mode, asking for JSON in the prompt instead")
+
+				metadata := make(map[string]any, len(last.Metadata))
+				for k, v := range last.Metadata {
+					if k != "schema" {
+						metadata[k] = v
+					}
+				}
+
+				last.Metadata = metadata
+				last.Parts = withInstruction(last.Parts,
+					"Respond only with JSON that matches this JSON schema, without any other text:\n"+string(buf),
+				)
+				changed = true
+			}
+		}
+	}
+
+	return out, changed
+}
+
+/*
+withInstruction returns a copy of the parts with the instruction appended to
+the first text part, which is the one providers send to the model.
+*/
+func withInstruction(parts []a2a.Part, instruction string) []a2a.Part {
+	out := append([]a2a.Part(nil), parts...)
+
+	for i := range out {
+		if out[i].Type == a2a.PartTypeText {
+			out[i].Text = strings.TrimRight(out[i].Text, "\n") + "\n\n" + instruction
+			return out
+		}
+	}
+
+	return append(out, a2a.NewTextPart(instruction))
+}
+
+/*
+isImagePart reports whether the part carries an image.
+*/
+func isImagePart(part a2a.Part) bool {
+	return part.Type == a2a.PartTypeFile &&
+		part.File != nil &&
+		part.File.MimeType != nil &&
+		strings.HasPrefix(*part.File.MimeType, "image/")
+}
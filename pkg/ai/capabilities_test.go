@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestProviderCapabilities(t *testing.T) {
+	Convey("Given an agent with a tool skill", t, func() {
+		card := &a2a.AgentCard{
+			Name:   "TestAgentCapabilities",
+			Skills: []a2a.AgentSkill{{ID: "calculator", Name: "calculator"}},
+		}
+
+		prov := NewControllableMockProvider()
+
+		manager, initErr := NewTaskManager(
+			card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov),
+		)
+		So(initErr, ShouldBeNil)
+
+		send := func(message a2a.Message) *a2a.Task {
+			task, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-capabilities",
+				Message: message,
+			})
+			So(err, ShouldBeNil)
+			return task
+		}
+
+		Convey("When the provider supports tools", func() {
+			send(*a2a.NewTextMessage("user", "what is 6 * 7?"))
+
+			Convey("Then the tools should be sent with the request", func() {
+				So(prov.lastGenerateParams.Tools, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the provider reports no tool support", func() {
+			prov.capabilities = &provider.ProviderCapabilities{Streaming: true}
+			send(*a2a.NewTextMessage("user", "what is 6 * 7?"))
+
+			Convey("Then the tools should be omitted from the request", func() {
+				So(prov.lastGenerateParams.Tools, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the provider has no vision and no JSON mode", func() {
+			prov.capabilities = &provider.ProviderCapabilities{Streaming: true, Tools: true}
+
+			var sent []a2a.Message
+			prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+				sent = append([]a2a.Message(nil), params.Task.History...)
+				ch := make(chan jsonrpc.Response)
+				close(ch)
+				return ch
+			}
+
+			message := *a2a.NewTextMessage("user", "describe this picture")
+			message.Parts = append(message.Parts, a2a.NewFilePart("picture.png", "image/png", []byte("hello")))
+			message.Metadata = map[string]any{"schema": map[string]any{"type": "object"}}
+
+			task := send(message)
+
+			Convey("Then image parts should be skipped for the call", func() {
+				last := sent[len(sent)-1]
+				So(last.Parts, ShouldHaveLength, 1)
+				So(last.Parts[0].Text, ShouldContainSubstring, "describe this picture")
+			})
+
+			Convey("Then the schema should be asked for in the prompt instead", func() {
+				last := sent[len(sent)-1]
+				So(last.Metadata, ShouldNotContainKey, "schema")
+				So(last.Parts[0].Text, ShouldContainSubstring, `{"type":"object"}`)
+			})
+
+			Convey("Then the task should keep its original history", func() {
+				last := task.History[len(task.History)-1]
+				So(last.Parts, ShouldHaveLength, 2)
+				So(last.Parts[0].Text, ShouldEqual, "describe this picture")
+				So(last.Metadata, ShouldContainKey, "schema")
+			})
+		})
+	})
+}
@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+WithStreamCheckpoint throttles how often a streaming task is written to the
+task store while it runs, to once every chunks chunks or once every
+interval, whichever comes first. Status changes are always written, and
+whatever is still pending is written when the stream stops, so output
+streamed before a crash can be recovered from the store with tasks/get,
+minus at most the last unwritten stretch. Without either limit, every chunk
+is written.
+*/
+func WithStreamCheckpoint(chunks int, interval time.Duration) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.checkpointChunks = chunks
+		t.checkpointInterval = interval
+	}
+}
+
+/*
+checkpoint tracks the chunks of a stream that were not written to the task
+store yet.
+*/
+type checkpoint struct {
+	chunks   int
+	interval time.Duration
+	pending  int
+	last     time.Time
+	state    a2a.TaskState
+}
+
+/*
+newCheckpoint returns the checkpoint of a stream, using the limits set with
+WithStreamCheckpoint, or else the a2a.checkpoint.chunks and
+a2a.checkpoint.interval config values.
+*/
+func (manager *TaskManager) newCheckpoint(task *a2a.Task) *checkpoint {
+	cp := &checkpoint{
+		chunks:   manager.checkpointChunks,
+		interval: manager.checkpointInterval,
+		last:     time.Now(),
+		state:    task.Status.State,
+	}
+
+	if cp.chunks <= 0 && cp.interval <= 0 {
+		v := viper.GetViper()
+		cp.chunks = v.GetInt("a2a.checkpoint.chunks")
+		cp.interval = v.GetDuration("a2a.checkpoint.interval")
+	}
+
+	return cp
+}
+
+/*
+due counts a chunk applied to the task and reports whether the task should
+be written now.
+*/
+func (cp *checkpoint) due(task *a2a.Task) bool {
+	cp.pending++
+
+	switch {
+	case task.Status.State != cp.state:
+	case cp.chunks <= 0 && cp.interval <= 0:
+	case cp.chunks > 0 && cp.pending >= cp.chunks:
+	case cp.interval > 0 && time.Since(cp.last) >= cp.interval:
+	default:
+		return false
+	}
+
+	cp.pending = 0
+	cp.last = time.Now()
+	cp.state = task.Status.State
+
+	return true
+}
+
+/*
+persistChunk writes the task to the store when its checkpoint is due.
+*/
+func (manager *TaskManager) persistChunk(ctx context.Context, task *a2a.Task, cp *checkpoint) {
+	if !cp.due(task) {
+		return
+	}
+
+	if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+		log.Error("failed to persist streaming update", "task_id", task.ID, "error", updErr)
+	}
+}
+
+/*
+flushCheckpoint writes the chunks that are still pending, for a stream that
+stops early. It runs without the stream's cancelation, as the stream often
+stops because its context was canceled.
+*/
+func (manager *TaskManager) flushCheckpoint(ctx context.Context, task *a2a.Task, cp *checkpoint) {
+	if cp.pending == 0 {
+		return
+	}
+
+	cp.pending = 0
+
+	if updErr := manager.taskStore.Update(context.WithoutCancel(ctx), task, manager.agent.Name); updErr != nil {
+		log.Error("failed to persist partial stream", "task_id", task.ID, "error", updErr)
+	}
+}
@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestStreamCheckpoint(t *testing.T) {
+	Convey("Given a streaming task that crashes after five chunks", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentCheckpoint"}
+
+		var (
+			mu       sync.Mutex
+			writes   int
+			recorded []string
+		)
+
+		recovered := make(chan struct{})
+		var once sync.Once
+
+		store := &taskStoreMockForTesting{
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+			updateFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+				mu.Lock()
+				defer mu.Unlock()
+
+				writes++
+				recorded = recorded[:0]
+
+				for _, artifact := range task.Artifacts {
+					recorded = append(recorded, artifact.Parts[0].Text)
+				}
+
+				if len(recorded) == 5 {
+					once.Do(func() { close(recovered) })
+				}
+
+				return nil
+			},
+		}
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response)
+
+			go func() {
+				for i := range 5 {
+					ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+						ID:       params.Task.ID,
+						Artifact: a2a.Artifact{Index: i, Parts: []a2a.Part{a2a.NewTextPart(fmt.Sprintf("chunk %d", i))}},
+					}}
+				}
+
+				// The provider hangs until the process goes away.
+				<-ctx.Done()
+			}()
+
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(card,
+			WithTaskStore(store), WithProvider(prov), WithStreamCheckpoint(2, time.Hour),
+		)
+		So(initErr, ShouldBeNil)
+
+		ctx, crash := context.WithCancel(context.Background())
+		defer crash()
+
+		task := a2a.NewTask(card.Name)
+		task.History = append(task.History, *a2a.NewTextMessage("user", "stream five chunks"))
+
+		out, err := manager.StreamTask(ctx, task)
+		So(err, ShouldBeNil)
+
+		for range 5 {
+			<-out
+		}
+
+		mu.Lock()
+		streamedWrites, streamed := writes, append([]string(nil), recorded...)
+		mu.Unlock()
+
+		Convey("Then the chunks should be written every second chunk while streaming", func() {
+			So(streamedWrites, ShouldEqual, 2)
+			So(streamed, ShouldResemble, []string{"chunk 0", "chunk 1", "chunk 2", "chunk 3"})
+		})
+
+		Convey("When the stream crashes before completing", func() {
+			crash()
+
+			select {
+			case <-recovered:
+			case <-time.After(time.Second):
+			}
+
+			Convey("Then every streamed chunk should be recoverable from the store", func() {
+				mu.Lock()
+				defer mu.Unlock()
+
+				So(recorded, ShouldResemble, []string{"chunk 0", "chunk 1", "chunk 2", "chunk 3", "chunk 4"})
+			})
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+/*
+ConcurrencyStats reports how many of an agent's tasks wait for a slot and
+how many run their provider call right now.
+*/
+type ConcurrencyStats struct {
+	Limit   int   `json:"limit"`
+	Queued  int64 `json:"queued"`
+	Running int64 `json:"running"`
+}
+
+/*
+WithMaxConcurrentTasks limits how many tasks of this agent run their
+provider call at the same time. Further tasks queue until a slot frees up,
+or their context is done. As every agent has its own task manager, a flood
+of tasks to one agent never delays the tasks of another, whatever their
+limits. A value of zero or less leaves the agent unlimited.
+*/
+func WithMaxConcurrentTasks(n int) TaskManagerOption {
+	return func(t *TaskManager) {
+		if n <= 0 {
+			t.slots = nil
+			return
+		}
+
+		t.slots = make(chan struct{}, n)
+	}
+}
+
+/*
+Concurrency returns the agent's queued and running tasks.
+*/
+func (manager *TaskManager) Concurrency() ConcurrencyStats {
+	return ConcurrencyStats{
+		Limit:   cap(manager.slots),
+		Queued:  manager.queued.Load(),
+		Running: manager.running.Load(),
+	}
+}
+
+/*
+acquireSlot waits for a free slot of the agent, and returns the function
+that frees it again. It fails when ctx is done before a slot frees up.
+*/
+func (manager *TaskManager) acquireSlot(
+	ctx context.Context, taskID string,
+) (func(), *errors.RpcError) {
+	release := func() {
+		manager.running.Add(-1)
+
+		if manager.slots != nil {
+			<-manager.slots
+		}
+	}
+
+	if manager.slots == nil {
+		manager.running.Add(1)
+		return release, nil
+	}
+
+	select {
+	case manager.slots <- struct{}{}:
+		manager.running.Add(1)
+		return release, nil
+	default:
+	}
+
+	manager.queued.Add(1)
+	defer manager.queued.Add(-1)
+
+	log.Info("task queued for a free slot", "task_id", taskID, "limit", cap(manager.slots))
+
+	select {
+	case manager.slots <- struct{}{}:
+		manager.running.Add(1)
+		return release, nil
+	case <-ctx.Done():
+		return nil, &errors.RpcError{
+			Code:    errors.ErrInternal.Code,
+			Message: "task was not started: " + ctx.Err().Error(),
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestConcurrencyIsolation(t *testing.T) {
+	Convey("Given two agents limited to one task at a time", t, func() {
+		unblock := make(chan struct{})
+
+		blocking := NewControllableMockProvider()
+		blocking.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response)
+
+			go func() {
+				defer close(ch)
+				<-unblock
+			}()
+
+			return ch
+		}
+
+		agentA, initErr := NewTaskManager(
+			&a2a.AgentCard{Name: "AgentA"},
+			WithTaskStore(&taskStoreMockForTesting{}), WithProvider(blocking),
+			WithMaxConcurrentTasks(1),
+		)
+		So(initErr, ShouldBeNil)
+
+		agentB, initErr := NewTaskManager(
+			&a2a.AgentCard{Name: "AgentB"},
+			WithTaskStore(&taskStoreMockForTesting{}), WithProvider(NewControllableMockProvider()),
+			WithMaxConcurrentTasks(1),
+		)
+		So(initErr, ShouldBeNil)
+
+		send := func(manager *TaskManager, id string) chan *errors.RpcError {
+			done := make(chan *errors.RpcError, 1)
+
+			go func() {
+				_, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+					ID:      id,
+					Message: *a2a.NewTextMessage("user", "hello"),
+				})
+				done <- err
+			}()
+
+			return done
+		}
+
+		Convey("When agent A is saturated", func() {
+			first := send(agentA, "a-1")
+			second := send(agentA, "a-2")
+
+			So(eventually(func() bool {
+				stats := agentA.Concurrency()
+				return stats.Running == 1 && stats.Queued == 1
+			}), ShouldBeTrue)
+
+			Convey("Then agent B should still run its task right away", func() {
+				select {
+				case err := <-send(agentB, "b-1"):
+					So(err, ShouldBeNil)
+				case <-time.After(time.Second):
+					t.Fatal("agent B was delayed by agent A")
+				}
+
+				So(agentB.Concurrency(), ShouldResemble, ConcurrencyStats{Limit: 1})
+			})
+
+			Convey("Then agent A should report its queued and running tasks", func() {
+				So(agentA.Concurrency(), ShouldResemble, ConcurrencyStats{Limit: 1, Queued: 1, Running: 1})
+			})
+
+			close(unblock)
+			So(<-first, ShouldBeNil)
+			So(<-second, ShouldBeNil)
+			So(agentA.Concurrency(), ShouldResemble, ConcurrencyStats{Limit: 1})
+		})
+
+		Convey("When a queued task's context is done", func() {
+			first := send(agentA, "a-1")
+
+			So(eventually(func() bool { return agentA.Concurrency().Running == 1 }), ShouldBeTrue)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			_, err := agentA.SendTask(ctx, a2a.TaskSendParams{
+				ID:      "a-2",
+				Message: *a2a.NewTextMessage("user", "hello"),
+			})
+
+			Convey("Then it should fail without running", func() {
+				So(err, ShouldNotBeNil)
+				So(agentA.Concurrency().Queued, ShouldEqual, 0)
+			})
+
+			close(unblock)
+			So(<-first, ShouldBeNil)
+		})
+	})
+}
+
+/*
+eventually polls condition until it holds, for at most a second.
+*/
+func eventually(condition func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	return false
+}
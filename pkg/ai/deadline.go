@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+WithMaxDuration bounds how long the provider may work on a single task.
+Once it is up, the provider's context is canceled and the task fails with
+a message saying so, rather than a runaway tool loop keeping it, and the
+request waiting on it, around forever. Zero or less leaves tasks unbounded.
+*/
+func WithMaxDuration(limit time.Duration) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.maxDuration = limit
+	}
+}
+
+/*
+taskDuration returns the limit set with WithMaxDuration, or the
+a2a.max_task_duration config value when none was given. Zero leaves tasks
+unbounded.
+*/
+func (manager *TaskManager) taskDuration() time.Duration {
+	if manager.maxDuration > 0 {
+		return manager.maxDuration
+	}
+
+	return viper.GetViper().GetDuration("a2a.max_task_duration")
+}
+
+/*
+withTaskDeadline derives the context a provider run works under, bounded by
+the task duration when there is one.
+*/
+func (manager *TaskManager) withTaskDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if limit := manager.taskDuration(); limit > 0 {
+		return context.WithTimeout(ctx, limit)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+/*
+overDeadline reports whether the provider run stopped because the task ran
+out of time, rather than because the caller went away.
+*/
+func overDeadline(parent, run context.Context) bool {
+	return parent.Err() == nil && run.Err() == context.DeadlineExceeded
+}
+
+/*
+failOverDeadline fails a task that ran out of time.
+*/
+func (manager *TaskManager) failOverDeadline(task *a2a.Task) {
+	manager.toStatus(task, a2a.TaskStateFailed, a2a.NewTextMessage(
+		manager.agent.Name,
+		fmt.Sprintf("task exceeded its maximum duration of %s and was stopped", manager.taskDuration()),
+	))
+}
+
+/*
+untilDone forwards the chunks of a provider run until it ends or ctx is
+done, whichever comes first. A provider that does not stop sending when its
+context is canceled is drained in the background, so it never blocks on a
+send nobody receives.
+*/
+func untilDone(ctx context.Context, chunks chan jsonrpc.Response) chan jsonrpc.Response {
+	out := make(chan jsonrpc.Response)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				go func() {
+					for range chunks {
+					}
+				}()
+
+				return
+			case chunk, ok := <-chunks:
+				if !ok {
+					return
+				}
+
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+				}
+			}
+		}
+	}()
+
+	return out
+}
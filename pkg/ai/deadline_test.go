@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestMaxDuration(t *testing.T) {
+	Convey("Given a TaskManager with a maximum task duration and a provider that never finishes", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentMaxDuration"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for deadline testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		var stored *a2a.Task
+
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+			updateFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+				stored = task
+				return nil
+			},
+		}
+
+		stopped := make(chan struct{})
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response)
+
+			go func() {
+				ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+					Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Message: a2a.NewTextMessage("assistant", "calling tools")},
+				}}
+
+				<-ctx.Done()
+				close(stopped)
+			}()
+
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(agentCard,
+			WithTaskStore(store), WithProvider(prov), WithMaxDuration(50*time.Millisecond),
+		)
+		So(initErr, ShouldBeNil)
+
+		Convey("When the task is sent", func() {
+			start := time.Now()
+			task, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-deadline",
+				Message: *a2a.NewTextMessage("user", "loop forever"),
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then SendTask should return once the task ran out of time", func() {
+				So(time.Since(start), ShouldBeLessThan, time.Second)
+			})
+
+			Convey("Then the task should have failed with a clear message", func() {
+				So(task.Status.State, ShouldEqual, a2a.TaskStateFailed)
+				So(task.Status.Message.String(), ShouldContainSubstring, "maximum duration of 50ms")
+				So(stored.Status.State, ShouldEqual, a2a.TaskStateFailed)
+			})
+
+			Convey("Then the provider should have seen its context end", func() {
+				select {
+				case <-stopped:
+				case <-time.After(time.Second):
+					So("provider still running", ShouldBeEmpty)
+				}
+			})
+		})
+
+		Convey("When the task is streamed", func() {
+			task := a2a.NewTask(agentCard.Name)
+			task.ID = "task-id-for-stream-deadline"
+			task.History = append(task.History, *a2a.NewTextMessage("user", "loop forever"))
+
+			stream, err := manager.StreamTask(context.Background(), task)
+			So(err, ShouldBeNil)
+
+			var last jsonrpc.Response
+
+			for chunk := range stream {
+				last = chunk
+			}
+
+			Convey("Then the stream should end with a final failed status", func() {
+				status, ok := last.Result.(a2a.TaskStatusUpdateResult)
+				So(ok, ShouldBeTrue)
+				So(status.Final, ShouldBeTrue)
+				So(status.Status.State, ShouldEqual, a2a.TaskStateFailed)
+				So(status.Status.Message.String(), ShouldContainSubstring, "maximum duration of 50ms")
+			})
+		})
+	})
+}
@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+defaultDebugLimit bounds a task's debug log when WithTaskDebug is given no
+explicit limit.
+*/
+const defaultDebugLimit = 100
+
+/*
+trace appends a breadcrumb to the task's debug log. It does nothing unless
+the manager was created with WithTaskDebug.
+*/
+func (manager *TaskManager) trace(
+	task *a2a.Task,
+	kind a2a.DebugEventKind,
+	message string,
+	duration time.Duration,
+	data map[string]any,
+) {
+	if !manager.debug {
+		return
+	}
+
+	task.AddDebugEvent(a2a.DebugEvent{
+		Kind:     kind,
+		Message:  message,
+		Duration: duration,
+		Data:     data,
+	}, manager.debugLimit)
+}
+
+/*
+toStatus moves the task to a new state and records the transition in the
+debug log when the state actually changes.
+*/
+func (manager *TaskManager) toStatus(task *a2a.Task, state a2a.TaskState, message *a2a.Message) {
+	from := task.Status.State
+	task.ToStatus(state, message)
+	manager.traceTransition(task, from)
+
+	if IsTerminalState(state) {
+		manager.emit(task, TaskEventTerminal, nil)
+	} else {
+		manager.emit(task, TaskEventStatus, nil)
+	}
+}
+
+func (manager *TaskManager) traceTransition(task *a2a.Task, from a2a.TaskState) {
+	if from == task.Status.State {
+		return
+	}
+
+	manager.trace(task, a2a.DebugEventTransition,
+		fmt.Sprintf("%s -> %s", from, task.Status.State), 0,
+		map[string]any{"from": from, "to": task.Status.State},
+	)
+}
+
+/*
+toolCallTracer returns the hook that records executed tool calls in the
+task's debug log, or nil when debugging is off.
+*/
+func (manager *TaskManager) toolCallTracer(task *a2a.Task) provider.ToolCallHook {
+	if !manager.debug {
+		return nil
+	}
+
+	return func(toolName string, args map[string]any, duration time.Duration, err error) {
+		data := map[string]any{"tool": toolName, "arguments": args}
+
+		if err != nil {
+			data["error"] = err.Error()
+		}
+
+		manager.trace(task, a2a.DebugEventToolCall, "called "+toolName, duration, data)
+	}
+}
+
+/*
+traceProviderCall records the start of a provider call and returns a func
+that records its end along with the time it took.
+*/
+func (manager *TaskManager) traceProviderCall(task *a2a.Task, params *provider.ProviderParams) func() {
+	if !manager.debug {
+		return func() {}
+	}
+
+	started := time.Now()
+	data := map[string]any{"model": params.Model, "stream": params.Stream}
+
+	manager.trace(task, a2a.DebugEventProvider, "provider call started", 0, data)
+
+	return func() {
+		manager.trace(task, a2a.DebugEventProvider, "provider call finished", time.Since(started), data)
+	}
+}
@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestTaskDebug(t *testing.T) {
+	Convey("Given a TaskManager with task debugging enabled", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentTaskDebug"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for task debug testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		var stored []a2a.Task
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				if len(stored) == 0 {
+					return nil, errors.ErrTaskNotFound
+				}
+				return stored[len(stored)-1:], nil
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+			updateFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+				stored = append(stored, *task)
+				return nil
+			},
+		}
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response, 1)
+			if params.OnToolCall != nil {
+				params.OnToolCall("docker", map[string]any{"cmd": "ls"}, 5*time.Millisecond, nil)
+			}
+			ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage("assistant", "done")},
+			}}
+			close(ch)
+			return ch
+		}
+
+		sendParams := a2a.TaskSendParams{
+			ID:      "task-id-for-debug",
+			Message: *a2a.NewTextMessage("user", "list the files"),
+		}
+
+		Convey("When a task runs and is fetched again", func() {
+			manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(prov), WithTaskDebug())
+			So(initErr, ShouldBeNil)
+
+			_, err := manager.SendTask(context.Background(), sendParams)
+			So(err, ShouldBeNil)
+
+			fetched, err := manager.GetTask(context.Background(), sendParams.ID, 0)
+			So(err, ShouldBeNil)
+
+			kinds := map[a2a.DebugEventKind][]a2a.DebugEvent{}
+			for _, event := range fetched.Debug {
+				kinds[event.Kind] = append(kinds[event.Kind], event)
+			}
+
+			Convey("Then it should carry the tool-call breadcrumb", func() {
+				So(kinds[a2a.DebugEventToolCall], ShouldHaveLength, 1)
+				So(kinds[a2a.DebugEventToolCall][0].Data["tool"], ShouldEqual, "docker")
+				So(kinds[a2a.DebugEventToolCall][0].Duration, ShouldEqual, 5*time.Millisecond)
+			})
+
+			Convey("Then it should carry the state transitions", func() {
+				transitions := kinds[a2a.DebugEventTransition]
+				So(transitions, ShouldHaveLength, 2)
+				So(transitions[0].Data["to"], ShouldEqual, a2a.TaskStateWorking)
+				So(transitions[1].Data["from"], ShouldEqual, a2a.TaskStateWorking)
+				So(transitions[1].Data["to"], ShouldEqual, a2a.TaskStateCompleted)
+			})
+
+			Convey("Then it should time the provider call", func() {
+				So(kinds[a2a.DebugEventProvider], ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When the debug log is bounded", func() {
+			manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(prov), WithTaskDebug(2))
+			So(initErr, ShouldBeNil)
+
+			task, err := manager.SendTask(context.Background(), sendParams)
+			So(err, ShouldBeNil)
+
+			Convey("Then only the most recent events should be kept", func() {
+				So(task.Debug, ShouldHaveLength, 2)
+				So(task.Debug[1].Message, ShouldEqual, "provider call finished")
+			})
+		})
+
+		Convey("When debugging is off", func() {
+			manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(prov))
+			So(initErr, ShouldBeNil)
+
+			task, err := manager.SendTask(context.Background(), sendParams)
+			So(err, ShouldBeNil)
+
+			Convey("Then no debug log should be recorded", func() {
+				So(task.Debug, ShouldBeEmpty)
+				So(prov.lastGenerateParams.OnToolCall, ShouldBeNil)
+			})
+		})
+	})
+}
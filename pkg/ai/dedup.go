@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+artifactDedup remembers the last artifact chunk of a provider run, so a chunk
+a provider emits twice in a row is dropped before it is stored or streamed.
+Chunks only count as duplicates when they share the artifact index, which
+keeps content the model genuinely repeats in another artifact.
+*/
+type artifactDedup struct {
+	seen  bool
+	index int
+	hash  [sha256.Size]byte
+}
+
+/*
+duplicate reports whether the chunk carries the same artifact, at the same
+index, as the artifact chunk before it.
+*/
+func (dedup *artifactDedup) duplicate(chunk jsonrpc.Response) bool {
+	var artifact a2a.Artifact
+
+	switch result := chunk.Result.(type) {
+	case a2a.TaskArtifactUpdateEvent:
+		artifact = result.Artifact
+	case a2a.ArtifactResult:
+		artifact = result.Artifact
+	default:
+		return false
+	}
+
+	hash, ok := artifactHash(artifact)
+
+	if !ok {
+		return false
+	}
+
+	if dedup.seen && dedup.index == artifact.Index && dedup.hash == hash {
+		return true
+	}
+
+	dedup.seen, dedup.index, dedup.hash = true, artifact.Index, hash
+	return false
+}
+
+/*
+artifactHash hashes what the artifact carries. Metadata is left out, as it
+may differ between two emissions of the same chunk.
+*/
+func artifactHash(artifact a2a.Artifact) ([sha256.Size]byte, bool) {
+	artifact.Metadata = nil
+	buf, err := json.Marshal(artifact)
+
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+
+	return sha256.Sum256(buf), true
+}
+
+/*
+suppressDuplicate reports whether the chunk repeats the previous artifact
+chunk, in which case it is counted and logged, and must be skipped.
+*/
+func (manager *TaskManager) suppressDuplicate(task *a2a.Task, dedup *artifactDedup, chunk jsonrpc.Response) bool {
+	if !dedup.duplicate(chunk) {
+		return false
+	}
+
+	manager.duplicateArtifacts.Add(1)
+	log.Warn("suppressed duplicate artifact chunk", "task_id", task.ID, "index", dedup.index)
+
+	return true
+}
+
+/*
+DuplicateArtifacts returns how many duplicate artifact chunks the manager
+suppressed across all tasks.
+*/
+func (manager *TaskManager) DuplicateArtifacts() int64 {
+	return manager.duplicateArtifacts.Load()
+}
@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestArtifactDeduplication(t *testing.T) {
+	Convey("Given a provider that emits an artifact chunk twice", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentDedup"}
+
+		var events []TaskEvent
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			chunk := func(index int) jsonrpc.Response {
+				return jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+					ID:       params.Task.ID,
+					Artifact: a2a.Artifact{Index: index, Parts: []a2a.Part{a2a.NewTextPart("hello")}},
+				}}
+			}
+
+			ch := make(chan jsonrpc.Response, 3)
+			ch <- chunk(0)
+			ch <- chunk(0)
+			ch <- chunk(1)
+			close(ch)
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(
+			card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov),
+			WithEventSink(func(event TaskEvent) {
+				if event.Kind == TaskEventArtifact {
+					events = append(events, event)
+				}
+			}),
+		)
+		So(initErr, ShouldBeNil)
+
+		Convey("When the task is streamed", func() {
+			task := a2a.NewTask(card.Name)
+			task.History = append(task.History, *a2a.NewTextMessage("user", "say hello"))
+
+			out, err := manager.StreamTask(context.Background(), task)
+			So(err, ShouldBeNil)
+
+			var streamed []jsonrpc.Response
+			for chunk := range out {
+				streamed = append(streamed, chunk)
+			}
+
+			Convey("Then the duplicate should be streamed and stored once", func() {
+				So(streamed, ShouldHaveLength, 3) // Two artifacts and the final status
+				So(task.Artifacts, ShouldHaveLength, 2)
+				So(events, ShouldHaveLength, 2)
+			})
+
+			Convey("Then the same content at another index should be kept", func() {
+				So(task.Artifacts[0].Index, ShouldEqual, 0)
+				So(task.Artifacts[1].Index, ShouldEqual, 1)
+			})
+
+			Convey("Then the suppressed chunk should be counted", func() {
+				So(manager.DuplicateArtifacts(), ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,189 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/stores"
+)
+
+// maxRevisionMarks bounds the revisions remembered per task. A diff against
+// an older revision holds the whole task.
+const maxRevisionMarks = 256
+
+/*
+revisionMark is what a task looked like at a revision, as far as DiffTask
+needs to know: how long its history was, a fingerprint of every artifact,
+and its status.
+*/
+type revisionMark struct {
+	revision  int64
+	history   int
+	artifacts []uint64
+	status    a2a.TaskStatus
+}
+
+/*
+revisionStore wraps the task store of a TaskManager. It numbers every write
+of a task with the next revision, and remembers a mark for each of the
+recent ones, so DiffTask can tell what changed since any of them. Every
+write goes through the task manager, so no write is missed.
+*/
+type revisionStore struct {
+	stores.TaskStore
+	mu     sync.Mutex
+	latest map[string]int64
+	marks  map[string][]revisionMark
+}
+
+func newRevisionStore(store stores.TaskStore) *revisionStore {
+	return &revisionStore{
+		TaskStore: store,
+		latest:    make(map[string]int64),
+		marks:     make(map[string][]revisionMark),
+	}
+}
+
+func (store *revisionStore) Create(ctx context.Context, task *a2a.Task, optionals ...string) *errors.RpcError {
+	return store.write(task, func() *errors.RpcError {
+		return store.TaskStore.Create(ctx, task, optionals...)
+	})
+}
+
+func (store *revisionStore) Update(ctx context.Context, task *a2a.Task, optionals ...string) *errors.RpcError {
+	return store.write(task, func() *errors.RpcError {
+		return store.TaskStore.Update(ctx, task, optionals...)
+	})
+}
+
+/*
+write sets the next revision on the task before it is stored, and marks
+the revision once it is. A task read back from the store after a restart
+carries on from its stored revision. The lock is not held while the task
+is stored, so a slow store does not hold up the writes of other tasks.
+*/
+func (store *revisionStore) write(task *a2a.Task, persist func() *errors.RpcError) *errors.RpcError {
+	store.mu.Lock()
+	revision := max(store.latest[task.ID], task.Revision) + 1
+	store.latest[task.ID] = revision
+	store.mu.Unlock()
+
+	task.Revision = revision
+
+	if err := persist(); err != nil {
+		return err
+	}
+
+	mark := revisionMark{
+		revision:  revision,
+		history:   len(task.History),
+		artifacts: make([]uint64, len(task.Artifacts)),
+		status:    task.Status,
+	}
+
+	for i, artifact := range task.Artifacts {
+		mark.artifacts[i] = fingerprintArtifact(artifact)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	marks := append(store.marks[task.ID], mark)
+
+	if len(marks) > maxRevisionMarks {
+		marks = marks[len(marks)-maxRevisionMarks:]
+	}
+
+	store.marks[task.ID] = marks
+	return nil
+}
+
+/*
+mark returns the mark of a revision of a task, and false when it is not
+remembered.
+*/
+func (store *revisionStore) mark(id string, revision int64) (revisionMark, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, mark := range store.marks[id] {
+		if mark.revision == revision {
+			return mark, true
+		}
+	}
+
+	return revisionMark{}, false
+}
+
+func fingerprintArtifact(artifact a2a.Artifact) uint64 {
+	buf, _ := json.Marshal(artifact)
+	hash := fnv.New64a()
+	hash.Write(buf)
+
+	return hash.Sum64()
+}
+
+/*
+DiffTask returns what changed in a task since the given revision, which
+clients take from the revision of a task they fetched before: the history
+messages added since, the artifacts added or changed since, and the status
+if it changed, together with the current revision to ask from next time.
+When the revision is not remembered, the diff holds the whole task and is
+marked as a reset.
+*/
+func (manager *TaskManager) DiffTask(
+	ctx context.Context, id string, revision int64,
+) (*a2a.TaskDiff, *errors.RpcError) {
+	task, err := manager.GetTask(ctx, id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &a2a.TaskDiff{ID: task.ID, Revision: task.Revision, Since: revision}
+
+	if revision == task.Revision {
+		return diff, nil
+	}
+
+	mark, ok := manager.revisions.mark(task.ID, revision)
+
+	if !ok || revision > task.Revision || mark.history > len(task.History) {
+		diff.Reset = true
+		diff.Status = &task.Status
+		diff.History = task.History
+		diff.Artifacts = task.Artifacts
+
+		return diff, nil
+	}
+
+	if mark.status.State != task.Status.State || !mark.status.Timestamp.Equal(task.Status.Timestamp) {
+		diff.Status = &task.Status
+	}
+
+	diff.History = task.History[mark.history:]
+
+	// Artifacts can be dropped by the overflow policy, so they are matched
+	// by content rather than by position.
+	known := make(map[uint64]int, len(mark.artifacts))
+
+	for _, fingerprint := range mark.artifacts {
+		known[fingerprint]++
+	}
+
+	for _, artifact := range task.Artifacts {
+		fingerprint := fingerprintArtifact(artifact)
+
+		if known[fingerprint] > 0 {
+			known[fingerprint]--
+			continue
+		}
+
+		diff.Artifacts = append(diff.Artifacts, artifact)
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestDiffTask(t *testing.T) {
+	Convey("Given a task with an artifact that a client fetched", t, func() {
+		ctx := context.Background()
+		agentCard := &a2a.AgentCard{Name: "TestAgentDiff"}
+
+		manager, err := NewTaskManager(agentCard, WithTaskStore(newPublishingTaskStore()), WithProvider(&mockOpenAIProvider{}))
+		So(err, ShouldBeNil)
+
+		task := a2a.NewTask(agentCard.Name)
+		task.AddArtifact(a2a.Artifact{Parts: []a2a.Part{a2a.NewTextPart("first")}})
+		So(manager.taskStore.Create(ctx, task, agentCard.Name), ShouldBeNil)
+
+		fetched, rpcErr := manager.GetTask(ctx, task.ID, 0)
+		So(rpcErr, ShouldBeNil)
+		So(fetched.Revision, ShouldEqual, 1)
+
+		Convey("When nothing changed since", func() {
+			diff, rpcErr := manager.DiffTask(ctx, task.ID, fetched.Revision)
+
+			Convey("Then the diff should be empty", func() {
+				So(rpcErr, ShouldBeNil)
+				So(diff.Revision, ShouldEqual, fetched.Revision)
+				So(diff.Status, ShouldBeNil)
+				So(diff.History, ShouldBeEmpty)
+				So(diff.Artifacts, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When an artifact is added and the status changes", func() {
+			task.AddArtifact(a2a.Artifact{Parts: []a2a.Part{a2a.NewTextPart("second")}})
+			task.ToStatus(a2a.TaskStateWorking, a2a.NewTextMessage("assistant", "working on it"))
+			So(manager.taskStore.Update(ctx, task, agentCard.Name), ShouldBeNil)
+
+			diff, rpcErr := manager.DiffTask(ctx, task.ID, fetched.Revision)
+
+			Convey("Then the diff should only hold the new artifact and the new status", func() {
+				So(rpcErr, ShouldBeNil)
+				So(diff.Reset, ShouldBeFalse)
+				So(diff.Since, ShouldEqual, 1)
+				So(diff.Revision, ShouldEqual, 2)
+				So(diff.History, ShouldBeEmpty)
+				So(diff.Artifacts, ShouldHaveLength, 1)
+				So(diff.Artifacts[0].Parts[0].Text, ShouldEqual, "second")
+				So(diff.Status, ShouldNotBeNil)
+				So(diff.Status.State, ShouldEqual, a2a.TaskStateWorking)
+			})
+		})
+
+		Convey("When the client asks from a revision that is not known", func() {
+			diff, rpcErr := manager.DiffTask(ctx, task.ID, 42)
+
+			Convey("Then the diff should hold the whole task", func() {
+				So(rpcErr, ShouldBeNil)
+				So(diff.Reset, ShouldBeTrue)
+				So(diff.Artifacts, ShouldHaveLength, 1)
+				So(diff.History, ShouldHaveLength, len(task.History))
+			})
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+isDryRun reports whether a streamed task asked for a dry run. StreamTask
+receives a task rather than TaskSendParams, so the flag travels in the
+task's metadata.
+*/
+func isDryRun(task *a2a.Task) bool {
+	dryRun, _ := task.Metadata["dryRun"].(bool)
+	return dryRun
+}
+
+/*
+addDryRunPlan attaches the tool calls captured during a dry run to the task
+as a final artifact, both as readable text and as structured data.
+*/
+func (manager *TaskManager) addDryRunPlan(task *a2a.Task, params *provider.ProviderParams) {
+	if !params.DryRun {
+		return
+	}
+
+	var sb strings.Builder
+	calls := make([]any, 0, len(params.PlannedToolCalls))
+
+	if len(params.PlannedToolCalls) == 0 {
+		sb.WriteString("No tool calls were planned.")
+	}
+
+	for i, call := range params.PlannedToolCalls {
+		fmt.Fprintf(&sb, "%d. %s %v\n", i+1, call.Name, call.Arguments)
+		calls = append(calls, map[string]any{
+			"id":        call.ID,
+			"name":      call.Name,
+			"arguments": call.Arguments,
+		})
+	}
+
+	name := "dry_run_plan"
+	description := "Tool calls the agent intended to make, none were executed."
+	lastChunk := true
+
+	task.AddArtifact(a2a.Artifact{
+		Name:        &name,
+		Description: &description,
+		Parts: []a2a.Part{
+			a2a.NewTextPart(strings.TrimSpace(sb.String())),
+			{Type: a2a.PartTypeData, Data: map[string]any{"toolCalls": calls}},
+		},
+		LastChunk: &lastChunk,
+	})
+}
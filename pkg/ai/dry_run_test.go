@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestAddDryRunPlan(t *testing.T) {
+	Convey("Given a TaskManager whose provider asks for a docker tool call", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentDryRun"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for dry-run testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+		}
+
+		var toolResponses []string
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response, 1)
+
+			_, response, err := provider.ExecuteAndProcessToolCall(
+				ctx, "docker", `{"command":"rm -rf /workspace"}`, "call-1", params,
+				func(toolCallID string, content string, isError bool) any { return content },
+			)
+			So(err, ShouldBeNil)
+			toolResponses = append(toolResponses, response.(string))
+
+			ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage("assistant", "done")},
+			}}
+			close(ch)
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(prov))
+		So(initErr, ShouldBeNil)
+
+		Convey("When the task is sent as a dry run", func() {
+			task, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-dry-run",
+				Message: *a2a.NewTextMessage("user", "clean up the workspace"),
+				DryRun:  true,
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then the docker handler should not have been invoked", func() {
+				So(toolResponses, ShouldResemble, []string{"(dry-run, not executed)"})
+
+				for _, artifact := range task.Artifacts {
+					So(*artifact.Name, ShouldNotEqual, "docker")
+				}
+			})
+
+			Convey("Then the planned call should be returned as the final artifact", func() {
+				So(len(task.Artifacts), ShouldEqual, 1)
+				plan := task.Artifacts[0]
+				So(*plan.Name, ShouldEqual, "dry_run_plan")
+
+				calls := plan.Parts[1].Data["toolCalls"].([]any)
+				So(len(calls), ShouldEqual, 1)
+				call := calls[0].(map[string]any)
+				So(call["name"], ShouldEqual, "docker")
+				So(call["arguments"], ShouldResemble, map[string]any{"command": "rm -rf /workspace"})
+			})
+		})
+	})
+}
@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+TaskEventKind names the point in a task's lifecycle a TaskEvent reports.
+*/
+type TaskEventKind string
+
+const (
+	TaskEventCreated  TaskEventKind = "created"
+	TaskEventStatus   TaskEventKind = "status"
+	TaskEventArtifact TaskEventKind = "artifact"
+	TaskEventTerminal TaskEventKind = "terminal"
+)
+
+/*
+TaskEvent is a single lifecycle event of a task, tagged with its session so
+events of every task in a session can be followed together.
+*/
+type TaskEvent struct {
+	Kind      TaskEventKind  `json:"kind"`
+	TaskID    string         `json:"taskId"`
+	SessionID string         `json:"sessionId,omitempty"`
+	State     a2a.TaskState  `json:"state"`
+	Message   *a2a.Message   `json:"message,omitempty"`
+	Artifact  *a2a.Artifact  `json:"artifact,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+}
+
+/*
+EventSink receives the lifecycle events of every task the manager runs. Sinks
+are called synchronously and in order, so they should hand events off rather
+than block.
+*/
+type EventSink func(event TaskEvent)
+
+/*
+WithEventSink registers a sink for the lifecycle events of every task.
+*/
+func WithEventSink(sink EventSink) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.AddEventSink(sink)
+	}
+}
+
+/*
+AddEventSink registers a sink after the manager was constructed, for
+consumers such as servers that are wired up around an existing manager.
+*/
+func (manager *TaskManager) AddEventSink(sink EventSink) {
+	if sink == nil {
+		return
+	}
+
+	manager.sinksMu.Lock()
+	defer manager.sinksMu.Unlock()
+
+	manager.sinks = append(manager.sinks, sink)
+}
+
+/*
+emit sends a lifecycle event for the task to every registered sink.
+*/
+func (manager *TaskManager) emit(task *a2a.Task, kind TaskEventKind, artifact *a2a.Artifact) {
+	manager.sinksMu.RLock()
+	sinks := manager.sinks
+	manager.sinksMu.RUnlock()
+
+	if len(sinks) == 0 {
+		return
+	}
+
+	event := TaskEvent{
+		Kind:      kind,
+		TaskID:    task.ID,
+		SessionID: task.SessionID,
+		State:     task.Status.State,
+		Message:   task.Status.Message,
+		Artifact:  artifact,
+		Timestamp: time.Now().UTC(),
+	}
+
+	for _, sink := range sinks {
+		sink(event)
+	}
+}
+
+/*
+emitArtifacts reports every artifact appended to the task since it held
+from artifacts.
+*/
+func (manager *TaskManager) emitArtifacts(task *a2a.Task, from int) {
+	for i := from; i < len(task.Artifacts); i++ {
+		artifact := task.Artifacts[i]
+		manager.emit(task, TaskEventArtifact, &artifact)
+	}
+}
@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestEventSink(t *testing.T) {
+	Convey("Given a TaskManager with an event sink following one session", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentEvents"}
+
+		var events []TaskEvent
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response, 2)
+			ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+				ID:       params.Task.ID,
+				Artifact: a2a.Artifact{Parts: []a2a.Part{a2a.NewTextPart("result of " + params.Task.ID)}},
+			}}
+			ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage("assistant", "done")},
+			}}
+			close(ch)
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(
+			card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov),
+			WithEventSink(func(event TaskEvent) {
+				if event.SessionID == "session-events" {
+					events = append(events, event)
+				}
+			}),
+		)
+		So(initErr, ShouldBeNil)
+
+		Convey("When two tasks are sent in the same session", func() {
+			for _, id := range []string{"task-events-1", "task-events-2"} {
+				_, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+					ID:        id,
+					SessionID: "session-events",
+					Message:   *a2a.NewTextMessage("user", "hello"),
+				})
+				So(err, ShouldBeNil)
+			}
+
+			_, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:        "task-events-other",
+				SessionID: "session-other",
+				Message:   *a2a.NewTextMessage("user", "hello"),
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then both tasks' events should arrive in order", func() {
+				type step struct {
+					task string
+					kind TaskEventKind
+				}
+
+				var got []step
+				for _, event := range events {
+					got = append(got, step{event.TaskID, event.Kind})
+				}
+
+				So(got, ShouldResemble, []step{
+					{"task-events-1", TaskEventCreated},
+					{"task-events-1", TaskEventStatus},
+					{"task-events-1", TaskEventArtifact},
+					{"task-events-1", TaskEventTerminal},
+					{"task-events-2", TaskEventCreated},
+					{"task-events-2", TaskEventStatus},
+					{"task-events-2", TaskEventArtifact},
+					{"task-events-2", TaskEventTerminal},
+				})
+			})
+
+			Convey("Then the events should carry the task's state", func() {
+				So(events[1].State, ShouldEqual, a2a.TaskStateWorking)
+				So(events[2].Artifact, ShouldNotBeNil)
+				So(events[3].State, ShouldEqual, a2a.TaskStateCompleted)
+			})
+		})
+	})
+}
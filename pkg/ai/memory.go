@@ -0,0 +1,83 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+/*
+MemoryFailureMode decides what happens to a task when the memory store fails
+while memories are injected or extracted.
+*/
+type MemoryFailureMode string
+
+const (
+	// MemoryDegrade logs the failure and carries on without memory.
+	MemoryDegrade MemoryFailureMode = "degrade"
+	// MemoryFail fails the task.
+	MemoryFail MemoryFailureMode = "fail"
+)
+
+/*
+MemoryDegradedKey is the task metadata key set when the task ran without
+memory because the memory store failed.
+*/
+const MemoryDegradedKey = "memory_degraded"
+
+/*
+WithMemoryFailureMode sets how memory store failures affect a task. The
+default is MemoryDegrade, so an unavailable Qdrant or Neo4j never takes the
+agent down with it.
+*/
+func WithMemoryFailureMode(mode MemoryFailureMode) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.memoryFailureMode = mode
+	}
+}
+
+/*
+injectMemories adds relevant memories to the task ahead of the provider
+call.
+*/
+func (manager *TaskManager) injectMemories(ctx context.Context, task *a2a.Task) *errors.RpcError {
+	if manager.memory == nil {
+		return nil
+	}
+
+	return manager.memoryFailure(task, "inject", manager.memory.InjectMemories(ctx, task))
+}
+
+/*
+extractMemories stores what the task produced once the provider is done.
+*/
+func (manager *TaskManager) extractMemories(ctx context.Context, task *a2a.Task) *errors.RpcError {
+	if manager.memory == nil {
+		return nil
+	}
+
+	return manager.memoryFailure(task, "extract", manager.memory.ExtractMemories(ctx, task))
+}
+
+/*
+memoryFailure applies the failure mode to the error of a memory step. In
+degrade mode the error is logged and recorded on the task, and nil is
+returned so the task proceeds without memory.
+*/
+func (manager *TaskManager) memoryFailure(task *a2a.Task, step string, err error) *errors.RpcError {
+	if err == nil {
+		return nil
+	}
+
+	if manager.memoryFailureMode == MemoryFail {
+		log.Error("memory store failed", "task_id", task.ID, "step", step, "error", err)
+		return errors.ErrInternal.WithMessagef("failed to %s memories: %v", step, err)
+	}
+
+	log.Warn("memory store failed, proceeding without memory", "task_id", task.ID, "step", step, "error", err)
+	task.MergeMetadata(map[string]any{MemoryDegradedKey: true})
+
+	return nil
+}
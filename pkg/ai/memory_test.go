@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/memory"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+var errStoreDown = errors.New("connection refused")
+
+/*
+unavailableVectorStore fails every call, like a Qdrant that is down.
+*/
+type unavailableVectorStore struct{}
+
+func (s *unavailableVectorStore) StoreMemory(ctx context.Context, mem memory.Memory) (string, error) {
+	return "", errStoreDown
+}
+
+func (s *unavailableVectorStore) StoreMemories(ctx context.Context, mems []memory.Memory) error {
+	return errStoreDown
+}
+
+func (s *unavailableVectorStore) UpdateMemory(ctx context.Context, mem memory.Memory) error {
+	return errStoreDown
+}
+
+func (s *unavailableVectorStore) GetMemory(ctx context.Context, id string) (memory.Memory, error) {
+	return memory.Memory{}, errStoreDown
+}
+
+func (s *unavailableVectorStore) SearchSimilar(ctx context.Context, embedding []float32, params memory.SearchParams) ([]memory.Memory, error) {
+	return nil, errStoreDown
+}
+
+func (s *unavailableVectorStore) DeleteMemory(ctx context.Context, id string) error {
+	return errStoreDown
+}
+
+func (s *unavailableVectorStore) DeleteMemories(ctx context.Context, ids []string) error {
+	return errStoreDown
+}
+
+func (s *unavailableVectorStore) DeleteByFilter(ctx context.Context, filters []memory.Filter) error {
+	return errStoreDown
+}
+
+func (s *unavailableVectorStore) Ping(ctx context.Context) error {
+	return errStoreDown
+}
+
+func TestMemoryFailureMode(t *testing.T) {
+	Convey("Given a TaskManager whose memory store is down", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentMemory"}
+		store := memory.NewUnifiedStore(memory.NewMockEmbeddingService(), &unavailableVectorStore{}, nil)
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response, 1)
+			ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage("assistant", "done")},
+			}}
+			close(ch)
+			return ch
+		}
+
+		send := func(options ...TaskManagerOption) (*a2a.Task, error) {
+			manager, initErr := NewTaskManager(card, append([]TaskManagerOption{
+				WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov), WithMemoryStore(store),
+			}, options...)...)
+			So(initErr, ShouldBeNil)
+
+			task, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-memory",
+				Message: *a2a.NewTextMessage("user", "hello"),
+			})
+
+			if err != nil {
+				return task, err
+			}
+			return task, nil
+		}
+
+		Convey("When the task runs in degrade mode", func() {
+			task, err := send(WithMemoryFailureMode(MemoryDegrade))
+
+			Convey("Then the task should complete without memory", func() {
+				So(err, ShouldBeNil)
+				So(task.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+				So(task.Metadata[MemoryDegradedKey], ShouldEqual, true)
+			})
+		})
+
+		Convey("When no mode is set", func() {
+			task, err := send()
+
+			Convey("Then it should degrade by default", func() {
+				So(err, ShouldBeNil)
+				So(task.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+			})
+		})
+
+		Convey("When the task runs in fail mode", func() {
+			task, err := send(WithMemoryFailureMode(MemoryFail))
+
+			Convey("Then the task should fail before the provider is called", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "connection refused")
+				So(task.Status.State, ShouldEqual, a2a.TaskStateFailed)
+				So(prov.lastGenerateParams, ShouldBeNil)
+			})
+		})
+	})
+}
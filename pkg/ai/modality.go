@@ -0,0 +1,318 @@
+package ai
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"sync"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+Modality is the kind of content an artifact carries, so clients know how to
+render it.
+*/
+type Modality string
+
+const (
+	ModalityText  Modality = "text"
+	ModalityImage Modality = "image"
+	ModalityAudio Modality = "audio"
+	ModalityVideo Modality = "video"
+	ModalityFile  Modality = "file"
+	ModalityData  Modality = "data"
+)
+
+/*
+ModalityKey is the artifact metadata key holding the artifact's modality.
+*/
+const ModalityKey = "modality"
+
+/*
+ErrStreamEnded is returned by an ArtifactProducer whose task stopped
+streaming before the artifact could be sent.
+*/
+var ErrStreamEnded = stderrors.New("task stream ended")
+
+/*
+artifactModality derives the modality of an artifact from its first part.
+*/
+func artifactModality(artifact a2a.Artifact) Modality {
+	if len(artifact.Parts) == 0 {
+		return ModalityText
+	}
+
+	part := artifact.Parts[0]
+
+	switch part.Type {
+	case a2a.PartTypeData:
+		return ModalityData
+	case a2a.PartTypeFile:
+		if part.File == nil || part.File.MimeType == nil {
+			return ModalityFile
+		}
+
+		switch mimeType := *part.File.MimeType; {
+		case strings.HasPrefix(mimeType, "image/"):
+			return ModalityImage
+		case strings.HasPrefix(mimeType, "audio/"):
+			return ModalityAudio
+		case strings.HasPrefix(mimeType, "video/"):
+			return ModalityVideo
+		}
+
+		return ModalityFile
+	}
+
+	return ModalityText
+}
+
+/*
+tagModality returns the chunk with its artifact, if it carries one, tagged
+with the artifact's modality, unless it was tagged already. The metadata is
+copied, as the artifact may share it with its sender.
+*/
+func tagModality(chunk jsonrpc.Response) (jsonrpc.Response, int, bool) {
+	tag := func(artifact *a2a.Artifact) {
+		if _, ok := artifact.Metadata[ModalityKey]; ok {
+			return
+		}
+
+		artifact.Metadata = a2a.MergeMetadata(
+			a2a.MergeMetadata(nil, artifact.Metadata),
+			map[string]any{ModalityKey: string(artifactModality(*artifact))},
+		)
+	}
+
+	switch result := chunk.Result.(type) {
+	case a2a.TaskArtifactUpdateEvent:
+		tag(&result.Artifact)
+		chunk.Result = result
+		return chunk, result.Artifact.Index, true
+	case a2a.ArtifactResult:
+		tag(&result.Artifact)
+		chunk.Result = result
+		return chunk, result.Artifact.Index, true
+	}
+
+	return chunk, 0, false
+}
+
+/*
+taskModalities merges the artifacts of the producers of a streamed task into
+the chunks of its provider, and keeps the stream open until every producer
+is done, so the final status always comes last.
+*/
+type taskModalities struct {
+	mu        sync.Mutex
+	chunks    chan jsonrpc.Response
+	producers int
+	maxIndex  int
+	sealed    bool
+	changed   chan struct{}
+	stopped   chan struct{}
+}
+
+func newTaskModalities() *taskModalities {
+	return &taskModalities{
+		chunks:  make(chan jsonrpc.Response),
+		changed: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+/*
+open registers a producer and hands it the next free artifact index, and
+returns false once the provider and every earlier producer are done.
+*/
+func (modalities *taskModalities) open() (int, bool) {
+	modalities.mu.Lock()
+	defer modalities.mu.Unlock()
+
+	if modalities.sealed {
+		return 0, false
+	}
+
+	modalities.producers++
+	modalities.maxIndex++
+
+	return modalities.maxIndex, true
+}
+
+func (modalities *taskModalities) close() {
+	modalities.mu.Lock()
+	defer modalities.mu.Unlock()
+
+	modalities.producers--
+	close(modalities.changed)
+	modalities.changed = make(chan struct{})
+}
+
+/*
+seal stops new producers from joining once none are left, and reports
+whether it did. Otherwise it returns a channel that is closed when a
+producer is done.
+*/
+func (modalities *taskModalities) seal() (bool, <-chan struct{}) {
+	modalities.mu.Lock()
+	defer modalities.mu.Unlock()
+
+	if modalities.producers == 0 {
+		modalities.sealed = true
+		return true, nil
+	}
+
+	return false, modalities.changed
+}
+
+/*
+stop turns away new producers and fails the sends of the ones left, once
+the stream ended.
+*/
+func (modalities *taskModalities) stop() {
+	modalities.mu.Lock()
+	defer modalities.mu.Unlock()
+
+	modalities.sealed = true
+	close(modalities.stopped)
+}
+
+func (modalities *taskModalities) sawIndex(index int) {
+	modalities.mu.Lock()
+	defer modalities.mu.Unlock()
+
+	modalities.maxIndex = max(modalities.maxIndex, index)
+}
+
+/*
+merge forwards the chunks of the provider together with the artifacts of
+the producers, every artifact tagged with its modality. The returned channel
+closes once the provider is done and no producer is left, or ctx is done.
+*/
+func (modalities *taskModalities) merge(ctx context.Context, provider chan jsonrpc.Response) chan jsonrpc.Response {
+	out := make(chan jsonrpc.Response)
+
+	go func() {
+		defer close(out)
+		defer modalities.stop()
+
+		var changed <-chan struct{}
+
+		for {
+			if provider == nil {
+				var sealed bool
+
+				if sealed, changed = modalities.seal(); sealed {
+					return
+				}
+			}
+
+			var chunk jsonrpc.Response
+
+			select {
+			case next, ok := <-provider:
+				if !ok {
+					provider = nil
+					continue
+				}
+
+				chunk = next
+			case chunk = <-modalities.chunks:
+			case <-changed:
+				continue
+			case <-ctx.Done():
+				return
+			}
+
+			chunk, index, isArtifact := tagModality(chunk)
+
+			if isArtifact {
+				modalities.sawIndex(index)
+			}
+
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+/*
+ArtifactProducer sends artifacts into a streamed task alongside its provider,
+such as an image a tool generates while the model is still writing. Its
+artifacts get an index of their own, so clients can tell them from the text
+stream, and the task does not finish before the producer is closed.
+*/
+type ArtifactProducer struct {
+	taskID     string
+	index      int
+	sent       bool
+	closeOnce  sync.Once
+	modalities *taskModalities
+}
+
+/*
+ProduceArtifacts adds a producer to a task started with StreamTask. Every
+producer must be closed, or the task only finishes once its context is done.
+
+Returns:
+- An ArtifactProducer for the task.
+- *errors.RpcError if the task is not streaming, or finished already.
+*/
+func (manager *TaskManager) ProduceArtifacts(taskID string) (*ArtifactProducer, *errors.RpcError) {
+	manager.streamsMu.Lock()
+	stream, ok := manager.streams[taskID]
+	manager.streamsMu.Unlock()
+
+	if !ok {
+		return nil, errors.ErrTaskNotFound
+	}
+
+	index, ok := stream.modalities.open()
+
+	if !ok {
+		return nil, errors.ErrTaskNotFound.WithMessagef("task %s finished streaming", taskID)
+	}
+
+	return &ArtifactProducer{taskID: taskID, index: index, modalities: stream.modalities}, nil
+}
+
+/*
+Send streams an artifact, or the next chunk of it, into the task. Chunks
+after the first are marked to be appended to it.
+*/
+func (producer *ArtifactProducer) Send(ctx context.Context, artifact a2a.Artifact) error {
+	appendChunk := producer.sent
+	artifact.Index = producer.index
+	artifact.Append = &appendChunk
+
+	chunk := jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+		ID:       producer.taskID,
+		Artifact: artifact,
+	}}
+
+	select {
+	case producer.modalities.chunks <- chunk:
+		producer.sent = true
+		return nil
+	case <-producer.modalities.stopped:
+		return ErrStreamEnded
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+Close tells the task the producer is done, so it can finish once its
+provider and every other producer are too.
+*/
+func (producer *ArtifactProducer) Close() {
+	producer.closeOnce.Do(producer.modalities.close)
+}
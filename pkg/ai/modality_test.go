@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestStreamModalities(t *testing.T) {
+	Convey("Given a provider streaming text while an image is produced separately", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentModalities"}
+		started := make(chan struct{})
+		textDone := make(chan struct{})
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response)
+
+			go func() {
+				defer close(textDone)
+				defer close(ch)
+
+				<-started
+
+				for _, text := range []string{"Here ", "is ", "your image."} {
+					ch <- a2a.NewArtifactResult(params.Task.ID, a2a.NewTextPart(text))
+				}
+
+				ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+					ID:     params.Task.ID,
+					Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+					Final:  true,
+				}}
+			}()
+
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov))
+		So(initErr, ShouldBeNil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		task := a2a.NewTask(card.Name)
+		task.History = append(task.History, *a2a.NewTextMessage("user", "draw a cat"))
+
+		Convey("When the image only arrives after the text stream ended", func() {
+			out, err := manager.StreamTask(ctx, task)
+			So(err, ShouldBeNil)
+
+			producer, err := manager.ProduceArtifacts(task.ID)
+			So(err, ShouldBeNil)
+			close(started)
+
+			go func() {
+				defer producer.Close()
+
+				<-textDone
+				time.Sleep(50 * time.Millisecond)
+				producer.Send(ctx, a2a.NewFileArtifact("cat.png", "image/png", "iVBORw0KGgo="))
+			}()
+
+			var events []jsonrpc.Response
+			for event := range out {
+				events = append(events, event)
+			}
+
+			Convey("Then both modalities should arrive before the terminal event", func() {
+				So(len(events), ShouldBeGreaterThan, 4)
+
+				last, isStatus := events[len(events)-1].Result.(a2a.TaskStatusUpdateResult)
+				So(isStatus, ShouldBeTrue)
+				So(last.Final, ShouldBeTrue)
+				So(last.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+
+				var text []a2a.Artifact
+				var images []a2a.Artifact
+
+				for _, event := range events[:len(events)-1] {
+					switch result := event.Result.(type) {
+					case a2a.ArtifactResult:
+						text = append(text, result.Artifact)
+					case a2a.TaskArtifactUpdateEvent:
+						images = append(images, result.Artifact)
+					case a2a.TaskStatusUpdateResult:
+						So(result.Final, ShouldBeFalse)
+					}
+				}
+
+				So(text, ShouldHaveLength, 3)
+				So(images, ShouldHaveLength, 1)
+
+				So(text[0].Metadata[ModalityKey], ShouldEqual, "text")
+				So(text[0].Index, ShouldEqual, 0)
+				So(images[0].Metadata[ModalityKey], ShouldEqual, "image")
+				So(images[0].Index, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the task finished streaming", func() {
+			out, err := manager.StreamTask(ctx, task)
+			So(err, ShouldBeNil)
+			close(started)
+
+			for range out {
+			}
+
+			Convey("Then no producer should be able to join it", func() {
+				_, err := manager.ProduceArtifacts(task.ID)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
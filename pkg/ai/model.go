@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+WithModelAllowlist restricts the models a request may ask for. Without an
+allowlist, a provider that can list the models it serves is asked instead,
+and any model is passed through when it cannot.
+*/
+func WithModelAllowlist(models ...string) TaskManagerOption {
+	return func(manager *TaskManager) {
+		manager.models = models
+	}
+}
+
+/*
+requestedModel returns the model a request asked for, if any. StreamTask
+receives a task rather than TaskSendParams, so there the model travels in
+the task's metadata.
+*/
+func requestedModel(params *a2a.TaskSendParams, task *a2a.Task) string {
+	if params != nil && params.Model != "" {
+		return params.Model
+	}
+
+	model, _ := task.Metadata["model"].(string)
+	return model
+}
+
+/*
+checkModel verifies that the provider can serve the requested model, so a
+request for an unknown model fails with a clear InvalidParams error instead
+of an opaque upstream one.
+*/
+func (manager *TaskManager) checkModel(ctx context.Context, model string) *errors.RpcError {
+	if model == "" {
+		return nil
+	}
+
+	available := manager.models
+	prvdr := manager.providerFor(model)
+
+	if len(available) == 0 {
+		lister, ok := prvdr.(provider.ModelLister)
+
+		if !ok {
+			return nil
+		}
+
+		models, err := lister.Models(ctx)
+
+		if err != nil {
+			log.Warn("failed to list provider models, skipping model check", "model", model, "error", err)
+			return nil
+		}
+
+		available = models
+	}
+
+	if slices.Contains(available, model) {
+		return nil
+	}
+
+	return errors.ErrInvalidParams.WithMessagef(
+		"model %s not available on provider %s; available: %s",
+		model, providerName(prvdr), strings.Join(available, ", "),
+	)
+}
+
+/*
+providerName derives a readable name from the provider's type, so
+*provider.OpenAIProvider becomes OpenAI.
+*/
+func providerName(prvdr provider.Interface) string {
+	name := fmt.Sprintf("%T", prvdr)
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.TrimSuffix(name, "Provider")
+}
+
+/*
+WithModelRouter chooses the provider of every task by the model it asks
+for, for agents served by more than one provider. Tasks that do not ask for
+a model go to the provider set with WithProvider, or to the router's
+fallback when none is set. A model no route matches goes to the router's
+fallback, or to the provider set with WithProvider when the fallback is not
+registered with the router.
+*/
+func WithModelRouter(router *provider.ModelRouter) TaskManagerOption {
+	return func(manager *TaskManager) {
+		manager.router = router
+	}
+}
+
+/*
+providerFor returns the provider serving the requested model.
+*/
+func (manager *TaskManager) providerFor(model string) provider.Interface {
+	if manager.router == nil || model == "" {
+		return manager.provider
+	}
+
+	prvdr, name := manager.router.Route(model)
+
+	if prvdr == nil {
+		return manager.provider
+	}
+
+	log.Debug("routed model to provider", "model", model, "provider", name)
+	return prvdr
+}
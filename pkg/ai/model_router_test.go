@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestModelRouter(t *testing.T) {
+	Convey("Given a TaskManager routing models over a registry of fake providers", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentModelRouter"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for routing testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+		}
+
+		var served []string
+
+		fake := func(name string) *controllableMockProvider {
+			prov := NewControllableMockProvider()
+			prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+				served = append(served, name+":"+params.Model)
+
+				ch := make(chan jsonrpc.Response, 1)
+				ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+					Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage("assistant", "answered by "+name)},
+				}}
+				close(ch)
+				return ch
+			}
+			return prov
+		}
+
+		router := provider.NewModelRouter(map[string]provider.Interface{
+			"openai": fake("openai"),
+			"google": fake("google"),
+			"ollama": fake("ollama"),
+		})
+
+		manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithModelRouter(router))
+		So(initErr, ShouldBeNil)
+
+		send := func(model string) {
+			_, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-" + model,
+				Message: *a2a.NewTextMessage("user", "hello"),
+				Model:   model,
+			})
+			So(err, ShouldBeNil)
+		}
+
+		Convey("When tasks ask for gemini-1.5-flash and gpt-4o", func() {
+			send("gemini-1.5-flash")
+			send("gpt-4o")
+
+			Convey("Then each should be served by the provider of its model", func() {
+				So(served, ShouldResemble, []string{"google:gemini-1.5-flash", "openai:gpt-4o"})
+			})
+		})
+
+		Convey("When a task does not ask for a model", func() {
+			send("")
+
+			Convey("Then it should go to the fallback provider", func() {
+				So(served, ShouldHaveLength, 1)
+				So(served[0], ShouldStartWith, "openai:")
+			})
+		})
+	})
+}
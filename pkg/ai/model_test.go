@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+/*
+listingMockProvider is a controllable mock that also reports the models it
+serves.
+*/
+type listingMockProvider struct {
+	*controllableMockProvider
+	models []string
+}
+
+func (m *listingMockProvider) Models(ctx context.Context) ([]string, error) {
+	return m.models, nil
+}
+
+func TestModelSelection(t *testing.T) {
+	Convey("Given a TaskManager with a model allowlist", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentModels"}
+		prov := NewControllableMockProvider()
+
+		manager, initErr := NewTaskManager(
+			card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov),
+			WithModelAllowlist("gpt-4o-mini", "gpt-4o"),
+		)
+		So(initErr, ShouldBeNil)
+
+		send := func(model string) (*a2a.Task, *errors.RpcError) {
+			return manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-models",
+				Message: *a2a.NewTextMessage("user", "hello"),
+				Model:   model,
+			})
+		}
+
+		Convey("When a model outside the allowlist is requested", func() {
+			_, err := send("gemini-2.0-flash")
+
+			Convey("Then a descriptive InvalidParams error should be returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Code, ShouldEqual, errors.ErrInvalidParams.Code)
+				So(err.Message, ShouldEqual,
+					"model gemini-2.0-flash not available on provider controllableMock; available: gpt-4o-mini, gpt-4o",
+				)
+			})
+
+			Convey("Then the provider should not be called", func() {
+				So(prov.lastGenerateParams, ShouldBeNil)
+			})
+		})
+
+		Convey("When an allowed model is requested", func() {
+			_, err := send("gpt-4o")
+
+			Convey("Then the model should be passed through to the provider", func() {
+				So(err, ShouldBeNil)
+				So(prov.lastGenerateParams, ShouldNotBeNil)
+				So(prov.lastGenerateParams.Model, ShouldEqual, "gpt-4o")
+			})
+		})
+
+		Convey("When no model is requested", func() {
+			_, err := send("")
+
+			Convey("Then the provider's default model should be used", func() {
+				So(err, ShouldBeNil)
+				So(prov.lastGenerateParams.Model, ShouldEqual, "gpt-4o-mini")
+			})
+		})
+	})
+
+	Convey("Given a provider that lists the models it serves", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentModels"}
+		prov := &listingMockProvider{
+			controllableMockProvider: NewControllableMockProvider(),
+			models:                   []string{"llama3"},
+		}
+
+		manager, initErr := NewTaskManager(
+			card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov),
+		)
+		So(initErr, ShouldBeNil)
+
+		Convey("When a model the provider does not serve is requested", func() {
+			_, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-models",
+				Message: *a2a.NewTextMessage("user", "hello"),
+				Model:   "gpt-4o",
+			})
+
+			Convey("Then the provider's model list should be used for the check", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Message, ShouldContainSubstring, "model gpt-4o not available on provider listingMock; available: llama3")
+			})
+		})
+	})
+}
@@ -0,0 +1,220 @@
+package ai
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+OutputParser extracts the final answer from a model's raw output, returning
+any extra information it stripped away as metadata.
+*/
+type OutputParser interface {
+	Parse(raw string) (answer string, meta map[string]any)
+}
+
+/*
+ThinkTagParser strips <think>...</think> reasoning blocks, keeping the
+reasoning in the metadata.
+*/
+type ThinkTagParser struct{}
+
+var thinkTagPattern = regexp.MustCompile(`(?s)<think>(.*?)</think>`)
+
+/*
+NewThinkTagParser creates a parser that strips <think> blocks.
+*/
+func NewThinkTagParser() *ThinkTagParser {
+	return &ThinkTagParser{}
+}
+
+/*
+Parse removes every <think> block and returns the remaining text.
+*/
+func (parser *ThinkTagParser) Parse(raw string) (string, map[string]any) {
+	matches := thinkTagPattern.FindAllStringSubmatch(raw, -1)
+
+	if len(matches) == 0 {
+		return strings.TrimSpace(raw), nil
+	}
+
+	reasoning := make([]string, 0, len(matches))
+
+	for _, match := range matches {
+		reasoning = append(reasoning, strings.TrimSpace(match[1]))
+	}
+
+	return strings.TrimSpace(thinkTagPattern.ReplaceAllString(raw, "")), map[string]any{
+		"reasoning": strings.Join(reasoning, "\n"),
+	}
+}
+
+/*
+FencedBlockParser extracts the contents of the last fenced code block.
+*/
+type FencedBlockParser struct{}
+
+var fencedBlockPattern = regexp.MustCompile("(?s)```([A-Za-z0-9_+-]*)[^\\n]*\\n(.*?)```")
+
+/*
+NewFencedBlockParser creates a parser that extracts the last fenced block.
+*/
+func NewFencedBlockParser() *FencedBlockParser {
+	return &FencedBlockParser{}
+}
+
+/*
+Parse returns the body of the last fenced block, or the trimmed input when
+there is none. The block's language, if any, is returned as metadata.
+*/
+func (parser *FencedBlockParser) Parse(raw string) (string, map[string]any) {
+	matches := fencedBlockPattern.FindAllStringSubmatch(raw, -1)
+
+	if len(matches) == 0 {
+		return strings.TrimSpace(raw), nil
+	}
+
+	last := matches[len(matches)-1]
+	meta := map[string]any{}
+
+	if last[1] != "" {
+		meta["language"] = last[1]
+	}
+
+	return strings.TrimRight(last[2], "\n"), meta
+}
+
+/*
+DelimiterParser takes the text after the last occurrence of a delimiter,
+such as "Final Answer:".
+*/
+type DelimiterParser struct {
+	delimiter string
+}
+
+/*
+NewDelimiterParser creates a parser splitting on the given delimiter.
+*/
+func NewDelimiterParser(delimiter string) *DelimiterParser {
+	return &DelimiterParser{delimiter: delimiter}
+}
+
+/*
+Parse returns the text after the last delimiter, or the trimmed input when
+the delimiter does not occur. The text before it is returned as reasoning.
+*/
+func (parser *DelimiterParser) Parse(raw string) (string, map[string]any) {
+	idx := strings.LastIndex(raw, parser.delimiter)
+
+	if parser.delimiter == "" || idx == -1 {
+		return strings.TrimSpace(raw), nil
+	}
+
+	return strings.TrimSpace(raw[idx+len(parser.delimiter):]), map[string]any{
+		"reasoning": strings.TrimSpace(raw[:idx]),
+	}
+}
+
+/*
+answerArtifact names the artifact holding the answer the parser extracted.
+*/
+const answerArtifact = "answer"
+
+/*
+parseOutput runs the configured parser over the final text of a task that
+completes with message, and returns the message it completes with instead:
+the parsed answer. The answer is also added as the "answer" artifact, with
+the parser's metadata. The raw output is not copied, since it already
+reached the client as the text the model streamed.
+*/
+func (manager *TaskManager) parseOutput(task *a2a.Task, message *a2a.Message) *a2a.Message {
+	if manager.outputParser == nil {
+		return message
+	}
+
+	raw := finalText(task, message)
+
+	if raw == "" {
+		return message
+	}
+
+	answer, meta := manager.outputParser.Parse(raw)
+
+	name := answerArtifact
+	description := "Final answer extracted from the model output."
+	lastChunk := true
+
+	// As the final result, the answer is kept by the artifact limit.
+	task.AddArtifact(a2a.Artifact{
+		Name:        &name,
+		Description: &description,
+		Parts:       []a2a.Part{a2a.NewTextPart(answer)},
+		Metadata:    meta,
+		LastChunk:   &lastChunk,
+	})
+
+	return a2a.NewTextMessage("assistant", answer)
+}
+
+/*
+applyOutputParser parses the output of a task the provider completed
+itself, without a final status passing through handleUpdate.
+*/
+func (manager *TaskManager) applyOutputParser(task *a2a.Task) {
+	if task.Status.State != a2a.TaskStateCompleted || parsedAnswer(task) != nil {
+		return
+	}
+
+	artifacts := len(task.Artifacts)
+	task.Status.Message = manager.parseOutput(task, task.Status.Message)
+	manager.emitArtifacts(task, artifacts)
+}
+
+/*
+parsedAnswer returns the answer artifact of a completed task whose status
+message the parser produced, or nil when the task did not complete with a
+parsed answer.
+*/
+func parsedAnswer(task *a2a.Task) *a2a.Artifact {
+	if task.Status.State != a2a.TaskStateCompleted || task.Status.Message == nil {
+		return nil
+	}
+
+	for i := len(task.Artifacts) - 1; i >= 0; i-- {
+		artifact := &task.Artifacts[i]
+
+		if artifact.Name == nil || *artifact.Name != answerArtifact {
+			continue
+		}
+
+		if len(artifact.Parts) == 1 && artifact.Parts[0].Text == task.Status.Message.String() {
+			return artifact
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+/*
+finalText returns the text of the task's final answer, preferring the
+status message and falling back to the last assistant message.
+*/
+func finalText(task *a2a.Task, message *a2a.Message) string {
+	if message != nil {
+		if text := message.String(); text != "" {
+			return text
+		}
+	}
+
+	for i := len(task.History) - 1; i >= 0; i-- {
+		if task.History[i].Role == "assistant" {
+			return task.History[i].String()
+		}
+	}
+
+	return ""
+}
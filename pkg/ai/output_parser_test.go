@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+func TestThinkTagParser(t *testing.T) {
+	Convey("Given a think tag parser", t, func() {
+		parser := NewThinkTagParser()
+
+		Convey("When the output contains reasoning in think tags", func() {
+			answer, meta := parser.Parse("<think>\nThe user wants a sum.\n2 + 2 = 4\n</think>\n\nThe answer is 4.")
+
+			Convey("Then the reasoning should be stripped and kept as metadata", func() {
+				So(answer, ShouldEqual, "The answer is 4.")
+				So(meta["reasoning"], ShouldEqual, "The user wants a sum.\n2 + 2 = 4")
+			})
+		})
+
+		Convey("When the output contains no think tags", func() {
+			answer, meta := parser.Parse("  Just the answer.  ")
+
+			Convey("Then the trimmed output should be returned", func() {
+				So(answer, ShouldEqual, "Just the answer.")
+				So(meta, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestFencedBlockParser(t *testing.T) {
+	Convey("Given a fenced block parser", t, func() {
+		parser := NewFencedBlockParser()
+
+		Convey("When the output contains several fenced blocks", func() {
+			raw := "First attempt:\n```python\nprint('no')\n```\nFixed version:\n```go\nfmt.Println(\"yes\")\n```\nDone."
+			answer, meta := parser.Parse(raw)
+
+			Convey("Then the last block should be extracted with its language", func() {
+				So(answer, ShouldEqual, "fmt.Println(\"yes\")")
+				So(meta["language"], ShouldEqual, "go")
+			})
+		})
+
+		Convey("When the output contains no fenced block", func() {
+			answer, _ := parser.Parse("plain text\n")
+
+			Convey("Then the trimmed output should be returned", func() {
+				So(answer, ShouldEqual, "plain text")
+			})
+		})
+	})
+}
+
+func TestDelimiterParser(t *testing.T) {
+	Convey("Given a delimiter parser", t, func() {
+		parser := NewDelimiterParser("Final Answer:")
+
+		Convey("When the output contains the delimiter", func() {
+			answer, meta := parser.Parse("Thought: I should look it up.\nFinal Answer: Paris")
+
+			Convey("Then the text after the delimiter should be returned", func() {
+				So(answer, ShouldEqual, "Paris")
+				So(meta["reasoning"], ShouldEqual, "Thought: I should look it up.")
+			})
+		})
+
+		Convey("When the output does not contain the delimiter", func() {
+			answer, meta := parser.Parse("Paris")
+
+			Convey("Then the output should be returned unchanged", func() {
+				So(answer, ShouldEqual, "Paris")
+				So(meta, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestApplyOutputParser(t *testing.T) {
+	Convey("Given a TaskManager with an output parser and a completed task", t, func() {
+		manager := &TaskManager{outputParser: NewDelimiterParser("Final Answer:")}
+		task := &a2a.Task{ID: "task-output-parser"}
+		task.ToStatus(a2a.TaskStateCompleted, a2a.NewTextMessage("assistant", "Thinking...\nFinal Answer: 42"))
+
+		Convey("When the parser is applied", func() {
+			manager.applyOutputParser(task)
+
+			Convey("Then the answer should replace the status message without copying the raw output", func() {
+				So(len(task.Artifacts), ShouldEqual, 1)
+				So(*task.Artifacts[0].Name, ShouldEqual, "answer")
+				So(task.Artifacts[0].Parts[0].Text, ShouldEqual, "42")
+				So(task.Status.Message.String(), ShouldEqual, "42")
+			})
+
+			Convey("Then applying it again should not parse the answer twice", func() {
+				manager.applyOutputParser(task)
+				So(len(task.Artifacts), ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given a TaskManager with an output parser and an event sink", t, func() {
+		var events []TaskEvent
+
+		manager := &TaskManager{outputParser: NewDelimiterParser("Final Answer:")}
+		manager.AddEventSink(func(event TaskEvent) { events = append(events, event) })
+
+		task := &a2a.Task{ID: "task-output-parser-sink"}
+
+		Convey("When the provider's final status completes the task", func() {
+			So(manager.handleUpdate(task, jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status: a2a.TaskStatus{
+					State:   a2a.TaskStateCompleted,
+					Message: a2a.NewTextMessage("assistant", "Thinking...\nFinal Answer: 42"),
+				},
+				Final: true,
+			}}), ShouldBeNil)
+
+			Convey("Then the answer artifact should be emitted before the terminal status", func() {
+				So(events, ShouldHaveLength, 2)
+				So(events[0].Kind, ShouldEqual, TaskEventArtifact)
+				So(*events[0].Artifact.Name, ShouldEqual, "answer")
+				So(events[1].Kind, ShouldEqual, TaskEventTerminal)
+				So(events[1].Message.String(), ShouldEqual, "42")
+			})
+		})
+	})
+}
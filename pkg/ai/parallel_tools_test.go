@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestParallelToolCalls(t *testing.T) {
+	Convey("Given an agent backed by an OpenAI compatible server", t, func() {
+		var requests []map[string]any
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]any{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, body)
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, `data: {"id":"chunk","object":"chat.completion.chunk","created":1,"model":"gpt-4o-mini","choices":[{"index":0,"delta":{"content":"Done."},"finish_reason":"stop"}]}`+"\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		agentCard := &a2a.AgentCard{Name: "TestAgentParallelToolCalls"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		parallelKey := fmt.Sprintf("agent.%s.parallelToolCalls", agentCard.Name)
+		vip.Set(systemMsgKey, "Default system message for parallel tool call testing")
+		defer vip.Set(systemMsgKey, "")
+
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+		}
+
+		run := func(options ...TaskManagerOption) any {
+			prvdr := provider.NewOpenAIProvider(
+				provider.WithOpenAIAPIKey("test"),
+				provider.WithOpenAIBaseURL(ts.URL),
+				provider.WithOpenAIClient(),
+			)
+
+			manager, err := NewTaskManager(agentCard, append(options, WithTaskStore(store), WithProvider(prvdr))...)
+			So(err, ShouldBeNil)
+
+			_, rpcErr := manager.CompleteStreaming(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-parallel-tool-calls",
+				Message: *a2a.NewTextMessage("user", "Look up the weather in Paris and Rome."),
+			})
+			So(rpcErr, ShouldBeNil)
+			So(requests, ShouldNotBeEmpty)
+
+			return requests[len(requests)-1]["parallel_tool_calls"]
+		}
+
+		Convey("When nothing is configured", func() {
+			Convey("Then parallel tool calls should be allowed", func() {
+				So(run(), ShouldEqual, true)
+			})
+		})
+
+		Convey("When the agent config disables parallel tool calls", func() {
+			vip.Set(parallelKey, false)
+			defer vip.Set(parallelKey, nil)
+
+			Convey("Then the OpenAI request should disable them", func() {
+				So(run(), ShouldEqual, false)
+			})
+
+			Convey("Then the task manager option should take precedence", func() {
+				So(run(WithParallelToolCalls(true)), ShouldEqual, true)
+			})
+		})
+	})
+}
@@ -0,0 +1,460 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+defaultMaxReplans bounds how often a plan is redone after a failed step when
+WithPlanning is given no limit.
+*/
+const defaultMaxReplans = 2
+
+// maxPlanSteps bounds the steps of a plan, so a runaway planner cannot keep a
+// task busy for good.
+const maxPlanSteps = 10
+
+const defaultPlannerPrompt = `Break the user's request down into the steps you will take to fulfil it.
+Answer with JSON only, in the form {"steps": [{"title": "...", "instruction": "..."}]}.
+The title is a few words, the instruction tells you what to do in that step.
+Use as few steps as the request needs, and no more than 10.`
+
+/*
+PlanStatus is how far a step of a plan got.
+*/
+type PlanStatus string
+
+const (
+	PlanStepPending PlanStatus = "pending"
+	PlanStepWorking PlanStatus = "working"
+	PlanStepDone    PlanStatus = "done"
+	PlanStepFailed  PlanStatus = "failed"
+)
+
+/*
+PlanStep is a single step of the plan a task commits to.
+*/
+type PlanStep struct {
+	Title       string     `json:"title"`
+	Instruction string     `json:"instruction"`
+	Status      PlanStatus `json:"status,omitempty"`
+}
+
+/*
+WithPlanning makes every task sent with SendTask or CompleteStreaming start
+with a plan. The provider is asked to break the request down into steps,
+which are stored on the task as the "plan" artifact and then run one after
+the other, each as a turn of the task. When a step fails, the remaining
+steps are planned again, at most maxReplans times, or twice when no limit
+is given. Streamed tasks run without a plan.
+*/
+func WithPlanning(maxReplans ...int) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.planning = true
+		t.maxReplans = defaultMaxReplans
+
+		if len(maxReplans) > 0 && maxReplans[0] >= 0 {
+			t.maxReplans = maxReplans[0]
+		}
+	}
+}
+
+/*
+WithPlannerPrompt sets the system prompt the plan is asked for with,
+overriding the a2a.planner_prompt config value. The planner must answer
+with the steps as JSON, in the form of the default prompt.
+*/
+func WithPlannerPrompt(prompt string) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.plannerPrompt = &prompt
+	}
+}
+
+/*
+plannerSystemPrompt returns the prompt set with WithPlannerPrompt, or the
+a2a.planner_prompt config value, or the default prompt when neither is set.
+*/
+func (manager *TaskManager) plannerSystemPrompt() string {
+	if manager.plannerPrompt != nil {
+		return *manager.plannerPrompt
+	}
+
+	if prompt := viper.GetViper().GetString("a2a.planner_prompt"); prompt != "" {
+		return prompt
+	}
+
+	return defaultPlannerPrompt
+}
+
+/*
+completePlanned plans the request of a task and runs the plan. A request the
+planner cannot break down runs as it would without planning.
+*/
+func (manager *TaskManager) completePlanned(
+	ctx context.Context, params a2a.TaskSendParams, stream bool,
+) (*a2a.Task, *errors.RpcError) {
+	request := params.Message.String()
+
+	steps, err := manager.plan(ctx, params, request)
+
+	if err != nil {
+		log.Warn("failed to plan task, running it without a plan", "task_id", params.ID, "error", err)
+		return manager.complete(ctx, params, stream)
+	}
+
+	selected, rpcErr := manager.selectTask(ctx, params)
+
+	if rpcErr != nil {
+		log.Error("failed to select task", "error", rpcErr)
+		return nil, rpcErr
+	}
+
+	task := &selected
+	replans := 0
+	manager.setPlan(task, steps)
+
+	for i := 0; i < len(steps); i++ {
+		steps[i].Status = PlanStepWorking
+		manager.setPlan(task, steps)
+
+		progress := a2a.NewTextMessage(
+			manager.agent.Name,
+			fmt.Sprintf("step %d of %d: %s", i+1, len(steps), steps[i].Title),
+		)
+		progress.Metadata = map[string]any{
+			"planStep":  i + 1,
+			"planSteps": len(steps),
+			"planTitle": steps[i].Title,
+		}
+
+		manager.toStatus(task, a2a.TaskStateWorking, progress)
+
+		// The step runs on the task as it is stored, so the plan and the
+		// progress have to be stored first.
+		if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+			log.Error("failed to persist plan progress", "task_id", task.ID, "error", updErr)
+			return task, updErr
+		}
+
+		stepParams := params
+		stepParams.Message = *a2a.NewTextMessage("user", fmt.Sprintf(
+			"Step %d of %d of the plan: %s\n\n%s", i+1, len(steps), steps[i].Title, steps[i].Instruction,
+		))
+
+		next, rpcErr := manager.complete(ctx, stepParams, stream)
+
+		if next != nil {
+			task = next
+		}
+
+		switch {
+		case rpcErr == nil && task.Status.State != a2a.TaskStateFailed:
+			if task.Status.State != a2a.TaskStateCompleted && isStoppedState(task.Status.State) {
+				// Canceled, or waiting for input, which ends the plan here.
+				steps[i].Status = PlanStepPending
+				manager.setPlan(task, steps)
+				return task, manager.persistPlan(ctx, task)
+			}
+
+			steps[i].Status = PlanStepDone
+			manager.setPlan(task, steps)
+
+			if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+				log.Error("failed to persist plan progress", "task_id", task.ID, "error", updErr)
+				return task, updErr
+			}
+
+			continue
+		}
+
+		steps[i].Status = PlanStepFailed
+		reason := stepFailure(task, rpcErr)
+
+		if replans >= manager.maxReplans {
+			manager.setPlan(task, steps)
+			manager.toStatus(task, a2a.TaskStateFailed, a2a.NewTextMessage(
+				manager.agent.Name,
+				fmt.Sprintf("step %d of %d failed: %s", i+1, len(steps), reason),
+			))
+
+			if updErr := manager.persistPlan(ctx, task); updErr != nil {
+				return task, updErr
+			}
+
+			return task, rpcErr
+		}
+
+		replans++
+		log.Info("plan step failed, replanning", "task_id", task.ID, "step", i+1, "replans", replans, "reason", reason)
+
+		remaining, err := manager.plan(ctx, params, replanRequest(request, steps[:i+1], reason))
+
+		if err != nil {
+			log.Warn("failed to replan task", "task_id", task.ID, "error", err)
+			manager.setPlan(task, steps)
+			manager.toStatus(task, a2a.TaskStateFailed, a2a.NewTextMessage(
+				manager.agent.Name,
+				fmt.Sprintf("step %d of %d failed and could not be replanned: %s", i+1, len(steps), reason),
+			))
+
+			return task, manager.persistPlan(ctx, task)
+		}
+
+		steps = append(steps[:i+1], remaining...)
+	}
+
+	return task, manager.persistPlan(ctx, task)
+}
+
+/*
+isStoppedState reports whether a task stopped for a reason other than its
+step failing, which ends the plan.
+*/
+func isStoppedState(state a2a.TaskState) bool {
+	switch state {
+	case a2a.TaskStateCanceled, a2a.TaskStateInputReq:
+		return true
+	}
+
+	return false
+}
+
+func (manager *TaskManager) persistPlan(ctx context.Context, task *a2a.Task) *errors.RpcError {
+	if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+		log.Error("failed to persist planned task", "task_id", task.ID, "error", updErr)
+		return updErr
+	}
+
+	return nil
+}
+
+/*
+stepFailure describes why a step failed, for the status of the task and the
+planner.
+*/
+func stepFailure(task *a2a.Task, err *errors.RpcError) string {
+	if err != nil {
+		return err.Message
+	}
+
+	if task.Status.Message != nil {
+		if text := task.Status.Message.String(); text != "" {
+			return text
+		}
+	}
+
+	return "the step failed without a reason"
+}
+
+/*
+replanRequest asks the planner for the steps that remain after a step
+failed, telling it what was done so far.
+*/
+func replanRequest(request string, steps []PlanStep, reason string) string {
+	var sb strings.Builder
+
+	sb.WriteString(request)
+	sb.WriteString("\n\nThis request is being worked on with a plan, whose steps went as follows:\n")
+
+	for i, step := range steps {
+		fmt.Fprintf(&sb, "%d. [%s] %s: %s\n", i+1, step.Status, step.Title, step.Instruction)
+	}
+
+	fmt.Fprintf(&sb, "\nThe last step failed: %s\nPlan the steps that remain to fulfil the request, without the ones that are done.", reason)
+
+	return sb.String()
+}
+
+/*
+plan asks the provider to break the request down into steps. The planner
+sees neither the history nor the tools of the task, only the request and
+the names of the tools it may plan to use.
+*/
+func (manager *TaskManager) plan(
+	ctx context.Context, params a2a.TaskSendParams, request string,
+) ([]PlanStep, error) {
+	prompt := manager.plannerSystemPrompt()
+
+	if tools := manager.tools(); len(tools) > 0 {
+		names := make([]string, 0, len(tools))
+
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+
+		prompt += "\n\nTools available to carry out the steps: " + strings.Join(names, ", ")
+	}
+
+	planTask := &a2a.Task{
+		ID:        params.ID,
+		SessionID: params.SessionID,
+		History: []a2a.Message{
+			*a2a.NewTextMessage("system", prompt),
+			*a2a.NewTextMessage("user", request),
+		},
+		Metadata: map[string]any{},
+	}
+
+	prvdrParams := provider.NewProviderParams(planTask, provider.WithStream(false))
+
+	if params.Model != "" {
+		prvdrParams.Model = params.Model
+	}
+
+	for chunk := range untilDone(ctx, manager.providerFor(params.Model).Generate(ctx, prvdrParams)) {
+		if chunk.Error != nil {
+			return nil, fmt.Errorf("planner failed: %s", chunk.Error.Message)
+		}
+
+		switch result := chunk.Result.(type) {
+		case a2a.TaskStatusUpdateResult:
+			planTask.ToStatus(result.Status.State, result.Status.Message)
+		case a2a.TaskArtifactUpdateEvent:
+			planTask.AddArtifact(result.Artifact)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	raw := finalText(planTask, planTask.Status.Message)
+
+	if raw == "" {
+		raw = artifactText(planTask)
+	}
+
+	return parsePlan(raw)
+}
+
+/*
+artifactText joins the text parts of every artifact of a task.
+*/
+func artifactText(task *a2a.Task) string {
+	var sb strings.Builder
+
+	for _, artifact := range task.Artifacts {
+		for _, part := range artifact.Parts {
+			if part.Type == a2a.PartTypeText {
+				sb.WriteString(part.Text)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+/*
+parsePlan reads the steps from the planner's answer, which may wrap its JSON
+in a fenced code block, and may give the steps as a bare array.
+*/
+func parsePlan(raw string) ([]PlanStep, error) {
+	raw, _ = NewFencedBlockParser().Parse(raw)
+
+	var steps []PlanStep
+
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &steps); err != nil {
+			return nil, fmt.Errorf("invalid plan: %w", err)
+		}
+	} else {
+		var plan struct {
+			Steps []PlanStep `json:"steps"`
+		}
+
+		if err := json.Unmarshal([]byte(raw), &plan); err != nil {
+			return nil, fmt.Errorf("invalid plan: %w", err)
+		}
+
+		steps = plan.Steps
+	}
+
+	valid := make([]PlanStep, 0, len(steps))
+
+	for _, step := range steps {
+		step.Title = strings.TrimSpace(step.Title)
+		step.Instruction = strings.TrimSpace(step.Instruction)
+
+		if step.Instruction == "" {
+			step.Instruction = step.Title
+		}
+
+		if step.Instruction == "" {
+			continue
+		}
+
+		if step.Title == "" {
+			step.Title = step.Instruction
+		}
+
+		step.Status = PlanStepPending
+		valid = append(valid, step)
+	}
+
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("invalid plan: no steps")
+	}
+
+	if len(valid) > maxPlanSteps {
+		log.Warn("plan has too many steps, dropping the rest", "steps", len(valid), "max", maxPlanSteps)
+		valid = valid[:maxPlanSteps]
+	}
+
+	return valid, nil
+}
+
+/*
+setPlan stores the steps on the task as the "plan" artifact, replacing the
+previous version of it, and reports the new version to the event sinks.
+*/
+func (manager *TaskManager) setPlan(task *a2a.Task, steps []PlanStep) {
+	var sb strings.Builder
+	data := make([]any, 0, len(steps))
+
+	for i, step := range steps {
+		fmt.Fprintf(&sb, "%d. [%s] %s\n", i+1, step.Status, step.Title)
+		data = append(data, map[string]any{
+			"title":       step.Title,
+			"instruction": step.Instruction,
+			"status":      step.Status,
+		})
+	}
+
+	name := "plan"
+	description := "The steps the agent committed to, and how far each got."
+
+	artifact := a2a.Artifact{
+		Name:        &name,
+		Description: &description,
+		Parts: []a2a.Part{
+			a2a.NewTextPart(strings.TrimSpace(sb.String())),
+			{Type: a2a.PartTypeData, Data: map[string]any{"steps": data}},
+		},
+	}
+
+	replaced := false
+
+	for i := range task.Artifacts {
+		if task.Artifacts[i].Name != nil && *task.Artifacts[i].Name == name {
+			artifact.Index = task.Artifacts[i].Index
+			task.Artifacts[i] = artifact
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		task.AddArtifact(artifact)
+	}
+
+	manager.emit(task, TaskEventArtifact, &artifact)
+}
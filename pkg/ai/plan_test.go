@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+planningProvider answers the planner with a fixed plan, and every other
+call by completing the step it was given, failing the steps failStep
+returns true for.
+*/
+func planningProvider(plans []string, failStep func(step string) bool) (*controllableMockProvider, *[]string) {
+	var (
+		mu       sync.Mutex
+		executed []string
+		planned  int
+	)
+
+	prov := NewControllableMockProvider()
+	prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+		ch := make(chan jsonrpc.Response, 1)
+		defer close(ch)
+
+		history := params.Task.History
+
+		if history[0].Role == "system" && history[0].String() == "PLAN" {
+			mu.Lock()
+			plan := plans[min(planned, len(plans)-1)]
+			planned++
+			mu.Unlock()
+
+			ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage("assistant", plan)},
+			}}
+
+			return ch
+		}
+
+		step := history[len(history)-1].String()
+
+		mu.Lock()
+		executed = append(executed, step)
+		mu.Unlock()
+
+		state := a2a.TaskStateCompleted
+
+		if failStep != nil && failStep(step) {
+			state = a2a.TaskStateFailed
+		}
+
+		ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+			Status: a2a.TaskStatus{State: state, Message: a2a.NewTextMessage("assistant", "did "+step)},
+		}}
+
+		return ch
+	}
+
+	return prov, &executed
+}
+
+func TestPlanning(t *testing.T) {
+	Convey("Given a TaskManager that plans its tasks", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentPlan"}
+		var progress []TaskEvent
+
+		sink := WithEventSink(func(event TaskEvent) {
+			if event.Kind == TaskEventStatus && event.Message != nil && event.Message.Metadata["planStep"] != nil {
+				progress = append(progress, event)
+			}
+		})
+
+		Convey("When the planner returns a plan of two steps", func() {
+			prov, executed := planningProvider([]string{
+				"```json\n" + `{"steps": [{"title": "Gather", "instruction": "gather the facts"}, {"title": "Write", "instruction": "write the report"}]}` + "\n```",
+			}, nil)
+
+			manager, err := NewTaskManager(card,
+				WithTaskStore(newPublishingTaskStore()), WithProvider(prov),
+				WithPlanning(), WithPlannerPrompt("PLAN"), sink,
+			)
+			So(err, ShouldBeNil)
+
+			task, rpcErr := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-plan",
+				Message: *a2a.NewTextMessage("user", "write a report"),
+			})
+			So(rpcErr, ShouldBeNil)
+
+			Convey("Then both steps should be executed in order", func() {
+				So(*executed, ShouldHaveLength, 2)
+				So((*executed)[0], ShouldContainSubstring, "Step 1 of 2 of the plan: Gather")
+				So((*executed)[0], ShouldContainSubstring, "gather the facts")
+				So((*executed)[1], ShouldContainSubstring, "Step 2 of 2 of the plan: Write")
+				So(task.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+			})
+
+			Convey("Then a progress event should be emitted per step", func() {
+				So(progress, ShouldHaveLength, 2)
+				So(progress[0].Message.Metadata["planStep"], ShouldEqual, 1)
+				So(progress[0].Message.Metadata["planTitle"], ShouldEqual, "Gather")
+				So(progress[1].Message.Metadata["planStep"], ShouldEqual, 2)
+				So(progress[1].Message.Metadata["planSteps"], ShouldEqual, 2)
+			})
+
+			Convey("Then the plan should be stored as an artifact with every step done", func() {
+				var plan *a2a.Artifact
+
+				for i := range task.Artifacts {
+					if task.Artifacts[i].Name != nil && *task.Artifacts[i].Name == "plan" {
+						plan = &task.Artifacts[i]
+					}
+				}
+
+				So(plan, ShouldNotBeNil)
+				So(plan.Parts[0].Text, ShouldEqual, "1. [done] Gather\n2. [done] Write")
+
+				steps := plan.Parts[1].Data["steps"].([]any)
+				So(steps, ShouldHaveLength, 2)
+				So(steps[1].(map[string]any)["instruction"], ShouldEqual, "write the report")
+			})
+		})
+
+		Convey("When a step fails", func() {
+			failed := false
+
+			prov, executed := planningProvider([]string{
+				`[{"title": "Fetch", "instruction": "fetch the data"}, {"title": "Chart", "instruction": "chart the data"}]`,
+				`[{"title": "Retry", "instruction": "fetch the data from the mirror"}]`,
+			}, func(step string) bool {
+				if !failed {
+					failed = true
+					return true
+				}
+
+				return false
+			})
+
+			manager, err := NewTaskManager(card,
+				WithTaskStore(newPublishingTaskStore()), WithProvider(prov),
+				WithPlanning(), WithPlannerPrompt("PLAN"), sink,
+			)
+			So(err, ShouldBeNil)
+
+			task, rpcErr := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-replan",
+				Message: *a2a.NewTextMessage("user", "chart the data"),
+			})
+			So(rpcErr, ShouldBeNil)
+
+			Convey("Then the remaining steps should be replanned", func() {
+				So(*executed, ShouldHaveLength, 2)
+				So((*executed)[1], ShouldContainSubstring, "Step 2 of 2 of the plan: Retry")
+				So(task.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+			})
+		})
+	})
+}
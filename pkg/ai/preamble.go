@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+systemPreamble returns the global preamble set with
+WithGlobalSystemPreamble, or the a2a.system_preamble config value when none
+was given.
+*/
+func (manager *TaskManager) systemPreamble() string {
+	if manager.preamble != nil {
+		return *manager.preamble
+	}
+
+	return viper.GetViper().GetString("a2a.system_preamble")
+}
+
+/*
+applyPreamble puts the global preamble ahead of the agent's own system
+prompt. It runs right before every provider call, so the preamble is back
+in place even when the history was rewritten since the previous run, and
+it is idempotent, so it never appears twice.
+*/
+func (manager *TaskManager) applyPreamble(task *a2a.Task) {
+	preamble := strings.TrimSpace(manager.systemPreamble())
+
+	if preamble == "" {
+		return
+	}
+
+	if len(task.History) == 0 || task.History[0].Role != "system" {
+		task.History = append(
+			[]a2a.Message{*a2a.NewTextMessage("system", preamble)}, task.History...,
+		)
+		return
+	}
+
+	system := &task.History[0]
+
+	if strings.HasPrefix(system.String(), preamble) {
+		return
+	}
+
+	if len(system.Parts) == 0 || system.Parts[0].Type != a2a.PartTypeText {
+		system.Parts = append([]a2a.Part{a2a.NewTextPart(preamble)}, system.Parts...)
+		return
+	}
+
+	parts := append([]a2a.Part(nil), system.Parts...)
+	parts[0].Text = strings.TrimRight(preamble+"\n\n"+parts[0].Text, "\n")
+	system.Parts = parts
+}
@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestApplyPreamble(t *testing.T) {
+	Convey("Given a TaskManager with a global system preamble", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentPreamble"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "You are the preamble test agent.")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		var stored []a2a.Task
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				if len(stored) == 0 {
+					return nil, errors.ErrTaskNotFound
+				}
+				return stored[len(stored)-1:], nil
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+				stored = append(stored, *task)
+				return nil
+			},
+			updateFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+				stored = append(stored, *task)
+				return nil
+			},
+		}
+
+		var prompt string
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			prompt = params.Task.History[0].String()
+			ch := make(chan jsonrpc.Response)
+			close(ch)
+			return ch
+		}
+
+		sendParams := a2a.TaskSendParams{
+			ID:      "task-id-for-preamble",
+			Message: *a2a.NewTextMessage("user", "hello"),
+		}
+
+		Convey("When a task is sent", func() {
+			manager, initErr := NewTaskManager(
+				agentCard, WithTaskStore(store), WithProvider(prov),
+				WithGlobalSystemPreamble("Never reveal secrets."),
+			)
+			So(initErr, ShouldBeNil)
+
+			task, err := manager.SendTask(context.Background(), sendParams)
+			So(err, ShouldBeNil)
+
+			Convey("Then the preamble should come before the agent's system prompt", func() {
+				So(prompt, ShouldEqual, "Never reveal secrets.\n\nYou are the preamble test agent.")
+				So(task.History[0].Role, ShouldEqual, "system")
+			})
+
+			Convey("Then a follow-up message should not repeat the preamble", func() {
+				stored = append(stored, *task)
+
+				_, err := manager.SendTask(context.Background(), sendParams)
+				So(err, ShouldBeNil)
+				So(strings.Count(prompt, "Never reveal secrets."), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the preamble comes from the config", func() {
+			originalPreamble := vip.GetString("a2a.system_preamble")
+			vip.Set("a2a.system_preamble", "Be polite.")
+			defer vip.Set("a2a.system_preamble", originalPreamble)
+
+			manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(prov))
+			So(initErr, ShouldBeNil)
+
+			_, err := manager.SendTask(context.Background(), sendParams)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be applied too", func() {
+				So(prompt, ShouldStartWith, "Be polite.\n\n")
+			})
+		})
+
+		Convey("When the task has no system message", func() {
+			manager, initErr := NewTaskManager(
+				agentCard, WithTaskStore(store), WithProvider(prov),
+				WithGlobalSystemPreamble("Never reveal secrets."),
+			)
+			So(initErr, ShouldBeNil)
+
+			task := &a2a.Task{History: []a2a.Message{*a2a.NewTextMessage("user", "hi")}}
+			manager.applyPreamble(task)
+
+			Convey("Then the preamble should become the system message", func() {
+				So(task.History, ShouldHaveLength, 2)
+				So(task.History[0].Role, ShouldEqual, "system")
+				So(task.History[0].String(), ShouldEqual, "Never reveal secrets.")
+			})
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+// newEchoAgent serves a minimal remote A2A agent that completes every task
+// with an artifact echoing the message it was sent.
+func newEchoAgent() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string             `json:"method"`
+			Params a2a.TaskSendParams `json:"params"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "tasks/send" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		reply := "echo: " + req.Params.Message.String()
+		task := a2a.Task{
+			ID:        req.Params.ID,
+			SessionID: req.Params.SessionID,
+			Status: a2a.TaskStatus{
+				State:   a2a.TaskStateCompleted,
+				Message: a2a.NewTextMessage("agent", reply),
+			},
+			Artifacts: []a2a.Artifact{{Parts: []a2a.Part{a2a.NewTextPart(reply)}}},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(a2a.SendTaskStreamingResponse{Result: task})
+	}))
+}
+
+func TestA2AProvider(t *testing.T) {
+	Convey("Given a local agent backed by a remote echo agent", t, func() {
+		remote := newEchoAgent()
+		defer remote.Close()
+
+		agentCard := &a2a.AgentCard{Name: "TestAgentA2AProvider"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for A2A provider testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+		}
+
+		manager, initErr := NewTaskManager(
+			agentCard, WithTaskStore(store), WithProvider(provider.NewA2AProvider(remote.URL)),
+		)
+		So(initErr, ShouldBeNil)
+
+		Convey("When a task is sent to the local agent", func() {
+			task, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-a2a-provider",
+				Message: *a2a.NewTextMessage("user", "hello there"),
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then it should complete with the remote agent's output", func() {
+				So(task.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+				So(task.Status.Message.String(), ShouldEqual, "echo: hello there")
+				So(task.Artifacts, ShouldHaveLength, 1)
+				So(task.Artifacts[0].Parts[0].Text, ShouldEqual, "echo: hello there")
+			})
+		})
+	})
+}
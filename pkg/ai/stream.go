@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+streamRetention is how long the events of a finished stream remain available
+to subscribers that reconnect late.
+*/
+var streamRetention = 5 * time.Minute
+
+/*
+StreamEvent is one event of a streamed task. Events are numbered from 1 in
+the order the task produced them, so a subscriber that reconnects can resume
+after the last ID it saw.
+*/
+type StreamEvent struct {
+	ID       int
+	Response jsonrpc.Response
+}
+
+/*
+taskStream records the events of a streamed task and fans them out to any
+number of subscribers. Every subscriber reads the full, ordered sequence at
+its own pace, so the provider runs once however many clients listen, and a
+slow client never holds up the others.
+*/
+type taskStream struct {
+	mu         sync.Mutex
+	events     []jsonrpc.Response
+	done       bool
+	updated    chan struct{}
+	modalities *taskModalities
+}
+
+func newTaskStream() *taskStream {
+	return &taskStream{updated: make(chan struct{}), modalities: newTaskModalities()}
+}
+
+/*
+publish appends an event and wakes the subscribers waiting for it.
+*/
+func (stream *taskStream) publish(response jsonrpc.Response) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if stream.done {
+		return
+	}
+
+	stream.events = append(stream.events, response)
+	close(stream.updated)
+	stream.updated = make(chan struct{})
+}
+
+/*
+finish marks the stream complete, which closes every subscription once it
+has read the remaining events.
+*/
+func (stream *taskStream) finish() {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if !stream.done {
+		stream.done = true
+		close(stream.updated)
+	}
+}
+
+/*
+since returns the events after the given ID, whether the stream is done, and
+a channel that is closed when either changes.
+*/
+func (stream *taskStream) since(after int) ([]jsonrpc.Response, bool, <-chan struct{}) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if after < 0 {
+		after = 0
+	}
+
+	if after >= len(stream.events) {
+		return nil, stream.done, stream.updated
+	}
+
+	return stream.events[after:], stream.done, stream.updated
+}
+
+/*
+subscribe streams the events after the given ID, followed by live events,
+until the stream is done or ctx is.
+*/
+func (stream *taskStream) subscribe(ctx context.Context, after int) <-chan StreamEvent {
+	out := make(chan StreamEvent)
+
+	go func() {
+		defer close(out)
+
+		for {
+			events, done, updated := stream.since(after)
+
+			for _, response := range events {
+				after++
+
+				select {
+				case out <- StreamEvent{ID: after, Response: response}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if len(events) > 0 {
+				continue
+			}
+
+			if done {
+				return
+			}
+
+			select {
+			case <-updated:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+/*
+openStream registers a new stream for a task, replacing any earlier one.
+*/
+func (manager *TaskManager) openStream(id string) *taskStream {
+	stream := newTaskStream()
+
+	manager.streamsMu.Lock()
+	defer manager.streamsMu.Unlock()
+
+	if manager.streams == nil {
+		manager.streams = make(map[string]*taskStream)
+	}
+
+	manager.streams[id] = stream
+	return stream
+}
+
+/*
+closeStream finishes a stream and forgets it once the retention period has
+passed, unless the task has started streaming again since.
+*/
+func (manager *TaskManager) closeStream(id string, stream *taskStream) {
+	stream.finish()
+
+	time.AfterFunc(streamRetention, func() {
+		manager.streamsMu.Lock()
+		defer manager.streamsMu.Unlock()
+
+		if manager.streams[id] == stream {
+			delete(manager.streams, id)
+		}
+	})
+}
+
+/*
+HasStream reports whether a task is streaming, or finished streaming
+recently enough that SubscribeStream can still replay it.
+*/
+func (manager *TaskManager) HasStream(id string) bool {
+	manager.streamsMu.Lock()
+	defer manager.streamsMu.Unlock()
+
+	_, ok := manager.streams[id]
+	return ok
+}
+
+/*
+SubscribeStream adds a subscriber to a task started with StreamTask. It
+receives every event after lastEventID, replayed in order, followed by the
+live events, until the task finishes or ctx is done. Any number of clients
+can subscribe to the same task without running the provider again.
+
+Returns:
+- A channel of the task's events.
+- *errors.RpcError if the task is not streaming.
+*/
+func (manager *TaskManager) SubscribeStream(
+	ctx context.Context, id string, lastEventID int,
+) (<-chan StreamEvent, *errors.RpcError) {
+	manager.streamsMu.Lock()
+	stream, ok := manager.streams[id]
+	manager.streamsMu.Unlock()
+
+	if !ok {
+		return nil, errors.ErrTaskNotFound
+	}
+
+	return stream.subscribe(ctx, lastEventID), nil
+}
@@ -0,0 +1,180 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestStreamMultiplexing(t *testing.T) {
+	Convey("Given a provider that streams five chunks, pausing after two", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentStreamMux"}
+
+		var calls atomic.Int32
+		release := make(chan struct{})
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			calls.Add(1)
+
+			ch := make(chan jsonrpc.Response)
+			go func() {
+				defer close(ch)
+
+				for i := range 5 {
+					if i == 2 {
+						<-release
+					}
+
+					ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+						ID:       params.Task.ID,
+						Artifact: a2a.Artifact{Index: i, Parts: []a2a.Part{a2a.NewTextPart(fmt.Sprintf("chunk %d", i))}},
+					}}
+				}
+			}()
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov))
+		So(initErr, ShouldBeNil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		task := a2a.NewTask(card.Name)
+		task.History = append(task.History, *a2a.NewTextMessage("user", "stream five chunks"))
+
+		Convey("When a second client subscribes while the task is streaming", func() {
+			out, err := manager.StreamTask(ctx, task)
+			So(err, ShouldBeNil)
+
+			var first []jsonrpc.Response
+			for len(first) < 2 {
+				first = append(first, <-out)
+			}
+
+			late, err := manager.SubscribeStream(ctx, task.ID, 0)
+			So(err, ShouldBeNil)
+			close(release)
+
+			for chunk := range out {
+				first = append(first, chunk)
+			}
+
+			var second []jsonrpc.Response
+			for event := range late {
+				second = append(second, event.Response)
+			}
+
+			Convey("Then both should receive the identical, complete sequence", func() {
+				So(first, ShouldHaveLength, 6) // Five chunks and the final status
+				So(second, ShouldResemble, first)
+			})
+
+			Convey("Then the provider should have run once", func() {
+				So(calls.Load(), ShouldEqual, 1)
+			})
+
+			Convey("Then a client resuming from an event ID should only get the later events", func() {
+				resumed, err := manager.SubscribeStream(ctx, task.ID, 3)
+				So(err, ShouldBeNil)
+
+				var ids []int
+				for event := range resumed {
+					ids = append(ids, event.ID)
+					So(event.Response, ShouldResemble, first[event.ID-1])
+				}
+
+				So(ids, ShouldResemble, []int{4, 5, 6})
+			})
+		})
+
+		Convey("When subscribing to a task that is not streaming", func() {
+			_, err := manager.SubscribeStream(ctx, "unknown", 0)
+
+			Convey("Then it should report the task as not found", func() {
+				So(err, ShouldEqual, errors.ErrTaskNotFound)
+			})
+		})
+	})
+}
+
+func TestStreamFinishMetadata(t *testing.T) {
+	Convey("Given a provider that streams an answer and reports why it stopped", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentStreamFinish"}
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response, 2)
+
+			go func() {
+				defer close(ch)
+
+				ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+					ID:       params.Task.ID,
+					Artifact: a2a.Artifact{Parts: []a2a.Part{a2a.NewTextPart("done")}},
+				}}
+
+				params.Task.AddUsage(10, 5)
+				params.FinishReason = "stop"
+
+				ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+					ID:       params.Task.ID,
+					Status:   a2a.TaskStatus{State: a2a.TaskStateCompleted},
+					Final:    true,
+					Metadata: map[string]any{"source": "provider"},
+				}}
+			}()
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(card, WithTaskStore(&taskStoreMockForTesting{}), WithProvider(prov))
+		So(initErr, ShouldBeNil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		Convey("When the task is streamed", func() {
+			task := a2a.NewTask(card.Name)
+			task.History = append(task.History, *a2a.NewTextMessage("user", "finish up"))
+
+			out, err := manager.StreamTask(ctx, task)
+			So(err, ShouldBeNil)
+
+			var streamed []jsonrpc.Response
+			for chunk := range out {
+				streamed = append(streamed, chunk)
+			}
+
+			So(streamed, ShouldHaveLength, 2)
+			last, ok := streamed[1].Result.(a2a.TaskStatusUpdateResult)
+			So(ok, ShouldBeTrue)
+
+			Convey("Then the last event should be the final status", func() {
+				So(last.Final, ShouldBeTrue)
+				So(last.ID, ShouldEqual, task.ID)
+				So(last.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+			})
+
+			Convey("Then it should carry the finish reason, usage and stop sequence", func() {
+				So(last.Metadata[provider.FinishReasonKey], ShouldEqual, "stop")
+				So(last.Metadata[provider.StopSequenceKey], ShouldBeFalse)
+				So(last.Metadata[provider.UsageKey], ShouldResemble, a2a.Usage{
+					PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15,
+				})
+			})
+
+			Convey("Then it should keep the metadata the provider sent", func() {
+				So(last.Metadata["source"], ShouldEqual, "provider")
+			})
+		})
+	})
+}
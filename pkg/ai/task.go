@@ -2,9 +2,15 @@ package ai
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/viper"
 	"github.com/theapemachine/a2a-go/pkg/a2a"
 	"github.com/theapemachine/a2a-go/pkg/errors"
 	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
@@ -14,11 +20,72 @@ import (
 	"github.com/theapemachine/a2a-go/pkg/types"
 )
 
+/*
+ArtifactOverflowPolicy decides what happens when a task reaches the
+configured maximum number of artifacts.
+*/
+type ArtifactOverflowPolicy string
+
+const (
+	// ArtifactOverflowReject keeps the oldest artifacts and drops new ones.
+	ArtifactOverflowReject ArtifactOverflowPolicy = "reject"
+	// ArtifactOverflowRollOff drops the oldest artifacts to make room for new ones.
+	ArtifactOverflowRollOff ArtifactOverflowPolicy = "roll-off"
+)
+
 type TaskManager struct {
-	agent     *a2a.AgentCard
-	taskStore stores.TaskStore
-	provider  provider.Interface
-	memory    memory.UnifiedStore
+	agent          *a2a.AgentCard
+	taskStore      stores.TaskStore
+	revisions      *revisionStore
+	provider       provider.Interface
+	memory         memory.UnifiedStore
+	maxArtifacts   int
+	artifactPolicy ArtifactOverflowPolicy
+	outputParser   OutputParser
+	dryRun         bool
+	debug          bool
+	debugLimit     int
+	preamble       *string
+	healthyTools   bool
+	models         []string
+	terminators    []provider.ToolTerminator
+	toolBudget     time.Duration
+	maxDuration    time.Duration
+	parallelTools  *bool
+	streamCalls    bool
+	planning       bool
+	maxReplans     int
+	plannerPrompt  *string
+
+	checkpointChunks   int
+	checkpointInterval time.Duration
+
+	runsMu sync.Mutex
+	runs   map[string]*providerRun
+
+	router *provider.ModelRouter
+
+	memoryFailureMode MemoryFailureMode
+
+	approvalTools    map[string]bool
+	approvalMu       sync.Mutex
+	pendingApprovals map[string]*pendingApproval
+
+	sinksMu sync.RWMutex
+	sinks   []EventSink
+
+	healthyMu    sync.Mutex
+	healthy      []*mcp.Tool
+	healthyUntil time.Time
+
+	streamsMu sync.Mutex
+	streams   map[string]*taskStream
+
+	duplicateArtifacts atomic.Int64
+
+	slots   chan struct{}
+	queued  atomic.Int64
+	running atomic.Int64
 }
 
 type TaskManagerOption func(*TaskManager)
@@ -27,7 +94,9 @@ func NewTaskManager(
 	card *a2a.AgentCard, options ...TaskManagerOption,
 ) (*TaskManager, error) {
 	taskManager := &TaskManager{
-		agent: card,
+		agent:             card,
+		artifactPolicy:    ArtifactOverflowReject,
+		memoryFailureMode: MemoryDegrade,
 	}
 
 	for _, option := range options {
@@ -39,6 +108,13 @@ func NewTaskManager(
 		return nil, errors.NewError(errors.ErrMissingTaskStore{})
 	}
 
+	taskManager.revisions = newRevisionStore(taskManager.taskStore)
+	taskManager.taskStore = taskManager.revisions
+
+	if taskManager.provider == nil && taskManager.router != nil {
+		taskManager.provider = taskManager.router.Fallback()
+	}
+
 	if taskManager.provider == nil {
 		log.Error("missing provider")
 		return nil, errors.NewError(errors.ErrMissingProvider{})
@@ -64,16 +140,127 @@ func (manager *TaskManager) handleUpdate(
 		}
 	}
 
+	artifacts := len(params.Artifacts)
+
 	switch result := chunk.Result.(type) {
 	case a2a.TaskStatusUpdateResult:
-		params.ToStatus(result.Status.State, result.Status.Message)
+		message := mergeStatusMessage(params.Status.Message, result.Status.Message)
+
+		// The answer is parsed before the task completes, so its artifact
+		// reaches the sinks ahead of the terminal status.
+		if result.Status.State == a2a.TaskStateCompleted {
+			message = manager.parseOutput(params, message)
+			manager.emitArtifacts(params, artifacts)
+			artifacts = len(params.Artifacts)
+		}
+
+		manager.toStatus(params, result.Status.State, message)
+		params.MergeMetadata(result.Metadata)
 	case a2a.TaskArtifactUpdateEvent:
 		params.AddArtifact(result.Artifact)
+		params.MergeMetadata(result.Metadata)
 	}
 
+	manager.emitArtifacts(params, artifacts)
+
+	// Providers may also append artifacts to the task directly, so the
+	// limit is enforced after every chunk rather than per artifact event.
+	manager.enforceArtifactLimit(params)
+
 	return nil
 }
 
+/*
+enforceArtifactLimit trims the task's artifacts down to the configured
+maximum according to the overflow policy. Artifacts marked as the last
+chunk are never dropped, so the final result of a stream always survives.
+*/
+func (manager *TaskManager) enforceArtifactLimit(task *a2a.Task) {
+	if manager.maxArtifacts <= 0 || len(task.Artifacts) <= manager.maxArtifacts {
+		return
+	}
+
+	dropped := 0
+
+	for len(task.Artifacts) > manager.maxArtifacts {
+		idx := -1
+
+		if manager.artifactPolicy == ArtifactOverflowRollOff {
+			for i := 0; i < len(task.Artifacts); i++ {
+				if !isFinalArtifact(task.Artifacts[i]) {
+					idx = i
+					break
+				}
+			}
+		} else {
+			for i := len(task.Artifacts) - 1; i >= 0; i-- {
+				if !isFinalArtifact(task.Artifacts[i]) {
+					idx = i
+					break
+				}
+			}
+		}
+
+		if idx == -1 {
+			break
+		}
+
+		task.Artifacts = append(task.Artifacts[:idx], task.Artifacts[idx+1:]...)
+		dropped++
+	}
+
+	if dropped == 0 {
+		return
+	}
+
+	log.Warn("artifact limit reached",
+		"task_id", task.ID,
+		"max_artifacts", manager.maxArtifacts,
+		"policy", manager.artifactPolicy,
+		"dropped", dropped,
+	)
+
+	if manager.artifactPolicy == ArtifactOverflowReject && !IsTerminalState(task.Status.State) {
+		task.ToStatus(task.Status.State, a2a.NewTextMessage(
+			manager.agent.Name,
+			fmt.Sprintf("artifact limit of %d reached, further artifacts are rejected", manager.maxArtifacts),
+		))
+	}
+}
+
+/*
+mergeStatusMessage carries the metadata of the previous status message over
+to its replacement, so keys set by an earlier update are not lost when a
+later one only sets a few of its own.
+*/
+func mergeStatusMessage(previous, next *a2a.Message) *a2a.Message {
+	if previous == nil || next == nil || previous == next || len(previous.Metadata) == 0 {
+		return next
+	}
+
+	merged := *next
+	merged.Metadata = a2a.MergeMetadata(
+		a2a.MergeMetadata(nil, previous.Metadata), next.Metadata,
+	)
+
+	return &merged
+}
+
+func isFinalArtifact(artifact a2a.Artifact) bool {
+	return artifact.LastChunk != nil && *artifact.LastChunk
+}
+
+/*
+IsTerminalState reports whether a task in the state will not change anymore.
+*/
+func IsTerminalState(state a2a.TaskState) bool {
+	switch state {
+	case a2a.TaskStateCompleted, a2a.TaskStateCanceled, a2a.TaskStateFailed:
+		return true
+	}
+	return false
+}
+
 func (manager *TaskManager) createNewTask(ctx context.Context, params a2a.TaskSendParams) (*a2a.Task, *errors.RpcError) {
 	log.Info("creating new task", "task_id", params.ID, "session_id", params.SessionID)
 	newTask := a2a.NewTask(manager.agent.Name)
@@ -90,6 +277,7 @@ func (manager *TaskManager) createNewTask(ctx context.Context, params a2a.TaskSe
 		return nil, createErr
 	}
 	log.Info("newly created task stored", "task_id", newTask.ID, "status", newTask.Status.State)
+	manager.emit(newTask, TaskEventCreated, nil)
 	return newTask, nil
 }
 
@@ -145,6 +333,61 @@ func (manager *TaskManager) selectTask(
 func (manager *TaskManager) SendTask(
 	ctx context.Context, params a2a.TaskSendParams,
 ) (*a2a.Task, *errors.RpcError) {
+	if task, ok := manager.resolveApproval(params); ok {
+		return task, nil
+	}
+
+	if manager.planning {
+		return manager.completePlanned(ctx, params, false)
+	}
+
+	return manager.complete(ctx, params, false)
+}
+
+/*
+CompleteStreaming runs a task with a streaming provider call, for a lower
+time-to-first-token and persistence of partial progress, but only returns
+the final consolidated task, just like SendTask.
+
+Returns:
+- The final task once the provider stream has ended.
+- *errors.RpcError if the task could not be selected or the stream failed.
+*/
+func (manager *TaskManager) CompleteStreaming(
+	ctx context.Context, params a2a.TaskSendParams,
+) (*a2a.Task, *errors.RpcError) {
+	if task, ok := manager.resolveApproval(params); ok {
+		return task, nil
+	}
+
+	if manager.planning {
+		return manager.completePlanned(ctx, params, true)
+	}
+
+	return manager.complete(ctx, params, true)
+}
+
+/*
+complete drives a task to the end of its provider run and returns the final
+task. When stream is true the provider streams and every chunk is persisted
+as it arrives.
+*/
+func (manager *TaskManager) complete(
+	ctx context.Context, params a2a.TaskSendParams, stream bool,
+) (*a2a.Task, *errors.RpcError) {
+	if err := manager.checkModel(ctx, params.Model); err != nil {
+		log.Error("requested model is not available", "model", params.Model, "error", err)
+		return nil, err
+	}
+
+	release, err := manager.acquireSlot(ctx, params.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
 	task, err := manager.selectTask(ctx, params)
 
 	if err != nil {
@@ -152,37 +395,110 @@ func (manager *TaskManager) SendTask(
 		return nil, err
 	}
 
-	task.ToStatus(a2a.TaskStateWorking,
+	manager.toStatus(&task, a2a.TaskStateWorking,
 		a2a.NewTextMessage(
 			manager.agent.Name,
 			"starting task",
 		),
 	)
 
-	if manager.memory != nil {
-		if err := manager.memory.InjectMemories(ctx, &task); err != nil {
-			log.Error("failed to inject memories", "task_id", task.ID, "error", err)
-		}
+	if err := manager.injectMemories(ctx, &task); err != nil {
+		manager.toStatus(&task, a2a.TaskStateFailed, a2a.NewTextMessage(manager.agent.Name, err.Message))
+		return &task, err
 	}
 
+	manager.applyPreamble(&task)
+
 	prvdrParams := provider.NewProviderParams(
-		&task, provider.WithTools(types.SkillsToTools(manager.agent.Skills)...),
+		&task,
+		provider.WithTools(manager.tools()...),
+		provider.WithToolApproval(manager.approvalFor(&task)),
+		provider.WithDryRun(manager.dryRun || params.DryRun),
+		provider.WithToolCallHook(manager.toolCallTracer(&task)),
+		provider.WithToolTerminators(manager.terminators...),
+		provider.WithTotalToolBudget(manager.totalToolBudget()),
+		provider.WithParallelToolCalls(manager.parallelToolCalls()),
+		provider.WithStreamToolCalls(manager.streamCalls),
 	)
 
-	prvdrParams.Stream = false
+	model := requestedModel(&params, &task)
+
+	if model != "" {
+		prvdrParams.Model = model
+	}
+
+	prvdr := manager.providerFor(model)
+	prvdrParams.Stream = stream
+	restoreHistory := manager.applyCapabilities(&task, prvdr, prvdrParams)
+	providerDone := manager.traceProviderCall(&task, prvdrParams)
+	dedup := &artifactDedup{}
+	cp := manager.newCheckpoint(&task)
+	runCtx, cancel := manager.startRun(ctx, task.ID)
+	defer cancel()
+
+	for chunk := range untilDone(runCtx, prvdr.Generate(
+		runCtx, prvdrParams,
+	)) {
+		if manager.suppressDuplicate(&task, dedup, chunk) {
+			continue
+		}
 
-	for chunk := range manager.provider.Generate(
-		ctx, prvdrParams,
-	) {
 		if err := manager.handleUpdate(&task, chunk); err != nil {
 			log.Error("failed to handle update", "error", err)
+
+			if stream {
+				manager.flushCheckpoint(ctx, &task, cp)
+			}
+
+			restoreHistory()
 			return &task, err.(*errors.RpcError)
 		}
+
+		if stream {
+			manager.persistChunk(ctx, &task, cp)
+		}
+	}
+
+	if canceledByRequest(runCtx) {
+		manager.cancelRun(&task)
+	}
+
+	if stream {
+		manager.flushCheckpoint(ctx, &task, cp)
 	}
 
-	if manager.memory != nil {
-		if err := manager.memory.ExtractMemories(ctx, &task); err != nil {
-			log.Error("failed to extract memories", "task_id", task.ID, "error", err)
+	providerDone()
+	restoreHistory()
+
+	if canceledByRequest(runCtx) {
+		return &task, nil
+	}
+
+	if overDeadline(ctx, runCtx) {
+		manager.failOverDeadline(&task)
+		manager.addToolTranscript(&task, prvdrParams)
+
+		if updErr := manager.taskStore.Update(ctx, &task, manager.agent.Name); updErr != nil {
+			log.Error("failed to persist timed out task", "task_id", task.ID, "error", updErr)
+		}
+
+		return &task, nil
+	}
+
+	manager.addDryRunPlan(&task, prvdrParams)
+	manager.addToolTranscript(&task, prvdrParams)
+	manager.applyOutputParser(&task)
+
+	if err := manager.extractMemories(ctx, &task); err != nil {
+		manager.toStatus(&task, a2a.TaskStateFailed, a2a.NewTextMessage(manager.agent.Name, err.Message))
+		return &task, err
+	}
+
+	// Persist the final task so its debug log and tool transcript can be
+	// fetched with tasks/get.
+	if manager.debug || len(prvdrParams.ToolTranscript) > 0 {
+		if updErr := manager.taskStore.Update(ctx, &task, manager.agent.Name); updErr != nil {
+			log.Error("failed to persist final task", "task_id", task.ID, "error", updErr)
 		}
 	}
 
@@ -190,7 +506,30 @@ func (manager *TaskManager) SendTask(
 }
 
 /*
-StreamTask handles a streaming task request.
+tools returns the tools advertised to the provider for the agent's skills.
+With WithHealthyToolsOnly, the prerequisites are checked again at most once
+per healthyToolsTTL rather than for every request, since they include a
+ping of the docker daemon.
+*/
+func (manager *TaskManager) tools() []*mcp.Tool {
+	if !manager.healthyTools {
+		return types.SkillsToTools(manager.agent.Skills)
+	}
+
+	manager.healthyMu.Lock()
+	defer manager.healthyMu.Unlock()
+
+	if now := time.Now(); now.After(manager.healthyUntil) {
+		manager.healthy = types.HealthySkillsToTools(manager.agent.Skills)
+		manager.healthyUntil = now.Add(healthyToolsTTL)
+	}
+
+	return slices.Clone(manager.healthy)
+}
+
+/*
+StreamTask handles a streaming task request. The returned channel is the
+first subscriber of the task's stream; SubscribeStream adds more.
 
 Returns:
 - A task if it exists.
@@ -200,19 +539,27 @@ func (manager *TaskManager) StreamTask(
 	ctx context.Context,
 	task *a2a.Task,
 ) (chan jsonrpc.Response, *errors.RpcError) {
-	task.ToStatus(a2a.TaskStateWorking,
+	model := requestedModel(nil, task)
+
+	if err := manager.checkModel(ctx, model); err != nil {
+		log.Error("requested model is not available", "model", model, "error", err)
+		return nil, err
+	}
+
+	manager.emit(task, TaskEventCreated, nil)
+	manager.toStatus(task, a2a.TaskStateWorking,
 		a2a.NewTextMessage(
 			manager.agent.Name,
 			"starting task",
 		),
 	)
 
-	if manager.memory != nil {
-		if err := manager.memory.InjectMemories(ctx, task); err != nil {
-			log.Error("failed to inject memories", "task_id", task.ID, "error", err)
-		}
+	if err := manager.injectMemories(ctx, task); err != nil {
+		return nil, err
 	}
 
+	manager.applyPreamble(task)
+
 	// Persist the task before streaming (fix for test expectations)
 	if createErr := manager.taskStore.Create(ctx, task, manager.agent.Name); createErr != nil {
 		log.Error("failed to create task in store before streaming", "task_id", task.ID, "error", createErr)
@@ -220,53 +567,149 @@ func (manager *TaskManager) StreamTask(
 	}
 
 	prvdrParams := provider.NewProviderParams(
-		task, provider.WithTools(types.SkillsToTools(manager.agent.Skills)...),
+		task,
+		provider.WithTools(manager.tools()...),
+		provider.WithToolApproval(manager.approvalFor(task)),
+		provider.WithDryRun(manager.dryRun || isDryRun(task)),
+		provider.WithToolCallHook(manager.toolCallTracer(task)),
+		provider.WithToolTerminators(manager.terminators...),
+		provider.WithTotalToolBudget(manager.totalToolBudget()),
+		provider.WithParallelToolCalls(manager.parallelToolCalls()),
+		provider.WithStreamToolCalls(manager.streamCalls),
 	)
 
+	if model != "" {
+		prvdrParams.Model = model
+	}
+
+	prvdr := manager.providerFor(model)
 	prvdrParams.Stream = true
+	restoreHistory := manager.applyCapabilities(task, prvdr, prvdrParams)
 
-	out := make(chan jsonrpc.Response)
+	stream := manager.openStream(task.ID)
 
 	go func() {
-		defer close(out) // Ensure out is closed when this goroutine exits
+		defer manager.closeStream(task.ID, stream) // Ends every subscription when this goroutine exits
+		defer restoreHistory()
 
-		providerChan := manager.provider.Generate(ctx, prvdrParams)
-	Loop:
-		for {
-			select {
-			case <-ctx.Done(): // If the overall context for StreamTask is done/cancelled
+		release, err := manager.acquireSlot(ctx, task.ID)
+
+		if err != nil {
+			log.Info("StreamTask context done while queued", "task_id", task.ID)
+			return
+		}
+
+		defer release()
+
+		runCtx, cancel := manager.startRun(ctx, task.ID)
+		defer cancel()
+
+		providerDone := manager.traceProviderCall(task, prvdrParams)
+		// Artifacts of producers are merged into the provider's chunks, and
+		// the channel only closes once every producer is done as well.
+		providerChan := stream.modalities.merge(runCtx, untilDone(runCtx, prvdr.Generate(runCtx, prvdrParams)))
+		dedup := &artifactDedup{}
+		cp := manager.newCheckpoint(task)
+		var final *a2a.TaskStatusUpdateResult
+
+		// The provider channel closes when the run and every producer ended,
+		// or when ctx is done or the task ran out of time.
+		for chunk := range providerChan {
+			if manager.suppressDuplicate(task, dedup, chunk) {
+				continue
+			}
+
+			if err := manager.handleUpdate(task, chunk); err != nil {
+				log.Error("failed to handle update during stream, stopping stream", "task_id", task.ID, "error", err)
+				manager.flushCheckpoint(ctx, task, cp)
+				// Error logged, goroutine will exit, and 'out' will be closed by defer.
+				// The client will see any chunks sent before this error, then the channel closes.
+				return
+			}
+
+			manager.persistChunk(ctx, task, cp)
+
+			// The provider's final status is held back and sent once the
+			// run has finished, carrying the finish details.
+			if update, isFinal := chunk.Result.(a2a.TaskStatusUpdateResult); isFinal && update.Final {
+				final = &update
+				continue
+			}
+
+			// Send the processed chunk to every subscriber
+			stream.publish(chunk)
+		}
+
+		if canceledByRequest(runCtx) {
+			manager.cancelRun(task)
+		}
+
+		manager.flushCheckpoint(ctx, task, cp)
+
+		if runCtx.Err() != nil {
+			if canceledByRequest(runCtx) {
+				providerDone()
+				stream.publish(finalStatus(task, nil, prvdrParams))
+				return
+			}
+
+			if !overDeadline(ctx, runCtx) {
 				log.Info("StreamTask context done, exiting stream processing.", "task_id", task.ID)
 				return
-			case chunk, ok := <-providerChan:
-				if !ok { // providerChan was closed, normal completion of provider stream
-					break Loop
-				}
+			}
 
-				if err := manager.handleUpdate(task, chunk); err != nil {
-					log.Error("failed to handle update during stream, stopping stream", "task_id", task.ID, "error", err)
-					// Error logged, goroutine will exit, and 'out' will be closed by defer.
-					// The client will see any chunks sent before this error, then the channel closes.
-					return
-				}
+			providerDone()
+			manager.failOverDeadline(task)
+			manager.addToolTranscript(task, prvdrParams)
 
-				if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
-					log.Error("failed to persist streaming update", "task_id", task.ID, "error", updErr)
-				}
+			if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+				log.Error("failed to persist timed out task", "task_id", task.ID, "error", updErr)
+			}
 
-				// Send the processed chunk to the output channel
-				select {
-				case out <- chunk:
-					// Chunk sent successfully
-				case <-ctx.Done():
-					log.Info("StreamTask context done while sending chunk to output, exiting stream processing.", "task_id", task.ID)
-					return
-				}
+			stream.publish(finalStatus(task, nil, prvdrParams))
+			return
+		}
+
+		providerDone()
+		restoreHistory()
+		manager.addDryRunPlan(task, prvdrParams)
+		manager.addToolTranscript(task, prvdrParams)
+		manager.applyOutputParser(task)
+
+		if err := manager.extractMemories(ctx, task); err != nil {
+			manager.toStatus(task, a2a.TaskStateFailed, a2a.NewTextMessage(manager.agent.Name, err.Message))
+
+			if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+				log.Error("failed to persist memory failure", "task_id", task.ID, "error", updErr)
 			}
+
+			stream.publish(jsonrpc.Response{Error: &jsonrpc.Error{Code: err.Code, Message: err.Message}})
+			return
+		}
+
+		if manager.debug || len(prvdrParams.ToolTranscript) > 0 {
+			if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+				log.Error("failed to persist final task", "task_id", task.ID, "error", updErr)
+			}
+		}
+
+		if answer := parsedAnswer(task); answer != nil {
+			stream.publish(jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{ID: task.ID, Artifact: *answer}})
 		}
 
-		if manager.memory != nil {
-			if err := manager.memory.ExtractMemories(ctx, task); err != nil {
-				log.Error("failed to extract memories for streaming task", "task_id", task.ID, "error", err)
+		stream.publish(finalStatus(task, final, prvdrParams))
+	}()
+
+	out := make(chan jsonrpc.Response)
+
+	go func() {
+		defer close(out)
+
+		for event := range stream.subscribe(ctx, 0) {
+			select {
+			case out <- event.Response:
+			case <-ctx.Done():
+				return
 			}
 		}
 	}()
@@ -274,6 +717,28 @@ func (manager *TaskManager) StreamTask(
 	return out, nil // Return immediately
 }
 
+/*
+finalStatus builds the last event of a stream: the task's final status, with
+the provider's finish reason, the token usage and whether a stop sequence
+ended the run added to the metadata of the final status the provider sent.
+*/
+func finalStatus(
+	task *a2a.Task, final *a2a.TaskStatusUpdateResult, params *provider.ProviderParams,
+) jsonrpc.Response {
+	var metadata map[string]any
+
+	if final != nil {
+		metadata = a2a.MergeMetadata(metadata, final.Metadata)
+	}
+
+	return jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+		ID:       task.ID,
+		Status:   task.Status,
+		Final:    true,
+		Metadata: a2a.MergeMetadata(metadata, params.FinishMetadata()),
+	}}
+}
+
 /*
 GetTask retrieves the current state of a task.
 
@@ -313,7 +778,9 @@ func (manager *TaskManager) GetTask(
 }
 
 /*
-CancelTask attempts to cancel an ongoing task.
+CancelTask attempts to cancel an ongoing task. A provider run in flight for
+the task is stopped, so it does not keep generating after the task was
+canceled.
 
 Returns:
 - nil if the task was successfully cancelled.
@@ -322,9 +789,62 @@ Returns:
 func (manager *TaskManager) CancelTask(
 	ctx context.Context, id string,
 ) *errors.RpcError {
+	if manager.stopRun(id) {
+		log.Info("stopped provider run of canceled task", "task_id", id)
+	}
+
 	return manager.taskStore.Cancel(ctx, manager.agent.Name+"/"+id)
 }
 
+/*
+CancelTaskWithReason cancels a task and records the reason and the
+requester, either of which may be empty, on its terminal status.
+
+Returns:
+- The canceled event to send to subscribers, carrying the same details.
+- *errors.RpcError if the task was not found or could not be cancelled.
+*/
+func (manager *TaskManager) CancelTaskWithReason(
+	ctx context.Context, id, reason, requester string,
+) (*a2a.TaskStatusUpdateResult, *errors.RpcError) {
+	if err := manager.CancelTask(ctx, id); err != nil {
+		return nil, err
+	}
+
+	task, err := manager.GetTask(ctx, id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]any{}
+	text := "task canceled"
+
+	if reason != "" {
+		details["reason"] = reason
+		text += ": " + reason
+	}
+
+	if requester != "" {
+		details["requester"] = requester
+	}
+
+	message := a2a.NewTextMessage(manager.agent.Name, text)
+	message.Metadata = details
+	manager.toStatus(task, a2a.TaskStateCanceled, message)
+
+	if updErr := manager.taskStore.Update(ctx, task, manager.agent.Name); updErr != nil {
+		log.Error("failed to persist cancellation reason", "task_id", task.ID, "error", updErr)
+		return nil, updErr
+	}
+
+	return &a2a.TaskStatusUpdateResult{
+		ID:       task.ID,
+		Status:   task.Status,
+		Final:    true,
+		Metadata: details,
+	}, nil
+}
+
 /*
 ResubscribeTask allows a client to resubscribe to task events.
 
@@ -362,3 +882,193 @@ func WithMemoryStore(m memory.UnifiedStore) TaskManagerOption {
 		t.memory = m
 	}
 }
+
+/*
+WithApprovalRequired gates the named tools behind operator approval. A call
+to one of them moves the task to input-required until the client approves
+or denies it with a follow-up tasks/send.
+*/
+func WithApprovalRequired(tools ...string) TaskManagerOption {
+	return func(t *TaskManager) {
+		if t.approvalTools == nil {
+			t.approvalTools = make(map[string]bool, len(tools))
+		}
+
+		for _, tool := range tools {
+			t.approvalTools[tool] = true
+		}
+	}
+}
+
+/*
+WithOutputParser sets the parser used to extract the final answer from the
+model's raw output once a task completes.
+*/
+func WithOutputParser(parser OutputParser) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.outputParser = parser
+	}
+}
+
+/*
+WithDryRun makes every task plan its tool calls instead of executing them.
+Individual requests can also opt in through TaskSendParams.DryRun.
+*/
+func WithDryRun() TaskManagerOption {
+	return func(t *TaskManager) {
+		t.dryRun = true
+	}
+}
+
+/*
+TerminateOnTool completes a task as soon as the named tool returns a result
+the predicate accepts, with that result, instead of handing it back to the
+model for another turn. A nil predicate accepts any successful result.
+*/
+func TerminateOnTool(toolName string, predicate func(result string) bool) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.terminators = append(t.terminators, provider.ToolTerminator{
+			Tool:      toolName,
+			Predicate: predicate,
+		})
+	}
+}
+
+/*
+WithTotalToolBudget caps the time every task may spend executing tools,
+summed over all of its tool calls, overriding the tools.budget config value.
+Once it is spent the model is asked to finalize its answer without further
+tool calls.
+*/
+func WithTotalToolBudget(budget time.Duration) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.toolBudget = budget
+	}
+}
+
+/*
+totalToolBudget returns the tool budget set with WithTotalToolBudget, or the
+tools.budget config value when none was given. Zero leaves it unbounded.
+*/
+func (manager *TaskManager) totalToolBudget() time.Duration {
+	if manager.toolBudget > 0 {
+		return manager.toolBudget
+	}
+
+	return viper.GetViper().GetDuration("tools.budget")
+}
+
+/*
+WithParallelToolCalls allows or forbids the model to make several tool calls
+in one turn, overriding the agent and skill config. Providers that cannot be
+told so execute the first call of a turn only, when it is forbidden.
+*/
+func WithParallelToolCalls(enabled bool) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.parallelTools = &enabled
+	}
+}
+
+/*
+parallelToolCalls returns the value set with WithParallelToolCalls, or the
+agent.<name>.parallelToolCalls config value when none was given. Without
+either, parallel tool calls are allowed unless one of the agent's skills sets
+skills.<id>.parallel_tool_calls to false.
+*/
+func (manager *TaskManager) parallelToolCalls() bool {
+	if manager.parallelTools != nil {
+		return *manager.parallelTools
+	}
+
+	v := viper.GetViper()
+
+	if key := fmt.Sprintf("agent.%s.parallelToolCalls", manager.agent.Name); v.IsSet(key) {
+		return v.GetBool(key)
+	}
+
+	for _, skill := range manager.agent.Skills {
+		if key := fmt.Sprintf("skills.%s.parallel_tool_calls", skill.ID); v.IsSet(key) && !v.GetBool(key) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+WithStreamToolCalls has streaming providers send a TaskToolCallEvent for
+every fragment of a tool call's arguments as it arrives, and one more once
+the call is complete, right before it runs, so clients can show a tool call
+while the model is still writing it.
+*/
+func WithStreamToolCalls() TaskManagerOption {
+	return func(t *TaskManager) {
+		t.streamCalls = true
+	}
+}
+
+/*
+healthyToolsTTL is how long the result of the prerequisite checks behind
+WithHealthyToolsOnly is reused.
+*/
+const healthyToolsTTL = time.Minute
+
+/*
+WithHealthyToolsOnly stops the agent from advertising tools whose
+prerequisites are not met, such as Azure tools without credentials or the
+docker tool without a reachable daemon. Prerequisites are checked again
+every healthyToolsTTL, so a tool that becomes available is picked up.
+*/
+func WithHealthyToolsOnly() TaskManagerOption {
+	return func(t *TaskManager) {
+		t.healthyTools = true
+	}
+}
+
+/*
+WithGlobalSystemPreamble sets a preamble that is put ahead of every task's
+system prompt, overriding the a2a.system_preamble config value. An empty
+preamble disables it.
+*/
+func WithGlobalSystemPreamble(preamble string) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.preamble = &preamble
+	}
+}
+
+/*
+WithTaskDebug keeps a bounded debug log on every task, recording provider
+calls, tool calls, state transitions and their timings. The log holds the
+most recent limit events, or 100 when no limit is given, and is only
+returned by tasks/get to authenticated callers that set includeDebug.
+*/
+func WithTaskDebug(limit ...int) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.debug = true
+		t.debugLimit = defaultDebugLimit
+
+		if len(limit) > 0 && limit[0] > 0 {
+			t.debugLimit = limit[0]
+		}
+	}
+}
+
+/*
+WithMaxArtifacts caps the number of artifacts retained on a task.
+A value of zero or less disables the cap.
+*/
+func WithMaxArtifacts(n int) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.maxArtifacts = n
+	}
+}
+
+/*
+WithArtifactOverflowPolicy selects how the artifact cap is enforced.
+The default is ArtifactOverflowReject.
+*/
+func WithArtifactOverflowPolicy(policy ArtifactOverflowPolicy) TaskManagerOption {
+	return func(t *TaskManager) {
+		t.artifactPolicy = policy
+	}
+}
@@ -28,6 +28,10 @@ func (m *mockOpenAIProvider) Generate(ctx context.Context, params *provider.Prov
 	return ch
 }
 
+func (m *mockOpenAIProvider) Capabilities() provider.ProviderCapabilities {
+	return provider.ProviderCapabilities{Streaming: true, Tools: true}
+}
+
 func NewMockOpenAIProvider() *mockOpenAIProvider {
 	return &mockOpenAIProvider{}
 }
@@ -83,6 +87,7 @@ func (s *taskStoreMockForTesting) Update(ctx context.Context, task *a2a.Task, op
 type controllableMockProvider struct {
 	generateFunc       func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response
 	lastGenerateParams *provider.ProviderParams
+	capabilities       *provider.ProviderCapabilities
 	mu                 sync.Mutex // For thread-safe access to lastGenerateParams
 }
 
@@ -98,6 +103,16 @@ func (m *controllableMockProvider) Generate(ctx context.Context, params *provide
 	return ch
 }
 
+// Capabilities reports full support unless the test narrowed it down.
+func (m *controllableMockProvider) Capabilities() provider.ProviderCapabilities {
+	if m.capabilities != nil {
+		return *m.capabilities
+	}
+	return provider.ProviderCapabilities{
+		Streaming: true, Tools: true, Vision: true, JSONMode: true, Reasoning: true, Embeddings: true,
+	}
+}
+
 func (m *controllableMockProvider) Embed(ctx context.Context, text string) ([]float32, error) {
 	return nil, nil // Mock implementation, not used by SendTask
 }
@@ -120,7 +135,8 @@ func TestNewTaskManager(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(tm, ShouldNotBeNil)
 				So(tm.agent, ShouldEqual, card)
-				So(tm.taskStore, ShouldResemble, store) // Use ShouldResemble for interface vs concrete mock comparison
+				// The store is wrapped to number the revisions of every task.
+				So(tm.taskStore.(*revisionStore).TaskStore, ShouldEqual, store)
 				So(tm.provider, ShouldEqual, prov)
 			})
 		})
@@ -223,6 +239,32 @@ func TestHandleUpdate(t *testing.T) {
 			})
 		})
 
+		Convey("When handleUpdate receives updates carrying metadata", func() {
+			task := a2a.NewTask("test-agent-for-status")
+			task.Status.Message.Metadata = map[string]any{"name": "planner"}
+			task.Metadata["schema"] = map[string]any{"type": "object", "required": []string{"answer"}}
+
+			err := manager.handleUpdate(task, jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status:   a2a.TaskStatus{State: a2a.TaskStateWorking, Message: a2a.NewTextMessage("updater", "working")},
+				Metadata: map[string]any{"usage": map[string]any{"prompt_tokens": 10}},
+			}})
+			So(err, ShouldBeNil)
+
+			err = manager.handleUpdate(task, jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+				Artifact: a2a.NewFileArtifact("a.txt", "text/plain", "data"),
+				Metadata: map[string]any{"usage": map[string]any{"completion_tokens": 5}},
+			}})
+			So(err, ShouldBeNil)
+
+			Convey("Then earlier keys should survive and nested maps should merge", func() {
+				So(task.Metadata["schema"], ShouldNotBeNil)
+				So(task.Metadata["usage"], ShouldResemble, map[string]any{
+					"prompt_tokens": 10, "completion_tokens": 5,
+				})
+				So(task.Status.Message.Metadata["name"], ShouldEqual, "planner")
+			})
+		})
+
 		Convey("When handleUpdate receives a chunk with an unknown result type", func() {
 			task := a2a.NewTask("test-agent-for-unknown")
 			originalTaskBytes, marshalErr := json.Marshal(task)
@@ -507,6 +549,146 @@ func TestSendTask(t *testing.T) {
 	})
 }
 
+func TestCompleteStreaming(t *testing.T) {
+	Convey("Given a TaskManager with a provider that can stream or complete", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentCompleteStreaming"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for CompleteStreaming testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		sendParams := a2a.TaskSendParams{
+			ID:      "task-id-for-complete-streaming",
+			Message: *a2a.NewTextMessage("user", "say hello"),
+		}
+
+		updates := 0
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+			updateFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+				updates++
+				return nil
+			},
+		}
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response, 4)
+			if params.Stream {
+				ch <- a2a.NewArtifactResult(params.Task.ID, a2a.NewTextPart("Hello, "))
+				ch <- a2a.NewArtifactResult(params.Task.ID, a2a.NewTextPart("world!"))
+			}
+			ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage("assistant", "Hello, world!")},
+			}}
+			close(ch)
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(prov))
+		So(initErr, ShouldBeNil)
+
+		Convey("When the task is completed with and without streaming", func() {
+			completed, err := manager.SendTask(context.Background(), sendParams)
+			So(err, ShouldBeNil)
+			So(prov.lastGenerateParams.Stream, ShouldBeFalse)
+			So(updates, ShouldEqual, 0)
+
+			streamed, err := manager.CompleteStreaming(context.Background(), sendParams)
+			So(err, ShouldBeNil)
+			So(prov.lastGenerateParams.Stream, ShouldBeTrue)
+
+			Convey("Then both should return the same final text", func() {
+				So(streamed.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+				So(streamed.Status.Message.String(), ShouldEqual, completed.Status.Message.String())
+			})
+
+			Convey("Then the streaming run should persist every chunk", func() {
+				So(updates, ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestEnforceArtifactLimit(t *testing.T) {
+	Convey("Given a TaskManager with an artifact cap and a provider emitting more artifacts than the cap", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentArtifactLimit"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for artifact limit testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		sendParams := a2a.TaskSendParams{
+			ID:      "task-id-for-artifact-limit",
+			Message: *a2a.NewTextMessage("user", "produce artifacts"),
+		}
+
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+		}
+
+		newArtifact := func(name string, last bool) a2a.Artifact {
+			return a2a.Artifact{Name: &name, Parts: []a2a.Part{a2a.NewTextPart(name)}, LastChunk: &last}
+		}
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response, 6)
+			for i := 1; i <= 4; i++ {
+				ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{Artifact: newArtifact(fmt.Sprintf("artifact-%d", i), false)}}
+			}
+			ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{Artifact: newArtifact("final", true)}}
+			close(ch)
+			return ch
+		}
+
+		names := func(task *a2a.Task) []string {
+			out := make([]string, 0, len(task.Artifacts))
+			for _, artifact := range task.Artifacts {
+				out = append(out, *artifact.Name)
+			}
+			return out
+		}
+
+		Convey("When the reject policy is used", func() {
+			manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(prov), WithMaxArtifacts(3))
+			So(initErr, ShouldBeNil)
+			task, err := manager.SendTask(context.Background(), sendParams)
+
+			Convey("Then the oldest artifacts and the final result should be kept", func() {
+				So(err, ShouldBeNil)
+				So(names(task), ShouldResemble, []string{"artifact-1", "artifact-2", "final"})
+				So(task.Status.Message.String(), ShouldContainSubstring, "artifact limit of 3 reached")
+			})
+		})
+
+		Convey("When the roll-off policy is used", func() {
+			manager, initErr := NewTaskManager(
+				agentCard,
+				WithTaskStore(store),
+				WithProvider(prov),
+				WithMaxArtifacts(3),
+				WithArtifactOverflowPolicy(ArtifactOverflowRollOff),
+			)
+			So(initErr, ShouldBeNil)
+			task, err := manager.SendTask(context.Background(), sendParams)
+
+			Convey("Then the newest artifacts and the final result should be kept", func() {
+				So(err, ShouldBeNil)
+				So(names(task), ShouldResemble, []string{"artifact-3", "artifact-4", "final"})
+			})
+		})
+	})
+}
+
 func TestStreamTask(t *testing.T) {
 	Convey("Given a TaskManager with controllable store and provider", t, func() {
 		agentName := "TestAgentStreamTask"
@@ -811,6 +993,45 @@ func TestCancelTask(t *testing.T) {
 				So(err, ShouldEqual, expectedErr)
 			})
 		})
+
+		Convey("When canceling with a reason and requester", func() {
+			stored := []a2a.Task{{ID: taskID, Status: a2a.TaskStatus{State: a2a.TaskStateWorking}}}
+			store := &taskStoreMockForTesting{
+				cancelFunc: func(ctx context.Context, id string) *errors.RpcError {
+					stored[len(stored)-1].Status.State = a2a.TaskStateCanceled
+					return nil
+				},
+				getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+					return stored[len(stored)-1:], nil
+				},
+				updateFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+					stored = append(stored, *task)
+					return nil
+				},
+			}
+			manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(mockProvider))
+			So(initErr, ShouldBeNil)
+
+			event, err := manager.CancelTaskWithReason(context.Background(), taskID, "user aborted", "alice")
+			So(err, ShouldBeNil)
+
+			Convey("Then the fetched task's status should carry them", func() {
+				fetched, err := manager.GetTask(context.Background(), taskID, 0)
+				So(err, ShouldBeNil)
+				So(fetched.Status.State, ShouldEqual, a2a.TaskStateCanceled)
+				So(fetched.Status.Message.String(), ShouldEqual, "task canceled: user aborted")
+				So(fetched.Status.Message.Metadata["reason"], ShouldEqual, "user aborted")
+				So(fetched.Status.Message.Metadata["requester"], ShouldEqual, "alice")
+			})
+
+			Convey("Then the canceled event should carry them too", func() {
+				So(event.ID, ShouldEqual, taskID)
+				So(event.Final, ShouldBeTrue)
+				So(event.Status.State, ShouldEqual, a2a.TaskStateCanceled)
+				So(event.Metadata["reason"], ShouldEqual, "user aborted")
+				So(event.Metadata["requester"], ShouldEqual, "alice")
+			})
+		})
 	})
 }
 
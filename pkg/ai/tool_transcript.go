@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+addToolTranscript attaches the tool calls the provider made to the task as
+an artifact, in the order they ran, with their arguments, their result or
+error, and how long they took. Being stored with the task, it comes back
+with tasks/get. Tasks that did not call any tool get no transcript.
+*/
+func (manager *TaskManager) addToolTranscript(task *a2a.Task, params *provider.ProviderParams) {
+	if len(params.ToolTranscript) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	calls := make([]any, 0, len(params.ToolTranscript))
+
+	for i, call := range params.ToolTranscript {
+		outcome := call.Result
+
+		if call.Error != "" {
+			outcome = "error: " + call.Error
+		}
+
+		fmt.Fprintf(&sb, "%d. %s %v (%s)\n%s\n", i+1, call.Name, call.Arguments, call.Duration.Round(time.Millisecond), outcome)
+
+		record := map[string]any{
+			"id":         call.ID,
+			"name":       call.Name,
+			"arguments":  call.Arguments,
+			"startedAt":  call.StartedAt.Format(time.RFC3339Nano),
+			"durationMs": call.Duration.Milliseconds(),
+		}
+
+		if call.Error != "" {
+			record["error"] = call.Error
+		} else {
+			record["result"] = call.Result
+		}
+
+		calls = append(calls, record)
+	}
+
+	name := "tool_transcript"
+	description := "Tool calls made while working on the task, in order."
+	lastChunk := true
+
+	task.AddArtifact(a2a.Artifact{
+		Name:        &name,
+		Description: &description,
+		Parts: []a2a.Part{
+			a2a.NewTextPart(strings.TrimSpace(sb.String())),
+			{Type: a2a.PartTypeData, Data: map[string]any{"toolCalls": calls}},
+		},
+		LastChunk: &lastChunk,
+	})
+}
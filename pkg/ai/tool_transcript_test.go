@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+func TestAddToolTranscript(t *testing.T) {
+	Convey("Given a TaskManager whose provider makes two tool calls", t, func() {
+		agentCard := &a2a.AgentCard{Name: "TestAgentToolTranscript"}
+		vip := viper.GetViper()
+		systemMsgKey := fmt.Sprintf("agent.%s.system", agentCard.Name)
+		originalSystemMessage := vip.GetString(systemMsgKey)
+		vip.Set(systemMsgKey, "Default system message for transcript testing")
+		defer vip.Set(systemMsgKey, originalSystemMessage)
+
+		var stored *a2a.Task
+
+		store := &taskStoreMockForTesting{
+			getFunc: func(ctx context.Context, id string, hl int) ([]a2a.Task, *errors.RpcError) {
+				return nil, errors.ErrTaskNotFound
+			},
+			createFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError { return nil },
+			updateFunc: func(ctx context.Context, task *a2a.Task) *errors.RpcError {
+				stored = task
+				return nil
+			},
+		}
+
+		started := time.Now()
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response, 1)
+
+			params.ToolTranscript = append(params.ToolTranscript,
+				provider.ToolCallRecord{
+					ID: "call-1", Name: "calculator", Arguments: map[string]any{"expression": "2+2"},
+					Result: "4", StartedAt: started, Duration: 3 * time.Millisecond,
+				},
+				provider.ToolCallRecord{
+					ID: "call-2", Name: "fetch", Arguments: map[string]any{"url": "https://example.com"},
+					Error: "Error: connection refused", StartedAt: started.Add(5 * time.Millisecond), Duration: time.Millisecond,
+				},
+			)
+
+			ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: a2a.NewTextMessage("assistant", "The answer is 4.")},
+			}}
+			close(ch)
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(agentCard, WithTaskStore(store), WithProvider(prov))
+		So(initErr, ShouldBeNil)
+
+		Convey("When the task is sent", func() {
+			task, err := manager.SendTask(context.Background(), a2a.TaskSendParams{
+				ID:      "task-id-for-transcript",
+				Message: *a2a.NewTextMessage("user", "What is 2+2?"),
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then the transcript should list both calls in order", func() {
+				So(len(task.Artifacts), ShouldEqual, 1)
+				transcript := task.Artifacts[0]
+				So(*transcript.Name, ShouldEqual, "tool_transcript")
+
+				calls := transcript.Parts[1].Data["toolCalls"].([]any)
+				So(len(calls), ShouldEqual, 2)
+
+				first := calls[0].(map[string]any)
+				So(first["name"], ShouldEqual, "calculator")
+				So(first["arguments"], ShouldResemble, map[string]any{"expression": "2+2"})
+				So(first["result"], ShouldEqual, "4")
+				So(first["durationMs"], ShouldEqual, int64(3))
+
+				second := calls[1].(map[string]any)
+				So(second["name"], ShouldEqual, "fetch")
+				So(second["arguments"], ShouldResemble, map[string]any{"url": "https://example.com"})
+				So(second["error"], ShouldEqual, "Error: connection refused")
+			})
+
+			Convey("Then the task should be stored with the transcript for tasks/get", func() {
+				So(stored, ShouldNotBeNil)
+				So(*stored.Artifacts[len(stored.Artifacts)-1].Name, ShouldEqual, "tool_transcript")
+			})
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+/*
+Wait blocks until the task reaches a terminal state and returns it as it was
+finally stored. It follows the writes of the task through the task store's
+subscription rather than polling, and gives up with ErrTaskWaitTimeout once
+the timeout elapses. A zero timeout waits for as long as ctx allows.
+*/
+func (manager *TaskManager) Wait(
+	ctx context.Context, id string, timeout time.Duration,
+) (*a2a.Task, *errors.RpcError) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Subscribe before looking at the stored task, so a transition between
+	// the two cannot be missed.
+	subCtx, unsubscribe := context.WithCancel(ctx)
+	defer unsubscribe()
+
+	updates := make(chan a2a.Task)
+
+	if err := manager.taskStore.Subscribe(subCtx, manager.agent.Name+"/"+id, updates); err != nil {
+		return nil, err
+	}
+
+	task, err := manager.GetTask(ctx, id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsTerminalState(task.Status.State) {
+		return task, nil
+	}
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return nil, errors.ErrInternal.WithMessagef("subscription to task %s ended before it finished", id)
+			}
+
+			task = &update
+
+			if IsTerminalState(task.Status.State) {
+				return task, nil
+			}
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, errors.ErrTaskWaitTimeout.WithMessagef(
+					"timed out after %s waiting for task %s, last seen %s",
+					timeout, id, task.Status.State,
+				)
+			}
+
+			return nil, errors.ErrInternal.WithMessagef("stopped waiting for task %s: %v", id, ctx.Err())
+		}
+	}
+}
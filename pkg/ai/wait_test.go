@@ -0,0 +1,177 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+// publishingTaskStore keeps the latest version of every task and hands each
+// write to the subscribers of the task, like the real stores do.
+type publishingTaskStore struct {
+	mockTaskStore
+	mu    sync.Mutex
+	tasks map[string][]byte
+	subs  map[string][]chan a2a.Task
+	ctxs  map[chan a2a.Task]context.Context
+}
+
+func newPublishingTaskStore() *publishingTaskStore {
+	return &publishingTaskStore{
+		tasks: map[string][]byte{},
+		subs:  map[string][]chan a2a.Task{},
+		ctxs:  map[chan a2a.Task]context.Context{},
+	}
+}
+
+func (s *publishingTaskStore) Get(ctx context.Context, id string, historyLength int) ([]a2a.Task, *errors.RpcError) {
+	s.mu.Lock()
+	data, ok := s.tasks[id]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	var task a2a.Task
+	json.Unmarshal(data, &task)
+
+	return []a2a.Task{task}, nil
+}
+
+func (s *publishingTaskStore) Subscribe(ctx context.Context, id string, ch chan a2a.Task) *errors.RpcError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subs[id] = append(s.subs[id], ch)
+	s.ctxs[ch] = ctx
+
+	return nil
+}
+
+func (s *publishingTaskStore) Create(ctx context.Context, task *a2a.Task, optionals ...string) *errors.RpcError {
+	return s.Update(ctx, task, optionals...)
+}
+
+func (s *publishingTaskStore) Update(ctx context.Context, task *a2a.Task, optionals ...string) *errors.RpcError {
+	key := strings.Join(append(append([]string{}, optionals...), task.ID), "/")
+	data, _ := json.Marshal(task)
+
+	s.mu.Lock()
+	s.tasks[key] = data
+	subs := map[chan a2a.Task]context.Context{}
+	for _, ch := range s.subs[key] {
+		subs[ch] = s.ctxs[ch]
+	}
+	s.mu.Unlock()
+
+	for ch, subCtx := range subs {
+		var copied a2a.Task
+		json.Unmarshal(data, &copied)
+
+		select {
+		case ch <- copied:
+		case <-subCtx.Done():
+		}
+	}
+
+	return nil
+}
+
+func TestWait(t *testing.T) {
+	Convey("Given a task streaming asynchronously", t, func() {
+		card := &a2a.AgentCard{Name: "TestAgentWait"}
+		store := newPublishingTaskStore()
+		release := make(chan struct{})
+
+		prov := NewControllableMockProvider()
+		prov.generateFunc = func(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+			ch := make(chan jsonrpc.Response)
+			go func() {
+				defer close(ch)
+
+				ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+					ID:       params.Task.ID,
+					Artifact: a2a.Artifact{Parts: []a2a.Part{a2a.NewTextPart("thinking")}},
+				}}
+
+				select {
+				case <-release:
+				case <-ctx.Done():
+					return
+				}
+
+				ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+					ID:     params.Task.ID,
+					Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+					Final:  true,
+				}}
+			}()
+			return ch
+		}
+
+		manager, initErr := NewTaskManager(card, WithTaskStore(store), WithProvider(prov))
+		So(initErr, ShouldBeNil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		task := a2a.NewTask(card.Name)
+		task.History = append(task.History, *a2a.NewTextMessage("user", "finish eventually"))
+
+		out, err := manager.StreamTask(ctx, task)
+		So(err, ShouldBeNil)
+
+		go func() {
+			for range out {
+			}
+		}()
+
+		Convey("When waiting for it while it completes", func() {
+			type waited struct {
+				task *a2a.Task
+				err  *errors.RpcError
+			}
+
+			done := make(chan waited, 1)
+
+			go func() {
+				final, err := manager.Wait(ctx, task.ID, 2*time.Second)
+				done <- waited{final, err}
+			}()
+
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+
+			result := <-done
+
+			Convey("Then it should return the completed task", func() {
+				So(result.err, ShouldBeNil)
+				So(result.task, ShouldNotBeNil)
+				So(result.task.ID, ShouldEqual, task.ID)
+				So(result.task.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+			})
+		})
+
+		Convey("When the task does not finish within the timeout", func() {
+			final, err := manager.Wait(ctx, task.ID, 50*time.Millisecond)
+			close(release)
+
+			Convey("Then it should fail with a timeout error", func() {
+				So(final, ShouldBeNil)
+				So(err, ShouldNotBeNil)
+				So(err.Code, ShouldEqual, errors.ErrTaskWaitTimeout.Code)
+				So(err.Message, ShouldContainSubstring, "timed out")
+			})
+		})
+	})
+}
@@ -46,13 +46,36 @@ func (s *Service) getSigningKey(token *jwt.Token) (any, error) {
 
 // AuthenticateRequest authenticates an HTTP request
 func (s *Service) AuthenticateRequest(req *http.Request) error {
+	_, err := s.authenticate(req)
+	return err
+}
+
+// Subject authenticates an HTTP request and returns the subject ("sub"
+// claim) of its token, which is empty when the token carries none.
+func (s *Service) Subject(req *http.Request) (string, error) {
+	token, err := s.authenticate(req)
+	if err != nil {
+		return "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", nil
+	}
+
+	sub, _ := claims["sub"].(string)
+	return sub, nil
+}
+
+// authenticate validates the bearer token of an HTTP request.
+func (s *Service) authenticate(req *http.Request) (*jwt.Token, error) {
 	if !s.rateLimiter.Allow() {
-		return fmt.Errorf("rate limit exceeded")
+		return nil, fmt.Errorf("rate limit exceeded")
 	}
 
 	authHeader := req.Header.Get("Authorization")
 	if authHeader == "" {
-		return fmt.Errorf("missing authorization header")
+		return nil, fmt.Errorf("missing authorization header")
 	}
 
 	// Extract token from header
@@ -64,15 +87,15 @@ func (s *Service) AuthenticateRequest(req *http.Request) error {
 	// Validate token
 	token, err := jwt.Parse(tokenStr, s.getSigningKey)
 	if err != nil {
-		return fmt.Errorf("invalid token: %w", err)
+		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
 	// Check token expiration
 	if !token.Valid {
-		return fmt.Errorf("token expired")
+		return nil, fmt.Errorf("token expired")
 	}
 
-	return nil
+	return token, nil
 }
 
 // GenerateToken generates a new JWT token
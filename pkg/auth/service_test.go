@@ -74,6 +74,32 @@ func TestAuthenticateRequest(t *testing.T) {
 	})
 }
 
+func TestSubject(t *testing.T) {
+	Convey("Given a signed request", t, func() {
+		svc := NewService()
+		tok, _ := svc.GenerateToken("Bearer", jwt.MapClaims{"sub": "user1"})
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+tok.Token)
+
+		subject, err := svc.Subject(req)
+
+		Convey("Then the subject of its token is returned", func() {
+			So(err, ShouldBeNil)
+			So(subject, ShouldEqual, "user1")
+		})
+	})
+
+	Convey("Given a request without authorization header", t, func() {
+		svc := NewService()
+		subject, err := svc.Subject(httptest.NewRequest("GET", "/", nil))
+
+		Convey("Then no subject is returned", func() {
+			So(err, ShouldNotBeNil)
+			So(subject, ShouldBeEmpty)
+		})
+	})
+}
+
 func TestRefreshToken(t *testing.T) {
 	Convey("Given a valid refresh token", t, func() {
 		svc := NewService()
@@ -37,6 +37,7 @@ var (
 	ErrTaskNotFound                   = &RpcError{Code: -32000, Message: "Task not found"}
 	ErrTaskCancelled                  = &RpcError{Code: -32001, Message: "Task was cancelled"}
 	ErrTaskCreationFailed             = &RpcError{Code: -32002, Message: "Task creation failed"}
+	ErrTaskWaitTimeout                = &RpcError{Code: -32003, Message: "Timed out waiting for task"}
 	ErrPushNotificationConfigNotFound = &RpcError{Code: -32010, Message: "Push notification config not found"}
 	ErrNotImplemented                 = &RpcError{Code: -32099, Message: "Method not implemented"}
 )
@@ -0,0 +1,79 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+// IsBatch reports whether body holds a batch, a JSON array of requests,
+// rather than a single request object.
+func IsBatch(body []byte) bool {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	return len(body) > 0 && body[0] == '['
+}
+
+// IsNotification reports whether the request is a notification, a request
+// without an id that the server does not answer.
+func (request Request) IsNotification() bool {
+	return request.ID.IsZero()
+}
+
+// ServeBatch answers a batch of requests, calling handle for each of them in
+// the order they were sent. The responses keep the ids of their requests, and
+// requests that cannot be read are answered with an Invalid Request error.
+// Notifications are handled but not answered, so a batch of notifications
+// only has no responses, which the server answers with an empty body. A body
+// that is not a JSON array, or an empty one, fails with the error to answer
+// with in place of an array.
+func ServeBatch(body []byte, handle func(Request) Response) ([]Response, *Error) {
+	var batch []json.RawMessage
+
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, &Error{
+			Code:    errors.ErrParseError.Code,
+			Message: errors.ErrParseError.Message + ": " + err.Error(),
+		}
+	}
+
+	if len(batch) == 0 {
+		return nil, &Error{
+			Code:    errors.ErrInvalidRequest.Code,
+			Message: errors.ErrInvalidRequest.Message + ": empty batch",
+		}
+	}
+
+	responses := make([]Response, 0, len(batch))
+
+	for _, raw := range batch {
+		var request Request
+
+		if err := json.Unmarshal(raw, &request); err != nil || request.Method == "" {
+			message := errors.ErrInvalidRequest.Message + ": missing method"
+
+			if err != nil {
+				message = errors.ErrInvalidRequest.Message + ": " + err.Error()
+			}
+
+			responses = append(responses, Response{
+				Message: Message{MessageIdentifier: request.MessageIdentifier, JSONRPC: "2.0"},
+				Error:   &Error{Code: errors.ErrInvalidRequest.Code, Message: message},
+			})
+
+			continue
+		}
+
+		response := handle(request)
+
+		if request.IsNotification() {
+			continue
+		}
+
+		response.ID = request.ID
+		response.JSONRPC = "2.0"
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
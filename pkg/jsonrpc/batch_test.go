@@ -0,0 +1,94 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServeBatch(t *testing.T) {
+	var handled []string
+
+	handle := func(request Request) Response {
+		handled = append(handled, request.Method)
+
+		if request.Method != "echo" && request.Method != "log" {
+			return Response{Error: &Error{Code: -32601, Message: "Method not found: " + request.Method}}
+		}
+
+		return Response{Result: request.Params}
+	}
+
+	Convey("Given a batch with a valid call, an unknown method and a notification", t, func() {
+		handled = nil
+
+		body := []byte(`[
+			{"jsonrpc":"2.0","id":1,"method":"echo","params":"hello"},
+			{"jsonrpc":"2.0","id":"two","method":"nope"},
+			{"jsonrpc":"2.0","method":"log","params":"quiet"}
+		]`)
+
+		So(IsBatch(body), ShouldBeTrue)
+
+		responses, rpcErr := ServeBatch(body, handle)
+		So(rpcErr, ShouldBeNil)
+
+		Convey("Then every request should be handled in order", func() {
+			So(handled, ShouldResemble, []string{"echo", "nope", "log"})
+		})
+
+		Convey("Then only the calls should be answered, under their ids", func() {
+			So(responses, ShouldHaveLength, 2)
+			So(responses[0].ID.Equal(NumberID(1)), ShouldBeTrue)
+			So(responses[0].Result, ShouldEqual, "hello")
+			So(responses[0].Error, ShouldBeNil)
+			So(responses[1].ID.Equal(StringID("two")), ShouldBeTrue)
+			So(responses[1].Error.Code, ShouldEqual, -32601)
+		})
+
+		Convey("Then the responses should encode as an array", func() {
+			buf, err := json.Marshal(responses)
+			So(err, ShouldBeNil)
+			So(string(buf), ShouldStartWith, `[{"id":1,"jsonrpc":"2.0","result":"hello"}`)
+		})
+	})
+
+	Convey("Given a batch of only notifications", t, func() {
+		responses, rpcErr := ServeBatch([]byte(`[{"jsonrpc":"2.0","method":"log"}]`), handle)
+
+		Convey("Then nothing should be answered", func() {
+			So(rpcErr, ShouldBeNil)
+			So(responses, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given a batch with an entry that is not a request", t, func() {
+		responses, rpcErr := ServeBatch([]byte(`[1, {"jsonrpc":"2.0","id":3}]`), handle)
+
+		Convey("Then each should be answered with an Invalid Request error", func() {
+			So(rpcErr, ShouldBeNil)
+			So(responses, ShouldHaveLength, 2)
+			So(responses[0].Error.Code, ShouldEqual, -32600)
+			So(responses[0].ID.IsZero(), ShouldBeTrue)
+			So(responses[1].Error.Code, ShouldEqual, -32600)
+			So(responses[1].ID.Equal(NumberID(3)), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given an empty or malformed batch", t, func() {
+		_, emptyErr := ServeBatch([]byte(`[]`), handle)
+		_, parseErr := ServeBatch([]byte(`[{"jsonrpc"`), handle)
+
+		Convey("Then it should fail as a whole", func() {
+			So(emptyErr.Code, ShouldEqual, -32600)
+			So(parseErr.Code, ShouldEqual, -32700)
+		})
+	})
+
+	Convey("Given a single request", t, func() {
+		Convey("Then it should not be taken for a batch", func() {
+			So(IsBatch([]byte(` {"jsonrpc":"2.0","id":1,"method":"echo"}`)), ShouldBeFalse)
+		})
+	})
+}
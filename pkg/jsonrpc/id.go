@@ -0,0 +1,96 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ID is a JSON-RPC request identifier. Per the spec it is a string, a number
+// or null. The JSON it was decoded from is kept as is, so a server echoes the
+// id back in exactly the form the client sent it, and a numeric id never
+// turns into a string, or a large one into a rounded float.
+type ID struct {
+	raw json.RawMessage
+}
+
+// StringID returns an id that is sent as a JSON string.
+func StringID(id string) ID {
+	raw, _ := json.Marshal(id)
+	return ID{raw: raw}
+}
+
+// NumberID returns an id that is sent as a JSON number.
+func NumberID(id int64) ID {
+	return ID{raw: json.RawMessage(strconv.FormatInt(id, 10))}
+}
+
+// IsZero reports whether the id is absent or null, as it is on notifications
+// and on errors for requests that could not be parsed.
+func (id ID) IsZero() bool {
+	return len(id.raw) == 0 || bytes.Equal(id.raw, []byte("null"))
+}
+
+// Equal reports whether two ids are the same id. A string never equals a
+// number, even when "1" and 1 read the same.
+func (id ID) Equal(other ID) bool {
+	if id.IsZero() || other.IsZero() {
+		return id.IsZero() && other.IsZero()
+	}
+
+	return bytes.Equal(id.raw, other.raw)
+}
+
+// String returns the id as it appears in JSON.
+func (id ID) String() string {
+	if id.IsZero() {
+		return "null"
+	}
+
+	return string(id.raw)
+}
+
+// MarshalJSON writes the id in the form it was created or decoded with.
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return id.raw, nil
+}
+
+// UnmarshalJSON accepts a string, a number or null, and rejects any other
+// JSON value.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*id = ID{}
+		return nil
+	}
+
+	switch data[0] {
+	case '"':
+		var s string
+
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		// Re-encode, so escapes spelled differently still compare equal.
+		*id = StringID(s)
+		return nil
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		var n json.Number
+
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+
+		*id = ID{raw: json.RawMessage(n.String())}
+		return nil
+	}
+
+	return fmt.Errorf("jsonrpc: id must be a string, a number or null, got %s", data)
+}
@@ -0,0 +1,63 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestID(t *testing.T) {
+	Convey("Given requests with numeric and string ids", t, func() {
+		for _, tc := range []struct {
+			name string
+			id   string
+		}{
+			{"a number", `7`},
+			{"a large number", `9007199254740993`},
+			{"a string", `"7"`},
+		} {
+			Convey("When the id is "+tc.name, func() {
+				var req Request
+				err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":`+tc.id+`,"method":"tasks/get"}`), &req)
+				So(err, ShouldBeNil)
+
+				res := Response{Message: Message{MessageIdentifier: req.MessageIdentifier, JSONRPC: "2.0"}}
+				buf, err := json.Marshal(res)
+				So(err, ShouldBeNil)
+
+				Convey("Then the response should echo it unchanged", func() {
+					So(string(buf), ShouldEqual, `{"id":`+tc.id+`,"jsonrpc":"2.0"}`)
+				})
+			})
+		}
+	})
+
+	Convey("Given ids of both types", t, func() {
+		Convey("Then they should only be equal to the same id of the same type", func() {
+			So(NumberID(1).Equal(NumberID(1)), ShouldBeTrue)
+			So(StringID("1").Equal(StringID("1")), ShouldBeTrue)
+			So(NumberID(1).Equal(StringID("1")), ShouldBeFalse)
+			So(NumberID(1).Equal(NumberID(2)), ShouldBeFalse)
+			So(ID{}.Equal(ID{}), ShouldBeTrue)
+		})
+
+		Convey("Then ids spelled differently in JSON should still match", func() {
+			var escaped ID
+			So(json.Unmarshal([]byte(`"\u0061"`), &escaped), ShouldBeNil)
+			So(escaped.Equal(StringID("a")), ShouldBeTrue)
+		})
+
+		Convey("Then a notification should leave the id out", func() {
+			buf, err := json.Marshal(Request{Message: Message{JSONRPC: "2.0"}, Method: "ping"})
+			So(err, ShouldBeNil)
+			So(string(buf), ShouldNotContainSubstring, `"id"`)
+		})
+
+		Convey("Then ids that are neither strings nor numbers should be rejected", func() {
+			var id ID
+			So(json.Unmarshal([]byte(`{"a":1}`), &id), ShouldNotBeNil)
+			So(json.Unmarshal([]byte(`true`), &id), ShouldNotBeNil)
+		})
+	})
+}
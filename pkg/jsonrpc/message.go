@@ -5,7 +5,7 @@ type MessageIdentifier struct {
 	// ID is the request identifier. Can be a string, number, or null.
 	// Responses must have the same ID as the request they relate to.
 	// Notifications (requests without an expected response) should omit the ID or use null.
-	ID any `json:"id,omitempty"`
+	ID ID `json:"id,omitzero"`
 }
 
 // JSONRPCMessage represents the base interface for all JSON-RPC messages
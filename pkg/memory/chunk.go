@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+const (
+	// DefaultChunkSize is the number of tokens a memory may hold before it
+	// is split into chunks.
+	DefaultChunkSize = 512
+	// DefaultChunkOverlap is the number of tokens consecutive chunks share.
+	DefaultChunkOverlap = 64
+	// RelationPartOf links a chunk memory to the memory it was split from.
+	RelationPartOf = "part_of"
+)
+
+var tokenPattern = regexp.MustCompile(`\S+`)
+
+// WithChunking sets the maximum number of tokens embedded in one call and
+// the overlap between consecutive chunks. Longer content is stored as a
+// parent memory with one linked child memory per chunk. A size of zero
+// disables chunking.
+func WithChunking(maxTokens, overlap int) UnifiedOption {
+	return func(u *UnifiedMemory) {
+		u.chunkSize = maxTokens
+		u.chunkOverlap = overlap
+	}
+}
+
+// ChunkText splits text into chunks of at most maxTokens tokens, where each
+// chunk repeats the last overlap tokens of the one before it. Tokens are
+// approximated by whitespace-separated words, and the original formatting
+// within a chunk is kept. Text that fits in one chunk is returned as is.
+func ChunkText(text string, maxTokens, overlap int) []string {
+	spans := tokenPattern.FindAllStringIndex(text, -1)
+
+	if maxTokens <= 0 || len(spans) <= maxTokens {
+		return []string{text}
+	}
+
+	if overlap < 0 || overlap >= maxTokens {
+		overlap = 0
+	}
+
+	chunks := make([]string, 0, len(spans)/(maxTokens-overlap)+1)
+
+	for start := 0; ; start += maxTokens - overlap {
+		end := min(start+maxTokens, len(spans))
+		chunks = append(chunks, text[spans[start][0]:spans[end-1][1]])
+
+		if end == len(spans) {
+			break
+		}
+	}
+
+	return chunks
+}
+
+// storeChunked stores content too long to embed in one call as a parent
+// memory and one child memory per chunk, each linked to the parent with a
+// part_of relation. The parent is embedded as the mean of its chunks, so it
+// can be found directly as well as through its chunks.
+func (u *UnifiedMemory) storeChunked(ctx context.Context, mem Memory, chunks []string) (string, error) {
+	embeddings, err := u.embedder.EmbedBatch(ctx, chunks)
+	if err != nil {
+		return "", err
+	}
+
+	if len(embeddings) != len(chunks) {
+		return "", fmt.Errorf("embedder returned %d embeddings for %d chunks", len(embeddings), len(chunks))
+	}
+
+	model := EmbeddingModelOf(u.embedder)
+
+	parentMetadata := copyMetadata(mem.Metadata)
+	parentMetadata["chunk_count"] = len(chunks)
+
+	parentID, err := u.persist(ctx, Memory{
+		Content:        mem.Content,
+		Metadata:       parentMetadata,
+		Type:           mem.Type,
+		Embedding:      meanVector(embeddings, u.normalize),
+		EmbeddingModel: model,
+		ExpiresAt:      mem.ExpiresAt,
+		CreatedAt:      mem.CreatedAt,
+		UpdatedAt:      mem.UpdatedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for i, chunk := range chunks {
+		chunkMetadata := copyMetadata(mem.Metadata)
+		delete(chunkMetadata, ContentHashKey) // only the parent stands for the whole content
+		chunkMetadata["parent_id"] = parentID
+		chunkMetadata["chunk_index"] = i
+
+		id, err := u.persist(ctx, Memory{
+			Content:        chunk,
+			Metadata:       chunkMetadata,
+			Type:           mem.Type,
+			Embedding:      embeddings[i],
+			EmbeddingModel: model,
+			ExpiresAt:      mem.ExpiresAt,
+			CreatedAt:      mem.CreatedAt,
+			UpdatedAt:      mem.UpdatedAt,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to store chunk %d: %w", i, err)
+		}
+
+		if err := u.CreateRelation(ctx, id, parentID, RelationPartOf, map[string]any{"index": i}); err != nil {
+			return "", fmt.Errorf("failed to link chunk %d: %w", i, err)
+		}
+	}
+
+	return parentID, nil
+}
+
+// copyMetadata returns a shallow copy of metadata that is safe to extend.
+func copyMetadata(metadata map[string]any) map[string]any {
+	out := make(map[string]any, len(metadata)+2)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	return out
+}
+
+// meanVector averages the given vectors, normalizing the result when the
+// vectors themselves are normalized.
+func meanVector(vectors [][]float32, normalize bool) []float32 {
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	out := make([]float32, len(vectors[0]))
+	for _, vec := range vectors {
+		for i := range out {
+			if i < len(vec) {
+				out[i] += vec[i] / float32(len(vectors))
+			}
+		}
+	}
+
+	if normalize {
+		return Normalize(out)
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+// ConversationStrategy decides what ExtractMemories embeds and stores of a
+// task's conversation. Without one, only the last message is stored.
+type ConversationStrategy func(ctx context.Context, messages []a2a.Message) (string, error)
+
+// Distiller condenses a conversation transcript into the facts worth
+// remembering, typically with a model call.
+type Distiller func(ctx context.Context, transcript string) (string, error)
+
+// WithConversationStrategy sets the strategy ExtractMemories stores the
+// conversation with, so a long conversation is embedded as a bounded
+// representation rather than a diffuse vector of the whole transcript.
+func WithConversationStrategy(strategy ConversationStrategy) UnifiedOption {
+	return func(u *UnifiedMemory) {
+		u.conversation = strategy
+	}
+}
+
+// LastTurns stores the last turns messages of the conversation as a
+// transcript of at most maxTokens tokens, keeping the most recent ones when
+// it is longer. A maxTokens of zero leaves the transcript unbounded.
+func LastTurns(turns, maxTokens int) ConversationStrategy {
+	return func(ctx context.Context, messages []a2a.Message) (string, error) {
+		messages = conversationMessages(messages)
+
+		if turns > 0 && len(messages) > turns {
+			messages = messages[len(messages)-turns:]
+		}
+
+		return lastTokens(transcript(messages), maxTokens), nil
+	}
+}
+
+// Distilled stores what distill makes of the conversation, cut to at most
+// maxTokens tokens. When distill fails, the last maxTokens tokens of the
+// transcript are stored instead, so the conversation is still remembered.
+func Distilled(distill Distiller, maxTokens int) ConversationStrategy {
+	return func(ctx context.Context, messages []a2a.Message) (string, error) {
+		full := transcript(conversationMessages(messages))
+
+		distilled, err := distill(ctx, full)
+		if err != nil {
+			log.Warn("failed to distill conversation, storing its last turns", "error", err)
+			return lastTokens(full, maxTokens), nil
+		}
+
+		return firstTokens(strings.TrimSpace(distilled), maxTokens), nil
+	}
+}
+
+// conversationMessages drops the memories injected into the conversation,
+// so they are not stored again as part of it.
+func conversationMessages(messages []a2a.Message) []a2a.Message {
+	out := make([]a2a.Message, 0, len(messages))
+
+	for _, msg := range messages {
+		if name, _ := msg.Metadata["name"].(string); msg.Role == "system" && name == "memory" {
+			continue
+		}
+
+		out = append(out, msg)
+	}
+
+	return out
+}
+
+// transcript renders messages as one "role: text" line each.
+func transcript(messages []a2a.Message) string {
+	lines := make([]string, 0, len(messages))
+
+	for _, msg := range messages {
+		if text := strings.TrimSpace(msg.String()); text != "" {
+			lines = append(lines, msg.Role+": "+text)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// firstTokens cuts text after its first maxTokens tokens.
+func firstTokens(text string, maxTokens int) string {
+	spans := tokenPattern.FindAllStringIndex(text, -1)
+
+	if maxTokens <= 0 || len(spans) <= maxTokens {
+		return text
+	}
+
+	return text[:spans[maxTokens-1][1]]
+}
+
+// lastTokens cuts text before its last maxTokens tokens.
+func lastTokens(text string, maxTokens int) string {
+	spans := tokenPattern.FindAllStringIndex(text, -1)
+
+	if maxTokens <= 0 || len(spans) <= maxTokens {
+		return text
+	}
+
+	return text[spans[len(spans)-maxTokens][0]:]
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+type recordingEmbedder struct {
+	texts []string
+}
+
+func (m *recordingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	m.texts = append(m.texts, text)
+	return []float32{0.1}, nil
+}
+func (m *recordingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i], _ = m.Embed(ctx, text)
+	}
+	return out, nil
+}
+
+func TestConversationStrategy(t *testing.T) {
+	Convey("Given a long conversation with an injected memory", t, func() {
+		task := a2a.NewTask("tester")
+		task.AddMessage("system", "memory", "an old memory")
+
+		for i := range 50 {
+			task.AddMessage("user", "u", fmt.Sprintf("question %d about the weather in Amsterdam", i))
+			task.AddMessage("assistant", "a", fmt.Sprintf("answer %d: it rains", i))
+		}
+
+		embedder := &recordingEmbedder{}
+		vs := &mockVectorStore{}
+
+		Convey("When memories are extracted from the last turns", func() {
+			um := NewUnifiedStore(embedder, vs, nil, WithConversationStrategy(LastTurns(4, 20)))
+			So(um.ExtractMemories(context.Background(), task), ShouldBeNil)
+
+			Convey("Then the stored memory should be the bounded tail of the conversation", func() {
+				So(vs.stored, ShouldHaveLength, 1)
+
+				content := vs.stored[0].Content
+				So(len(strings.Fields(content)), ShouldBeLessThanOrEqualTo, 20)
+				So(content, ShouldEndWith, "answer 49: it rains")
+				So(content, ShouldNotContainSubstring, "question 0 ")
+				So(content, ShouldNotContainSubstring, "an old memory")
+				So(embedder.texts, ShouldResemble, []string{content})
+			})
+		})
+
+		Convey("When memories are extracted with a distiller", func() {
+			var seen string
+
+			um := NewUnifiedStore(embedder, vs, nil, WithConversationStrategy(Distilled(
+				func(ctx context.Context, transcript string) (string, error) {
+					seen = transcript
+					return "The user keeps asking about the weather in Amsterdam; it rains.", nil
+				}, 50,
+			)))
+			So(um.ExtractMemories(context.Background(), task), ShouldBeNil)
+
+			Convey("Then the distillation should be stored instead of the transcript", func() {
+				So(seen, ShouldContainSubstring, "user: question 0 about the weather")
+				So(vs.stored, ShouldHaveLength, 1)
+				So(vs.stored[0].Content, ShouldEqual, "The user keeps asking about the weather in Amsterdam; it rains.")
+				So(vs.stored[0].Type, ShouldEqual, "conversation")
+			})
+		})
+
+		Convey("When the distiller fails", func() {
+			um := NewUnifiedStore(embedder, vs, nil, WithConversationStrategy(Distilled(
+				func(ctx context.Context, transcript string) (string, error) {
+					return "", errors.New("model unavailable")
+				}, 10,
+			)))
+			So(um.ExtractMemories(context.Background(), task), ShouldBeNil)
+
+			Convey("Then the bounded tail of the transcript should be stored", func() {
+				So(vs.stored, ShouldHaveLength, 1)
+				So(strings.Fields(vs.stored[0].Content), ShouldHaveLength, 10)
+				So(vs.stored[0].Content, ShouldEndWith, "it rains")
+			})
+		})
+	})
+}
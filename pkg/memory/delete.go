@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// errNoDeleteFilters keeps DeleteByFilter from clearing a whole store when it
+// is called without filters.
+var errNoDeleteFilters = errors.New("delete by filter needs at least one filter")
+
+// validateDeleteFilters checks the filters of a DeleteByFilter call.
+func validateDeleteFilters(filters []Filter) error {
+	if len(filters) == 0 {
+		return errNoDeleteFilters
+	}
+	return validateFilters(filters)
+}
+
+// Delete removes memories from the cache.
+func (c *MemoryCache) Delete(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		delete(c.items, id)
+	}
+}
+
+// DeleteMatching removes the cached memories whose metadata matches every
+// filter.
+func (c *MemoryCache) DeleteMatching(filters []Filter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, item := range c.items {
+		if matchesFilters(item.memory.Metadata, filters) {
+			delete(c.items, id)
+		}
+	}
+}
+
+// DeleteMemories removes the memories with the given IDs, and their
+// relations, from every store. Unknown IDs are ignored.
+func (u *UnifiedMemory) DeleteMemories(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	u.dropPending(func(mem Memory) bool { return slices.Contains(ids, mem.ID) })
+
+	if u.vector != nil {
+		if err := u.vector.DeleteMemories(ctx, ids); err != nil {
+			return err
+		}
+	}
+
+	if u.graph != nil {
+		if err := u.graph.DeleteMemories(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete memories from graph store: %w", err)
+		}
+	}
+
+	u.cache.Delete(ids...)
+	return nil
+}
+
+// DeleteByFilter removes every memory whose metadata matches all of the
+// filters, such as all memories of a session, from every store. At least one
+// filter is required, so a missing filter never clears the stores.
+func (u *UnifiedMemory) DeleteByFilter(ctx context.Context, filters []Filter) error {
+	if err := validateDeleteFilters(filters); err != nil {
+		return err
+	}
+
+	u.dropPending(func(mem Memory) bool { return matchesFilters(mem.Metadata, filters) })
+
+	if u.vector != nil {
+		if err := u.vector.DeleteByFilter(ctx, filters); err != nil {
+			return err
+		}
+	}
+
+	if u.graph != nil {
+		if err := u.graph.DeleteByFilter(ctx, filters); err != nil {
+			return fmt.Errorf("failed to delete memories from graph store: %w", err)
+		}
+	}
+
+	u.cache.DeleteMatching(filters)
+	return nil
+}
+
+// dropPending removes the memories that are still waiting in the batch, so
+// they are not written after they were deleted.
+func (u *UnifiedMemory) dropPending(match func(Memory) bool) {
+	u.batchMutex.Lock()
+	defer u.batchMutex.Unlock()
+
+	u.memBatch = slices.DeleteFunc(u.memBatch, match)
+}
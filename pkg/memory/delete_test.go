@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeleteMemories(t *testing.T) {
+	Convey("Given a unified memory holding two sessions", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+		gs := NewInMemoryGraphStore()
+		um := NewUnifiedStore(&keywordEmbedder{}, vs, gs)
+
+		store := func(content, session string) string {
+			id, err := um.StoreMemory(ctx, content, map[string]any{"session": session}, "fact")
+			So(err, ShouldBeNil)
+			return id
+		}
+
+		first := store("the project uses go", "s1")
+		second := store("the alpha build runs nightly", "s1")
+		other := store("the omega deploy runs hourly", "s2")
+
+		So(um.CreateRelation(ctx, other, first, "mentions", nil), ShouldBeNil)
+
+		exists := func(id string) bool {
+			_, vecErr := vs.GetMemory(ctx, id)
+			_, graphErr := gs.GetMemory(ctx, id)
+			return vecErr == nil && graphErr == nil
+		}
+
+		Convey("When deleting by ids", func() {
+			So(um.DeleteMemories(ctx, []string{first, second, "missing"}), ShouldBeNil)
+
+			Convey("Then only those memories should be gone from both stores", func() {
+				So(exists(first), ShouldBeFalse)
+				So(exists(second), ShouldBeFalse)
+				So(exists(other), ShouldBeTrue)
+			})
+
+			Convey("Then their relations should be gone too", func() {
+				related, err := um.FindRelated(ctx, other, nil, 10)
+				So(err, ShouldBeNil)
+				So(related, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When deleting by a session filter", func() {
+			err := um.DeleteByFilter(ctx, []Filter{{Field: "session", Operator: "eq", Value: "s1"}})
+			So(err, ShouldBeNil)
+
+			Convey("Then the whole session should be gone from both stores", func() {
+				So(exists(first), ShouldBeFalse)
+				So(exists(second), ShouldBeFalse)
+				So(exists(other), ShouldBeTrue)
+
+				results, err := um.SearchSimilar(ctx, "project build deploy", SearchParams{Limit: 10})
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(results[0].ID, ShouldEqual, other)
+			})
+		})
+
+		Convey("When deleting without a filter", func() {
+			err := um.DeleteByFilter(ctx, nil)
+
+			Convey("Then it should refuse and delete nothing", func() {
+				So(err, ShouldEqual, errNoDeleteFilters)
+				So(exists(first), ShouldBeTrue)
+				So(exists(other), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestQdrantFilter(t *testing.T) {
+	Convey("Given metadata filters", t, func() {
+		Convey("When they are translated for Qdrant", func() {
+			filter, err := qdrantFilter([]Filter{
+				{Field: "session", Operator: "eq", Value: "s1"},
+				{Field: "source", Operator: "ne", Value: "web"},
+				{Field: "rank", Operator: "gte", Value: 3},
+			})
+
+			Convey("Then equality and ranges should be required and inequality excluded", func() {
+				So(err, ShouldBeNil)
+				So(filter["must"], ShouldResemble, []map[string]any{
+					{"key": "session", "match": map[string]any{"value": "s1"}},
+					{"key": "rank", "range": map[string]any{"gte": float64(3)}},
+				})
+				So(filter["must_not"], ShouldResemble, []map[string]any{
+					{"key": "source", "match": map[string]any{"value": "web"}},
+				})
+			})
+		})
+
+		Convey("When a range is asked of a string", func() {
+			_, err := qdrantFilter([]Filter{{Field: "session", Operator: "gt", Value: "s1"}})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
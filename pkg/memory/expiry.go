@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Sweeper is implemented by stores that can purge their expired memories.
+// Stores only leave expired memories out of reads, so without sweeping they
+// keep taking up space.
+type Sweeper interface {
+	Sweep(ctx context.Context) (int, error)
+}
+
+// SweepEvery sweeps the stores every interval until ctx is done, logging
+// failures instead of stopping on them. It blocks, so it is meant to run in
+// its own goroutine.
+func SweepEvery(ctx context.Context, interval time.Duration, sweepers ...Sweeper) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, sweeper := range sweepers {
+				swept, err := sweeper.Sweep(ctx)
+				if err != nil {
+					log.Warn("failed to sweep expired memories", "error", err)
+					continue
+				}
+				if swept > 0 {
+					log.Debug("swept expired memories", "count", swept)
+				}
+			}
+		}
+	}
+}
+
+// expiryAfter returns the expiry of a memory stored now with ttl, or the zero
+// time, which never expires, when ttl is not positive.
+func expiryAfter(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// unexpired returns the memories that have not expired at now.
+func unexpired(mems []Memory, now time.Time) []Memory {
+	out := make([]Memory, 0, len(mems))
+	for _, mem := range mems {
+		if !mem.Expired(now) {
+			out = append(out, mem)
+		}
+	}
+	return out
+}
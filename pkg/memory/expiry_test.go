@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/stores/qdrant"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMemoryExpiry(t *testing.T) {
+	Convey("Given a unified memory over in-memory stores", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+		gs := NewInMemoryGraphStore()
+		um := NewUnifiedStore(&mockEmbedder{}, vs, gs)
+
+		keptID, err := um.StoreMemory(ctx, "a lasting fact", nil, "fact")
+		So(err, ShouldBeNil)
+
+		scratchID, err := um.StoreMemoryWithTTL(ctx, "a scratch note", nil, "scratch", 50*time.Millisecond)
+		So(err, ShouldBeNil)
+
+		So(um.CreateRelation(ctx, keptID, scratchID, "notes", nil), ShouldBeNil)
+
+		Convey("When the scratch memory has not expired yet", func() {
+			Convey("Then it should be returned by every read", func() {
+				_, err := vs.GetMemory(ctx, scratchID)
+				So(err, ShouldBeNil)
+
+				results, err := um.SearchSimilar(ctx, "note", SearchParams{Limit: 10})
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 2)
+
+				related, err := um.FindRelated(ctx, keptID, nil, 10)
+				So(err, ShouldBeNil)
+				So(related, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the scratch memory expires mid-test", func() {
+			time.Sleep(60 * time.Millisecond)
+
+			Convey("Then reads should leave it out", func() {
+				_, err := vs.GetMemory(ctx, scratchID)
+				So(err, ShouldNotBeNil)
+
+				_, err = gs.GetMemory(ctx, scratchID)
+				So(err, ShouldNotBeNil)
+
+				results, err := um.SearchSimilar(ctx, "note", SearchParams{Limit: 10})
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(results[0].ID, ShouldEqual, keptID)
+
+				related, err := um.FindRelated(ctx, keptID, nil, 10)
+				So(err, ShouldBeNil)
+				So(related, ShouldBeEmpty)
+			})
+
+			Convey("Then a sweep should purge it and its relations", func() {
+				swept, err := um.Sweep(ctx)
+				So(err, ShouldBeNil)
+				So(swept, ShouldEqual, 2)
+
+				So(vs.order, ShouldResemble, []string{keptID})
+				So(gs.memories, ShouldContainKey, keptID)
+				So(gs.memories, ShouldNotContainKey, scratchID)
+				So(gs.relations, ShouldBeEmpty)
+
+				swept, err = um.Sweep(ctx)
+				So(err, ShouldBeNil)
+				So(swept, ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("Given a Qdrant document with an expiry in its payload", t, func() {
+		expiresAt := time.UnixMilli(time.Now().Add(-time.Second).UnixMilli())
+		doc := qdrant.NewDocument("a", "alpha", map[string]any{
+			expiresAtKey: float64(expiresAt.UnixMilli()),
+		})
+
+		Convey("Then the memory should carry the expiry and be expired", func() {
+			mem := memoryFromDocument(*doc)
+			So(mem.ExpiresAt.Equal(expiresAt), ShouldBeTrue)
+			So(mem.Expired(time.Now()), ShouldBeTrue)
+		})
+	})
+}
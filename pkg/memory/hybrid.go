@@ -0,0 +1,59 @@
+package memory
+
+import "strings"
+
+// DefaultKeywordWeight is the weight of the keyword score in a hybrid search
+// that does not set SearchParams.KeywordWeight.
+const DefaultKeywordWeight = 0.3
+
+// keywordTerms splits a query into its distinct lower-case terms.
+func keywordTerms(query string) []string {
+	seen := map[string]bool{}
+	terms := make([]string, 0)
+
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// keywordScore is the fraction of the terms that occur in the content,
+// ignoring case, from 0 when none do to 1 when all do.
+func keywordScore(content string, terms []string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	content = strings.ToLower(content)
+	found := 0
+
+	for _, term := range terms {
+		if strings.Contains(content, term) {
+			found++
+		}
+	}
+
+	return float64(found) / float64(len(terms))
+}
+
+// hybridScore blends the similarity of a memory with its keyword score:
+//
+//	score = (1 - weight) * similarity + weight * keywordScore
+//
+// With unit-length embeddings both scores lie in [0, 1], so the weight is the
+// share of the score decided by exact terms.
+func hybridScore(similarity, keyword, weight float64) float64 {
+	return (1-weight)*similarity + weight*keyword
+}
+
+// keywordWeight returns the weight of the keyword score in a hybrid search.
+func (params SearchParams) keywordWeight() float64 {
+	if params.KeywordWeight > 0 {
+		return params.KeywordWeight
+	}
+	return DefaultKeywordWeight
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHybridSearch(t *testing.T) {
+	Convey("Given an in-memory store with a semantic and a keyword match", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+
+		_, err := vs.StoreMemory(ctx, Memory{ID: "semantic", Content: "notes on the deployment pipeline", Embedding: []float32{0.6, 0.8}})
+		So(err, ShouldBeNil)
+
+		_, err = vs.StoreMemory(ctx, Memory{ID: "keyword", Content: "the build failed with E1234", Embedding: []float32{0, 1}})
+		So(err, ShouldBeNil)
+
+		query := []float32{1, 0}
+
+		Convey("When searching by vector only", func() {
+			results, err := vs.SearchSimilar(ctx, query, SearchParams{Query: "E1234"})
+
+			Convey("Then the semantic match should rank first", func() {
+				So(err, ShouldBeNil)
+				So(results[0].ID, ShouldEqual, "semantic")
+			})
+		})
+
+		Convey("When searching with a heavy keyword weight", func() {
+			results, err := vs.SearchSimilar(ctx, query, SearchParams{Hybrid: true, Query: "e1234", KeywordWeight: 0.7})
+
+			Convey("Then the keyword-only match should rank first", func() {
+				So(err, ShouldBeNil)
+				So(results[0].ID, ShouldEqual, "keyword")
+				So(results[1].ID, ShouldEqual, "semantic")
+			})
+		})
+	})
+
+	Convey("Given a Qdrant store whose text search finds a point the vector search misses", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+
+			if _, ok := body["filter"]; ok {
+				fmt.Fprint(w, `{"result":[{"id":"keyword","score":0.1,"payload":{"content":"the build failed with E1234"}}]}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"result":[{"id":"semantic","score":0.6,"payload":{"content":"notes on the deployment pipeline"}}]}`)
+		}))
+		defer ts.Close()
+
+		store := NewQdrantVectorStore(ts.URL, "mem", nil)
+
+		Convey("When running a hybrid search", func() {
+			results, err := store.SearchSimilar(context.Background(), []float32{1, 0}, SearchParams{
+				Limit: 2, Hybrid: true, Query: "E1234", KeywordWeight: 0.7,
+			})
+
+			Convey("Then both points should be merged and the keyword match ranked first", func() {
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 2)
+				So(results[0].ID, ShouldEqual, "keyword")
+				So(results[0].Metadata[ScoreKey], ShouldAlmostEqual, 0.3*0.1+0.7)
+				So(results[1].ID, ShouldEqual, "semantic")
+			})
+		})
+	})
+}
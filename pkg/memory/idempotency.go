@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Metadata keys of idempotent stores. NamespaceKey scopes the content hash,
+// so the same content can be stored once per namespace, such as per agent or
+// per session. ContentHashKey holds the hash itself.
+const (
+	NamespaceKey   = "namespace"
+	ContentHashKey = "content_hash"
+)
+
+// ContentHashStore is implemented by vector stores that index memories by
+// their content hash, so an idempotent store can find an identical memory
+// before writing a duplicate.
+type ContentHashStore interface {
+	FindByContentHash(ctx context.Context, hash string) (string, bool, error)
+}
+
+// WithIdempotent makes StoreMemory return the ID of an existing memory with
+// the same content, type and namespace instead of storing a duplicate. The
+// lookup uses the content hash index of vector stores implementing
+// ContentHashStore; other vector stores only catch duplicates that are still
+// waiting to be written.
+func WithIdempotent(idempotent bool) UnifiedOption {
+	return func(u *UnifiedMemory) {
+		u.idempotent = idempotent
+	}
+}
+
+// ContentHash returns the hash identifying a memory's content, type and
+// namespace. Each part is length-prefixed, so no two different combinations
+// hash the same.
+func ContentHash(content, memType, namespace string) string {
+	sum := sha256.New()
+
+	for _, part := range []string{content, memType, namespace} {
+		fmt.Fprintf(sum, "%d:%s", len(part), part)
+	}
+
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// contentHashOf returns the content hash of a memory.
+func contentHashOf(mem Memory) string {
+	namespace, _ := mem.Metadata[NamespaceKey].(string)
+	return ContentHash(mem.Content, mem.Type, namespace)
+}
+
+// findDuplicate returns the ID of a memory with the given content hash,
+// looking in the pending batch first and the vector store second.
+func (u *UnifiedMemory) findDuplicate(ctx context.Context, hash string) (string, bool, error) {
+	u.batchMutex.Lock()
+	for _, pending := range u.memBatch {
+		if pending.Metadata[ContentHashKey] == hash {
+			u.batchMutex.Unlock()
+			return pending.ID, true, nil
+		}
+	}
+	u.batchMutex.Unlock()
+
+	if index, ok := u.vector.(ContentHashStore); ok {
+		return index.FindByContentHash(ctx, hash)
+	}
+
+	return "", false, nil
+}
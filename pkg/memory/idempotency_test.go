@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIdempotentStore(t *testing.T) {
+	Convey("Given an idempotent unified memory", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+		um := NewUnifiedStore(&mockEmbedder{}, vs, nil, WithIdempotent(true))
+
+		firstID, err := um.StoreMemory(ctx, "the project uses go", map[string]any{NamespaceKey: "agent-a"}, "fact")
+		So(err, ShouldBeNil)
+
+		Convey("When identical content is stored again", func() {
+			secondID, err := um.StoreMemory(ctx, "the project uses go", map[string]any{NamespaceKey: "agent-a"}, "fact")
+			So(err, ShouldBeNil)
+
+			Convey("Then the existing ID should be returned and nothing stored", func() {
+				So(secondID, ShouldEqual, firstID)
+
+				all, err := vs.SearchSimilar(ctx, nil, SearchParams{})
+				So(err, ShouldBeNil)
+				So(all, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the same content is stored with another type or namespace", func() {
+			otherType, err := um.StoreMemory(ctx, "the project uses go", map[string]any{NamespaceKey: "agent-a"}, "note")
+			So(err, ShouldBeNil)
+
+			otherNamespace, err := um.StoreMemory(ctx, "the project uses go", map[string]any{NamespaceKey: "agent-b"}, "fact")
+			So(err, ShouldBeNil)
+
+			Convey("Then each should be stored as a new memory", func() {
+				So(otherType, ShouldNotEqual, firstID)
+				So(otherNamespace, ShouldNotEqual, firstID)
+
+				all, err := vs.SearchSimilar(ctx, nil, SearchParams{})
+				So(err, ShouldBeNil)
+				So(all, ShouldHaveLength, 3)
+			})
+		})
+	})
+}
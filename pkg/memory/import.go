@@ -0,0 +1,243 @@
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Formats accepted by ImportRelations.
+const (
+	ImportCSV    = "csv"
+	ImportTSV    = "tsv"
+	ImportNDJSON = "ndjson"
+)
+
+// relationTypePattern matches the relation types that are safe to use as a
+// Cypher relationship type.
+var relationTypePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// BatchGraphStore is implemented by graph stores that can create many
+// relations in one call.
+type BatchGraphStore interface {
+	CreateRelations(ctx context.Context, relations []Relation) error
+}
+
+// SkippedRow is an edge list row that ImportRelations did not import.
+type SkippedRow struct {
+	Line   int
+	Reason string
+}
+
+// RelationImportError lists the rows an import skipped. The valid rows are
+// still imported.
+type RelationImportError struct {
+	Skipped []SkippedRow
+}
+
+func (e *RelationImportError) Error() string {
+	reasons := make([]string, 0, len(e.Skipped))
+	for _, row := range e.Skipped {
+		reasons = append(reasons, fmt.Sprintf("line %d: %s", row.Line, row.Reason))
+	}
+	return fmt.Sprintf("skipped %d relation rows: %s", len(e.Skipped), strings.Join(reasons, "; "))
+}
+
+// edgeRow is one row of an edge list, as read from any of the formats.
+type edgeRow struct {
+	Line     int      `json:"-"`
+	SourceID string   `json:"source_id"`
+	TargetID string   `json:"target_id"`
+	Type     string   `json:"type"`
+	Strength *float64 `json:"strength,omitempty"`
+	err      error
+}
+
+// ImportRelations creates the relations of an edge list in bulk and returns
+// how many were created. The edge list is CSV or TSV with the columns
+// source_id, target_id, type and an optional strength, with or without a
+// header, or NDJSON objects with the same keys. Rows that cannot be parsed or
+// that refer to unknown memories are skipped and reported in a
+// *RelationImportError, alongside the count of the rows that were imported.
+func (u *UnifiedMemory) ImportRelations(ctx context.Context, r io.Reader, format string) (int, error) {
+	if u.graph == nil {
+		return 0, errors.New("relation import needs a graph store")
+	}
+
+	rows, err := readEdgeRows(r, format)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		relations []Relation
+		skipped   []SkippedRow
+		known     = map[string]bool{}
+	)
+
+	exists := func(id string) bool {
+		if found, ok := known[id]; ok {
+			return found
+		}
+		_, err := u.graph.GetMemory(ctx, id)
+		known[id] = err == nil
+		return known[id]
+	}
+
+	for _, row := range rows {
+		reason := ""
+
+		switch {
+		case row.err != nil:
+			reason = row.err.Error()
+		case row.SourceID == "" || row.TargetID == "" || row.Type == "":
+			reason = "source_id, target_id and type are required"
+		case !relationTypePattern.MatchString(row.Type):
+			reason = fmt.Sprintf("invalid relation type %q", row.Type)
+		case !exists(row.SourceID):
+			reason = fmt.Sprintf("unknown source memory %s", row.SourceID)
+		case !exists(row.TargetID):
+			reason = fmt.Sprintf("unknown target memory %s", row.TargetID)
+		}
+
+		if reason != "" {
+			skipped = append(skipped, SkippedRow{Line: row.Line, Reason: reason})
+			continue
+		}
+
+		rel := Relation{SourceID: row.SourceID, TargetID: row.TargetID, Type: row.Type}
+		if row.Strength != nil {
+			rel.Properties = map[string]any{"strength": *row.Strength}
+		}
+		relations = append(relations, rel)
+	}
+
+	if err := u.createRelations(ctx, relations); err != nil {
+		return 0, err
+	}
+
+	if len(skipped) > 0 {
+		return len(relations), &RelationImportError{Skipped: skipped}
+	}
+
+	return len(relations), nil
+}
+
+// createRelations uses the batch API of the graph store when it has one.
+func (u *UnifiedMemory) createRelations(ctx context.Context, relations []Relation) error {
+	if len(relations) == 0 {
+		return nil
+	}
+
+	if batch, ok := u.graph.(BatchGraphStore); ok {
+		return batch.CreateRelations(ctx, relations)
+	}
+
+	for _, rel := range relations {
+		if err := u.graph.CreateRelation(ctx, rel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readEdgeRows parses an edge list. Malformed rows are returned with their
+// error set, only an unreadable input fails the whole read.
+func readEdgeRows(r io.Reader, format string) ([]edgeRow, error) {
+	switch strings.ToLower(format) {
+	case ImportCSV:
+		return readDelimitedRows(r, ',')
+	case ImportTSV:
+		return readDelimitedRows(r, '\t')
+	case ImportNDJSON, "jsonl":
+		return readNDJSONRows(r)
+	default:
+		return nil, fmt.Errorf("unsupported relation import format %q", format)
+	}
+}
+
+func readDelimitedRows(r io.Reader, delimiter rune) ([]edgeRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []edgeRow
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, edgeRow{Line: parseErr.Line, err: parseErr.Err})
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if len(rows) == 0 && line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "source_id") {
+			continue
+		}
+
+		row := edgeRow{Line: line}
+
+		if len(record) < 3 || len(record) > 4 {
+			row.err = fmt.Errorf("expected 3 or 4 columns, got %d", len(record))
+			rows = append(rows, row)
+			continue
+		}
+
+		row.SourceID = strings.TrimSpace(record[0])
+		row.TargetID = strings.TrimSpace(record[1])
+		row.Type = strings.TrimSpace(record[2])
+
+		if len(record) == 4 && strings.TrimSpace(record[3]) != "" {
+			strength, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+			if err != nil {
+				row.err = fmt.Errorf("invalid strength %q", record[3])
+			}
+			row.Strength = &strength
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func readNDJSONRows(r io.Reader) ([]edgeRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []edgeRow
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := edgeRow{}
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			row = edgeRow{err: fmt.Errorf("invalid JSON: %w", err)}
+		}
+		row.Line = line
+
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
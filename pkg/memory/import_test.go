@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestImportRelations(t *testing.T) {
+	Convey("Given a unified memory with three memories in its graph", t, func() {
+		ctx := context.Background()
+		gs := NewInMemoryGraphStore()
+		um := NewUnifiedStore(&mockEmbedder{}, NewInMemoryVectorStore(), gs)
+
+		for _, id := range []string{"a", "b", "c"} {
+			_, err := gs.StoreMemory(ctx, Memory{ID: id, Content: id})
+			So(err, ShouldBeNil)
+		}
+
+		Convey("When importing a CSV edge list with invalid rows", func() {
+			edges := strings.Join([]string{
+				"source_id,target_id,type,strength",
+				"a,b,knows,0.8",
+				"b,c,knows",
+				"a,missing,knows,0.5",
+				"a,c,not a type,1",
+				"c,a,cites,strong",
+				"a,b",
+			}, "\n")
+
+			count, err := um.ImportRelations(ctx, strings.NewReader(edges), ImportCSV)
+
+			Convey("Then the valid edges should exist", func() {
+				So(count, ShouldEqual, 2)
+
+				related, err := um.FindRelated(ctx, "a", []string{"knows"}, 10)
+				So(err, ShouldBeNil)
+				So(related, ShouldHaveLength, 1)
+				So(related[0].ID, ShouldEqual, "b")
+
+				related, err = um.FindRelated(ctx, "b", nil, 10)
+				So(err, ShouldBeNil)
+				So(related, ShouldHaveLength, 1)
+				So(related[0].ID, ShouldEqual, "c")
+			})
+
+			Convey("Then the invalid rows should be reported by line", func() {
+				var importErr *RelationImportError
+				So(errors.As(err, &importErr), ShouldBeTrue)
+				So(importErr.Skipped, ShouldHaveLength, 4)
+				So(importErr.Skipped[0].Line, ShouldEqual, 4)
+				So(importErr.Skipped[0].Reason, ShouldContainSubstring, "missing")
+				So(importErr.Skipped[1].Line, ShouldEqual, 5)
+				So(importErr.Skipped[2].Line, ShouldEqual, 6)
+				So(importErr.Skipped[3].Line, ShouldEqual, 7)
+			})
+		})
+
+		Convey("When importing an NDJSON edge list", func() {
+			edges := `{"source_id":"c","target_id":"b","type":"cites","strength":0.3}
+{"source_id":"c","target_id":"a"
+`
+
+			count, err := um.ImportRelations(ctx, strings.NewReader(edges), ImportNDJSON)
+
+			Convey("Then the valid edge should be created with its strength", func() {
+				So(count, ShouldEqual, 1)
+				So(gs.relations, ShouldHaveLength, 1)
+				So(gs.relations[0].Properties["strength"], ShouldEqual, 0.3)
+
+				var importErr *RelationImportError
+				So(errors.As(err, &importErr), ShouldBeTrue)
+				So(importErr.Skipped[0].Line, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When importing an unknown format", func() {
+			_, err := um.ImportRelations(ctx, strings.NewReader(""), "xml")
+
+			Convey("Then the import should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,264 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InMemoryGraphStore implements GraphStore using maps guarded by a mutex.
+// It is intended for unit tests and local development servers.
+type InMemoryGraphStore struct {
+	mu        sync.RWMutex
+	memories  map[string]Memory
+	relations []Relation
+}
+
+// graphSnapshot is the serialized form of an InMemoryGraphStore.
+type graphSnapshot struct {
+	Memories  []Memory   `json:"memories"`
+	Relations []Relation `json:"relations"`
+}
+
+// NewInMemoryGraphStore creates an empty in-memory graph store.
+func NewInMemoryGraphStore() *InMemoryGraphStore {
+	return &InMemoryGraphStore{
+		memories:  make(map[string]Memory),
+		relations: make([]Relation, 0),
+	}
+}
+
+// StoreMemory stores a memory node, generating an ID when none is set.
+func (s *InMemoryGraphStore) StoreMemory(ctx context.Context, mem Memory) (string, error) {
+	if mem.ID == "" {
+		mem.ID = uuid.NewString()
+	}
+
+	stampCreated(&mem)
+
+	s.mu.Lock()
+	s.memories[mem.ID] = mem
+	s.mu.Unlock()
+
+	return mem.ID, nil
+}
+
+// CreateRelation adds a directed edge between two memories.
+func (s *InMemoryGraphStore) CreateRelation(ctx context.Context, rel Relation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.relations {
+		if existing.SourceID == rel.SourceID && existing.TargetID == rel.TargetID && existing.Type == rel.Type {
+			s.relations[i] = rel
+			return nil
+		}
+	}
+
+	s.relations = append(s.relations, rel)
+	return nil
+}
+
+// CreateRelations implements BatchGraphStore.
+func (s *InMemoryGraphStore) CreateRelations(ctx context.Context, relations []Relation) error {
+	for _, rel := range relations {
+		if err := s.CreateRelation(ctx, rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMemory retrieves a memory node by ID. Expired memories are not found.
+func (s *InMemoryGraphStore) GetMemory(ctx context.Context, id string) (Memory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mem, ok := s.memories[id]
+	if !ok || mem.Expired(time.Now()) {
+		return Memory{}, fmt.Errorf("memory %s not found", id)
+	}
+	return mem, nil
+}
+
+// FindRelated returns the targets of outgoing relations from id, optionally
+// restricted to the given relation types. Expired targets are left out.
+func (s *InMemoryGraphStore) FindRelated(ctx context.Context, id string, relationTypes []string, limit int) ([]Memory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	out := make([]Memory, 0)
+	for _, rel := range s.relations {
+		if rel.SourceID != id {
+			continue
+		}
+		if len(relationTypes) > 0 && !containsString(relationTypes, rel.Type) {
+			continue
+		}
+		mem, ok := s.memories[rel.TargetID]
+		if !ok || mem.Expired(now) {
+			continue
+		}
+		out = append(out, mem)
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// QueryGraph is not supported by the in-memory store, which has no query language.
+func (s *InMemoryGraphStore) QueryGraph(ctx context.Context, query string, params map[string]any) ([]Memory, error) {
+	return nil, fmt.Errorf("query graph is not supported by the in-memory graph store")
+}
+
+// UpdateMemory replaces a stored memory node, keeping its CreatedAt and
+// every relation touching it.
+func (s *InMemoryGraphStore) UpdateMemory(ctx context.Context, mem Memory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.memories[mem.ID]
+	if !ok {
+		return fmt.Errorf("memory %s not found", mem.ID)
+	}
+
+	s.memories[mem.ID] = stampUpdated(existing, mem)
+	return nil
+}
+
+// DeleteMemory removes a memory node and every relation touching it.
+func (s *InMemoryGraphStore) DeleteMemory(ctx context.Context, id string) error {
+	return s.DeleteMemories(ctx, []string{id})
+}
+
+// DeleteMemories removes the memory nodes with the given IDs and every
+// relation touching them.
+func (s *InMemoryGraphStore) DeleteMemories(ctx context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWhere(func(mem Memory) bool { return slices.Contains(ids, mem.ID) })
+	return nil
+}
+
+// DeleteByFilter removes every memory node whose metadata matches all
+// filters, and every relation touching them.
+func (s *InMemoryGraphStore) DeleteByFilter(ctx context.Context, filters []Filter) error {
+	if err := validateDeleteFilters(filters); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWhere(func(mem Memory) bool { return matchesFilters(mem.Metadata, filters) })
+	return nil
+}
+
+// deleteWhere removes the matching nodes and their relations and returns how
+// many nodes it removed. The caller holds the lock.
+func (s *InMemoryGraphStore) deleteWhere(match func(Memory) bool) int {
+	deleted := map[string]bool{}
+
+	for id, mem := range s.memories {
+		if match(mem) {
+			delete(s.memories, id)
+			deleted[id] = true
+		}
+	}
+
+	if len(deleted) == 0 {
+		return 0
+	}
+
+	kept := s.relations[:0]
+	for _, rel := range s.relations {
+		if !deleted[rel.SourceID] && !deleted[rel.TargetID] {
+			kept = append(kept, rel)
+		}
+	}
+	s.relations = kept
+	return len(deleted)
+}
+
+// DeleteRelation removes a single relation.
+func (s *InMemoryGraphStore) DeleteRelation(ctx context.Context, source, target, relationType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, rel := range s.relations {
+		if rel.SourceID == source && rel.TargetID == target && rel.Type == relationType {
+			s.relations = append(s.relations[:i], s.relations[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// Sweep implements Sweeper, deleting every expired memory node and every
+// relation touching one.
+func (s *InMemoryGraphStore) Sweep(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	return s.deleteWhere(func(mem Memory) bool { return mem.Expired(now) }), nil
+}
+
+// Ping always succeeds for the in-memory store.
+func (s *InMemoryGraphStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Snapshot serializes the full state of the store, including relations.
+func (s *InMemoryGraphStore) Snapshot() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := graphSnapshot{
+		Memories:  make([]Memory, 0, len(s.memories)),
+		Relations: make([]Relation, len(s.relations)),
+	}
+	for _, mem := range s.memories {
+		snap.Memories = append(snap.Memories, mem)
+	}
+	sort.Slice(snap.Memories, func(i, j int) bool {
+		return snap.Memories[i].ID < snap.Memories[j].ID
+	})
+	copy(snap.Relations, s.relations)
+
+	return json.Marshal(snap)
+}
+
+// RestoreSnapshot replaces the state of the store with a snapshot
+// previously produced by Snapshot.
+func (s *InMemoryGraphStore) RestoreSnapshot(data []byte) error {
+	var snap graphSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to unmarshal graph store snapshot: %w", err)
+	}
+
+	memories := make(map[string]Memory, len(snap.Memories))
+	for _, mem := range snap.Memories {
+		memories[mem.ID] = mem
+	}
+
+	if snap.Relations == nil {
+		snap.Relations = make([]Relation, 0)
+	}
+
+	s.mu.Lock()
+	s.memories = memories
+	s.relations = snap.Relations
+	s.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInMemoryGraphStoreSnapshot(t *testing.T) {
+	Convey("Given a populated in-memory graph store", t, func() {
+		ctx := context.Background()
+		store := NewInMemoryGraphStore()
+
+		_, err := store.StoreMemory(ctx, Memory{ID: "a", Content: "alpha", Type: "fact"})
+		So(err, ShouldBeNil)
+		_, err = store.StoreMemory(ctx, Memory{ID: "b", Content: "beta", Type: "fact"})
+		So(err, ShouldBeNil)
+		_, err = store.StoreMemory(ctx, Memory{ID: "c", Content: "gamma", Type: "fact"})
+		So(err, ShouldBeNil)
+
+		So(store.CreateRelation(ctx, Relation{SourceID: "a", TargetID: "b", Type: "knows"}), ShouldBeNil)
+		So(store.CreateRelation(ctx, Relation{SourceID: "a", TargetID: "c", Type: "likes"}), ShouldBeNil)
+
+		Convey("When it is snapshotted and restored into a fresh store", func() {
+			data, err := store.Snapshot()
+			So(err, ShouldBeNil)
+
+			restored := NewInMemoryGraphStore()
+			So(restored.RestoreSnapshot(data), ShouldBeNil)
+
+			Convey("Then FindRelated should return the same results", func() {
+				for _, relTypes := range [][]string{nil, {"knows"}, {"likes"}} {
+					expected, err := store.FindRelated(ctx, "a", relTypes, 10)
+					So(err, ShouldBeNil)
+					actual, err := restored.FindRelated(ctx, "a", relTypes, 10)
+					So(err, ShouldBeNil)
+					So(actual, ShouldResemble, expected)
+				}
+			})
+		})
+
+		Convey("When restoring invalid data", func() {
+			err := NewInMemoryGraphStore().RestoreSnapshot([]byte("not json"))
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestInMemoryVectorStoreSnapshot(t *testing.T) {
+	Convey("Given a populated in-memory vector store", t, func() {
+		ctx := context.Background()
+		store := NewInMemoryVectorStore()
+
+		So(store.StoreMemories(ctx, []Memory{
+			{ID: "a", Content: "alpha", Type: "fact", Embedding: []float32{1, 0, 0}},
+			{ID: "b", Content: "beta", Type: "fact", Embedding: []float32{0, 1, 0}},
+			{ID: "c", Content: "gamma", Type: "note", Embedding: []float32{0.5, 0.5, 0}},
+		}), ShouldBeNil)
+
+		Convey("When it is snapshotted and restored into a fresh store", func() {
+			data, err := store.Snapshot()
+			So(err, ShouldBeNil)
+
+			restored := NewInMemoryVectorStore()
+			So(restored.RestoreSnapshot(data), ShouldBeNil)
+
+			Convey("Then embeddings should survive the round-trip", func() {
+				mem, err := restored.GetMemory(ctx, "c")
+				So(err, ShouldBeNil)
+				So(mem.Embedding, ShouldResemble, []float32{0.5, 0.5, 0})
+			})
+
+			Convey("Then SearchSimilar should return the same results", func() {
+				query := []float32{0.9, 0.1, 0}
+				for _, params := range []SearchParams{{Limit: 3}, {Limit: 1}, {Limit: 3, Types: []string{"fact"}}} {
+					expected, err := store.SearchSimilar(ctx, query, params)
+					So(err, ShouldBeNil)
+					actual, err := restored.SearchSimilar(ctx, query, params)
+					So(err, ShouldBeNil)
+					So(actual, ShouldResemble, expected)
+				}
+			})
+		})
+	})
+}
+
+func TestInMemoryVectorStoreRanking(t *testing.T) {
+	Convey("Given an in-memory vector store with three distinct vectors", t, func() {
+		ctx := context.Background()
+		store := NewInMemoryVectorStore()
+
+		// The content is chosen to mislead any ranking by substring.
+		So(store.StoreMemories(ctx, []Memory{
+			{ID: "far", Content: "query query query", Type: "fact", Embedding: []float32{0, 1, 0}},
+			{ID: "near", Content: "unrelated", Type: "fact", Embedding: []float32{4, 1, 0}, Metadata: map[string]any{"source": "web", "rank": 2}},
+			{ID: "middle", Content: "query", Type: "note", Embedding: []float32{1, 1, 0}, Metadata: map[string]any{"source": "docs", "rank": 5}},
+		}), ShouldBeNil)
+
+		query := []float32{1, 0, 0}
+
+		Convey("When searching by the query vector", func() {
+			results, err := store.SearchSimilar(ctx, query, SearchParams{Limit: 3})
+			So(err, ShouldBeNil)
+
+			Convey("Then the closest vector should rank first, whatever its length", func() {
+				So(results, ShouldHaveLength, 3)
+				So(results[0].ID, ShouldEqual, "near")
+				So(results[1].ID, ShouldEqual, "middle")
+				So(results[2].ID, ShouldEqual, "far")
+			})
+		})
+
+		Convey("When the limit is smaller than the matches", func() {
+			results, err := store.SearchSimilar(ctx, query, SearchParams{Limit: 1})
+
+			Convey("Then only the best match should be returned", func() {
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(results[0].ID, ShouldEqual, "near")
+			})
+		})
+
+		Convey("When filtering by type and metadata", func() {
+			byType, err := store.SearchSimilar(ctx, query, SearchParams{Types: []string{"note"}})
+			So(err, ShouldBeNil)
+
+			byMetadata, err := store.SearchSimilar(ctx, query, SearchParams{
+				Filters: []Filter{{Field: "rank", Operator: "gte", Value: 3}},
+			})
+			So(err, ShouldBeNil)
+
+			missing, err := store.SearchSimilar(ctx, query, SearchParams{
+				Filters: []Filter{{Field: "source", Operator: "ne", Value: "web"}},
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then only the matching memories should be ranked", func() {
+				So(byType, ShouldHaveLength, 1)
+				So(byType[0].ID, ShouldEqual, "middle")
+
+				So(byMetadata, ShouldHaveLength, 1)
+				So(byMetadata[0].ID, ShouldEqual, "middle")
+
+				So(missing, ShouldHaveLength, 2)
+				So(missing[0].ID, ShouldEqual, "middle")
+				So(missing[1].ID, ShouldEqual, "far")
+			})
+		})
+
+		Convey("When filtering with an unknown operator", func() {
+			_, err := store.SearchSimilar(ctx, query, SearchParams{
+				Filters: []Filter{{Field: "rank", Operator: "like", Value: 1}},
+			})
+
+			Convey("Then the search should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
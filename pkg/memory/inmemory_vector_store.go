@@ -0,0 +1,424 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InMemoryVectorStore implements VectorStore using a map guarded by a mutex.
+// It is intended for unit tests and local development servers.
+type InMemoryVectorStore struct {
+	mu       sync.RWMutex
+	memories map[string]Memory
+	order    []string
+}
+
+// vectorSnapshot is the serialized form of an InMemoryVectorStore.
+type vectorSnapshot struct {
+	Memories []Memory `json:"memories"`
+}
+
+// NewInMemoryVectorStore creates an empty in-memory vector store.
+func NewInMemoryVectorStore() *InMemoryVectorStore {
+	return &InMemoryVectorStore{
+		memories: make(map[string]Memory),
+		order:    make([]string, 0),
+	}
+}
+
+// StoreMemory stores a memory, generating an ID when none is set.
+func (s *InMemoryVectorStore) StoreMemory(ctx context.Context, mem Memory) (string, error) {
+	if mem.ID == "" {
+		mem.ID = uuid.NewString()
+	}
+
+	stampCreated(&mem)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.memories[mem.ID]; !exists {
+		s.order = append(s.order, mem.ID)
+	}
+
+	s.memories[mem.ID] = mem
+	return mem.ID, nil
+}
+
+// StoreMemories stores each memory in turn.
+func (s *InMemoryVectorStore) StoreMemories(ctx context.Context, mems []Memory) error {
+	for _, m := range mems {
+		if _, err := s.StoreMemory(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMemory retrieves a memory by ID. Expired memories are not found.
+func (s *InMemoryVectorStore) GetMemory(ctx context.Context, id string) (Memory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mem, ok := s.memories[id]
+	if !ok || mem.Expired(time.Now()) {
+		return Memory{}, fmt.Errorf("memory %s not found", id)
+	}
+	return mem, nil
+}
+
+// Dimension implements DimensionedVectorStore with the length of the first
+// stored embedding, or zero when none is stored.
+func (s *InMemoryVectorStore) Dimension(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, id := range s.order {
+		if emb := s.memories[id].Embedding; len(emb) > 0 {
+			return len(emb), nil
+		}
+	}
+	return 0, nil
+}
+
+// SearchSimilar ranks stored memories by the cosine similarity of their
+// embedding to the query embedding, honoring the type and metadata filters,
+// offset and limit and leaving out expired memories. Memories with the same
+// score are ordered by ascending ID, so results are stable across runs.
+func (s *InMemoryVectorStore) SearchSimilar(ctx context.Context, embedding []float32, params SearchParams) ([]Memory, error) {
+	if err := validateFilters(params.Filters); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type scored struct {
+		memory Memory
+		score  float64
+	}
+
+	now := time.Now()
+	terms := keywordTerms(params.Query)
+
+	candidates := make([]scored, 0, len(s.order))
+	for _, id := range s.order {
+		mem := s.memories[id]
+		if len(params.Types) > 0 && !containsString(params.Types, mem.Type) {
+			continue
+		}
+		if !matchesFilters(mem.Metadata, params.Filters) {
+			continue
+		}
+		if mem.Expired(now) {
+			continue
+		}
+		score := cosine(embedding, mem.Embedding)
+		if params.Hybrid {
+			score = hybridScore(score, keywordScore(mem.Content, terms), params.keywordWeight())
+		}
+		candidates = append(candidates, scored{memory: mem, score: score})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].memory.ID < candidates[j].memory.ID
+	})
+
+	if params.Offset >= len(candidates) {
+		candidates = nil
+	} else if params.Offset > 0 {
+		candidates = candidates[params.Offset:]
+	}
+
+	if params.Limit > 0 && len(candidates) > params.Limit {
+		candidates = candidates[:params.Limit]
+	}
+
+	out := make([]Memory, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, c.memory)
+	}
+	return out, nil
+}
+
+// SearchSimilarBatch runs SearchSimilar for each query embedding in order.
+func (s *InMemoryVectorStore) SearchSimilarBatch(ctx context.Context, embeddings [][]float32, params SearchParams) ([][]Memory, error) {
+	out := make([][]Memory, len(embeddings))
+	for i, emb := range embeddings {
+		results, err := s.SearchSimilar(ctx, emb, params)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = results
+	}
+	return out, nil
+}
+
+// FindByContentHash implements ContentHashStore, returning the first
+// unexpired memory stored with the given content hash.
+func (s *InMemoryVectorStore) FindByContentHash(ctx context.Context, hash string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for _, id := range s.order {
+		mem := s.memories[id]
+		if mem.Metadata[ContentHashKey] == hash && !mem.Expired(now) {
+			return id, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// UpdateMemory replaces a stored memory, keeping its position and CreatedAt.
+func (s *InMemoryVectorStore) UpdateMemory(ctx context.Context, mem Memory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.memories[mem.ID]
+	if !ok {
+		return fmt.Errorf("memory %s not found", mem.ID)
+	}
+
+	s.memories[mem.ID] = stampUpdated(existing, mem)
+	return nil
+}
+
+// DeleteMemory removes a memory by ID.
+func (s *InMemoryVectorStore) DeleteMemory(ctx context.Context, id string) error {
+	return s.DeleteMemories(ctx, []string{id})
+}
+
+// DeleteMemories removes the memories with the given IDs.
+func (s *InMemoryVectorStore) DeleteMemories(ctx context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWhere(func(mem Memory) bool { return slices.Contains(ids, mem.ID) })
+	return nil
+}
+
+// DeleteByFilter removes every memory whose metadata matches all filters.
+func (s *InMemoryVectorStore) DeleteByFilter(ctx context.Context, filters []Filter) error {
+	if err := validateDeleteFilters(filters); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWhere(func(mem Memory) bool { return matchesFilters(mem.Metadata, filters) })
+	return nil
+}
+
+// deleteWhere removes the matching memories, keeping the order of the rest.
+// The caller holds the lock.
+func (s *InMemoryVectorStore) deleteWhere(match func(Memory) bool) int {
+	kept := s.order[:0]
+
+	for _, id := range s.order {
+		if match(s.memories[id]) {
+			delete(s.memories, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+
+	deleted := len(s.order) - len(kept)
+	s.order = kept
+	return deleted
+}
+
+// Sweep implements Sweeper, deleting every expired memory.
+func (s *InMemoryVectorStore) Sweep(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	return s.deleteWhere(func(mem Memory) bool { return mem.Expired(now) }), nil
+}
+
+// Ping always succeeds for the in-memory store.
+func (s *InMemoryVectorStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Snapshot serializes the full state of the store, including embeddings.
+func (s *InMemoryVectorStore) Snapshot() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := vectorSnapshot{Memories: make([]Memory, 0, len(s.order))}
+	for _, id := range s.order {
+		snap.Memories = append(snap.Memories, s.memories[id])
+	}
+
+	return json.Marshal(snap)
+}
+
+// RestoreSnapshot replaces the state of the store with a snapshot
+// previously produced by Snapshot.
+func (s *InMemoryVectorStore) RestoreSnapshot(data []byte) error {
+	var snap vectorSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to unmarshal vector store snapshot: %w", err)
+	}
+
+	memories := make(map[string]Memory, len(snap.Memories))
+	order := make([]string, 0, len(snap.Memories))
+	for _, mem := range snap.Memories {
+		if _, exists := memories[mem.ID]; !exists {
+			order = append(order, mem.ID)
+		}
+		memories[mem.ID] = mem
+	}
+
+	s.mu.Lock()
+	s.memories = memories
+	s.order = order
+	s.mu.Unlock()
+
+	return nil
+}
+
+// cosine returns the cosine similarity of two vectors, ignoring any trailing
+// dimensions the shorter vector does not have. A zero vector is similar to
+// nothing.
+func cosine(a, b []float32) float64 {
+	n := min(len(a), len(b))
+
+	var dot, normA, normB float64
+	for i := 0; i < n; i++ {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// matchesFilters reports whether metadata satisfies every filter, with the
+// operators of the pgvector store. Numbers and strings can be ordered, any
+// value can be compared for equality, and a missing field only matches "ne".
+func matchesFilters(metadata map[string]any, filters []Filter) bool {
+	for _, filter := range filters {
+		value, ok := metadata[filter.Field]
+
+		if filter.Operator == "ne" {
+			if ok && filterEqual(value, filter.Value) {
+				return false
+			}
+			continue
+		}
+
+		if !ok {
+			return false
+		}
+
+		if filter.Operator == "eq" {
+			if !filterEqual(value, filter.Value) {
+				return false
+			}
+			continue
+		}
+
+		order, comparable := filterCompare(value, filter.Value)
+		if !comparable {
+			return false
+		}
+
+		switch filter.Operator {
+		case "gt":
+			ok = order > 0
+		case "gte":
+			ok = order >= 0
+		case "lt":
+			ok = order < 0
+		case "lte":
+			ok = order <= 0
+		}
+
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// validateFilters rejects the operators matchesFilters does not know.
+func validateFilters(filters []Filter) error {
+	for _, filter := range filters {
+		if _, ok := pgOperators[filter.Operator]; !ok {
+			return fmt.Errorf("unsupported filter operator %q on %s", filter.Operator, filter.Field)
+		}
+	}
+	return nil
+}
+
+func filterEqual(a, b any) bool {
+	if order, ok := filterCompare(a, b); ok {
+		return order == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// filterCompare orders two numbers or two strings, reporting false for any
+// other pair.
+func filterCompare(a, b any) (int, bool) {
+	if x, ok := filterNumber(a); ok {
+		if y, ok := filterNumber(b); ok {
+			return cmp.Compare(x, y), true
+		}
+		return 0, false
+	}
+
+	x, okA := a.(string)
+	y, okB := b.(string)
+	if okA && okB {
+		return strings.Compare(x, y), true
+	}
+	return 0, false
+}
+
+func filterNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// containsString reports whether needle is present in haystack.
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
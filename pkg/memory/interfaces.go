@@ -2,6 +2,8 @@ package memory
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/theapemachine/a2a-go/pkg/a2a"
 )
@@ -12,24 +14,57 @@ type Embedder interface {
 	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
 }
 
+// ModelEmbedder is implemented by embedders that can report the model they
+// embed with, so every stored vector can be traced back to its model.
+type ModelEmbedder interface {
+	EmbeddingModel() string
+}
+
+// EmbeddingModelOf returns the model reported by the embedder, or an empty
+// string when it does not implement ModelEmbedder.
+func EmbeddingModelOf(embedder Embedder) string {
+	if me, ok := embedder.(ModelEmbedder); ok {
+		return me.EmbeddingModel()
+	}
+	return ""
+}
+
 // VectorStore provides semantic search capabilities over memories.
+// UpdateMemory replaces a stored memory with the given one, which carries its
+// new embedding, keeping its ID and CreatedAt, and fails when it is missing.
+// DeleteByFilter removes every memory whose metadata matches all filters, and
+// fails without any filter rather than deleting everything.
 type VectorStore interface {
 	StoreMemory(ctx context.Context, memory Memory) (string, error)
 	StoreMemories(ctx context.Context, memories []Memory) error
 	GetMemory(ctx context.Context, id string) (Memory, error)
 	SearchSimilar(ctx context.Context, embedding []float32, params SearchParams) ([]Memory, error)
+	UpdateMemory(ctx context.Context, memory Memory) error
 	DeleteMemory(ctx context.Context, id string) error
+	DeleteMemories(ctx context.Context, ids []string) error
+	DeleteByFilter(ctx context.Context, filters []Filter) error
 	Ping(ctx context.Context) error
 }
 
-// GraphStore manages relationships between memories.
+// BatchVectorStore is implemented by vector stores that can run several
+// similarity searches in one call. Results are returned in query order.
+type BatchVectorStore interface {
+	SearchSimilarBatch(ctx context.Context, embeddings [][]float32, params SearchParams) ([][]Memory, error)
+}
+
+// GraphStore manages relationships between memories. UpdateMemory replaces
+// a stored node like VectorStore.UpdateMemory, leaving its relations intact.
+// The deletes remove the relations of the deleted nodes along with them.
 type GraphStore interface {
 	StoreMemory(ctx context.Context, memory Memory) (string, error)
 	CreateRelation(ctx context.Context, relation Relation) error
 	GetMemory(ctx context.Context, id string) (Memory, error)
 	FindRelated(ctx context.Context, id string, relationTypes []string, limit int) ([]Memory, error)
 	QueryGraph(ctx context.Context, query string, params map[string]any) ([]Memory, error)
+	UpdateMemory(ctx context.Context, memory Memory) error
 	DeleteMemory(ctx context.Context, id string) error
+	DeleteMemories(ctx context.Context, ids []string) error
+	DeleteByFilter(ctx context.Context, filters []Filter) error
 	DeleteRelation(ctx context.Context, source, target, relationType string) error
 	Ping(ctx context.Context) error
 }
@@ -37,8 +72,15 @@ type GraphStore interface {
 // UnifiedStore exposes a combined interface for vector and graph stores.
 type UnifiedStore interface {
 	StoreMemory(ctx context.Context, content string, metadata map[string]any, memType string) (string, error)
+	StoreMemoryWithTTL(ctx context.Context, content string, metadata map[string]any, memType string, ttl time.Duration) (string, error)
+	UpdateMemory(ctx context.Context, id string, content string, metadata map[string]any) error
+	DeleteMemories(ctx context.Context, ids []string) error
+	DeleteByFilter(ctx context.Context, filters []Filter) error
 	CreateRelation(ctx context.Context, source, target, relationType string, properties map[string]any) error
+	ImportRelations(ctx context.Context, r io.Reader, format string) (int, error)
 	SearchSimilar(ctx context.Context, query string, params SearchParams) ([]Memory, error)
+	SearchSimilarPage(ctx context.Context, query string, params SearchParams) (SearchPage, error)
+	SearchSimilarBatch(ctx context.Context, queries []string, params SearchParams) ([][]Memory, error)
 	FindRelated(ctx context.Context, id string, relationTypes []string, limit int) ([]Memory, error)
 	InjectMemories(ctx context.Context, task TaskLike) error
 	ExtractMemories(ctx context.Context, task TaskLike) error
@@ -48,4 +90,5 @@ type UnifiedStore interface {
 type TaskLike interface {
 	AddMessage(role, name, text string)
 	LastMessage() *a2a.Message
+	Messages() []a2a.Message
 }
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// DefaultMockDimension is the dimension of MockEmbeddingService vectors when
+// no model is mirrored.
+const DefaultMockDimension = 4
+
+var (
+	dimensionsMu sync.RWMutex
+	// embeddingDimensions maps embedding models to the dimension of the
+	// vectors they produce.
+	embeddingDimensions = map[string]int{
+		"text-embedding-3-small":  1536,
+		"text-embedding-3-large":  3072,
+		"text-embedding-ada-002":  1536,
+		"mistral-embed":           1024,
+		"nomic-embed-text":        768,
+		"mxbai-embed-large":       1024,
+		"all-minilm":              384,
+		"embed-english-v3.0":      1024,
+		"embed-multilingual-v3.0": 1024,
+		"text-embedding-004":      768,
+		"gemini-embedding-001":    3072,
+	}
+)
+
+// EmbeddingDimension returns the vector dimension of a known embedding model.
+func EmbeddingDimension(model string) (int, bool) {
+	dimensionsMu.RLock()
+	defer dimensionsMu.RUnlock()
+
+	dim, ok := embeddingDimensions[model]
+	return dim, ok
+}
+
+// RegisterEmbeddingDimension records the vector dimension of an embedding
+// model, so it can be mirrored by MockEmbeddingService.
+func RegisterEmbeddingDimension(model string, dim int) {
+	dimensionsMu.Lock()
+	defer dimensionsMu.Unlock()
+
+	embeddingDimensions[model] = dim
+}
+
+// MockEmbeddingService is a deterministic embedder for tests and local
+// development. The same text always embeds to the same normalized vector,
+// without calling out to a model.
+type MockEmbeddingService struct {
+	model string
+	dim   int
+}
+
+// NewMockEmbeddingService returns a mock embedder producing small vectors of
+// DefaultMockDimension.
+func NewMockEmbeddingService() *MockEmbeddingService {
+	return &MockEmbeddingService{model: "mock", dim: DefaultMockDimension}
+}
+
+// NewMockEmbeddingServiceLike returns a mock embedder producing vectors of
+// the same dimension as the named model, so local stores mirror production
+// geometry. Unknown models fall back to DefaultMockDimension.
+func NewMockEmbeddingServiceLike(model string) *MockEmbeddingService {
+	dim, ok := EmbeddingDimension(model)
+	if !ok {
+		log.Warn("unknown embedding model, using default mock dimension", "model", model, "dimension", DefaultMockDimension)
+		dim = DefaultMockDimension
+	}
+
+	return &MockEmbeddingService{model: model, dim: dim}
+}
+
+// Dimension returns the length of the vectors the mock produces.
+func (m *MockEmbeddingService) Dimension() int {
+	return m.dim
+}
+
+// EmbeddingModel implements ModelEmbedder.
+func (m *MockEmbeddingService) EmbeddingModel() string {
+	return m.model
+}
+
+func (m *MockEmbeddingService) Embed(ctx context.Context, text string) ([]float32, error) {
+	hash := fnv.New64a()
+	hash.Write([]byte(text))
+	rng := rand.New(rand.NewPCG(hash.Sum64(), uint64(m.dim)))
+
+	vec := make([]float32, m.dim)
+	for i := range vec {
+		vec[i] = rng.Float32()*2 - 1
+	}
+
+	return Normalize(vec), nil
+}
+
+func (m *MockEmbeddingService) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i], _ = m.Embed(ctx, text)
+	}
+	return out, nil
+}
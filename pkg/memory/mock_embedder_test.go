@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMockEmbeddingService(t *testing.T) {
+	Convey("Given a mock embedder mirroring text-embedding-3-small", t, func() {
+		ctx := context.Background()
+		embedder := NewMockEmbeddingServiceLike("text-embedding-3-small")
+
+		Convey("Then its vectors should match the model's dimension", func() {
+			dim, ok := EmbeddingDimension("text-embedding-3-small")
+			So(ok, ShouldBeTrue)
+			So(dim, ShouldEqual, 1536)
+
+			vec, err := embedder.Embed(ctx, "hello")
+			So(err, ShouldBeNil)
+			So(vec, ShouldHaveLength, dim)
+			So(embedder.Dimension(), ShouldEqual, dim)
+			So(EmbeddingModelOf(embedder), ShouldEqual, "text-embedding-3-small")
+		})
+
+		Convey("Then embedding should be deterministic", func() {
+			first, _ := embedder.Embed(ctx, "hello")
+			second, _ := NewMockEmbeddingServiceLike("text-embedding-3-small").Embed(ctx, "hello")
+			other, _ := embedder.Embed(ctx, "goodbye")
+
+			So(second, ShouldResemble, first)
+			So(other, ShouldNotResemble, first)
+
+			batch, err := embedder.EmbedBatch(ctx, []string{"goodbye", "hello"})
+			So(err, ShouldBeNil)
+			So(batch, ShouldResemble, [][]float32{other, first})
+		})
+	})
+
+	Convey("Given an unknown model", t, func() {
+		embedder := NewMockEmbeddingServiceLike("not-a-model")
+
+		Convey("Then the default dimension should be used until it is registered", func() {
+			So(embedder.Dimension(), ShouldEqual, DefaultMockDimension)
+
+			RegisterEmbeddingDimension("not-a-model", 8)
+			So(NewMockEmbeddingServiceLike("not-a-model").Dimension(), ShouldEqual, 8)
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -159,17 +160,21 @@ func (s *Neo4jGraphStore) flushBatch() {
 			query.WriteString("MERGE (m:Memory {id: item.id}) ")
 			query.WriteString("SET m.content = item.content, ")
 			query.WriteString("m.type = item.type, ")
-			query.WriteString("m.metadata = item.metadata ")
+			query.WriteString("m.metadata = item.metadata, ")
+			query.WriteString("m.embedding_model = item.embedding_model, ")
+			query.WriteString("m.expires_at = item.expires_at ")
 			query.WriteString("RETURN m.id")
 
 			batch := make([]map[string]any, len(memories))
 			for i, mem := range memories {
 				mdBytes, _ := json.Marshal(mem.Metadata)
 				batch[i] = map[string]any{
-					"id":       mem.ID,
-					"content":  mem.Content,
-					"type":     mem.Type,
-					"metadata": string(mdBytes),
+					"id":              mem.ID,
+					"content":         mem.Content,
+					"type":            mem.Type,
+					"metadata":        string(mdBytes),
+					"embedding_model": mem.EmbeddingModel,
+					"expires_at":      expiresAtMillis(mem),
 				}
 			}
 
@@ -181,8 +186,8 @@ func (s *Neo4jGraphStore) flushBatch() {
 				for _, mem := range memories {
 					mdBytes, _ := json.Marshal(mem.Metadata)
 					_, _ = s.client.ExecCypher(ctx,
-						"MERGE (m:Memory {id:$id}) SET m.content=$content, m.type=$type, m.metadata=$metadata RETURN m.id",
-						map[string]any{"id": mem.ID, "content": mem.Content, "type": mem.Type, "metadata": string(mdBytes)})
+						"MERGE (m:Memory {id:$id}) SET m.content=$content, m.type=$type, m.metadata=$metadata, m.embedding_model=$embedding_model, m.expires_at=$expires_at RETURN m.id",
+						map[string]any{"id": mem.ID, "content": mem.Content, "type": mem.Type, "metadata": string(mdBytes), "embedding_model": mem.EmbeddingModel, "expires_at": expiresAtMillis(mem)})
 				}
 			}
 		}(memBatch)
@@ -287,6 +292,17 @@ func (s *Neo4jGraphStore) CreateRelation(ctx context.Context, rel Relation) erro
 	return nil
 }
 
+// CreateRelations implements BatchGraphStore, writing the relations in one
+// batch per relation type
+func (s *Neo4jGraphStore) CreateRelations(ctx context.Context, relations []Relation) error {
+	s.batchMutex.Lock()
+	s.relBatch = append(s.relBatch, relations...)
+	s.batchMutex.Unlock()
+
+	s.flushBatch()
+	return nil
+}
+
 // GetMemory retrieves a memory with caching
 func (s *Neo4jGraphStore) GetMemory(ctx context.Context, id string) (Memory, error) {
 	// Check cache first
@@ -296,7 +312,7 @@ func (s *Neo4jGraphStore) GetMemory(ctx context.Context, id string) (Memory, err
 
 	// Not in cache, query Neo4j
 	out, err := s.client.ExecCypher(ctx,
-		"MATCH (m:Memory {id:$id}) RETURN m.id as id, m.content as content, m.metadata as metadata, m.type as type",
+		"MATCH (m:Memory {id:$id}) WHERE "+neo4jUnexpired("m")+" RETURN m.id as id, m.content as content, m.metadata as metadata, m.type as type, m.embedding_model as embedding_model, m.expires_at as expires_at",
 		map[string]any{"id": id})
 
 	if err != nil {
@@ -315,10 +331,12 @@ func (s *Neo4jGraphStore) GetMemory(ctx context.Context, id string) (Memory, err
 	}
 
 	mem := Memory{
-		ID:       row[0].(string),
-		Content:  row[1].(string),
-		Metadata: meta,
-		Type:     row[3].(string),
+		ID:             row[0].(string),
+		Content:        row[1].(string),
+		Metadata:       meta,
+		Type:           row[3].(string),
+		EmbeddingModel: rowString(row, 4),
+		ExpiresAt:      rowMillis(row, 5),
 	}
 
 	// Add to cache
@@ -334,7 +352,7 @@ func (s *Neo4jGraphStore) FindRelated(ctx context.Context, id string, relationTy
 
 	// Build query based on relation types
 	if len(relationTypes) == 0 {
-		query = "MATCH (a:Memory {id:$id})-->(b:Memory) RETURN b.id as id, b.content as content, b.metadata as metadata, b.type as type LIMIT $limit"
+		query = "MATCH (a:Memory {id:$id})-->(b:Memory) WHERE " + neo4jUnexpired("b") + " RETURN b.id as id, b.content as content, b.metadata as metadata, b.type as type, b.embedding_model as embedding_model, b.expires_at as expires_at LIMIT $limit"
 	} else {
 		var relTypeStr string
 		for i, relType := range relationTypes {
@@ -343,12 +361,12 @@ func (s *Neo4jGraphStore) FindRelated(ctx context.Context, id string, relationTy
 			}
 			relTypeStr += ":" + relType
 		}
-		query = fmt.Sprintf("MATCH (a:Memory {id:$id})-[r %s]->(b:Memory) RETURN b.id as id, b.content as content, b.metadata as metadata, b.type as type LIMIT $limit", relTypeStr)
+		query = fmt.Sprintf("MATCH (a:Memory {id:$id})-[r %s]->(b:Memory) WHERE %s RETURN b.id as id, b.content as content, b.metadata as metadata, b.type as type, b.embedding_model as embedding_model, b.expires_at as expires_at LIMIT $limit", relTypeStr, neo4jUnexpired("b"))
 	}
 
-	// Check query cache
+	// Check query cache, dropping results that expired since they were cached
 	if cachedResults, found := s.queryCache.Get(query, params); found {
-		return cachedResults, nil
+		return unexpired(cachedResults, time.Now()), nil
 	}
 
 	// Execute query
@@ -369,10 +387,12 @@ func (s *Neo4jGraphStore) FindRelated(ctx context.Context, id string, relationTy
 		}
 
 		mem := Memory{
-			ID:       row[0].(string),
-			Content:  row[1].(string),
-			Metadata: meta,
-			Type:     row[3].(string),
+			ID:             row[0].(string),
+			Content:        row[1].(string),
+			Metadata:       meta,
+			Type:           row[3].(string),
+			EmbeddingModel: rowString(row, 4),
+			ExpiresAt:      rowMillis(row, 5),
 		}
 
 		mems = append(mems, mem)
@@ -431,6 +451,53 @@ func (s *Neo4jGraphStore) QueryGraph(ctx context.Context, query string, params m
 	return mems, nil
 }
 
+// UpdateMemory overwrites the properties of a stored memory in place, so
+// its relations are left untouched
+func (s *Neo4jGraphStore) UpdateMemory(ctx context.Context, mem Memory) error {
+	// A memory still waiting in the batch is updated before it is written
+	s.batchMutex.Lock()
+	for i, pending := range s.memBatch {
+		if pending.ID == mem.ID {
+			s.memBatch[i] = stampUpdated(pending, mem)
+			s.cache.Set(s.memBatch[i])
+			s.batchMutex.Unlock()
+			return nil
+		}
+	}
+	s.batchMutex.Unlock()
+
+	mdBytes, _ := json.Marshal(mem.Metadata)
+
+	out, err := s.client.ExecCypher(ctx,
+		"MATCH (m:Memory {id:$id}) SET m.content=$content, m.type=$type, m.metadata=$metadata, m.embedding_model=$embedding_model, m.expires_at=$expires_at, m.updated_at=timestamp() RETURN m.id",
+		map[string]any{"id": mem.ID, "content": mem.Content, "type": mem.Type, "metadata": string(mdBytes), "embedding_model": mem.EmbeddingModel, "expires_at": expiresAtMillis(mem)})
+	if err != nil {
+		return err
+	}
+
+	results, _ := out["results"].([]any)
+	if len(results) == 0 {
+		return fmt.Errorf("memory %s not found", mem.ID)
+	}
+	if data, _ := results[0].(map[string]any)["data"].([]any); len(data) == 0 {
+		return fmt.Errorf("memory %s not found", mem.ID)
+	}
+
+	// Refresh the cache, keeping the creation time we already know about
+	existing, _ := s.cache.Get(mem.ID)
+	if existing.CreatedAt.IsZero() {
+		existing.CreatedAt = mem.CreatedAt
+	}
+	s.cache.Set(stampUpdated(existing, mem))
+
+	// Clear query cache since results may change
+	s.queryCache.mu.Lock()
+	s.queryCache.items = make(map[string]queryCacheItem)
+	s.queryCache.mu.Unlock()
+
+	return nil
+}
+
 // DeleteMemory removes a memory and its relations
 func (s *Neo4jGraphStore) DeleteMemory(ctx context.Context, id string) error {
 	// Remove from cache
@@ -448,6 +515,87 @@ func (s *Neo4jGraphStore) DeleteMemory(ctx context.Context, id string) error {
 	return err
 }
 
+// DeleteMemories removes the memories with the given IDs and their relations
+// in one statement, along with any of them still waiting in the batch
+func (s *Neo4jGraphStore) DeleteMemories(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	s.dropPending(func(mem Memory) bool { return slices.Contains(ids, mem.ID) })
+	s.cache.Delete(ids...)
+
+	// Clear query cache since results may change
+	s.queryCache.mu.Lock()
+	s.queryCache.items = make(map[string]queryCacheItem)
+	s.queryCache.mu.Unlock()
+
+	_, err := s.client.ExecCypher(ctx, "MATCH (m:Memory) WHERE m.id IN $ids DETACH DELETE m", map[string]any{"ids": ids})
+	return err
+}
+
+// DeleteByFilter removes every memory whose metadata matches all filters, and
+// their relations. Metadata is stored as a JSON string, which Cypher cannot
+// look into without APOC, so the nodes are matched here and deleted by ID
+func (s *Neo4jGraphStore) DeleteByFilter(ctx context.Context, filters []Filter) error {
+	if err := validateDeleteFilters(filters); err != nil {
+		return err
+	}
+
+	s.dropPending(func(mem Memory) bool { return matchesFilters(mem.Metadata, filters) })
+
+	out, err := s.client.ExecCypher(ctx, "MATCH (m:Memory) RETURN m.id, m.metadata", nil)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+
+	results, _ := out["results"].([]any)
+	for _, result := range results {
+		data, _ := result.(map[string]any)["data"].([]any)
+
+		for _, item := range data {
+			row, _ := item.(map[string]any)["row"].([]any)
+			id := rowString(row, 0)
+
+			meta := make(map[string]any)
+			if err := json.Unmarshal([]byte(rowString(row, 1)), &meta); err != nil {
+				continue
+			}
+
+			if id != "" && matchesFilters(meta, filters) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return s.DeleteMemories(ctx, ids)
+}
+
+// dropPending removes the memories still waiting in the batch that match,
+// and the pending relations touching them, so they are not written after
+// they were deleted
+func (s *Neo4jGraphStore) dropPending(match func(Memory) bool) {
+	s.batchMutex.Lock()
+	defer s.batchMutex.Unlock()
+
+	dropped := map[string]bool{}
+	s.memBatch = slices.DeleteFunc(s.memBatch, func(mem Memory) bool {
+		if match(mem) {
+			dropped[mem.ID] = true
+			return true
+		}
+		return false
+	})
+
+	if len(dropped) > 0 {
+		s.relBatch = slices.DeleteFunc(s.relBatch, func(rel Relation) bool {
+			return dropped[rel.SourceID] || dropped[rel.TargetID]
+		})
+	}
+}
+
 // DeleteRelation removes a relation
 func (s *Neo4jGraphStore) DeleteRelation(ctx context.Context, source, target, relationType string) error {
 	// Clear query cache since results may change
@@ -462,8 +610,80 @@ func (s *Neo4jGraphStore) DeleteRelation(ctx context.Context, source, target, re
 	return err
 }
 
+// Sweep implements Sweeper, deleting every expired memory and its relations.
+func (s *Neo4jGraphStore) Sweep(ctx context.Context) (int, error) {
+	out, err := s.client.ExecCypher(ctx,
+		"MATCH (m:Memory) WHERE m.expires_at > 0 AND m.expires_at <= timestamp() DETACH DELETE m RETURN count(m)",
+		nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// Clear query cache since results may change
+	s.queryCache.mu.Lock()
+	s.queryCache.items = make(map[string]queryCacheItem)
+	s.queryCache.mu.Unlock()
+
+	results, _ := out["results"].([]any)
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	data, _ := results[0].(map[string]any)["data"].([]any)
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	row, _ := data[0].(map[string]any)["row"].([]any)
+	count, _ := rowNumber(row, 0)
+	return int(count), nil
+}
+
 // Ping checks if the Neo4j connection is alive
 func (s *Neo4jGraphStore) Ping(ctx context.Context) error {
 	_, err := s.client.ExecCypher(ctx, "RETURN 1", nil)
 	return err
 }
+
+// neo4jUnexpired is the Cypher condition matching the node bound to name
+// unless it has expired. Nodes written before expiry existed never expire.
+func neo4jUnexpired(name string) string {
+	return fmt.Sprintf("(coalesce(%[1]s.expires_at, 0) = 0 OR %[1]s.expires_at > timestamp())", name)
+}
+
+// expiresAtMillis returns the expiry of mem in Unix milliseconds, or zero
+// when it never expires.
+func expiresAtMillis(mem Memory) int64 {
+	if mem.ExpiresAt.IsZero() {
+		return 0
+	}
+	return mem.ExpiresAt.UnixMilli()
+}
+
+// rowNumber reads an optional numeric column from a Cypher result row.
+func rowNumber(row []any, idx int) (float64, bool) {
+	if idx >= len(row) {
+		return 0, false
+	}
+	value, ok := row[idx].(float64)
+	return value, ok
+}
+
+// rowMillis reads an optional expiry column in Unix milliseconds from a
+// Cypher result row, returning the zero time when it is missing or zero.
+func rowMillis(row []any, idx int) time.Time {
+	if ms, ok := rowNumber(row, idx); ok && ms > 0 {
+		return time.UnixMilli(int64(ms))
+	}
+	return time.Time{}
+}
+
+// rowString reads an optional string column from a Cypher result row, so
+// nodes written before the column existed still load.
+func rowString(row []any, idx int) string {
+	if idx >= len(row) {
+		return ""
+	}
+	value, _ := row[idx].(string)
+	return value
+}
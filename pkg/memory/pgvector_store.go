@@ -0,0 +1,491 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// pgIdentifier matches the table names PgVectorStore accepts, as they are
+// interpolated into its SQL.
+var pgIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pgUnexpired is the condition matching the rows that have not expired.
+const pgUnexpired = "(expires_at IS NULL OR expires_at > now())"
+
+// pgOperators maps filter operators to the SQL comparing a metadata value.
+var pgOperators = map[string]string{
+	"eq":  "=",
+	"ne":  "<>",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+// PgVectorStore implements VectorStore on a Postgres table with the pgvector
+// extension, for deployments that already run Postgres. Memories are ranked
+// by cosine similarity, and metadata is kept in a JSONB column so searches
+// can filter on it.
+type PgVectorStore struct {
+	db       *sql.DB
+	table    string
+	dim      int
+	embedder Embedder
+}
+
+// NewPgVectorStore connects to the Postgres database at dsn and creates the
+// table, for vectors of dimension dim, and its ivfflat index when missing.
+func NewPgVectorStore(dsn, table string, dim int, embedder Embedder) (*PgVectorStore, error) {
+	if !pgIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid pgvector table name %q", table)
+	}
+
+	if dim <= 0 {
+		return nil, fmt.Errorf("invalid pgvector dimension %d", dim)
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+
+	store := &PgVectorStore{db: db, table: table, dim: dim, embedder: embedder}
+
+	if err := store.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// migrate creates the extension, the table and its index when missing.
+func (s *PgVectorStore) migrate(ctx context.Context) error {
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS vector`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			content TEXT NOT NULL,
+			type TEXT NOT NULL DEFAULT '',
+			metadata JSONB NOT NULL DEFAULT '{}',
+			embedding vector(%d),
+			embedding_model TEXT NOT NULL DEFAULT '',
+			expires_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`, s.table, s.dim),
+		fmt.Sprintf(
+			`CREATE INDEX IF NOT EXISTS %s_embedding_idx ON %s USING ivfflat (embedding vector_cosine_ops) WITH (lists = 100)`,
+			s.table, s.table,
+		),
+	}
+
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to migrate pgvector table %s: %w", s.table, err)
+		}
+	}
+
+	return nil
+}
+
+// StoreMemory upserts a memory, embedding its content when it carries no
+// embedding and generating an ID when none is set.
+func (s *PgVectorStore) StoreMemory(ctx context.Context, mem Memory) (string, error) {
+	return s.storeMemory(ctx, s.db, mem)
+}
+
+// StoreMemories upserts the memories in a single transaction.
+func (s *PgVectorStore) StoreMemories(ctx context.Context, mems []Memory) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, m := range mems {
+		if _, err := s.storeMemory(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateMemory overwrites a stored memory, keeping its creation time. The
+// memory is re-embedded when it carries no embedding.
+func (s *PgVectorStore) UpdateMemory(ctx context.Context, mem Memory) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var createdAt time.Time
+
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT created_at FROM %s WHERE id = $1 FOR UPDATE`, s.table,
+	), mem.ID).Scan(&createdAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("memory %s not found", mem.ID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.storeMemory(ctx, tx, stampUpdated(Memory{CreatedAt: createdAt}, mem)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execer is what storeMemory needs of a *sql.DB or *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (s *PgVectorStore) storeMemory(ctx context.Context, db execer, mem Memory) (string, error) {
+	if mem.ID == "" {
+		mem.ID = uuid.NewString()
+	}
+
+	if mem.Embedding == nil && s.embedder != nil {
+		emb, err := s.embedder.Embed(ctx, mem.Content)
+		if err != nil {
+			return "", err
+		}
+		mem.Embedding = emb
+		mem.EmbeddingModel = EmbeddingModelOf(s.embedder)
+	}
+
+	if len(mem.Embedding) != s.dim {
+		return "", fmt.Errorf(
+			"memory %s has an embedding of dimension %d, table %s holds dimension %d",
+			mem.ID, len(mem.Embedding), s.table, s.dim,
+		)
+	}
+
+	metadata, err := json.Marshal(mem.Metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	if mem.Metadata == nil {
+		metadata = []byte("{}")
+	}
+
+	var expiresAt sql.NullTime
+	if !mem.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: mem.ExpiresAt, Valid: true}
+	}
+
+	stampCreated(&mem)
+
+	// The creation time of a memory that is stored again is left alone.
+	_, err = db.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (id, content, type, metadata, embedding, embedding_model, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5::vector, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			content = EXCLUDED.content,
+			type = EXCLUDED.type,
+			metadata = EXCLUDED.metadata,
+			embedding = EXCLUDED.embedding,
+			embedding_model = EXCLUDED.embedding_model,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = EXCLUDED.updated_at`, s.table),
+		mem.ID, mem.Content, mem.Type, string(metadata), pgVector(mem.Embedding), mem.EmbeddingModel, expiresAt,
+		mem.CreatedAt, mem.UpdatedAt,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to store memory %s: %w", mem.ID, err)
+	}
+
+	return mem.ID, nil
+}
+
+// GetMemory retrieves a memory by ID, including its embedding. Expired
+// memories are not found.
+func (s *PgVectorStore) GetMemory(ctx context.Context, id string) (Memory, error) {
+	var (
+		mem       Memory
+		metadata  []byte
+		embedding string
+		expiresAt sql.NullTime
+	)
+
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT id, content, type, metadata, embedding::text, embedding_model, expires_at, created_at, updated_at
+		FROM %s WHERE id = $1 AND %s`,
+		s.table, pgUnexpired,
+	), id).Scan(
+		&mem.ID, &mem.Content, &mem.Type, &metadata, &embedding, &mem.EmbeddingModel, &expiresAt,
+		&mem.CreatedAt, &mem.UpdatedAt,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return Memory{}, fmt.Errorf("memory %s not found", id)
+	}
+
+	if err != nil {
+		return Memory{}, err
+	}
+
+	if err := json.Unmarshal(metadata, &mem.Metadata); err != nil {
+		return Memory{}, fmt.Errorf("failed to unmarshal metadata of memory %s: %w", id, err)
+	}
+
+	if mem.Embedding, err = parsePgVector(embedding); err != nil {
+		return Memory{}, fmt.Errorf("failed to parse embedding of memory %s: %w", id, err)
+	}
+
+	if expiresAt.Valid {
+		mem.ExpiresAt = expiresAt.Time
+	}
+
+	return mem, nil
+}
+
+// SearchSimilar ranks memories by cosine similarity to the embedding,
+// honoring the type filter, the metadata filters and the limit and leaving
+// out expired memories. The similarity of each result is returned under
+// ScoreKey in its metadata.
+func (s *PgVectorStore) SearchSimilar(ctx context.Context, embedding []float32, params SearchParams) ([]Memory, error) {
+	where, args, err := pgWhere(params, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if where == "" {
+		where = " WHERE " + pgUnexpired
+	} else {
+		where += " AND " + pgUnexpired
+	}
+
+	query := fmt.Sprintf(
+		`SELECT id, content, type, metadata, embedding_model, expires_at, created_at, updated_at, 1 - (embedding <=> $1::vector) FROM %s%s ORDER BY embedding <=> $1::vector`,
+		s.table, where,
+	)
+
+	if params.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(params.Limit)
+	}
+
+	if params.Offset > 0 {
+		query += " OFFSET " + strconv.Itoa(params.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, append([]any{pgVector(embedding)}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search memories: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Memory, 0)
+
+	for rows.Next() {
+		var (
+			mem       Memory
+			metadata  []byte
+			expiresAt sql.NullTime
+			score     float64
+		)
+
+		if err := rows.Scan(
+			&mem.ID, &mem.Content, &mem.Type, &metadata, &mem.EmbeddingModel, &expiresAt,
+			&mem.CreatedAt, &mem.UpdatedAt, &score,
+		); err != nil {
+			return nil, err
+		}
+
+		if expiresAt.Valid {
+			mem.ExpiresAt = expiresAt.Time
+		}
+
+		if err := json.Unmarshal(metadata, &mem.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata of memory %s: %w", mem.ID, err)
+		}
+
+		if mem.Metadata == nil {
+			mem.Metadata = make(map[string]any)
+		}
+		mem.Metadata[ScoreKey] = score
+
+		out = append(out, mem)
+	}
+
+	return out, rows.Err()
+}
+
+// SearchSimilarBatch runs one search per query embedding concurrently.
+func (s *PgVectorStore) SearchSimilarBatch(ctx context.Context, embeddings [][]float32, params SearchParams) ([][]Memory, error) {
+	return searchConcurrently(ctx, s, embeddings, params)
+}
+
+// DeleteMemory removes a memory by ID.
+func (s *PgVectorStore) DeleteMemory(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, s.table), id)
+	return err
+}
+
+// DeleteMemories removes the memories with the given IDs in one statement.
+func (s *PgVectorStore) DeleteMemories(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = id
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE id IN (%s)`, s.table, strings.Join(placeholders, ", ")),
+		args...)
+	return err
+}
+
+// DeleteByFilter removes every memory whose metadata matches all filters,
+// comparing them like SearchSimilar does.
+func (s *PgVectorStore) DeleteByFilter(ctx context.Context, filters []Filter) error {
+	if err := validateDeleteFilters(filters); err != nil {
+		return err
+	}
+
+	where, args, err := pgWhere(SearchParams{Filters: filters}, 1)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s%s`, s.table, where), args...)
+	return err
+}
+
+// Sweep implements Sweeper, deleting every expired memory.
+func (s *PgVectorStore) Sweep(ctx context.Context) (int, error) {
+	result, err := s.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE expires_at <= now()`, s.table))
+	if err != nil {
+		return 0, fmt.Errorf("failed to sweep expired memories: %w", err)
+	}
+
+	swept, err := result.RowsAffected()
+	return int(swept), err
+}
+
+// Ping checks the connection to Postgres.
+func (s *PgVectorStore) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("postgres ping failed: %w", err)
+	}
+	return nil
+}
+
+// Dimension implements DimensionedVectorStore with the dimension of the
+// table's vector column.
+func (s *PgVectorStore) Dimension(ctx context.Context) (int, error) {
+	return s.dim, nil
+}
+
+// Close closes the database connections.
+func (s *PgVectorStore) Close() error {
+	return s.db.Close()
+}
+
+// pgWhere translates the type filter and the metadata filters of params into
+// a WHERE clause, numbering its placeholders from next. Filter values are
+// compared as JSONB, and only with metadata values of the same JSON type, so
+// a number never compares greater than a string.
+func pgWhere(params SearchParams, next int) (string, []any, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+
+	placeholder := func(arg any) string {
+		args = append(args, arg)
+		return "$" + strconv.Itoa(next+len(args)-1)
+	}
+
+	if len(params.Types) > 0 {
+		types := make([]string, len(params.Types))
+		for i, t := range params.Types {
+			types[i] = placeholder(t)
+		}
+		clauses = append(clauses, "type IN ("+strings.Join(types, ", ")+")")
+	}
+
+	for _, filter := range params.Filters {
+		operator, ok := pgOperators[filter.Operator]
+		if !ok {
+			return "", nil, fmt.Errorf("unsupported filter operator %q on %s", filter.Operator, filter.Field)
+		}
+
+		value, err := json.Marshal(filter.Value)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal filter value of %s: %w", filter.Field, err)
+		}
+
+		field, arg := placeholder(filter.Field)+"::text", placeholder(string(value))+"::jsonb"
+
+		if filter.Operator == "ne" {
+			// A memory without the field is not equal to the value either.
+			clauses = append(clauses, fmt.Sprintf("metadata->%s IS DISTINCT FROM %s", field, arg))
+			continue
+		}
+
+		clauses = append(clauses, fmt.Sprintf(
+			"(jsonb_typeof(metadata->%s) = jsonb_typeof(%s) AND metadata->%s %s %s)",
+			field, arg, field, operator, arg,
+		))
+	}
+
+	if len(clauses) == 0 {
+		return "", nil, nil
+	}
+
+	return " WHERE " + strings.Join(clauses, " AND "), args, nil
+}
+
+// pgVector formats an embedding as a pgvector literal.
+func pgVector(embedding []float32) string {
+	parts := make([]string, len(embedding))
+	for i, v := range embedding {
+		parts[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// parsePgVector parses a pgvector literal.
+func parsePgVector(literal string) ([]float32, error) {
+	literal = strings.TrimSuffix(strings.TrimPrefix(literal, "["), "]")
+	if literal == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(literal, ",")
+	out := make([]float32, len(parts))
+
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = float32(v)
+	}
+
+	return out, nil
+}
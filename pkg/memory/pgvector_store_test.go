@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPgWhere(t *testing.T) {
+	Convey("Given search params with types and filters", t, func() {
+		params := SearchParams{
+			Types: []string{"knowledge", "conversation"},
+			Filters: []Filter{
+				{Field: "importance", Operator: "gte", Value: 5},
+				{Field: "topic", Operator: "ne", Value: "weather"},
+			},
+		}
+
+		Convey("When they are translated to SQL", func() {
+			where, args, err := pgWhere(params, 2)
+
+			Convey("Then every value should be a placeholder numbered after the query vector", func() {
+				So(err, ShouldBeNil)
+				So(where, ShouldEqual, " WHERE type IN ($2, $3)"+
+					" AND (jsonb_typeof(metadata->$4::text) = jsonb_typeof($5::jsonb) AND metadata->$4::text >= $5::jsonb)"+
+					" AND metadata->$6::text IS DISTINCT FROM $7::jsonb")
+				So(args, ShouldResemble, []any{"knowledge", "conversation", "importance", "5", "topic", `"weather"`})
+			})
+		})
+
+		Convey("When there is nothing to filter on", func() {
+			where, args, err := pgWhere(SearchParams{Limit: 3}, 2)
+
+			Convey("Then there should be no WHERE clause", func() {
+				So(err, ShouldBeNil)
+				So(where, ShouldBeEmpty)
+				So(args, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a filter has an unknown operator", func() {
+			_, _, err := pgWhere(SearchParams{Filters: []Filter{{Field: "topic", Operator: "like", Value: "w%"}}}, 2)
+
+			Convey("Then it should be rejected", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, `unsupported filter operator "like"`)
+			})
+		})
+	})
+}
+
+func TestPgVectorRoundTrip(t *testing.T) {
+	Convey("Given an embedding", t, func() {
+		embedding := []float32{0.25, -1, 3.5e-7}
+
+		Convey("Then it should survive the pgvector literal", func() {
+			literal := pgVector(embedding)
+			So(literal, ShouldEqual, "[0.25,-1,3.5e-07]")
+
+			parsed, err := parsePgVector(literal)
+			So(err, ShouldBeNil)
+			So(parsed, ShouldResemble, embedding)
+		})
+	})
+}
+
+// TestPgVectorStore runs against the Postgres of
+// testdata/docker-compose.pgvector.yml, and is skipped unless PGVECTOR_DSN
+// points at it.
+func TestPgVectorStore(t *testing.T) {
+	dsn := os.Getenv("PGVECTOR_DSN")
+	if dsn == "" {
+		t.Skip("PGVECTOR_DSN not set; skipping pgvector integration test")
+	}
+
+	Convey("Given a pgvector store", t, func() {
+		ctx := context.Background()
+		embedder := NewMockEmbeddingService()
+
+		store, err := NewPgVectorStore(dsn, "memories_test", DefaultMockDimension, embedder)
+		So(err, ShouldBeNil)
+		defer store.Close()
+
+		_, err = store.db.ExecContext(ctx, "TRUNCATE memories_test")
+		So(err, ShouldBeNil)
+
+		So(store.Ping(ctx), ShouldBeNil)
+		So(store.StoreMemories(ctx, []Memory{
+			{ID: "a", Content: "the sky is blue", Type: "knowledge", Metadata: map[string]any{"importance": 8, "topic": "sky"}},
+			{ID: "b", Content: "it rains in Amsterdam", Type: "knowledge", Metadata: map[string]any{"importance": 3, "topic": "weather"}},
+			{ID: "c", Content: "hello there", Type: "conversation", Metadata: map[string]any{"importance": "high"}},
+		}), ShouldBeNil)
+
+		Convey("When a memory is retrieved", func() {
+			mem, err := store.GetMemory(ctx, "a")
+
+			Convey("Then it should carry its metadata and embedding", func() {
+				So(err, ShouldBeNil)
+				So(mem.Content, ShouldEqual, "the sky is blue")
+				So(mem.Metadata["topic"], ShouldEqual, "sky")
+				So(mem.EmbeddingModel, ShouldEqual, "mock")
+
+				expected, _ := embedder.Embed(ctx, "the sky is blue")
+				So(mem.Embedding, ShouldResemble, expected)
+			})
+		})
+
+		Convey("When searching for the exact content of a memory", func() {
+			query, _ := embedder.Embed(ctx, "it rains in Amsterdam")
+			results, err := store.SearchSimilar(ctx, query, SearchParams{Limit: 1})
+
+			Convey("Then it should rank first with a similarity of one", func() {
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(results[0].ID, ShouldEqual, "b")
+				So(results[0].Metadata[ScoreKey], ShouldAlmostEqual, 1, 1e-5)
+			})
+		})
+
+		Convey("When searching with filters", func() {
+			query, _ := embedder.Embed(ctx, "anything")
+
+			gte, err := store.SearchSimilar(ctx, query, SearchParams{
+				Filters: []Filter{{Field: "importance", Operator: "gte", Value: 5}},
+			})
+			So(err, ShouldBeNil)
+
+			ne, err := store.SearchSimilar(ctx, query, SearchParams{
+				Types:   []string{"knowledge"},
+				Filters: []Filter{{Field: "topic", Operator: "ne", Value: "weather"}},
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then only matching memories should be returned", func() {
+				So(gte, ShouldHaveLength, 1)
+				So(gte[0].ID, ShouldEqual, "a")
+				So(ne, ShouldHaveLength, 1)
+				So(ne[0].ID, ShouldEqual, "a")
+			})
+		})
+
+		Convey("When a memory is deleted", func() {
+			So(store.DeleteMemory(ctx, "c"), ShouldBeNil)
+
+			Convey("Then it should no longer be found", func() {
+				_, err := store.GetMemory(ctx, "c")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
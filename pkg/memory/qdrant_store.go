@@ -3,6 +3,9 @@ package memory
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/theapemachine/a2a-go/pkg/stores/qdrant"
@@ -12,10 +15,27 @@ import (
 type QdrantVectorStore struct {
 	client   *qdrant.Client
 	embedder Embedder
+
+	hashIndexMutex sync.Mutex
+	hashIndexed    bool
 }
 
-func NewQdrantVectorStore(endpoint, collection string, embedder Embedder) *QdrantVectorStore {
-	return &QdrantVectorStore{client: qdrant.New(endpoint, collection), embedder: embedder}
+// embeddingModelKey is the payload key holding the model a point was
+// embedded with.
+const embeddingModelKey = "embedding_model"
+
+// Payload keys holding the Unix times in milliseconds at which a point
+// expires, was created and was last updated.
+const (
+	expiresAtKey = "expires_at"
+	createdAtKey = "created_at"
+	updatedAtKey = "updated_at"
+)
+
+// NewQdrantVectorStore creates a Qdrant-backed vector store. Client options
+// such as qdrant.WithQdrantWait are passed through to the underlying client.
+func NewQdrantVectorStore(endpoint, collection string, embedder Embedder, options ...qdrant.ClientOption) *QdrantVectorStore {
+	return &QdrantVectorStore{client: qdrant.New(endpoint, collection, options...), embedder: embedder}
 }
 
 func (s *QdrantVectorStore) StoreMemory(ctx context.Context, mem Memory) (string, error) {
@@ -28,10 +48,23 @@ func (s *QdrantVectorStore) StoreMemory(ctx context.Context, mem Memory) (string
 			return "", err
 		}
 		mem.Embedding = emb
+		mem.EmbeddingModel = EmbeddingModelOf(s.embedder)
+	}
+	stampCreated(&mem)
+	md := map[string]any{
+		"embedding":  mem.Embedding,
+		"type":       mem.Type,
+		createdAtKey: mem.CreatedAt.UnixMilli(),
+		updatedAtKey: mem.UpdatedAt.UnixMilli(),
+	}
+	if mem.EmbeddingModel != "" {
+		md[embeddingModelKey] = mem.EmbeddingModel
+	}
+	if !mem.ExpiresAt.IsZero() {
+		md[expiresAtKey] = mem.ExpiresAt.UnixMilli()
 	}
-	md := map[string]any{"embedding": mem.Embedding, "type": mem.Type}
 	for k, v := range mem.Metadata {
-		if k == "embedding" || k == "type" {
+		if k == "embedding" || k == "type" || k == embeddingModelKey || k == expiresAtKey || k == createdAtKey || k == updatedAtKey {
 			continue
 		}
 		md[k] = v
@@ -57,25 +90,164 @@ func (s *QdrantVectorStore) GetMemory(ctx context.Context, id string) (Memory, e
 	if err != nil {
 		return Memory{}, err
 	}
-	return Memory{ID: doc.ID, Content: doc.Content, Metadata: doc.Metadata}, nil
+	mem := memoryFromDocument(*doc)
+	if mem.Expired(time.Now()) {
+		return Memory{}, fmt.Errorf("memory %s not found", id)
+	}
+	return mem, nil
 }
 
 func (s *QdrantVectorStore) SearchSimilar(ctx context.Context, embedding []float32, params SearchParams) ([]Memory, error) {
-	docs, err := s.client.Search(ctx, embedding, params.Limit)
+	if params.Hybrid {
+		return s.searchHybrid(ctx, embedding, params)
+	}
+
+	docs, err := s.client.SearchFrom(ctx, embedding, params.Limit, params.Offset)
 	if err != nil {
 		return nil, err
 	}
 	out := make([]Memory, 0, len(docs))
 	for _, d := range docs {
-		out = append(out, Memory{ID: d.ID, Content: d.Content, Metadata: d.Metadata})
+		out = append(out, memoryFromDocument(d))
+	}
+	return unexpired(out, time.Now()), nil
+}
+
+// searchHybrid merges the nearest points with the nearest points whose
+// content holds a query term, and reranks them by their hybrid score. Both
+// searches fetch every result up to the end of the requested page, which is
+// cut out of the merged ranking.
+func (s *QdrantVectorStore) searchHybrid(ctx context.Context, embedding []float32, params SearchParams) ([]Memory, error) {
+	depth := params.Offset + params.Limit
+	if params.Limit <= 0 {
+		depth = params.Offset + 10
+	}
+
+	docs, err := s.client.Search(ctx, embedding, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := keywordTerms(params.Query)
+
+	if len(terms) > 0 {
+		// Qdrant matches the terms as written, unless the content field has
+		// a full-text index that lowercases them.
+		matches, err := s.client.TextSearch(ctx, embedding, depth, "content", strings.Fields(params.Query))
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, matches...)
+	}
+
+	seen := map[string]bool{}
+	out := make([]Memory, 0, len(docs))
+
+	for _, d := range docs {
+		if seen[d.ID] {
+			continue
+		}
+		seen[d.ID] = true
+
+		mem := memoryFromDocument(d)
+		similarity, _ := mem.Metadata[ScoreKey].(float64)
+		mem.Metadata[ScoreKey] = hybridScore(similarity, keywordScore(mem.Content, terms), params.keywordWeight())
+		out = append(out, mem)
 	}
+
+	out = orderResults(unexpired(out, time.Now()))
+
+	if params.Offset >= len(out) {
+		return []Memory{}, nil
+	}
+
+	out = out[params.Offset:]
+
+	if params.Limit > 0 && len(out) > params.Limit {
+		out = out[:params.Limit]
+	}
+
 	return out, nil
 }
 
+// SearchSimilarBatch runs one Qdrant search per query embedding concurrently.
+func (s *QdrantVectorStore) SearchSimilarBatch(ctx context.Context, embeddings [][]float32, params SearchParams) ([][]Memory, error) {
+	return searchConcurrently(ctx, s, embeddings, params)
+}
+
+// UpdateMemory overwrites the point of a stored memory, keeping its
+// CreatedAt. The memory is re-embedded when it carries no embedding.
+func (s *QdrantVectorStore) UpdateMemory(ctx context.Context, mem Memory) error {
+	doc, err := s.client.Get(ctx, mem.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.StoreMemory(ctx, stampUpdated(memoryFromDocument(*doc), mem))
+	return err
+}
+
 func (s *QdrantVectorStore) DeleteMemory(ctx context.Context, id string) error {
 	return s.client.Delete(ctx, id)
 }
 
+// DeleteMemories removes the points of the memories in one request.
+func (s *QdrantVectorStore) DeleteMemories(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.client.DeletePoints(ctx, ids)
+}
+
+// DeleteByFilter removes the points whose payload matches every filter,
+// leaving the selection to Qdrant.
+func (s *QdrantVectorStore) DeleteByFilter(ctx context.Context, filters []Filter) error {
+	if err := validateDeleteFilters(filters); err != nil {
+		return err
+	}
+
+	filter, err := qdrantFilter(filters)
+	if err != nil {
+		return err
+	}
+	return s.client.DeleteWhere(ctx, filter)
+}
+
+// FindByContentHash implements ContentHashStore with a payload filter on the
+// content hash. The first lookup indexes the content hash field, so later
+// lookups do not scan the collection; until the collection exists the index
+// cannot be created, and the lookup filters without it.
+func (s *QdrantVectorStore) FindByContentHash(ctx context.Context, hash string) (string, bool, error) {
+	s.ensureHashIndex(ctx)
+
+	docs, err := s.client.ScrollWhere(ctx, map[string]any{
+		"must": []map[string]any{{"key": ContentHashKey, "match": map[string]any{"value": hash}}},
+	}, 10)
+	if err != nil {
+		return "", false, err
+	}
+
+	now := time.Now()
+	for _, d := range docs {
+		if !memoryFromDocument(d).Expired(now) {
+			return d.ID, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// ensureHashIndex creates the keyword index on the content hash field once
+// it succeeds.
+func (s *QdrantVectorStore) ensureHashIndex(ctx context.Context) {
+	s.hashIndexMutex.Lock()
+	defer s.hashIndexMutex.Unlock()
+
+	if s.hashIndexed {
+		return
+	}
+	s.hashIndexed = s.client.CreatePayloadIndex(ctx, ContentHashKey, "keyword") == nil
+}
+
 func (s *QdrantVectorStore) Ping(ctx context.Context) error {
 	_, err := s.client.Search(ctx, []float32{0}, 1)
 	if err != nil {
@@ -83,3 +255,64 @@ func (s *QdrantVectorStore) Ping(ctx context.Context) error {
 	}
 	return nil
 }
+
+// Dimension implements DimensionedVectorStore with the vector size of the
+// collection.
+func (s *QdrantVectorStore) Dimension(ctx context.Context) (int, error) {
+	return s.client.VectorSize(ctx)
+}
+
+// qdrantFilter translates metadata filters into a Qdrant filter. Equality is
+// a match on the payload value, "ne" excludes the matches, and the ordering
+// operators become ranges, which Qdrant only supports on numbers.
+func qdrantFilter(filters []Filter) (map[string]any, error) {
+	var must, mustNot []map[string]any
+
+	for _, filter := range filters {
+		switch filter.Operator {
+		case "eq":
+			must = append(must, map[string]any{"key": filter.Field, "match": map[string]any{"value": filter.Value}})
+		case "ne":
+			mustNot = append(mustNot, map[string]any{"key": filter.Field, "match": map[string]any{"value": filter.Value}})
+		default:
+			value, ok := filterNumber(filter.Value)
+			if !ok {
+				return nil, fmt.Errorf("filter operator %q on %s needs a number, got %v", filter.Operator, filter.Field, filter.Value)
+			}
+			must = append(must, map[string]any{"key": filter.Field, "range": map[string]any{filter.Operator: value}})
+		}
+	}
+
+	out := map[string]any{}
+	if len(must) > 0 {
+		out["must"] = must
+	}
+	if len(mustNot) > 0 {
+		out["must_not"] = mustNot
+	}
+	return out, nil
+}
+
+// memoryFromDocument converts a Qdrant document back into a Memory,
+// restoring the embedding model and times recorded in its payload.
+func memoryFromDocument(doc qdrant.Document) Memory {
+	model, _ := doc.Metadata[embeddingModelKey].(string)
+	return Memory{
+		ID:             doc.ID,
+		Content:        doc.Content,
+		Metadata:       doc.Metadata,
+		EmbeddingModel: model,
+		ExpiresAt:      payloadTime(doc.Metadata, expiresAtKey),
+		CreatedAt:      payloadTime(doc.Metadata, createdAtKey),
+		UpdatedAt:      payloadTime(doc.Metadata, updatedAtKey),
+	}
+}
+
+// payloadTime reads a Unix time in milliseconds from a payload, returning the
+// zero time when it is missing.
+func payloadTime(payload map[string]any, key string) time.Time {
+	if ms, ok := payload[key].(float64); ok && ms > 0 {
+		return time.UnixMilli(int64(ms))
+	}
+	return time.Time{}
+}
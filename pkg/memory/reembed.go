@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// PendingEmbeddingKey is the metadata key that marks a memory stored without
+// an embedding because the embedder failed, until ReembedPending embeds it.
+const PendingEmbeddingKey = "_pending_embedding"
+
+// WithEmbeddingFallback keeps memories whose embedding fails instead of
+// losing them: they are stored without an embedding and queued for
+// ReembedPending. Until then searches leave them out of the vector results
+// and find them by keyword instead. Content long enough to be chunked is not
+// covered, and still fails when the embedder does.
+func WithEmbeddingFallback(enabled bool) UnifiedOption {
+	return func(u *UnifiedMemory) {
+		u.embeddingFallback = enabled
+	}
+}
+
+// embed sets the embedding of a memory. With the embedding fallback, an
+// embedder failure marks the memory pending instead of failing, and is
+// reported through the returned flag.
+func (u *UnifiedMemory) embed(ctx context.Context, mem *Memory) (bool, error) {
+	emb, err := u.embedder.Embed(ctx, mem.Content)
+	if err == nil {
+		mem.Embedding = emb
+		mem.EmbeddingModel = EmbeddingModelOf(u.embedder)
+		return false, nil
+	}
+
+	if !u.embeddingFallback {
+		return false, err
+	}
+
+	log.Warn("failed to embed memory, storing it for re-embedding", "error", err)
+
+	mem.Metadata = copyMetadata(mem.Metadata)
+	mem.Metadata[PendingEmbeddingKey] = true
+	return true, nil
+}
+
+func (u *UnifiedMemory) markPending(id string) {
+	u.pendingMutex.Lock()
+	defer u.pendingMutex.Unlock()
+
+	u.pending[id] = struct{}{}
+}
+
+// pendingIDs returns the IDs of the memories waiting for an embedding, in
+// ascending order.
+func (u *UnifiedMemory) pendingIDs() []string {
+	u.pendingMutex.Lock()
+	defer u.pendingMutex.Unlock()
+
+	ids := make([]string, 0, len(u.pending))
+	for id := range u.pending {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+func (u *UnifiedMemory) isPending(id string) bool {
+	u.pendingMutex.Lock()
+	defer u.pendingMutex.Unlock()
+
+	_, ok := u.pending[id]
+	return ok
+}
+
+// ReembedPending embeds the memories that were stored without an embedding
+// and updates them in the stores. It stops at the first embedder failure,
+// leaving the rest queued for the next run, and returns how many memories
+// were embedded. Memories that were deleted in the meantime are dropped from
+// the queue.
+func (u *UnifiedMemory) ReembedPending(ctx context.Context) (int, error) {
+	if u.embedder == nil || u.vector == nil {
+		return 0, nil
+	}
+
+	embedded := 0
+
+	for _, id := range u.pendingIDs() {
+		mem, err := u.vector.GetMemory(ctx, id)
+		if err != nil {
+			u.unmarkPending(id)
+			continue
+		}
+
+		emb, err := u.embedder.Embed(ctx, mem.Content)
+		if err != nil {
+			return embedded, fmt.Errorf("failed to re-embed memory %s: %w", id, err)
+		}
+
+		mem.Embedding = emb
+		mem.EmbeddingModel = EmbeddingModelOf(u.embedder)
+		mem.Metadata = copyMetadata(mem.Metadata)
+		delete(mem.Metadata, PendingEmbeddingKey)
+
+		if err := u.vector.UpdateMemory(ctx, mem); err != nil {
+			return embedded, err
+		}
+
+		if u.graph != nil {
+			if err := u.graph.UpdateMemory(ctx, mem); err != nil {
+				return embedded, fmt.Errorf("failed to update memory in graph store: %w", err)
+			}
+		}
+
+		u.cache.Set(mem)
+		u.unmarkPending(id)
+		embedded++
+	}
+
+	return embedded, nil
+}
+
+func (u *UnifiedMemory) unmarkPending(id string) {
+	u.pendingMutex.Lock()
+	defer u.pendingMutex.Unlock()
+
+	delete(u.pending, id)
+}
+
+// ReembedEvery runs ReembedPending every interval until ctx is done, logging
+// failures instead of stopping on them. It blocks, so it is meant to run in
+// its own goroutine.
+func (u *UnifiedMemory) ReembedEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			embedded, err := u.ReembedPending(ctx)
+			if err != nil {
+				log.Warn("failed to re-embed pending memories", "error", err)
+			}
+			if embedded > 0 {
+				log.Debug("re-embedded pending memories", "count", embedded)
+			}
+		}
+	}
+}
+
+// withPendingMatches leaves the memories waiting for an embedding out of
+// vector results, where they only score by chance, and adds those whose
+// content holds terms of the query instead. They are added to the first page
+// only, after the vector results and as far as the limit allows.
+func (u *UnifiedMemory) withPendingMatches(ctx context.Context, query string, params SearchParams, results []Memory) []Memory {
+	ids := u.pendingIDs()
+	if len(ids) == 0 {
+		return results
+	}
+
+	out := make([]Memory, 0, len(results))
+	for _, mem := range results {
+		if !u.isPending(mem.ID) {
+			out = append(out, mem)
+		}
+	}
+
+	if params.Offset > 0 {
+		return out
+	}
+
+	terms := keywordTerms(query)
+
+	for _, id := range ids {
+		if params.Limit > 0 && len(out) >= params.Limit {
+			break
+		}
+
+		mem, err := u.vector.GetMemory(ctx, id)
+		if err != nil {
+			continue
+		}
+
+		if len(params.Types) > 0 && !containsString(params.Types, mem.Type) {
+			continue
+		}
+
+		if !matchesFilters(mem.Metadata, params.Filters) || keywordScore(mem.Content, terms) == 0 {
+			continue
+		}
+
+		out = append(out, mem)
+	}
+
+	return out
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+flakyEmbedder fails while down, and embeds like keywordEmbedder otherwise.
+*/
+type flakyEmbedder struct {
+	keywordEmbedder
+	down bool
+}
+
+func (m *flakyEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if m.down {
+		return nil, errors.New("embedding service unavailable")
+	}
+	return m.keywordEmbedder.Embed(ctx, text)
+}
+
+func TestEmbeddingFallback(t *testing.T) {
+	Convey("Given a unified memory with the embedding fallback and an embedder that is down", t, func() {
+		ctx := context.Background()
+		embedder := &flakyEmbedder{down: true}
+		vs := NewInMemoryVectorStore()
+		um := NewUnifiedStore(embedder, vs, nil, WithEmbeddingFallback(true))
+
+		Convey("When a memory is stored", func() {
+			id, err := um.StoreMemory(ctx, "the alpha build failed with E1234", nil, "fact")
+
+			Convey("Then it should be stored without an embedding, marked pending", func() {
+				So(err, ShouldBeNil)
+
+				stored, err := vs.GetMemory(ctx, id)
+				So(err, ShouldBeNil)
+				So(stored.Embedding, ShouldBeEmpty)
+				So(stored.Metadata[PendingEmbeddingKey], ShouldEqual, true)
+			})
+
+			Convey("Then it should be found by keyword once the embedder recovers", func() {
+				embedder.down = false
+
+				results, err := um.SearchSimilar(ctx, "e1234", SearchParams{Limit: 5})
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(results[0].ID, ShouldEqual, id)
+
+				results, err = um.SearchSimilar(ctx, "unrelated", SearchParams{Limit: 5})
+				So(err, ShouldBeNil)
+				So(results, ShouldBeEmpty)
+			})
+
+			Convey("Then re-embedding should wait while the embedder is still down", func() {
+				embedded, err := um.ReembedPending(ctx)
+				So(err, ShouldNotBeNil)
+				So(embedded, ShouldEqual, 0)
+				So(um.pendingIDs(), ShouldResemble, []string{id})
+			})
+
+			Convey("Then it should get a real embedding after the embedder recovers and re-embedding runs", func() {
+				embedder.down = false
+
+				embedded, err := um.ReembedPending(ctx)
+				So(err, ShouldBeNil)
+				So(embedded, ShouldEqual, 1)
+				So(um.pendingIDs(), ShouldBeEmpty)
+
+				stored, err := vs.GetMemory(ctx, id)
+				So(err, ShouldBeNil)
+				So(stored.Embedding, ShouldResemble, []float32{1, 0, 0.01})
+				So(stored.Metadata, ShouldNotContainKey, PendingEmbeddingKey)
+
+				results, err := um.SearchSimilar(ctx, "alpha", SearchParams{Limit: 5})
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(results[0].ID, ShouldEqual, id)
+			})
+		})
+	})
+
+	Convey("Given a unified memory without the embedding fallback and an embedder that is down", t, func() {
+		um := NewUnifiedStore(&flakyEmbedder{down: true}, NewInMemoryVectorStore(), nil)
+
+		Convey("When a memory is stored", func() {
+			_, err := um.StoreMemory(context.Background(), "the alpha build failed", nil, "fact")
+
+			Convey("Then it should fail as before", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EmbedderOptions configures an embedder created by GetEmbedder. Empty fields
+// fall back to the embedder's defaults, such as its default model or the API
+// key in the environment.
+type EmbedderOptions struct {
+	Model   string
+	APIKey  string
+	BaseURL string
+}
+
+// EmbedderFactory creates an embedder from options.
+type EmbedderFactory func(opts EmbedderOptions) (Embedder, error)
+
+var (
+	embeddersMu sync.RWMutex
+	embedders   = map[string]EmbedderFactory{
+		"mock": func(opts EmbedderOptions) (Embedder, error) {
+			if opts.Model == "" {
+				return NewMockEmbeddingService(), nil
+			}
+			return NewMockEmbeddingServiceLike(opts.Model), nil
+		},
+	}
+)
+
+// RegisterEmbedder makes an embedder available to GetEmbedder under name,
+// replacing any earlier registration. The provider package registers its
+// embedders when it is imported.
+func RegisterEmbedder(name string, factory EmbedderFactory) {
+	embeddersMu.Lock()
+	defer embeddersMu.Unlock()
+
+	embedders[strings.ToLower(name)] = factory
+}
+
+// GetEmbedder creates the embedder registered under name, so configuration
+// such as `memory.embedder: openai` can pick one.
+func GetEmbedder(name string, opts EmbedderOptions) (Embedder, error) {
+	embeddersMu.RLock()
+	factory, ok := embedders[strings.ToLower(name)]
+	embeddersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown embedder %q, registered embedders are: %s",
+			name, strings.Join(Embedders(), ", "),
+		)
+	}
+
+	embedder, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedder %q: %w", name, err)
+	}
+
+	return embedder, nil
+}
+
+// Embedders returns the names of the registered embedders in sorted order.
+func Embedders() []string {
+	embeddersMu.RLock()
+	defer embeddersMu.RUnlock()
+
+	names := make([]string, 0, len(embedders))
+	for name := range embedders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// DimensionedEmbedder is implemented by embedders that know the length of
+// the vectors they produce.
+type DimensionedEmbedder interface {
+	Dimension() int
+}
+
+// DimensionedVectorStore is implemented by vector stores that can report the
+// length of the vectors they hold. A dimension of zero means the store does
+// not hold any vectors yet and accepts any length.
+type DimensionedVectorStore interface {
+	Dimension(ctx context.Context) (int, error)
+}
+
+// EmbedderDimension returns the vector length of the embedder, from the
+// embedder itself or from the known dimension of its model.
+func EmbedderDimension(embedder Embedder) (int, bool) {
+	if de, ok := embedder.(DimensionedEmbedder); ok {
+		return de.Dimension(), true
+	}
+
+	return EmbeddingDimension(EmbeddingModelOf(embedder))
+}
+
+// CheckDimensions returns an error when the embedder produces vectors of
+// another length than the vector store holds, which would otherwise only
+// surface as failing writes or meaningless searches. It passes when either
+// dimension is unknown.
+func CheckDimensions(ctx context.Context, embedder Embedder, vector VectorStore) error {
+	embedderDim, ok := EmbedderDimension(embedder)
+	if !ok {
+		return nil
+	}
+
+	ds, ok := vector.(DimensionedVectorStore)
+	if !ok {
+		return nil
+	}
+
+	storeDim, err := ds.Dimension(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get vector store dimension: %w", err)
+	}
+
+	if storeDim != 0 && storeDim != embedderDim {
+		return fmt.Errorf(
+			"embedder %q produces vectors of dimension %d, but the vector store holds vectors of dimension %d",
+			EmbeddingModelOf(embedder), embedderDim, storeDim,
+		)
+	}
+
+	return nil
+}
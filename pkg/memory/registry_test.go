@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEmbedderRegistry(t *testing.T) {
+	Convey("Given the embedder registry", t, func() {
+		Convey("When the mock embedder is resolved by name with a model", func() {
+			embedder, err := GetEmbedder("Mock", EmbedderOptions{Model: "nomic-embed-text"})
+
+			Convey("Then it should mirror the dimension of the model", func() {
+				So(err, ShouldBeNil)
+				dim, ok := EmbedderDimension(embedder)
+				So(ok, ShouldBeTrue)
+				So(dim, ShouldEqual, 768)
+			})
+		})
+
+		Convey("When an embedder is registered", func() {
+			RegisterEmbedder("custom", func(opts EmbedderOptions) (Embedder, error) {
+				return NewMockEmbeddingServiceLike(opts.Model), nil
+			})
+
+			embedder, err := GetEmbedder("custom", EmbedderOptions{Model: "all-minilm"})
+
+			Convey("Then it should be resolved by its name", func() {
+				So(err, ShouldBeNil)
+				So(EmbeddingModelOf(embedder), ShouldEqual, "all-minilm")
+				So(Embedders(), ShouldContain, "custom")
+			})
+		})
+
+		Convey("When an unknown embedder is resolved", func() {
+			_, err := GetEmbedder("word2vec", EmbedderOptions{})
+
+			Convey("Then the error should name it and list the registered embedders", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, `unknown embedder "word2vec"`)
+				So(err.Error(), ShouldContainSubstring, "mock")
+			})
+		})
+	})
+}
+
+func TestCheckDimensions(t *testing.T) {
+	Convey("Given a vector store holding 4-dimensional vectors", t, func() {
+		ctx := context.Background()
+		store := NewInMemoryVectorStore()
+		_, err := store.StoreMemory(ctx, Memory{Content: "hello", Embedding: []float32{1, 0, 0, 0}})
+		So(err, ShouldBeNil)
+
+		Convey("Then an embedder of the same dimension should pass", func() {
+			So(CheckDimensions(ctx, NewMockEmbeddingService(), store), ShouldBeNil)
+		})
+
+		Convey("Then an embedder of another dimension should be rejected", func() {
+			err := CheckDimensions(ctx, NewMockEmbeddingServiceLike("text-embedding-3-small"), store)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "dimension 1536")
+			So(err.Error(), ShouldContainSubstring, "dimension 4")
+		})
+
+		Convey("Then an empty store should accept any dimension", func() {
+			So(CheckDimensions(ctx, NewMockEmbeddingServiceLike("text-embedding-3-small"), NewInMemoryVectorStore()), ShouldBeNil)
+		})
+	})
+}
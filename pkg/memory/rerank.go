@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// DefaultRerankDepth is how many vector search candidates are reranked when
+// WithReranker is given no depth.
+const DefaultRerankDepth = 50
+
+// RerankScoreKey is the metadata key under which rerankers that score their
+// candidates report the relevance of each one.
+const RerankScoreKey = "_rerank_score"
+
+// Reranker reorders the candidates of a vector search by how relevant they
+// are to the query, most relevant first. It may drop candidates, but must not
+// add any.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, candidates []Memory) ([]Memory, error)
+}
+
+// NoopReranker keeps the candidates in the order of the vector search.
+type NoopReranker struct{}
+
+// Rerank returns the candidates unchanged.
+func (NoopReranker) Rerank(ctx context.Context, query string, candidates []Memory) ([]Memory, error) {
+	return candidates, nil
+}
+
+// WithReranker reranks the top depth candidates of every SearchSimilar and
+// SearchSimilarPage before the offset and limit of the search are applied,
+// trading a slower search for more precise results. A depth that is not
+// positive means DefaultRerankDepth.
+func WithReranker(reranker Reranker, depth int) UnifiedOption {
+	return func(u *UnifiedMemory) {
+		if depth <= 0 {
+			depth = DefaultRerankDepth
+		}
+		u.reranker = reranker
+		u.rerankDepth = depth
+	}
+}
+
+// CrossEncoderReranker scores every candidate against the query with a
+// cross-encoder served over HTTP, which reads query and memory together and
+// so judges relevance better than comparing their embeddings. It speaks the
+// rerank API shared by Cohere, Jina and Text Embeddings Inference:
+//
+//	POST {"model": ..., "query": ..., "documents": [...]}
+//	  -> {"results": [{"index": 0, "relevance_score": 0.93}, ...]}
+type CrossEncoderReranker struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+}
+
+// CrossEncoderOption configures a CrossEncoderReranker.
+type CrossEncoderOption func(*CrossEncoderReranker)
+
+// WithRerankModel sets the model named in every rerank request.
+func WithRerankModel(model string) CrossEncoderOption {
+	return func(r *CrossEncoderReranker) {
+		r.model = model
+	}
+}
+
+// WithRerankAPIKey sends the key as a bearer token with every request.
+func WithRerankAPIKey(apiKey string) CrossEncoderOption {
+	return func(r *CrossEncoderReranker) {
+		r.apiKey = apiKey
+	}
+}
+
+// WithRerankHTTPClient sets the HTTP client used to reach the endpoint.
+func WithRerankHTTPClient(client *http.Client) CrossEncoderOption {
+	return func(r *CrossEncoderReranker) {
+		r.client = client
+	}
+}
+
+// NewCrossEncoderReranker creates a reranker posting to the rerank endpoint,
+// such as https://api.cohere.com/v2/rerank.
+func NewCrossEncoderReranker(endpoint string, options ...CrossEncoderOption) *CrossEncoderReranker {
+	reranker := &CrossEncoderReranker{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, option := range options {
+		option(reranker)
+	}
+
+	return reranker
+}
+
+// Rerank orders the candidates by the relevance the endpoint scores them
+// with, recording each score under RerankScoreKey. Candidates the endpoint
+// leaves out of its results are dropped.
+func (r *CrossEncoderReranker) Rerank(ctx context.Context, query string, candidates []Memory) ([]Memory, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	documents := make([]string, len(candidates))
+	for i, mem := range candidates {
+		documents[i] = mem.Content
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model":     r.model,
+		"query":     query,
+		"documents": documents,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rerank request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rerank request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("rerank request failed with status %s", resp.Status)
+	}
+
+	var out struct {
+		Results []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+		} `json:"results"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode rerank response: %w", err)
+	}
+
+	scored := make([]Memory, 0, len(out.Results))
+	seen := make(map[int]bool, len(out.Results))
+
+	for _, result := range out.Results {
+		if result.Index < 0 || result.Index >= len(candidates) || seen[result.Index] {
+			return nil, fmt.Errorf("rerank response has an invalid index %d", result.Index)
+		}
+		seen[result.Index] = true
+
+		mem := candidates[result.Index]
+		mem.Metadata = copyMetadata(mem.Metadata)
+		mem.Metadata[RerankScoreKey] = result.RelevanceScore
+		scored = append(scored, mem)
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Metadata[RerankScoreKey].(float64) > scored[j].Metadata[RerankScoreKey].(float64)
+	})
+
+	return scored, nil
+}
+
+// rerank reranks the candidates of a search and cuts out the page params
+// selects.
+func (u *UnifiedMemory) rerank(ctx context.Context, query string, candidates []Memory, params SearchParams) ([]Memory, error) {
+	reranked, err := u.reranker.Rerank(ctx, query, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rerank search results: %w", err)
+	}
+
+	if params.Offset >= len(reranked) {
+		return []Memory{}, nil
+	}
+
+	reranked = reranked[params.Offset:]
+
+	if params.Limit > 0 && len(reranked) > params.Limit {
+		reranked = reranked[:params.Limit]
+	}
+
+	return reranked, nil
+}
+
+// rerankCandidates returns the params of the vector search feeding the
+// reranker: the first rerankDepth results, or more when the requested page
+// reaches further, plus one to tell whether a page follows.
+func (u *UnifiedMemory) rerankCandidates(params SearchParams) SearchParams {
+	candidates := params
+	candidates.Offset = 0
+	candidates.Limit = max(u.rerankDepth, params.Offset+params.Limit+1)
+	return candidates
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// reversingReranker reverses the order of its candidates and remembers how
+// many it was given.
+type reversingReranker struct {
+	given int
+}
+
+func (r *reversingReranker) Rerank(ctx context.Context, query string, candidates []Memory) ([]Memory, error) {
+	r.given = len(candidates)
+	out := slices.Clone(candidates)
+	slices.Reverse(out)
+	return out, nil
+}
+
+func TestReranker(t *testing.T) {
+	Convey("Given five ranked memories", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+
+		So(vs.StoreMemories(ctx, []Memory{
+			{ID: "m1", Embedding: []float32{1, 0}},
+			{ID: "m2", Embedding: []float32{1, 1}},
+			{ID: "m3", Embedding: []float32{1, 2}},
+			{ID: "m4", Embedding: []float32{1, 3}},
+			{ID: "m5", Embedding: []float32{1, 4}},
+		}), ShouldBeNil)
+
+		embedder := &countingEmbedder{vectors: map[string][]float32{"query": {1, 0}}}
+
+		ids := func(mems []Memory) []string {
+			out := make([]string, 0, len(mems))
+			for _, mem := range mems {
+				out = append(out, mem.ID)
+			}
+			return out
+		}
+
+		Convey("When searching through a reranker that reverses the candidates", func() {
+			reranker := &reversingReranker{}
+			um := NewUnifiedStore(embedder, vs, nil, WithReranker(reranker, 0))
+
+			results, err := um.SearchSimilar(ctx, "query", SearchParams{Limit: 2})
+			So(err, ShouldBeNil)
+
+			Convey("Then the limit should apply to the reranked order", func() {
+				So(reranker.given, ShouldEqual, 5)
+				So(ids(results), ShouldResemble, []string{"m5", "m4"})
+			})
+		})
+
+		Convey("When the rerank depth is smaller than the store", func() {
+			reranker := &reversingReranker{}
+			um := NewUnifiedStore(embedder, vs, nil, WithReranker(reranker, 3))
+
+			results, err := um.SearchSimilar(ctx, "query", SearchParams{Limit: 2})
+			So(err, ShouldBeNil)
+
+			Convey("Then only the top candidates should be reranked", func() {
+				So(reranker.given, ShouldEqual, 3)
+				So(ids(results), ShouldResemble, []string{"m3", "m2"})
+			})
+		})
+
+		Convey("When paging through reranked results", func() {
+			um := NewUnifiedStore(embedder, vs, nil, WithReranker(&reversingReranker{}, 0))
+
+			first, err := um.SearchSimilarPage(ctx, "query", SearchParams{Limit: 3})
+			So(err, ShouldBeNil)
+
+			second, err := um.SearchSimilarPage(ctx, "query", SearchParams{Limit: 3, Offset: first.NextOffset})
+			So(err, ShouldBeNil)
+
+			Convey("Then the pages should follow the reranked order", func() {
+				So(ids(first.Memories), ShouldResemble, []string{"m5", "m4", "m3"})
+				So(first.NextOffset, ShouldEqual, 3)
+				So(ids(second.Memories), ShouldResemble, []string{"m2", "m1"})
+				So(second.NextOffset, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When searching through the no-op reranker", func() {
+			um := NewUnifiedStore(embedder, vs, nil, WithReranker(NoopReranker{}, 0))
+
+			results, err := um.SearchSimilar(ctx, "query", SearchParams{Limit: 2})
+			So(err, ShouldBeNil)
+
+			Convey("Then the vector order should be kept", func() {
+				So(ids(results), ShouldResemble, []string{"m1", "m2"})
+			})
+		})
+	})
+}
+
+func TestCrossEncoderReranker(t *testing.T) {
+	Convey("Given a rerank endpoint scoring two of three documents", t, func() {
+		var (
+			request       map[string]any
+			authorization string
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			json.NewDecoder(r.Body).Decode(&request)
+
+			fmt.Fprint(w, `{"results":[{"index":1,"relevance_score":0.2},{"index":0,"relevance_score":0.9}]}`)
+		}))
+		defer ts.Close()
+
+		reranker := NewCrossEncoderReranker(ts.URL, WithRerankModel("rerank-v3"), WithRerankAPIKey("secret"))
+
+		Convey("When reranking candidates", func() {
+			out, err := reranker.Rerank(context.Background(), "deploys", []Memory{
+				{ID: "a", Content: "the deploy runs hourly"},
+				{ID: "b", Content: "lunch is at noon"},
+				{ID: "c", Content: "unscored"},
+			})
+
+			Convey("Then they should be ordered by relevance and scored", func() {
+				So(err, ShouldBeNil)
+				So(authorization, ShouldEqual, "Bearer secret")
+				So(request["model"], ShouldEqual, "rerank-v3")
+				So(request["query"], ShouldEqual, "deploys")
+				So(request["documents"], ShouldHaveLength, 3)
+
+				So(out, ShouldHaveLength, 2)
+				So(out[0].ID, ShouldEqual, "a")
+				So(out[0].Metadata[RerankScoreKey], ShouldEqual, 0.9)
+				So(out[1].ID, ShouldEqual, "b")
+			})
+		})
+	})
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchSimilarPage(t *testing.T) {
+	Convey("Given a unified memory holding five ranked memories", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+
+		// The further a vector turns away from the query, the lower it ranks.
+		So(vs.StoreMemories(ctx, []Memory{
+			{ID: "m1", Embedding: []float32{1, 0}},
+			{ID: "m2", Embedding: []float32{1, 1}},
+			{ID: "m3", Embedding: []float32{1, 2}},
+			{ID: "m4", Embedding: []float32{1, 3}},
+			{ID: "m5", Embedding: []float32{1, 4}},
+		}), ShouldBeNil)
+
+		um := NewUnifiedStore(&countingEmbedder{vectors: map[string][]float32{"query": {1, 0}}}, vs, nil)
+
+		search := func(offset int) SearchPage {
+			page, err := um.SearchSimilarPage(ctx, "query", SearchParams{Limit: 2, Offset: offset})
+			So(err, ShouldBeNil)
+			return page
+		}
+
+		ids := func(page SearchPage) []string {
+			out := make([]string, 0, len(page.Memories))
+			for _, mem := range page.Memories {
+				out = append(out, mem.ID)
+			}
+			return out
+		}
+
+		Convey("When paging through the results two at a time", func() {
+			first := search(0)
+			second := search(first.NextOffset)
+			last := search(second.NextOffset)
+
+			Convey("Then every memory should be returned once, best first", func() {
+				So(ids(first), ShouldResemble, []string{"m1", "m2"})
+				So(first.NextOffset, ShouldEqual, 2)
+
+				So(ids(second), ShouldResemble, []string{"m3", "m4"})
+				So(second.NextOffset, ShouldEqual, 4)
+
+				So(ids(last), ShouldResemble, []string{"m5"})
+				So(last.NextOffset, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When the offset is past the end", func() {
+			page := search(10)
+
+			Convey("Then the page should be empty", func() {
+				So(page.Memories, ShouldBeEmpty)
+				So(page.NextOffset, ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -1,5 +1,7 @@
 package memory
 
+import "time"
+
 // Memory represents a single unit of stored knowledge.
 type Memory struct {
 	ID        string
@@ -7,6 +9,21 @@ type Memory struct {
 	Metadata  map[string]any
 	Type      string
 	Embedding []float32
+	// EmbeddingModel names the model that produced Embedding. It is empty
+	// when the embedder does not report its model.
+	EmbeddingModel string
+	// ExpiresAt is when the memory expires, after which stores no longer
+	// return it. The zero time means it never expires.
+	ExpiresAt time.Time
+	// CreatedAt is when the memory was first stored, and UpdatedAt when its
+	// content or metadata last changed.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Expired reports whether the memory has expired at now.
+func (m Memory) Expired(now time.Time) bool {
+	return !m.ExpiresAt.IsZero() && !now.Before(m.ExpiresAt)
 }
 
 // Relation connects two memories in the graph store.
@@ -24,9 +41,44 @@ type Filter struct {
 	Value    any
 }
 
-// SearchParams specify vector search options.
+// ScoreKey is the metadata key under which vector stores that report
+// similarity scores return the score of each search result.
+const ScoreKey = "_score"
+
+// SearchParams specify vector search options. Offset skips that many of the
+// best matches, so results can be paged through Limit at a time.
+//
+// Hybrid also ranks memories by how many terms of Query their content holds,
+// so exact terms such as error codes are found even when they are not
+// semantically close. KeywordWeight is the share of the score given to those
+// terms, DefaultKeywordWeight when zero. The unified store sets Query to the
+// search query when it is empty.
 type SearchParams struct {
-	Limit   int
-	Types   []string
-	Filters []Filter
+	Limit         int
+	Offset        int
+	Types         []string
+	Filters       []Filter
+	Hybrid        bool
+	Query         string
+	KeywordWeight float64
 }
+
+// SearchPage is one page of search results. NextOffset is the Offset of the
+// following page, or zero when this page is the last.
+type SearchPage struct {
+	Memories   []Memory
+	NextOffset int
+}
+
+// ModelMismatchPolicy decides what a search does with memories embedded by
+// a different model than the one used for the query.
+type ModelMismatchPolicy string
+
+const (
+	// ModelMismatchWarn logs mismatched results but still returns them.
+	ModelMismatchWarn ModelMismatchPolicy = "warn"
+	// ModelMismatchFilter drops mismatched results.
+	ModelMismatchFilter ModelMismatchPolicy = "filter"
+	// ModelMismatchIgnore returns all results without checking.
+	ModelMismatchIgnore ModelMismatchPolicy = "ignore"
+)
@@ -4,8 +4,12 @@ package memory
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/charmbracelet/log"
 )
 
 // UnifiedMemory implements the UnifiedStore interface with caching and batching.
@@ -19,6 +23,41 @@ type UnifiedMemory struct {
 	memBatch     []Memory
 	batchMutex   sync.Mutex
 	batchTimer   *time.Timer
+	normalize    bool
+	modelPolicy  ModelMismatchPolicy
+	chunkSize    int
+	chunkOverlap int
+	conversation ConversationStrategy
+	reranker     Reranker
+	rerankDepth  int
+
+	idempotent      bool
+	idempotentMutex sync.Mutex
+
+	embeddingFallback bool
+	pending           map[string]struct{}
+	pendingMutex      sync.Mutex
+}
+
+// UnifiedOption configures a UnifiedMemory.
+type UnifiedOption func(*UnifiedMemory)
+
+// WithNormalize L2-normalizes every embedding before it is stored or used as
+// a query, for stores and metrics that assume unit-length vectors. Embedders
+// that already return normalized vectors are unaffected.
+func WithNormalize(normalize bool) UnifiedOption {
+	return func(u *UnifiedMemory) {
+		u.normalize = normalize
+	}
+}
+
+// WithModelMismatchPolicy sets what searches do with memories that were
+// embedded by a different model than the query. The default is
+// ModelMismatchWarn.
+func WithModelMismatchPolicy(policy ModelMismatchPolicy) UnifiedOption {
+	return func(u *UnifiedMemory) {
+		u.modelPolicy = policy
+	}
 }
 
 // MemoryCache provides a simple in-memory cache for frequently accessed memories
@@ -53,8 +92,8 @@ func (c *MemoryCache) Get(id string) (Memory, bool) {
 		return Memory{}, false
 	}
 
-	// Check if item has expired
-	if time.Since(item.timestamp) > c.expiration {
+	// Check if item has expired, or the memory itself has
+	if time.Since(item.timestamp) > c.expiration || item.memory.Expired(time.Now()) {
 		c.mu.Lock()
 		delete(c.items, id)
 		c.mu.Unlock()
@@ -93,7 +132,7 @@ func (c *MemoryCache) Set(memory Memory) {
 }
 
 // NewUnifiedStore creates a new unified memory store with caching and batching
-func NewUnifiedStore(embedder Embedder, vector VectorStore, graph GraphStore) *UnifiedMemory {
+func NewUnifiedStore(embedder Embedder, vector VectorStore, graph GraphStore, options ...UnifiedOption) *UnifiedMemory {
 	store := &UnifiedMemory{
 		embedder:     embedder,
 		vector:       vector,
@@ -102,6 +141,20 @@ func NewUnifiedStore(embedder Embedder, vector VectorStore, graph GraphStore) *U
 		batchSize:    50,
 		batchTimeout: 5 * time.Second,
 		memBatch:     make([]Memory, 0, 50),
+		modelPolicy:  ModelMismatchWarn,
+		chunkSize:    DefaultChunkSize,
+		chunkOverlap: DefaultChunkOverlap,
+		pending:      make(map[string]struct{}),
+	}
+
+	for _, option := range options {
+		option(store)
+	}
+
+	// Wrapping the embedder applies normalization to stored and query vectors
+	// alike, since both are produced through it.
+	if store.normalize && store.embedder != nil {
+		store.embedder = &normalizingEmbedder{embedder: store.embedder}
 	}
 
 	store.batchTimer = time.AfterFunc(store.batchTimeout, func() {
@@ -150,40 +203,73 @@ func (u *UnifiedMemory) flushBatch() {
 	}(batch)
 }
 
-// StoreMemory stores a memory with batching for better performance
+// StoreMemory stores a memory with batching for better performance. Content
+// longer than the chunk size is split into linked chunk memories.
 func (u *UnifiedMemory) StoreMemory(ctx context.Context, content string, metadata map[string]any, memType string) (string, error) {
-	mem := Memory{Content: content, Metadata: metadata, Type: memType}
+	return u.storeMemory(ctx, Memory{Content: content, Metadata: metadata, Type: memType})
+}
+
+// StoreMemoryWithTTL stores a memory like StoreMemory that expires after
+// ttl, for scratch memories that are only useful for a while. A ttl that is
+// not positive never expires.
+func (u *UnifiedMemory) StoreMemoryWithTTL(ctx context.Context, content string, metadata map[string]any, memType string, ttl time.Duration) (string, error) {
+	return u.storeMemory(ctx, Memory{Content: content, Metadata: metadata, Type: memType, ExpiresAt: expiryAfter(ttl)})
+}
+
+func (u *UnifiedMemory) storeMemory(ctx context.Context, mem Memory) (string, error) {
+	stampCreated(&mem)
+
+	if u.idempotent {
+		// Holding the lock until the memory is stored keeps two identical
+		// memories stored at once from both missing each other.
+		u.idempotentMutex.Lock()
+		defer u.idempotentMutex.Unlock()
+
+		hash := contentHashOf(mem)
+
+		id, found, err := u.findDuplicate(ctx, hash)
+		if err != nil {
+			return "", fmt.Errorf("failed to look up content hash: %w", err)
+		}
+		if found {
+			return id, nil
+		}
+
+		mem.Metadata = copyMetadata(mem.Metadata)
+		mem.Metadata[ContentHashKey] = hash
+	}
+
+	pending := false
 
 	// Generate embedding if needed
 	if u.embedder != nil {
-		emb, err := u.embedder.Embed(ctx, content)
-		if err != nil {
+		if chunks := ChunkText(mem.Content, u.chunkSize, u.chunkOverlap); len(chunks) > 1 {
+			return u.storeChunked(ctx, mem, chunks)
+		}
+
+		var err error
+		if pending, err = u.embed(ctx, &mem); err != nil {
 			return "", err
 		}
-		mem.Embedding = emb
 	}
 
-	// Generate ID if needed
-	if mem.ID == "" {
-		id, err := u.vector.StoreMemory(ctx, mem)
+	// A memory without an embedding skips the batch, so it is stored before
+	// it is queued for re-embedding.
+	if pending {
+		id, err := u.persist(ctx, mem)
 		if err != nil {
 			return "", err
 		}
-		mem.ID = id
-
-		// Add to cache
-		u.cache.Set(mem)
-
-		// Store in graph if available
-		if u.graph != nil {
-			if _, err := u.graph.StoreMemory(ctx, mem); err != nil {
-				return "", fmt.Errorf("failed to store memory in graph store: %w", err)
-			}
-		}
 
+		u.markPending(id)
 		return id, nil
 	}
 
+	// Generate ID if needed
+	if mem.ID == "" {
+		return u.persist(ctx, mem)
+	}
+
 	// Add to batch for efficient storage
 	u.batchMutex.Lock()
 	defer u.batchMutex.Unlock()
@@ -204,6 +290,49 @@ func (u *UnifiedMemory) StoreMemory(ctx context.Context, content string, metadat
 	return mem.ID, nil
 }
 
+// persist writes a memory to the vector store, the cache and the graph
+// store, if available, and returns the ID the vector store assigned.
+func (u *UnifiedMemory) persist(ctx context.Context, mem Memory) (string, error) {
+	id, err := u.vector.StoreMemory(ctx, mem)
+	if err != nil {
+		return "", err
+	}
+	mem.ID = id
+
+	// Add to cache
+	u.cache.Set(mem)
+
+	// Store in graph if available
+	if u.graph != nil {
+		if _, err := u.graph.StoreMemory(ctx, mem); err != nil {
+			return "", fmt.Errorf("failed to store memory in graph store: %w", err)
+		}
+	}
+
+	return id, nil
+}
+
+// Sweep implements Sweeper, sweeping the vector and graph stores that
+// implement it.
+func (u *UnifiedMemory) Sweep(ctx context.Context) (int, error) {
+	swept := 0
+
+	for _, store := range []any{u.vector, u.graph} {
+		sweeper, ok := store.(Sweeper)
+		if !ok {
+			continue
+		}
+
+		n, err := sweeper.Sweep(ctx)
+		swept += n
+		if err != nil {
+			return swept, err
+		}
+	}
+
+	return swept, nil
+}
+
 // CreateRelation creates a relation between two memories
 func (u *UnifiedMemory) CreateRelation(ctx context.Context, source, target, relationType string, properties map[string]any) error {
 	if u.graph == nil {
@@ -212,29 +341,194 @@ func (u *UnifiedMemory) CreateRelation(ctx context.Context, source, target, rela
 	return u.graph.CreateRelation(ctx, Relation{SourceID: source, TargetID: target, Type: relationType, Properties: properties})
 }
 
-// SearchSimilar searches for similar memories with caching
+// SearchSimilar searches for similar memories with caching. Results are
+// ordered by descending score, with ties broken by ascending ID whenever the
+// vector store reports scores. With a reranker, they are in the order it
+// gives the top candidates instead.
 func (u *UnifiedMemory) SearchSimilar(ctx context.Context, query string, params SearchParams) ([]Memory, error) {
 	if u.vector == nil || u.embedder == nil {
 		return nil, nil
 	}
 
-	// Generate embedding
-	emb, err := u.embedder.Embed(ctx, query)
+	return u.search(ctx, query, params)
+}
+
+// SearchSimilarPage runs SearchSimilar for the page params selects, and
+// reports where the next page starts. The vector store is asked for one
+// result more than the limit, to learn whether there is a next page.
+func (u *UnifiedMemory) SearchSimilarPage(ctx context.Context, query string, params SearchParams) (SearchPage, error) {
+	if u.vector == nil || u.embedder == nil {
+		return SearchPage{}, nil
+	}
+
+	if params.Limit <= 0 {
+		results, err := u.SearchSimilar(ctx, query, params)
+		return SearchPage{Memories: results}, err
+	}
+
+	probe := params
+	probe.Limit++
+
+	results, err := u.search(ctx, query, probe)
+	if err != nil {
+		return SearchPage{}, err
+	}
+
+	page := SearchPage{}
+
+	if len(results) > params.Limit {
+		results = results[:params.Limit]
+		page.NextOffset = params.Offset + params.Limit
+	}
+
+	page.Memories = results
+	return page, nil
+}
+
+// search runs the vector search for params, reranking its top candidates
+// before the page is cut out when a reranker is set. Memories waiting for an
+// embedding are matched by keyword instead.
+func (u *UnifiedMemory) search(ctx context.Context, query string, params SearchParams) ([]Memory, error) {
+	results, err := u.searchVector(ctx, query, u.vectorParams(params))
 	if err != nil {
 		return nil, err
 	}
 
-	// Search vector store
-	results, err := u.vector.SearchSimilar(ctx, emb, params)
+	return u.rank(ctx, query, params, results)
+}
+
+// vectorParams returns the params of the vector search behind a search for
+// params, which asks for the reranker's candidates when one is set.
+func (u *UnifiedMemory) vectorParams(params SearchParams) SearchParams {
+	if u.reranker == nil {
+		return params
+	}
+
+	return u.rerankCandidates(params)
+}
+
+// rank turns the results of the vector search for a query into the results
+// of the search: collected, reranked when a reranker is set, and joined by
+// the pending memories the query matches.
+func (u *UnifiedMemory) rank(ctx context.Context, query string, params SearchParams, results []Memory) ([]Memory, error) {
+	results = u.collect(results)
+
+	if u.reranker != nil {
+		var err error
+
+		if results, err = u.rerank(ctx, query, results, params); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.withPendingMatches(ctx, query, params, results), nil
+}
+
+// searchVector embeds the query and searches the vector store with it.
+func (u *UnifiedMemory) searchVector(ctx context.Context, query string, params SearchParams) ([]Memory, error) {
+	if params.Hybrid && params.Query == "" {
+		params.Query = query
+	}
+
+	emb, err := u.embedder.Embed(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update cache with results
+	return u.vector.SearchSimilar(ctx, emb, params)
+}
+
+// collect orders search results, checks their embedding models and caches
+// them.
+func (u *UnifiedMemory) collect(results []Memory) []Memory {
+	results = orderResults(u.checkModels(results))
+
 	for _, mem := range results {
 		u.cache.Set(mem)
 	}
 
+	return results
+}
+
+// SearchSimilarBatch embeds all queries in a single call and runs the
+// vector searches concurrently, returning the results in query order. Each
+// result then goes through the same reranking and pending matches as
+// SearchSimilar.
+func (u *UnifiedMemory) SearchSimilarBatch(ctx context.Context, queries []string, params SearchParams) ([][]Memory, error) {
+	if u.vector == nil || u.embedder == nil || len(queries) == 0 {
+		return make([][]Memory, len(queries)), nil
+	}
+
+	embeddings, err := u.embedder.EmbedBatch(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embeddings) != len(queries) {
+		return nil, fmt.Errorf("embedder returned %d embeddings for %d queries", len(embeddings), len(queries))
+	}
+
+	var (
+		results      [][]Memory
+		vectorParams = u.vectorParams(params)
+	)
+
+	if vectorParams.Hybrid && vectorParams.Query == "" {
+		// Every query brings its own keywords, so each is searched on its own.
+		results, err = searchEach(ctx, u.vector, embeddings, func(i int) SearchParams {
+			queryParams := vectorParams
+			queryParams.Query = queries[i]
+			return queryParams
+		})
+	} else if batch, ok := u.vector.(BatchVectorStore); ok {
+		results, err = batch.SearchSimilarBatch(ctx, embeddings, vectorParams)
+	} else {
+		results, err = searchConcurrently(ctx, u.vector, embeddings, vectorParams)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, memories := range results {
+		if results[i], err = u.rank(ctx, queries[i], params, memories); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// searchConcurrently runs one SearchSimilar per embedding in parallel and
+// returns the first error encountered, if any.
+func searchConcurrently(ctx context.Context, store VectorStore, embeddings [][]float32, params SearchParams) ([][]Memory, error) {
+	return searchEach(ctx, store, embeddings, func(int) SearchParams { return params })
+}
+
+// searchEach is searchConcurrently with the params of each search given by
+// paramsFor.
+func searchEach(ctx context.Context, store VectorStore, embeddings [][]float32, paramsFor func(int) SearchParams) ([][]Memory, error) {
+	results := make([][]Memory, len(embeddings))
+	errs := make([]error, len(embeddings))
+
+	var wg sync.WaitGroup
+
+	for i, emb := range embeddings {
+		wg.Add(1)
+		go func(i int, emb []float32) {
+			defer wg.Done()
+			results[i], errs[i] = store.SearchSimilar(ctx, emb, paramsFor(i))
+		}(i, emb)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return results, nil
 }
 
@@ -289,6 +583,8 @@ func (u *UnifiedMemory) InjectMemories(ctx context.Context, task TaskLike) error
 		return err
 	}
 
+	mems = orderResults(u.checkModels(mems))
+
 	// Add memories to task
 	for _, m := range mems {
 		task.AddMessage("system", "memory", m.Content)
@@ -314,14 +610,157 @@ func (u *UnifiedMemory) InjectMemories(ctx context.Context, task TaskLike) error
 	return nil
 }
 
-// ExtractMemories extracts memories from a task with batching
+// ExtractMemories extracts memories from a task with batching. With a
+// conversation strategy the conversation is stored as the strategy renders
+// it, otherwise only its last message.
 func (u *UnifiedMemory) ExtractMemories(ctx context.Context, task TaskLike) error {
 	msg := task.LastMessage()
 	if msg == nil {
 		return nil
 	}
 
+	if u.conversation != nil {
+		content, err := u.conversation(ctx, task.Messages())
+		if err != nil || content == "" {
+			return err
+		}
+
+		_, err = u.StoreMemory(ctx, content, map[string]any{"role": "conversation"}, "conversation")
+		return err
+	}
+
 	// Store memory with batching
 	_, err := u.StoreMemory(ctx, msg.String(), map[string]any{"role": msg.Role}, "message")
 	return err
 }
+
+// orderResults sorts search results by descending score, breaking ties by
+// ascending ID, so equally similar memories come back in the same order on
+// every run. Results are left as the store returned them unless every one
+// carries its score under ScoreKey.
+func orderResults(mems []Memory) []Memory {
+	scores := make([]float64, len(mems))
+	for i, mem := range mems {
+		score, ok := mem.Metadata[ScoreKey].(float64)
+		if !ok {
+			return mems
+		}
+		scores[i] = score
+	}
+
+	order := make([]int, len(mems))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		return mems[a].ID < mems[b].ID
+	})
+
+	out := make([]Memory, len(mems))
+	for i, idx := range order {
+		out[i] = mems[idx]
+	}
+	return out
+}
+
+// checkModels applies the model mismatch policy to search results, comparing
+// each memory's embedding model with the one the queries are embedded with.
+func (u *UnifiedMemory) checkModels(mems []Memory) []Memory {
+	if u.modelPolicy == ModelMismatchIgnore {
+		return mems
+	}
+
+	model := EmbeddingModelOf(u.embedder)
+	mismatched := ModelMismatches(model, mems)
+
+	if len(mismatched) == 0 {
+		return mems
+	}
+
+	if u.modelPolicy != ModelMismatchFilter {
+		log.Warn("search results were embedded by a different model",
+			"query_model", model, "mismatched", len(mismatched), "results", len(mems))
+		return mems
+	}
+
+	out := make([]Memory, 0, len(mems)-len(mismatched))
+	for _, mem := range mems {
+		if !isModelMismatch(model, mem) {
+			out = append(out, mem)
+		}
+	}
+	return out
+}
+
+// ModelMismatches returns the memories that were embedded by a model other
+// than model. Memories or queries with an unknown model never mismatch,
+// since there is nothing to compare.
+func ModelMismatches(model string, mems []Memory) []Memory {
+	var out []Memory
+	for _, mem := range mems {
+		if isModelMismatch(model, mem) {
+			out = append(out, mem)
+		}
+	}
+	return out
+}
+
+func isModelMismatch(model string, mem Memory) bool {
+	return model != "" && mem.EmbeddingModel != "" && mem.EmbeddingModel != model
+}
+
+// normalizingEmbedder L2-normalizes the vectors produced by the wrapped embedder.
+type normalizingEmbedder struct {
+	embedder Embedder
+}
+
+func (n *normalizingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	emb, err := n.embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return Normalize(emb), nil
+}
+
+// EmbeddingModel reports the model of the wrapped embedder.
+func (n *normalizingEmbedder) EmbeddingModel() string {
+	return EmbeddingModelOf(n.embedder)
+}
+
+func (n *normalizingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embs, err := n.embedder.EmbedBatch(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	for i, emb := range embs {
+		embs[i] = Normalize(emb)
+	}
+	return embs, nil
+}
+
+// Normalize returns a copy of vec scaled to unit L2 length. A zero vector is
+// returned unchanged since it has no direction.
+func Normalize(vec []float32) []float32 {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+
+	out := make([]float32, len(vec))
+	copy(out, vec)
+
+	if sum == 0 {
+		return out
+	}
+
+	norm := math.Sqrt(sum)
+	for i, v := range out {
+		out[i] = float32(float64(v) / norm)
+	}
+	return out
+}
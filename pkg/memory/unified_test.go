@@ -2,6 +2,8 @@ package memory
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/theapemachine/a2a-go/pkg/a2a"
@@ -31,14 +33,17 @@ func (m *mockVectorStore) StoreMemory(ctx context.Context, mem Memory) (string,
 	return "1", nil
 }
 func (m *mockVectorStore) StoreMemories(ctx context.Context, mems []Memory) error { return nil }
+func (m *mockVectorStore) UpdateMemory(ctx context.Context, mem Memory) error     { return nil }
 func (m *mockVectorStore) GetMemory(ctx context.Context, id string) (Memory, error) {
 	return Memory{}, nil
 }
 func (m *mockVectorStore) SearchSimilar(ctx context.Context, embedding []float32, params SearchParams) ([]Memory, error) {
 	return []Memory{{ID: "m1", Content: "previous"}}, nil
 }
-func (m *mockVectorStore) DeleteMemory(ctx context.Context, id string) error { return nil }
-func (m *mockVectorStore) Ping(ctx context.Context) error                    { return nil }
+func (m *mockVectorStore) DeleteMemory(ctx context.Context, id string) error          { return nil }
+func (m *mockVectorStore) DeleteMemories(ctx context.Context, ids []string) error     { return nil }
+func (m *mockVectorStore) DeleteByFilter(ctx context.Context, filters []Filter) error { return nil }
+func (m *mockVectorStore) Ping(ctx context.Context) error                             { return nil }
 
 func TestUnifiedMemoryInjectAndExtract(t *testing.T) {
 	Convey("Given a unified memory with mock stores", t, func() {
@@ -66,3 +71,378 @@ func TestUnifiedMemoryInjectAndExtract(t *testing.T) {
 		})
 	})
 }
+
+type countingEmbedder struct {
+	vectors    map[string][]float32
+	batchCalls int
+}
+
+func (m *countingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return m.vectors[text], nil
+}
+func (m *countingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	m.batchCalls++
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = m.vectors[text]
+	}
+	return out, nil
+}
+
+func TestUnifiedMemorySearchSimilarBatch(t *testing.T) {
+	Convey("Given a unified memory over a populated vector store", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+		So(vs.StoreMemories(ctx, []Memory{
+			{ID: "a", Content: "alpha", Embedding: []float32{1, 0, 0}},
+			{ID: "b", Content: "beta", Embedding: []float32{0, 1, 0}},
+			{ID: "c", Content: "gamma", Embedding: []float32{0, 0, 1}},
+		}), ShouldBeNil)
+
+		embedder := &countingEmbedder{vectors: map[string][]float32{
+			"first":  {0.9, 0.1, 0},
+			"second": {0, 0.2, 0.8},
+			"third":  {0.1, 0.9, 0.3},
+		}}
+		um := NewUnifiedStore(embedder, vs, nil)
+		queries := []string{"first", "second", "third"}
+		params := SearchParams{Limit: 2}
+
+		Convey("When the queries are searched as a batch", func() {
+			batch, err := um.SearchSimilarBatch(ctx, queries, params)
+			So(err, ShouldBeNil)
+
+			Convey("Then the embedder should be called once for the batch", func() {
+				So(embedder.batchCalls, ShouldEqual, 1)
+			})
+
+			Convey("Then each result should match the individual search", func() {
+				So(len(batch), ShouldEqual, len(queries))
+				for i, query := range queries {
+					single, err := um.SearchSimilar(ctx, query, params)
+					So(err, ShouldBeNil)
+					So(batch[i], ShouldResemble, single)
+				}
+			})
+		})
+
+		Convey("When the queries are searched as a batch through a reranker", func() {
+			reranked := NewUnifiedStore(embedder, vs, nil, WithReranker(&reversingReranker{}, 0))
+			batch, err := reranked.SearchSimilarBatch(ctx, queries, params)
+			So(err, ShouldBeNil)
+
+			Convey("Then each result should match the reranked individual search", func() {
+				for i, query := range queries {
+					single, err := reranked.SearchSimilar(ctx, query, params)
+					So(err, ShouldBeNil)
+					So(batch[i], ShouldResemble, single)
+				}
+
+				So(batch[0][0].ID, ShouldNotEqual, "a")
+			})
+		})
+
+		Convey("When the vector store has no batch support", func() {
+			fallback := NewUnifiedStore(embedder, &mockVectorStore{}, nil)
+			batch, err := fallback.SearchSimilarBatch(ctx, queries, params)
+
+			Convey("Then the searches should still run per query", func() {
+				So(err, ShouldBeNil)
+				So(len(batch), ShouldEqual, len(queries))
+				So(batch[2][0].Content, ShouldEqual, "previous")
+			})
+		})
+	})
+}
+
+type recordingVectorStore struct {
+	mockVectorStore
+	mu      sync.Mutex
+	queries [][]float32
+}
+
+func (m *recordingVectorStore) SearchSimilar(ctx context.Context, embedding []float32, params SearchParams) ([]Memory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queries = append(m.queries, embedding)
+	return nil, nil
+}
+
+type scaledEmbedder struct{}
+
+func (m *scaledEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{3, 4}, nil
+}
+func (m *scaledEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{6, 8}
+	}
+	return out, nil
+}
+
+func TestNormalize(t *testing.T) {
+	unitLength := func(vec []float32) float64 {
+		var sum float64
+		for _, v := range vec {
+			sum += float64(v) * float64(v)
+		}
+		return sum
+	}
+
+	Convey("Given a vector that is not unit length", t, func() {
+		vec := []float32{3, 4}
+
+		Convey("Then Normalize should scale it to unit length without mutating it", func() {
+			out := Normalize(vec)
+			So(unitLength(out), ShouldAlmostEqual, 1.0, 1e-6)
+			So(out, ShouldResemble, []float32{0.6, 0.8})
+			So(vec, ShouldResemble, []float32{3, 4})
+		})
+
+		Convey("Then a zero vector should be left unchanged", func() {
+			So(Normalize([]float32{0, 0}), ShouldResemble, []float32{0, 0})
+		})
+	})
+
+	Convey("Given a unified memory with normalization enabled", t, func() {
+		ctx := context.Background()
+		vs := &recordingVectorStore{}
+		um := NewUnifiedStore(&scaledEmbedder{}, vs, nil, WithNormalize(true))
+
+		_, err := um.StoreMemory(ctx, "remember this", nil, "fact")
+		So(err, ShouldBeNil)
+		_, err = um.SearchSimilar(ctx, "recall", SearchParams{Limit: 1})
+		So(err, ShouldBeNil)
+		_, err = um.SearchSimilarBatch(ctx, []string{"a", "b"}, SearchParams{Limit: 1})
+		So(err, ShouldBeNil)
+
+		Convey("Then stored and query vectors should both be unit length", func() {
+			So(len(vs.stored), ShouldEqual, 1)
+			So(unitLength(vs.stored[0].Embedding), ShouldAlmostEqual, 1.0, 1e-6)
+
+			So(len(vs.queries), ShouldEqual, 3)
+			for _, query := range vs.queries {
+				So(unitLength(query), ShouldAlmostEqual, 1.0, 1e-6)
+			}
+			So(vs.queries[0], ShouldResemble, vs.stored[0].Embedding)
+		})
+	})
+
+	Convey("Given a unified memory without normalization", t, func() {
+		vs := &recordingVectorStore{}
+		um := NewUnifiedStore(&scaledEmbedder{}, vs, nil)
+		_, err := um.SearchSimilar(context.Background(), "recall", SearchParams{Limit: 1})
+		So(err, ShouldBeNil)
+
+		Convey("Then query vectors should be passed through untouched", func() {
+			So(vs.queries[0], ShouldResemble, []float32{3, 4})
+		})
+	})
+}
+
+// modelEmbedder returns a fixed vector and reports the given model name.
+type modelEmbedder struct {
+	model string
+}
+
+func (m *modelEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{1, 0}, nil
+}
+
+func (m *modelEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{1, 0}
+	}
+	return out, nil
+}
+
+func (m *modelEmbedder) EmbeddingModel() string { return m.model }
+
+func TestEmbeddingModelTracking(t *testing.T) {
+	Convey("Given memories stored by two embedders with different models", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+
+		oldID, err := NewUnifiedStore(&modelEmbedder{model: "old-model"}, vs, nil).StoreMemory(ctx, "old fact", nil, "fact")
+		So(err, ShouldBeNil)
+		newID, err := NewUnifiedStore(&modelEmbedder{model: "new-model"}, vs, nil).StoreMemory(ctx, "new fact", nil, "fact")
+		So(err, ShouldBeNil)
+
+		Convey("Then each memory should record the model that embedded it", func() {
+			oldMem, err := vs.GetMemory(ctx, oldID)
+			So(err, ShouldBeNil)
+			So(oldMem.EmbeddingModel, ShouldEqual, "old-model")
+
+			newMem, err := vs.GetMemory(ctx, newID)
+			So(err, ShouldBeNil)
+			So(newMem.EmbeddingModel, ShouldEqual, "new-model")
+		})
+
+		Convey("When searching with the new model", func() {
+			results, err := NewUnifiedStore(&modelEmbedder{model: "new-model"}, vs, nil).SearchSimilar(ctx, "fact", SearchParams{Limit: 10})
+			So(err, ShouldBeNil)
+
+			Convey("Then the mismatch should be detectable", func() {
+				So(len(results), ShouldEqual, 2)
+				mismatched := ModelMismatches("new-model", results)
+				So(len(mismatched), ShouldEqual, 1)
+				So(mismatched[0].ID, ShouldEqual, oldID)
+			})
+		})
+
+		Convey("When searching with the filter policy", func() {
+			um := NewUnifiedStore(&modelEmbedder{model: "new-model"}, vs, nil, WithModelMismatchPolicy(ModelMismatchFilter))
+			results, err := um.SearchSimilar(ctx, "fact", SearchParams{Limit: 10})
+			So(err, ShouldBeNil)
+
+			Convey("Then memories from the other model should be dropped", func() {
+				So(len(results), ShouldEqual, 1)
+				So(results[0].ID, ShouldEqual, newID)
+			})
+		})
+
+		Convey("When the query embedder does not report a model", func() {
+			Convey("Then nothing should count as a mismatch", func() {
+				results, err := NewUnifiedStore(&mockEmbedder{}, vs, nil, WithModelMismatchPolicy(ModelMismatchFilter)).SearchSimilar(ctx, "fact", SearchParams{Limit: 10})
+				So(err, ShouldBeNil)
+				So(len(results), ShouldEqual, 2)
+			})
+		})
+	})
+}
+
+type keywordEmbedder struct{}
+
+func (m *keywordEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{
+		float32(strings.Count(text, "alpha")),
+		float32(strings.Count(text, "omega")),
+		0.01,
+	}, nil
+}
+func (m *keywordEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i], _ = m.Embed(ctx, text)
+	}
+	return out, nil
+}
+
+func TestChunkText(t *testing.T) {
+	Convey("Given text longer than the chunk size", t, func() {
+		text := "one two  three\nfour five six seven"
+
+		Convey("Then it should be split into overlapping chunks keeping formatting", func() {
+			So(ChunkText(text, 3, 1), ShouldResemble, []string{
+				"one two  three",
+				"three\nfour five",
+				"five six seven",
+			})
+		})
+
+		Convey("Then text within the chunk size should be returned whole", func() {
+			So(ChunkText(text, 10, 2), ShouldResemble, []string{text})
+			So(ChunkText(text, 0, 0), ShouldResemble, []string{text})
+		})
+	})
+}
+
+func TestStoreMemoryChunking(t *testing.T) {
+	Convey("Given a unified memory with a small chunk size", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+		gs := NewInMemoryGraphStore()
+		um := NewUnifiedStore(&keywordEmbedder{}, vs, gs, WithChunking(10, 2))
+
+		document := "alpha " + strings.Repeat("filler ", 30) + "omega"
+
+		Convey("When a long document is stored", func() {
+			parentID, err := um.StoreMemory(ctx, document, map[string]any{"source": "doc"}, "document")
+			So(err, ShouldBeNil)
+
+			Convey("Then the parent should keep the whole document", func() {
+				parent, err := vs.GetMemory(ctx, parentID)
+				So(err, ShouldBeNil)
+				So(parent.Content, ShouldEqual, document)
+				So(parent.Metadata["chunk_count"], ShouldEqual, 4)
+			})
+
+			Convey("Then a search should hit a chunk linked to the parent", func() {
+				results, err := um.SearchSimilar(ctx, "omega", SearchParams{Limit: 1})
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+
+				chunk := results[0]
+				So(chunk.Content, ShouldEndWith, "omega")
+				So(chunk.Content, ShouldNotEqual, document)
+				So(chunk.Metadata["parent_id"], ShouldEqual, parentID)
+				So(chunk.Metadata["chunk_index"], ShouldEqual, 3)
+				So(chunk.Metadata["source"], ShouldEqual, "doc")
+
+				parents, err := um.FindRelated(ctx, chunk.ID, []string{RelationPartOf}, 1)
+				So(err, ShouldBeNil)
+				So(parents, ShouldHaveLength, 1)
+				So(parents[0].ID, ShouldEqual, parentID)
+				So(parents[0].Content, ShouldEqual, document)
+			})
+		})
+
+		Convey("When a short memory is stored", func() {
+			id, err := um.StoreMemory(ctx, "alpha omega", nil, "fact")
+			So(err, ShouldBeNil)
+
+			Convey("Then it should not be chunked", func() {
+				mem, err := vs.GetMemory(ctx, id)
+				So(err, ShouldBeNil)
+				So(mem.Content, ShouldEqual, "alpha omega")
+				So(mem.Metadata, ShouldBeNil)
+			})
+		})
+	})
+}
+
+type scoredVectorStore struct {
+	mockVectorStore
+	results []Memory
+}
+
+func (m *scoredVectorStore) SearchSimilar(ctx context.Context, embedding []float32, params SearchParams) ([]Memory, error) {
+	return append([]Memory(nil), m.results...), nil
+}
+
+func TestSearchOrdering(t *testing.T) {
+	Convey("Given an in-memory store with two memories of identical score", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+		So(vs.StoreMemories(ctx, []Memory{
+			{ID: "c", Content: "weaker", Embedding: []float32{0.5, 0}},
+			{ID: "b", Content: "tied", Embedding: []float32{1, 0}},
+			{ID: "a", Content: "tied", Embedding: []float32{1, 0}},
+		}), ShouldBeNil)
+
+		Convey("Then ties should be ordered by ascending ID on every run", func() {
+			for range 20 {
+				results, err := vs.SearchSimilar(ctx, []float32{1, 0}, SearchParams{})
+				So(err, ShouldBeNil)
+				So([]string{results[0].ID, results[1].ID, results[2].ID}, ShouldResemble, []string{"a", "b", "c"})
+			}
+		})
+	})
+
+	Convey("Given a vector store reporting tied scores out of ID order", t, func() {
+		vs := &scoredVectorStore{results: []Memory{
+			{ID: "y", Metadata: map[string]any{ScoreKey: 0.9}},
+			{ID: "z", Metadata: map[string]any{ScoreKey: 0.5}},
+			{ID: "x", Metadata: map[string]any{ScoreKey: 0.9}},
+		}}
+		um := NewUnifiedStore(&mockEmbedder{}, vs, nil)
+
+		Convey("Then the unified store should order by score, then ascending ID", func() {
+			results, err := um.SearchSimilar(context.Background(), "query", SearchParams{})
+			So(err, ShouldBeNil)
+			So([]string{results[0].ID, results[1].ID, results[2].ID}, ShouldResemble, []string{"x", "y", "z"})
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// stampCreated sets the creation time of a memory that is stored for the
+// first time, unless the caller already set one. Times are taken in UTC,
+// which drops the monotonic reading, so they survive a snapshot unchanged.
+func stampCreated(mem *Memory) {
+	if mem.CreatedAt.IsZero() {
+		mem.CreatedAt = time.Now().UTC()
+	}
+	if mem.UpdatedAt.IsZero() {
+		mem.UpdatedAt = mem.CreatedAt
+	}
+}
+
+// stampUpdated returns the update of existing, carrying over its creation
+// time and recording the time of the update.
+func stampUpdated(existing, update Memory) Memory {
+	update.CreatedAt = existing.CreatedAt
+	update.UpdatedAt = time.Now().UTC()
+	return update
+}
+
+// UpdateMemory changes the content and metadata of a memory in place, so it
+// keeps its ID and graph relations. The memory is re-embedded when its
+// content changes, and its type, expiry and creation time are kept.
+func (u *UnifiedMemory) UpdateMemory(ctx context.Context, id string, content string, metadata map[string]any) error {
+	var (
+		existing Memory
+		err      error
+	)
+
+	switch {
+	case u.vector != nil:
+		existing, err = u.vector.GetMemory(ctx, id)
+	case u.graph != nil:
+		existing, err = u.graph.GetMemory(ctx, id)
+	default:
+		return fmt.Errorf("memory %s not found", id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	mem := existing
+	mem.Content = content
+	mem.Metadata = metadata
+
+	if content != existing.Content && u.embedder != nil {
+		if mem.Embedding, err = u.embedder.Embed(ctx, content); err != nil {
+			return err
+		}
+		mem.EmbeddingModel = EmbeddingModelOf(u.embedder)
+	}
+
+	if u.vector != nil {
+		if err := u.vector.UpdateMemory(ctx, mem); err != nil {
+			return err
+		}
+	}
+
+	if u.graph != nil {
+		if err := u.graph.UpdateMemory(ctx, mem); err != nil {
+			return fmt.Errorf("failed to update memory in graph store: %w", err)
+		}
+	}
+
+	u.cache.Set(stampUpdated(existing, mem))
+	return nil
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpdateMemory(t *testing.T) {
+	Convey("Given a unified memory with two related memories", t, func() {
+		ctx := context.Background()
+		vs := NewInMemoryVectorStore()
+		gs := NewInMemoryGraphStore()
+		um := NewUnifiedStore(&keywordEmbedder{}, vs, gs)
+
+		sourceID, err := um.StoreMemory(ctx, "the project uses go", nil, "fact")
+		So(err, ShouldBeNil)
+
+		targetID, err := um.StoreMemory(ctx, "the alpha build runs nightly", map[string]any{"source": "ci"}, "fact")
+		So(err, ShouldBeNil)
+
+		So(um.CreateRelation(ctx, sourceID, targetID, "mentions", nil), ShouldBeNil)
+
+		before, err := vs.GetMemory(ctx, targetID)
+		So(err, ShouldBeNil)
+
+		Convey("When the target memory is updated", func() {
+			time.Sleep(time.Millisecond)
+			err := um.UpdateMemory(ctx, targetID, "the omega deploy runs hourly", map[string]any{"source": "ops"})
+			So(err, ShouldBeNil)
+
+			after, err := vs.GetMemory(ctx, targetID)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should keep its ID and creation time", func() {
+				So(after.ID, ShouldEqual, targetID)
+				So(after.Type, ShouldEqual, "fact")
+				So(after.CreatedAt, ShouldEqual, before.CreatedAt)
+				So(after.UpdatedAt.After(before.UpdatedAt), ShouldBeTrue)
+			})
+
+			Convey("Then its content should be re-embedded", func() {
+				So(after.Content, ShouldEqual, "the omega deploy runs hourly")
+				So(after.Metadata["source"], ShouldEqual, "ops")
+				So(after.Embedding, ShouldNotResemble, before.Embedding)
+			})
+
+			Convey("Then its relations should survive", func() {
+				related, err := um.FindRelated(ctx, sourceID, nil, 10)
+				So(err, ShouldBeNil)
+				So(related, ShouldHaveLength, 1)
+				So(related[0].ID, ShouldEqual, targetID)
+				So(related[0].Content, ShouldEqual, "the omega deploy runs hourly")
+			})
+		})
+
+		Convey("When a missing memory is updated", func() {
+			err := um.UpdateMemory(ctx, "missing", "anything", nil)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
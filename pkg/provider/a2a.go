@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+A2AProvider uses a remote A2A agent as the model behind a local agent. The
+task's latest user message is forwarded to the remote agent, and whatever it
+streams back is translated into the updates a local provider would send.
+The remote agent owns its tools, so the local tool list is not forwarded and
+no tool calls are ever surfaced to the local agent.
+*/
+type A2AProvider struct {
+	client *a2a.Client
+}
+
+type A2AProviderOption func(*A2AProvider)
+
+func NewA2AProvider(agentURL string, options ...A2AProviderOption) *A2AProvider {
+	prvdr := &A2AProvider{
+		client: a2a.NewClient(agentURL),
+	}
+
+	for _, option := range options {
+		option(prvdr)
+	}
+
+	return prvdr
+}
+
+func (prvdr *A2AProvider) Generate(
+	ctx context.Context, params *ProviderParams,
+) chan jsonrpc.Response {
+	ch := make(chan jsonrpc.Response)
+
+	go func() {
+		defer close(ch)
+
+		send := func(response jsonrpc.Response) bool {
+			select {
+			case ch <- response:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		message := lastUserMessage(params.Task)
+
+		if message == nil {
+			send(jsonrpc.Response{Error: &jsonrpc.Error{
+				Code:    errors.ErrInvalidParams.Code,
+				Message: "A2A provider: task has no user message to forward",
+			}})
+			return
+		}
+
+		events := make(chan any)
+		done := make(chan error, 1)
+
+		go func() {
+			defer close(events)
+			done <- prvdr.client.SendTaskStreaming(a2a.TaskSendParams{
+				ID:        params.Task.ID,
+				SessionID: params.Task.SessionID,
+				Message:   *message,
+			}, events)
+		}()
+
+		// The client call cannot be interrupted, so once ctx is done the rest
+		// of its events are drained, which lets it return.
+		defer func() {
+			go func() {
+				for range events {
+				}
+			}()
+		}()
+
+		final := false
+
+		for event := range events {
+			for _, response := range prvdr.translate(params.Task.ID, event) {
+				if status, ok := response.Result.(a2a.TaskStatusUpdateResult); ok && status.Final {
+					final = true
+				}
+
+				if !send(response) {
+					return
+				}
+			}
+		}
+
+		if err := <-done; err != nil {
+			log.Error("A2A provider: remote agent call failed", "error", err)
+			send(jsonrpc.Response{Error: &jsonrpc.Error{
+				Code:    errors.ErrInternal.Code,
+				Message: fmt.Sprintf("A2A provider: %v", err),
+			}})
+			return
+		}
+
+		if !final {
+			send(jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+				ID:     params.Task.ID,
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+				Final:  true,
+			}})
+		}
+	}()
+
+	return ch
+}
+
+/*
+translate turns one event from the remote agent into local updates. The
+remote agent may answer with a whole task, a status update or an artifact
+update, which are told apart by the fields they carry.
+*/
+func (prvdr *A2AProvider) translate(taskID string, event any) []jsonrpc.Response {
+	buf, err := json.Marshal(event)
+
+	if err != nil {
+		log.Error("A2A provider: failed to read remote event", "error", err)
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		log.Warn("A2A provider: ignoring unexpected remote event", "event", string(buf))
+		return nil
+	}
+
+	if _, ok := fields["artifact"]; ok {
+		var update a2a.TaskArtifactUpdateEvent
+
+		if err := json.Unmarshal(buf, &update); err != nil {
+			log.Error("A2A provider: failed to decode artifact update", "error", err)
+			return nil
+		}
+
+		return []jsonrpc.Response{{Result: a2a.TaskArtifactUpdateEvent{
+			ID: taskID, Artifact: update.Artifact, Metadata: update.Metadata,
+		}}}
+	}
+
+	if _, ok := fields["status"]; !ok {
+		log.Warn("A2A provider: ignoring unexpected remote event", "event", string(buf))
+		return nil
+	}
+
+	var remote struct {
+		Status    a2a.TaskStatus `json:"status"`
+		Final     *bool          `json:"final"`
+		Artifacts []a2a.Artifact `json:"artifacts"`
+	}
+
+	if err := json.Unmarshal(buf, &remote); err != nil {
+		log.Error("A2A provider: failed to decode remote status", "error", err)
+		return nil
+	}
+
+	out := make([]jsonrpc.Response, 0, len(remote.Artifacts)+1)
+
+	for _, artifact := range remote.Artifacts {
+		out = append(out, jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+			ID: taskID, Artifact: artifact,
+		}})
+	}
+
+	final := isFinalState(remote.Status.State)
+
+	if remote.Final != nil {
+		final = *remote.Final
+	}
+
+	return append(out, jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+		ID:     taskID,
+		Status: remote.Status,
+		Final:  final,
+	}})
+}
+
+/*
+Capabilities implements Interface. The remote agent runs its own tools, so
+none are offered through the provider.
+*/
+func (prvdr *A2AProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Streaming: true}
+}
+
+func lastUserMessage(task *a2a.Task) *a2a.Message {
+	for i := len(task.History) - 1; i >= 0; i-- {
+		if task.History[i].Role == "user" {
+			return &task.History[i]
+		}
+	}
+
+	return nil
+}
+
+func isFinalState(state a2a.TaskState) bool {
+	switch state {
+	case a2a.TaskStateCompleted, a2a.TaskStateCanceled, a2a.TaskStateFailed:
+		return true
+	}
+
+	return false
+}
@@ -3,7 +3,9 @@ package provider
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	anthropic "github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
@@ -35,8 +37,9 @@ var anthropicRoleMap = map[string]func(string) anthropic.MessageParam{
 AnthropicProvider is a provider for the Anthropic API.
 */
 type AnthropicProvider struct {
-	client *anthropic.Client
-	params *anthropic.MessageNewParams
+	client         *anthropic.Client
+	params         *anthropic.MessageNewParams
+	requestOptions []option.RequestOption
 }
 
 type AnthropicProviderOption func(*AnthropicProvider)
@@ -75,12 +78,25 @@ func (prvdr *AnthropicProvider) Generate(
 			StopSequences: params.Stop,
 		}
 
+		// Anthropic only accepts a tool choice alongside tools.
+		if !params.ParallelToolCalls && len(prvdr.params.Tools) > 0 {
+			prvdr.params.ToolChoice = anthropic.ToolChoiceUnionParam{
+				OfAuto: &anthropic.ToolChoiceAutoParam{DisableParallelToolUse: anthropic.Bool(true)},
+			}
+		}
+
 		isDone := false
+		resume := newStreamResume(params.StreamRetries)
 
 		for !isDone {
+			if !waitLimiter(ctx, params, ch) {
+				return
+			}
+
 			if params.Stream {
-				stream := prvdr.client.Messages.NewStreaming(ctx, *prvdr.params)
+				stream := prvdr.client.Messages.NewStreaming(ctx, *prvdr.params, prvdr.requestOptions...)
 				message := anthropic.Message{} // Used by accumulator
+				messages := prvdr.params.Messages
 
 				for stream.Next() {
 					event := stream.Current()
@@ -92,6 +108,7 @@ func (prvdr *AnthropicProvider) Generate(
 					switch event := event.AsAny().(type) { // then switch on the event type
 					case anthropic.ContentBlockDeltaEvent:
 						if event.Delta.Text != "" {
+							resume.add(event.Delta.Text)
 							ch <- a2a.NewArtifactResult(params.Task.ID, a2a.NewTextPart(event.Delta.Text))
 						}
 					case anthropic.ContentBlockStartEvent:
@@ -101,6 +118,9 @@ func (prvdr *AnthropicProvider) Generate(
 							log.Info("Tool use started", "name", toolUse.Name, "id", toolUse.ID)
 						}
 					case anthropic.MessageStopEvent:
+						params.addUsage(message.Usage.InputTokens, message.Usage.OutputTokens)
+						recordAnthropicFinish(params, &message)
+
 						// Handle tool use from accumulated message
 						prvdr.params.Messages = append(prvdr.params.Messages, message.ToParam())
 						assistantCalledTool := false
@@ -113,7 +133,7 @@ func (prvdr *AnthropicProvider) Generate(
 									toolUse.Name,
 									string(toolUse.Input),
 									toolUse.ID,
-									params.Task,
+									params,
 									anthropicToolResponseGenerator,
 								)
 								params.Task = updatedTask
@@ -130,24 +150,42 @@ func (prvdr *AnthropicProvider) Generate(
 							}
 						}
 
-						if !assistantCalledTool {
+						if finishTerminated(params, ch) {
+							isDone = true
+						} else if !assistantCalledTool {
 							isDone = true
 							ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{ID: params.Task.ID, Status: params.Task.Status, Final: true}}
 						}
 					}
 				}
 				if stream.Err() != nil {
+					if resume.retry(ctx, params.Task, stream.Err()) {
+						// Prefill the content streamed so far, so the model carries on where it stopped.
+						prvdr.params.Messages = append(
+							messages[:len(messages):len(messages)],
+							anthropic.NewAssistantMessage(anthropic.NewTextBlock(strings.TrimRight(resume.partial(), " \t\n"))),
+						)
+						continue
+					}
+
 					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: int(a2a.ErrorCodeInternalError), Message: stream.Err().Error()}}
 				}
 				isDone = true // Ensure loop terminates after stream or if stream.Next() finishes
 
 			} else { // Non-streaming path
-				llmResponse, err := prvdr.client.Messages.New(ctx, *prvdr.params)
+				var llmResponse *anthropic.Message
+				err := retry(ctx, params, func() (err error) {
+					llmResponse, err = prvdr.client.Messages.New(ctx, *prvdr.params, prvdr.requestOptions...)
+					return err
+				})
 				if err != nil {
 					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: int(a2a.ErrorCodeInternalError), Message: err.Error()}}
 					return // Use return for non-streaming fatal error
 				}
 
+				params.addUsage(llmResponse.Usage.InputTokens, llmResponse.Usage.OutputTokens)
+				recordAnthropicFinish(params, llmResponse)
+
 				prvdr.params.Messages = append(prvdr.params.Messages, llmResponse.ToParam())
 				assistantCalledTool := false
 				var assistantTextResponse string // Accumulate text here
@@ -167,7 +205,7 @@ func (prvdr *AnthropicProvider) Generate(
 							contentBlock.Name,
 							string(contentBlock.Input),
 							contentBlock.ID,
-							params.Task,
+							params,
 							anthropicToolResponseGenerator,
 						)
 						params.Task = updatedTask
@@ -184,7 +222,9 @@ func (prvdr *AnthropicProvider) Generate(
 					}
 				}
 
-				if !assistantCalledTool {
+				if finishTerminated(params, ch) {
+					isDone = true
+				} else if !assistantCalledTool {
 					// If no tools were called, then any accumulated text is the final response for this turn.
 					if assistantTextResponse != "" {
 						params.Task.AddFinalPart(a2a.NewTextPart(assistantTextResponse))
@@ -228,6 +268,26 @@ func (prvdr *AnthropicProvider) Generate(
 	return ch
 }
 
+/*
+recordAnthropicFinish records why Claude stopped, which tells a stop sequence
+apart from the end of its turn.
+*/
+func recordAnthropicFinish(params *ProviderParams, message *anthropic.Message) {
+	params.recordFinish(string(message.StopReason), message.StopReason == anthropic.StopReasonStopSequence)
+}
+
+/*
+Capabilities implements Interface. Anthropic has no JSON mode nor an
+embeddings API.
+*/
+func (prvdr *AnthropicProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming: true,
+		Tools:     true,
+		Vision:    true,
+	}
+}
+
 func (prvdr *AnthropicProvider) convertMessages(
 	task *a2a.Task,
 ) []anthropic.MessageParam {
@@ -291,6 +351,13 @@ func NewAnthropicEmbedder(options ...AnthropicEmbedderOption) *AnthropicEmbedder
 	return embedder
 }
 
+/*
+EmbeddingModel implements memory.ModelEmbedder.
+*/
+func (e *AnthropicEmbedder) EmbeddingModel() string {
+	return e.Model
+}
+
 func (e *AnthropicEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
 	// Note: Anthropic doesn't have a direct embedding API like OpenAI
 	// This is a placeholder implementation
@@ -313,6 +380,19 @@ func WithAnthropicClient() AnthropicProviderOption {
 	}
 }
 
+/*
+WithAnthropicDebugDump writes every message request sent to the API, and
+the raw response to it, to w. The API key is always redacted, the message
+content only when redact is set.
+*/
+func WithAnthropicDebugDump(w io.Writer, redact bool) AnthropicProviderOption {
+	return func(prvdr *AnthropicProvider) {
+		prvdr.requestOptions = append(
+			prvdr.requestOptions, option.WithMiddleware(newDebugDump(w, redact).roundTrip),
+		)
+	}
+}
+
 func WithAnthropicEmbedderModel(model string) AnthropicEmbedderOption {
 	return func(e *AnthropicEmbedder) {
 		e.Model = model
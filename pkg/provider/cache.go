@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+CachedResponse is the recorded output of a provider run: the responses it
+streamed, the messages it added to the task history and the status it left
+the task in.
+*/
+type CachedResponse struct {
+	Responses []jsonrpc.Response
+	History   []a2a.Message
+	Status    a2a.TaskStatus
+}
+
+/*
+ResponseCache stores recorded provider runs by key until their ttl runs out.
+*/
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse, ttl time.Duration)
+}
+
+/*
+MemoryResponseCache is an in-process ResponseCache. Expired entries are
+dropped when they are next looked up.
+*/
+type MemoryResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	response *CachedResponse
+	expires  time.Time
+}
+
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{entries: map[string]memoryCacheEntry{}}
+}
+
+func (cache *MemoryResponseCache) Get(key string) (*CachedResponse, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(cache.entries, key)
+		return nil, false
+	}
+
+	return entry.response, true
+}
+
+/*
+Set stores the response for ttl, or forever when ttl is zero or less.
+*/
+func (cache *MemoryResponseCache) Set(key string, response *CachedResponse, ttl time.Duration) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry := memoryCacheEntry{response: response}
+
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	cache.entries[key] = entry
+}
+
+/*
+CachedProvider replays the recorded output of an identical earlier request
+instead of calling the provider it wraps.
+*/
+type CachedProvider struct {
+	inner Interface
+	cache ResponseCache
+	ttl   time.Duration
+}
+
+/*
+CachingProvider wraps inner with a response cache, for idempotent requests
+such as classification and routing. Only requests at temperature 0 without
+tools are cached, as tool calls may have side effects and their results may
+change, and a run is only stored once it finished without errors. Other
+requests always go to inner.
+*/
+func CachingProvider(inner Interface, cache ResponseCache, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{inner: inner, cache: cache, ttl: ttl}
+}
+
+func (prvdr *CachedProvider) Capabilities() ProviderCapabilities {
+	return prvdr.inner.Capabilities()
+}
+
+func (prvdr *CachedProvider) Generate(
+	ctx context.Context, params *ProviderParams,
+) chan jsonrpc.Response {
+	if !cacheable(params) {
+		return prvdr.inner.Generate(ctx, params)
+	}
+
+	key, err := cacheKey(params)
+
+	if err != nil {
+		log.Warn("failed to hash request, skipping cache", "task", params.Task.ID, "error", err)
+		return prvdr.inner.Generate(ctx, params)
+	}
+
+	if cached, ok := prvdr.cache.Get(key); ok {
+		log.Info("provider cache hit", "task", params.Task.ID, "model", params.Model)
+		return replay(ctx, params.Task, cached)
+	}
+
+	ch := make(chan jsonrpc.Response)
+
+	go func() {
+		defer close(ch)
+
+		historyLength := len(params.Task.History)
+		recorded := &CachedResponse{}
+		failed := false
+
+		for response := range prvdr.inner.Generate(ctx, params) {
+			failed = failed || response.Error != nil
+			recorded.Responses = append(recorded.Responses, response)
+
+			select {
+			case ch <- response:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if failed || ctx.Err() != nil {
+			return
+		}
+
+		recorded.History = append(recorded.History, params.Task.History[historyLength:]...)
+		recorded.Status = params.Task.Status
+		prvdr.cache.Set(key, recorded, prvdr.ttl)
+	}()
+
+	return ch
+}
+
+/*
+cacheable reports whether the output of a request only depends on the
+request itself.
+*/
+func cacheable(params *ProviderParams) bool {
+	return params.Task != nil && params.Temperature == 0 && len(params.Tools) == 0
+}
+
+/*
+cacheKey hashes everything about a request that shapes its output.
+*/
+func cacheKey(params *ProviderParams) (string, error) {
+	buf, err := json.Marshal(map[string]any{
+		"model":             params.Model,
+		"messages":          params.Task.History,
+		"schema":            params.Schema,
+		"max_tokens":        params.MaxTokens,
+		"top_p":             params.TopP,
+		"top_k":             params.TopK,
+		"frequency_penalty": params.FrequencyPenalty,
+		"presence_penalty":  params.PresencePenalty,
+		"seed":              params.Seed,
+		"stop":              params.Stop,
+		"stream":            params.Stream,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+/*
+replay applies a cached run to task and streams its responses again,
+addressed to task rather than the task they were recorded for.
+*/
+func replay(ctx context.Context, task *a2a.Task, cached *CachedResponse) chan jsonrpc.Response {
+	ch := make(chan jsonrpc.Response)
+
+	go func() {
+		defer close(ch)
+
+		task.History = append(task.History, cached.History...)
+		task.ToStatus(cached.Status.State, cached.Status.Message)
+
+		for _, response := range cached.Responses {
+			select {
+			case ch <- retarget(response, task):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+/*
+retarget points a recorded response at task.
+*/
+func retarget(response jsonrpc.Response, task *a2a.Task) jsonrpc.Response {
+	switch result := response.Result.(type) {
+	case a2a.ArtifactResult:
+		result.ID = task.ID
+		response.Result = result
+	case a2a.TaskArtifactUpdateEvent:
+		result.ID = task.ID
+		response.Result = result
+	case a2a.TaskStatusUpdateResult:
+		result.ID = task.ID
+		response.Result = result
+	case *a2a.Task:
+		response.Result = task
+	}
+
+	return response
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+countingProvider answers every prompt with the same text, counting its
+calls.
+*/
+type countingProvider struct {
+	calls int
+}
+
+func (prvdr *countingProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Streaming: true}
+}
+
+func (prvdr *countingProvider) Generate(ctx context.Context, params *ProviderParams) chan jsonrpc.Response {
+	prvdr.calls++
+	ch := make(chan jsonrpc.Response, 1)
+
+	params.Task.AddFinalPart(a2a.NewTextPart("positive"))
+	params.Task.ToStatus(a2a.TaskStateCompleted, nil)
+	ch <- a2a.NewArtifactResult(params.Task.ID, a2a.NewTextPart("positive"))
+	close(ch)
+
+	return ch
+}
+
+func TestCachingProvider(t *testing.T) {
+	convey.Convey("Given a caching provider", t, func() {
+		inner := &countingProvider{}
+		prvdr := CachingProvider(inner, NewMemoryResponseCache(), 0)
+
+		run := func(options ...ProviderParamsOption) (*a2a.Task, []jsonrpc.Response) {
+			task := a2a.NewTask("classifier")
+			task.History = append(task.History, *a2a.NewTextMessage("user", "Classify: I love it"))
+
+			var responses []jsonrpc.Response
+			for response := range prvdr.Generate(context.Background(), NewProviderParams(task, options...)) {
+				responses = append(responses, response)
+			}
+
+			return task, responses
+		}
+
+		convey.Convey("When the same temperature 0 request is made twice", func() {
+			run(WithTemperature(0))
+			task, responses := run(WithTemperature(0))
+
+			convey.Convey("Then the second should be answered from the cache", func() {
+				convey.So(inner.calls, convey.ShouldEqual, 1)
+			})
+
+			convey.Convey("Then it should be replayed onto the new task", func() {
+				convey.So(responses, convey.ShouldHaveLength, 1)
+				convey.So(responses[0].Result.(a2a.ArtifactResult).ID, convey.ShouldEqual, task.ID)
+				// NewTask seeds the history with a system message.
+				convey.So(task.History, convey.ShouldHaveLength, 3)
+				convey.So(task.History[2].Parts[0].Text, convey.ShouldEqual, "positive")
+				convey.So(task.Status.State, convey.ShouldEqual, a2a.TaskStateCompleted)
+			})
+		})
+
+		convey.Convey("When the request is not at temperature 0", func() {
+			run(WithTemperature(0.7))
+			run(WithTemperature(0.7))
+
+			convey.Convey("Then every request should call the provider", func() {
+				convey.So(inner.calls, convey.ShouldEqual, 2)
+			})
+		})
+
+		convey.Convey("When the request has tools", func() {
+			tool := mcp.NewTool("calculator")
+			run(WithTemperature(0), WithTools(&tool))
+			run(WithTemperature(0), WithTools(&tool))
+
+			convey.Convey("Then every request should call the provider", func() {
+				convey.So(inner.calls, convey.ShouldEqual, 2)
+			})
+		})
+	})
+}
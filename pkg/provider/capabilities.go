@@ -0,0 +1,52 @@
+package provider
+
+import "strings"
+
+/*
+ProviderCapabilities lists the features a provider, or one of its models,
+supports, so callers can leave out what would otherwise be rejected at
+runtime.
+*/
+type ProviderCapabilities struct {
+	Streaming  bool `json:"streaming"`
+	Tools      bool `json:"tools"`
+	Vision     bool `json:"vision"`
+	JSONMode   bool `json:"jsonMode"`
+	Reasoning  bool `json:"reasoning"`
+	Embeddings bool `json:"embeddings"`
+}
+
+/*
+ModelCapabilityReporter is implemented by providers whose capabilities vary
+per model.
+*/
+type ModelCapabilityReporter interface {
+	ModelCapabilities(model string) ProviderCapabilities
+}
+
+/*
+CapabilitiesFor returns the capabilities of the provider for the given
+model, falling back to the provider-wide capabilities when the provider does
+not distinguish between its models or no model is given.
+*/
+func CapabilitiesFor(prvdr Interface, model string) ProviderCapabilities {
+	if reporter, ok := prvdr.(ModelCapabilityReporter); ok && model != "" {
+		return reporter.ModelCapabilities(model)
+	}
+
+	return prvdr.Capabilities()
+}
+
+/*
+hasAnyPrefix reports whether the model name starts with one of the prefixes,
+for the model families that differ from their provider's defaults.
+*/
+func hasAnyPrefix(model string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
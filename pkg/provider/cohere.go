@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	cohere "github.com/cohere-ai/cohere-go/v2"
 	cohereclient "github.com/cohere-ai/cohere-go/v2/client"
+	cohereoption "github.com/cohere-ai/cohere-go/v2/option"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/theapemachine/a2a-go/pkg/a2a"
 	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
@@ -29,8 +31,9 @@ var cohereRoleMap = map[string]func(string) *cohere.ChatMessage{
 CohereProvider is a provider for the Cohere API.
 */
 type CohereProvider struct {
-	client *cohereclient.Client
-	params *cohere.ChatRequest
+	client         *cohereclient.Client
+	params         *cohere.ChatRequest
+	requestOptions []cohereoption.RequestOption
 }
 
 type CohereProviderOption func(*CohereProvider)
@@ -70,6 +73,10 @@ func (prvdr *CohereProvider) Generate(
 
 		isDone := false
 		for !isDone {
+			if !waitLimiter(ctx, params, ch) {
+				return
+			}
+
 			prvdr.params = &cohere.ChatRequest{
 				Model:         &model,
 				Message:       currentMessage, // Built-up message string
@@ -89,7 +96,7 @@ func (prvdr *CohereProvider) Generate(
 					StopSequences: prvdr.params.StopSequences,
 				}
 
-				stream, err := prvdr.client.ChatStream(ctx, streamParams)
+				stream, err := prvdr.client.ChatStream(ctx, streamParams, prvdr.requestOptions...)
 				if err != nil {
 					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: int(a2a.ErrorCodeInternalError), Message: err.Error()}}
 					return // Fatal error for stream setup
@@ -120,6 +127,10 @@ func (prvdr *CohereProvider) Generate(
 						streamCalledTools = append(streamCalledTools, tcg.GetToolCalls()...)
 					}
 
+					if end := streamEvent.GetStreamEnd(); end != nil {
+						params.recordFinish(string(end.FinishReason), false)
+					}
+
 					if streamEvent.EventType == "stream-end" {
 						break // StreamEnd event signals the end of the current LLM turn's stream.
 					}
@@ -136,7 +147,7 @@ func (prvdr *CohereProvider) Generate(
 							toolCall.Name,
 							string(toolParamsJSON),
 							"", // Cohere doesn't use tool_call_id in its response like OpenAI
-							params.Task,
+							params,
 							cohereToolResponseGenerator,
 						)
 						params.Task = updatedTask
@@ -154,7 +165,7 @@ func (prvdr *CohereProvider) Generate(
 							ch <- jsonrpc.Response{Result: params.Task}
 						}
 					}
-					isDone = false // Need to make another call to LLM with tool results
+					isDone = finishTerminated(params, ch) // Make another call to LLM with tool results, unless one ended the task
 				} else {
 					if streamTextResponse != "" { // Final text from stream if no tools were called
 						params.Task.AddFinalPart(a2a.NewTextPart(streamTextResponse))
@@ -165,13 +176,18 @@ func (prvdr *CohereProvider) Generate(
 				}
 
 			} else { // Non-streaming path
-				response, err := prvdr.client.Chat(ctx, prvdr.params)
+				response, err := prvdr.client.Chat(ctx, prvdr.params, prvdr.requestOptions...)
 				if err != nil {
 					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: int(a2a.ErrorCodeInternalError), Message: err.Error()}}
 					return // Fatal error
 				}
 
 				assistantResponseText := response.GetText()
+
+				if reason := response.GetFinishReason(); reason != nil {
+					params.recordFinish(string(*reason), false)
+				}
+
 				currentMessage += "\n" + assistantResponseText // Add assistant's text to overall message
 				params.Task.AddMessage("assistant", assistantResponseText, "")
 
@@ -184,7 +200,7 @@ func (prvdr *CohereProvider) Generate(
 							toolCall.Name,
 							string(toolParamsJSON),
 							"",
-							params.Task,
+							params,
 							cohereToolResponseGenerator,
 						)
 						params.Task = updatedTask
@@ -197,7 +213,7 @@ func (prvdr *CohereProvider) Generate(
 							ch <- jsonrpc.Response{Result: params.Task}
 						}
 					}
-					isDone = false // Loop again to send tool results to Cohere
+					isDone = finishTerminated(params, ch) // Loop again to send tool results to Cohere, unless one ended the task
 				} else {
 					if assistantResponseText != "" { // Final text response if no tools
 						params.Task.AddFinalPart(a2a.NewTextPart(assistantResponseText))
@@ -214,6 +230,17 @@ func (prvdr *CohereProvider) Generate(
 	return ch
 }
 
+/*
+Capabilities implements Interface.
+*/
+func (prvdr *CohereProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming:  true,
+		Tools:      true,
+		Embeddings: true,
+	}
+}
+
 func (prvdr *CohereProvider) convertMessages(
 	task *a2a.Task,
 ) string {
@@ -278,6 +305,13 @@ func NewCohereEmbedder(options ...CohereEmbedderOption) *CohereEmbedder {
 	return embedder
 }
 
+/*
+EmbeddingModel implements memory.ModelEmbedder.
+*/
+func (e *CohereEmbedder) EmbeddingModel() string {
+	return e.Model
+}
+
 func (e *CohereEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
 	model := e.Model
 	resp, err := e.api.Embed(ctx, &cohere.EmbedRequest{
@@ -318,6 +352,19 @@ func WithCohereClient() CohereProviderOption {
 	}
 }
 
+/*
+WithCohereDebugDump writes every chat request sent to the API, and the raw
+response to it, to w. The API key is always redacted, the message content
+only when redact is set.
+*/
+func WithCohereDebugDump(w io.Writer, redact bool) CohereProviderOption {
+	return func(prvdr *CohereProvider) {
+		prvdr.requestOptions = append(
+			prvdr.requestOptions, cohereoption.WithHTTPClient(newDebugDump(w, redact).wrap(nil)),
+		)
+	}
+}
+
 func WithCohereEmbedderModel(model string) CohereEmbedderOption {
 	return func(e *CohereEmbedder) {
 		e.Model = model
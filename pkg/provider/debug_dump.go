@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+/*
+redacted replaces every secret, and with redaction every piece of message
+content, in a debug dump.
+*/
+const redacted = "[REDACTED]"
+
+/*
+secretHeaders carry credentials, so they are redacted from every dump.
+*/
+var secretHeaders = []string{
+	"Authorization", "Api-Key", "X-Api-Key", "X-Goog-Api-Key", "Cookie",
+}
+
+/*
+contentKeys are the JSON keys under which the providers send and receive
+message content. Their string values are redacted when the dump redacts
+content, which leaves the structure of the exchange and the model visible.
+*/
+var contentKeys = map[string]bool{
+	"content":   true,
+	"text":      true,
+	"prompt":    true,
+	"input":     true,
+	"system":    true,
+	"message":   true,
+	"preamble":  true,
+	"arguments": true,
+	"refusal":   true,
+}
+
+/*
+debugDump writes the raw HTTP exchanges of a provider to a writer, for
+finding out what exactly was sent to the model and what came back. It sits
+between the SDK and the network, so it sees the requests as they are sent
+and leaves them untouched. Secrets are always redacted.
+*/
+type debugDump struct {
+	mu     sync.Mutex
+	w      io.Writer
+	redact bool
+}
+
+func newDebugDump(w io.Writer, redact bool) *debugDump {
+	return &debugDump{w: w, redact: redact}
+}
+
+/*
+roundTrip dumps the request, sends it with next and dumps the response. A
+streamed response is dumped as it is read, so streaming is not held up.
+*/
+func (dump *debugDump) roundTrip(
+	req *http.Request, next func(*http.Request) (*http.Response, error),
+) (*http.Response, error) {
+	var body []byte
+
+	if req.Body != nil {
+		var err error
+
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	dump.write(
+		fmt.Sprintf(">>> %s %s\n", req.Method, dump.url(req)),
+		dump.headers(req.Header),
+		dump.body(body),
+	)
+
+	resp, err := next(req)
+
+	if err != nil {
+		dump.write(fmt.Sprintf("<<< error: %v\n\n", err))
+		return resp, err
+	}
+
+	dump.write(fmt.Sprintf("<<< %s\n", resp.Status), dump.headers(resp.Header), "\n")
+	resp.Body = &dumpedBody{ReadCloser: resp.Body, dump: dump}
+
+	return resp, nil
+}
+
+/*
+transport wraps base, http.DefaultTransport when nil, in the dump.
+*/
+func (dump *debugDump) transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return dumpTransport{dump: dump, base: base}
+}
+
+/*
+wrap returns a copy of client, http.DefaultClient when nil, that sends its
+requests through the dump.
+*/
+func (dump *debugDump) wrap(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	wrapped := *client
+	wrapped.Transport = dump.transport(client.Transport)
+
+	return &wrapped
+}
+
+func (dump *debugDump) write(parts ...string) {
+	dump.mu.Lock()
+	defer dump.mu.Unlock()
+
+	for _, part := range parts {
+		io.WriteString(dump.w, part)
+	}
+}
+
+/*
+url returns the request URL with any API key in its query redacted.
+*/
+func (dump *debugDump) url(req *http.Request) string {
+	u := *req.URL
+	query := u.Query()
+
+	if query.Has("key") {
+		query.Set("key", redacted)
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
+
+func (dump *debugDump) headers(header http.Header) string {
+	clean := header.Clone()
+
+	for _, name := range secretHeaders {
+		if clean.Get(name) != "" {
+			clean.Set(name, redacted)
+		}
+	}
+
+	var out strings.Builder
+	clean.Write(&out)
+
+	return out.String()
+}
+
+func (dump *debugDump) body(body []byte) string {
+	if len(body) == 0 {
+		return "\n"
+	}
+
+	return "\n" + dump.line(string(body)) + "\n\n"
+}
+
+/*
+line redacts the content of one line of a body, which is either JSON or a
+server-sent event carrying JSON. Anything else is dumped as it is.
+*/
+func (dump *debugDump) line(text string) string {
+	if !dump.redact {
+		return text
+	}
+
+	prefix, payload := "", text
+	if strings.HasPrefix(text, "data:") {
+		prefix, payload = "data: ", strings.TrimSpace(strings.TrimPrefix(text, "data:"))
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(payload), &value); err != nil {
+		return text
+	}
+
+	out, err := json.Marshal(redactContent(value))
+	if err != nil {
+		return text
+	}
+
+	return prefix + string(out)
+}
+
+/*
+redactContent replaces the string values under contentKeys, at any depth.
+*/
+func redactContent(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if contentKeys[key] {
+				if _, ok := field.(string); ok {
+					v[key] = redacted
+					continue
+				}
+			}
+			v[key] = redactContent(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactContent(item)
+		}
+	}
+
+	return value
+}
+
+/*
+dumpTransport is the dump as an http.RoundTripper, for SDKs that take an
+HTTP client rather than middleware.
+*/
+type dumpTransport struct {
+	dump *debugDump
+	base http.RoundTripper
+}
+
+func (transport dumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return transport.dump.roundTrip(req, transport.base.RoundTrip)
+}
+
+/*
+dumpedBody copies a response body to the dump line by line as it is read.
+*/
+type dumpedBody struct {
+	io.ReadCloser
+	dump    *debugDump
+	pending []byte
+	closed  bool
+}
+
+func (body *dumpedBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	body.pending = append(body.pending, p[:n]...)
+
+	for {
+		idx := bytes.IndexByte(body.pending, '\n')
+		if idx < 0 {
+			break
+		}
+
+		body.dump.write(body.dump.line(string(body.pending[:idx])) + "\n")
+		body.pending = body.pending[idx+1:]
+	}
+
+	if err == io.EOF {
+		body.flush()
+	}
+
+	return n, err
+}
+
+func (body *dumpedBody) Close() error {
+	body.flush()
+	return body.ReadCloser.Close()
+}
+
+/*
+flush dumps what is left of the body once it has been read or closed.
+*/
+func (body *dumpedBody) flush() {
+	if body.closed {
+		return
+	}
+
+	body.closed = true
+
+	if len(body.pending) > 0 {
+		body.dump.write(body.dump.line(string(body.pending)) + "\n")
+		body.pending = nil
+	}
+
+	body.dump.write("\n")
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestDebugDump(t *testing.T) {
+	convey.Convey("Given an OpenAI provider writing a debug dump", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, contentChunk("Hello there.", ""))
+			fmt.Fprint(w, contentChunk("", "stop"))
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		run := func(redact bool) string {
+			var dump bytes.Buffer
+
+			prvdr := NewOpenAIProvider(
+				WithOpenAIBaseURL(ts.URL),
+				WithOpenAIAPIKey("sk-secret"),
+				WithOpenAIDebugDump(&dump, redact),
+			)
+
+			task := a2a.NewTask("test")
+			task.History = append(task.History, *a2a.NewTextMessage("user", "Say hello."))
+
+			for range prvdr.Generate(context.Background(), NewProviderParams(task)) {
+			}
+
+			return dump.String()
+		}
+
+		convey.Convey("When the content is not redacted", func() {
+			dump := run(false)
+
+			convey.Convey("Then the dump should show the request and the raw response", func() {
+				convey.So(dump, convey.ShouldContainSubstring, ">>> POST "+ts.URL+"/chat/completions")
+				convey.So(dump, convey.ShouldContainSubstring, `"model":"gpt-4o-mini"`)
+				convey.So(dump, convey.ShouldContainSubstring, "Say hello.")
+				convey.So(dump, convey.ShouldContainSubstring, "<<< 200 OK")
+				convey.So(dump, convey.ShouldContainSubstring, "Hello there.")
+			})
+
+			convey.Convey("Then the API key should be redacted", func() {
+				convey.So(dump, convey.ShouldNotContainSubstring, "sk-secret")
+				convey.So(dump, convey.ShouldContainSubstring, "Authorization: [REDACTED]")
+			})
+		})
+
+		convey.Convey("When the content is redacted", func() {
+			dump := run(true)
+
+			convey.Convey("Then only the content should be hidden", func() {
+				convey.So(dump, convey.ShouldContainSubstring, `"model":"gpt-4o-mini"`)
+				convey.So(dump, convey.ShouldContainSubstring, `"role":"user"`)
+				convey.So(dump, convey.ShouldNotContainSubstring, "Say hello.")
+				convey.So(dump, convey.ShouldNotContainSubstring, "Hello there.")
+				convey.So(dump, convey.ShouldNotContainSubstring, "sk-secret")
+			})
+		})
+	})
+}
@@ -2,6 +2,8 @@ package provider
 
 import (
 	"context"
+	"io"
+	"net/http"
 	"os"
 
 	"github.com/charmbracelet/log"
@@ -53,6 +55,7 @@ DeepseekProvider is a provider for the Deepseek API.
 type DeepseekProvider struct {
 	client *deepseek.Client
 	params *deepseek.ChatCompletionRequest
+	dump   *debugDump
 }
 
 type DeepseekProviderOption func(*DeepseekProvider)
@@ -64,6 +67,12 @@ func NewDeepseekProvider(options ...DeepseekProviderOption) *DeepseekProvider {
 		option(prvdr)
 	}
 
+	// The dump wraps the HTTP client of the client, whichever option came first.
+	if prvdr.dump != nil && prvdr.client != nil {
+		httpClient, _ := prvdr.client.HTTPClient.(*http.Client)
+		prvdr.client.HTTPClient = prvdr.dump.wrap(httpClient)
+	}
+
 	return prvdr
 }
 
@@ -88,6 +97,10 @@ func (prvdr *DeepseekProvider) Generate(
 		isDone := false
 
 		for !isDone {
+			if !waitLimiter(ctx, params, ch) {
+				return
+			}
+
 			if params.Stream {
 				streamReq := &deepseek.StreamChatCompletionRequest{
 					Model:       prvdr.params.Model,
@@ -131,6 +144,7 @@ func (prvdr *DeepseekProvider) Generate(
 					}
 
 					for _, choice := range response.Choices {
+						params.recordFinish(choice.FinishReason, false)
 						fullMessage += choice.Delta.Content
 						ch <- a2a.NewArtifactResult(
 							params.Task.ID,
@@ -155,6 +169,7 @@ func (prvdr *DeepseekProvider) Generate(
 				}
 
 				if len(response.Choices) > 0 {
+					params.recordFinish(response.Choices[0].FinishReason, false)
 					content := response.Choices[0].Message.Content
 					ch <- a2a.NewArtifactResult(
 						params.Task.ID,
@@ -171,6 +186,25 @@ func (prvdr *DeepseekProvider) Generate(
 	return ch
 }
 
+/*
+Capabilities implements Interface. Tool calls returned by Deepseek are not
+executed, so tools are not reported as supported.
+*/
+func (prvdr *DeepseekProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming: true,
+	}
+}
+
+/*
+ModelCapabilities implements ModelCapabilityReporter.
+*/
+func (prvdr *DeepseekProvider) ModelCapabilities(model string) ProviderCapabilities {
+	caps := prvdr.Capabilities()
+	caps.Reasoning = model == deepseek.DeepSeekReasoner
+	return caps
+}
+
 func (prvdr *DeepseekProvider) convertMessages(
 	task *a2a.Task,
 ) []deepseek.ChatCompletionMessage {
@@ -236,6 +270,13 @@ func NewDeepseekEmbedder(options ...DeepseekEmbedderOption) *DeepseekEmbedder {
 	return embedder
 }
 
+/*
+EmbeddingModel implements memory.ModelEmbedder.
+*/
+func (e *DeepseekEmbedder) EmbeddingModel() string {
+	return e.Model
+}
+
 func (e *DeepseekEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
 	// Deepseek doesn't have a direct embedding API, so we'll use the chat completion API
 	// to generate embeddings-like output
@@ -284,6 +325,17 @@ func WithDeepseekClient() DeepseekProviderOption {
 	}
 }
 
+/*
+WithDeepseekDebugDump writes every request sent to the API, and the raw
+response to it, to w. The API key is always redacted, the message content
+only when redact is set.
+*/
+func WithDeepseekDebugDump(w io.Writer, redact bool) DeepseekProviderOption {
+	return func(prvdr *DeepseekProvider) {
+		prvdr.dump = newDebugDump(w, redact)
+	}
+}
+
 func WithDeepseekEmbedderModel(model string) DeepseekEmbedderOption {
 	return func(e *DeepseekEmbedder) {
 		e.Model = model
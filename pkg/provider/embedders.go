@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+
+	cohereclient "github.com/cohere-ai/cohere-go/v2/client"
+	cohereoption "github.com/cohere-ai/cohere-go/v2/option"
+	"github.com/ollama/ollama/api"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/theapemachine/a2a-go/pkg/memory"
+	"google.golang.org/genai"
+)
+
+/*
+ErrAnthropicEmbeddings is returned when the anthropic embedder is selected,
+as Anthropic does not offer an embeddings API.
+*/
+var ErrAnthropicEmbeddings = errors.New(
+	"anthropic does not offer an embeddings API, select another embedder such as openai, google or ollama",
+)
+
+/*
+init registers the embedders of this package with the memory embedder
+registry, so they can be selected by name from configuration.
+*/
+func init() {
+	memory.RegisterEmbedder("openai", newOpenAIEmbedderFromOptions)
+	memory.RegisterEmbedder("ollama", newOllamaEmbedderFromOptions)
+	memory.RegisterEmbedder("google", newGoogleEmbedderFromOptions)
+	memory.RegisterEmbedder("mistral", newMistralEmbedderFromOptions)
+	memory.RegisterEmbedder("cohere", newCohereEmbedderFromOptions)
+	memory.RegisterEmbedder("anthropic", func(opts memory.EmbedderOptions) (memory.Embedder, error) {
+		return nil, ErrAnthropicEmbeddings
+	})
+}
+
+/*
+embedderAPIKey returns the configured API key, or the one in the environment.
+*/
+func embedderAPIKey(opts memory.EmbedderOptions, env string) string {
+	if opts.APIKey != "" {
+		return opts.APIKey
+	}
+
+	return os.Getenv(env)
+}
+
+func newOpenAIEmbedderFromOptions(opts memory.EmbedderOptions) (memory.Embedder, error) {
+	requestOptions := []option.RequestOption{
+		option.WithAPIKey(embedderAPIKey(opts, "OPENAI_API_KEY")),
+	}
+
+	if opts.BaseURL != "" {
+		requestOptions = append(requestOptions, option.WithBaseURL(opts.BaseURL))
+	}
+
+	client := openai.NewClient(requestOptions...)
+	embedder := NewOpenAIEmbedder(
+		WithOpenAIEmbedderModel("text-embedding-3-small"),
+		WithOpenAIEmbedderClient(&client),
+	)
+
+	if opts.Model != "" {
+		embedder.Model = opts.Model
+	}
+
+	return embedder, nil
+}
+
+func newOllamaEmbedderFromOptions(opts memory.EmbedderOptions) (memory.Embedder, error) {
+	var (
+		client *api.Client
+		err    error
+	)
+
+	if opts.BaseURL != "" {
+		var base *url.URL
+
+		if base, err = url.Parse(opts.BaseURL); err != nil {
+			return nil, err
+		}
+
+		client = api.NewClient(base, http.DefaultClient)
+	} else if client, err = api.ClientFromEnvironment(); err != nil {
+		return nil, err
+	}
+
+	embedder := NewOllamaEmbedder(WithOllamaEmbedderClient(client))
+
+	if opts.Model != "" {
+		embedder.Model = opts.Model
+	}
+
+	return embedder, nil
+}
+
+func newGoogleEmbedderFromOptions(opts memory.EmbedderOptions) (memory.Embedder, error) {
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      embedderAPIKey(opts, "GOOGLE_API_KEY"),
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: opts.BaseURL},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	embedder := NewGoogleEmbedder(WithGoogleEmbedderClient(client))
+
+	if opts.Model != "" {
+		embedder.Model = opts.Model
+	}
+
+	return embedder, nil
+}
+
+func newMistralEmbedderFromOptions(opts memory.EmbedderOptions) (memory.Embedder, error) {
+	base := opts.BaseURL
+	if base == "" {
+		base = DefaultMistralBaseURL
+	}
+
+	client := openai.NewClient(
+		option.WithAPIKey(embedderAPIKey(opts, "MISTRAL_API_KEY")),
+		option.WithBaseURL(base),
+	)
+
+	embedder := NewMistralEmbedder()
+	embedder.api = &client
+
+	if opts.Model != "" {
+		embedder.Model = opts.Model
+	}
+
+	return embedder, nil
+}
+
+func newCohereEmbedderFromOptions(opts memory.EmbedderOptions) (memory.Embedder, error) {
+	requestOptions := []cohereoption.RequestOption{
+		cohereclient.WithToken(embedderAPIKey(opts, "COHERE_API_KEY")),
+	}
+
+	if opts.BaseURL != "" {
+		requestOptions = append(requestOptions, cohereclient.WithBaseURL(opts.BaseURL))
+	}
+
+	embedder := NewCohereEmbedder(
+		WithCohereEmbedderModel("embed-english-v3.0"),
+		WithCohereEmbedderClient(cohereclient.NewClient(requestOptions...)),
+	)
+
+	if opts.Model != "" {
+		embedder.Model = opts.Model
+	}
+
+	return embedder, nil
+}
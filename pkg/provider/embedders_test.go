@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/memory"
+)
+
+func TestEmbedderRegistrations(t *testing.T) {
+	convey.Convey("Given the embedders registered by the provider package", t, func() {
+		convey.Convey("When openai is resolved by name", func() {
+			embedder, err := memory.GetEmbedder("openai", memory.EmbedderOptions{
+				Model: "text-embedding-3-large", APIKey: "test",
+			})
+
+			convey.Convey("Then it should be an OpenAI embedder for the model", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(embedder, convey.ShouldHaveSameTypeAs, &OpenAIEmbedder{})
+				convey.So(memory.EmbeddingModelOf(embedder), convey.ShouldEqual, "text-embedding-3-large")
+			})
+		})
+
+		convey.Convey("When ollama is resolved without a model", func() {
+			embedder, err := memory.GetEmbedder("ollama", memory.EmbedderOptions{BaseURL: "http://localhost:11434"})
+
+			convey.Convey("Then it should use the default model", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(memory.EmbeddingModelOf(embedder), convey.ShouldEqual, "nomic-embed-text")
+			})
+		})
+
+		convey.Convey("When anthropic is resolved", func() {
+			_, err := memory.GetEmbedder("anthropic", memory.EmbedderOptions{})
+
+			convey.Convey("Then it should explain that Anthropic has no embeddings", func() {
+				convey.So(errors.Is(err, ErrAnthropicEmbeddings), convey.ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+SystemFingerprintKey is the task metadata key recording the fingerprint of
+the backend configuration that served the response.
+*/
+const SystemFingerprintKey = "system_fingerprint"
+
+/*
+SeedKey is the task metadata key recording the seed the response was
+sampled with, when one was set.
+*/
+const SeedKey = "seed"
+
+/*
+fingerprints remembers the last system fingerprint seen for each seed, so a
+backend change that breaks reproducibility does not go unnoticed. The zero
+value is ready to use.
+*/
+type fingerprints struct {
+	mu   sync.Mutex
+	seen map[int64]string
+}
+
+/*
+record puts the seed and fingerprint of a response on the task, and warns
+when the fingerprint differs from the one last seen with the same seed.
+Without a seed the fingerprint is recorded, but there is nothing to compare.
+*/
+func (prints *fingerprints) record(task *a2a.Task, seed int64, fingerprint string) {
+	if fingerprint == "" {
+		return
+	}
+
+	metadata := map[string]any{SystemFingerprintKey: fingerprint}
+
+	if seed == 0 {
+		task.MergeMetadata(metadata)
+		return
+	}
+
+	metadata[SeedKey] = seed
+	task.MergeMetadata(metadata)
+
+	prints.mu.Lock()
+	defer prints.mu.Unlock()
+
+	if prints.seen == nil {
+		prints.seen = make(map[int64]string)
+	}
+
+	if previous, ok := prints.seen[seed]; ok && previous != fingerprint {
+		log.Warn(
+			"system fingerprint changed for the same seed, responses may no longer be reproducible",
+			"seed", seed,
+			"previous", previous,
+			"fingerprint", fingerprint,
+			"task", task.ID,
+		)
+	}
+
+	prints.seen[seed] = fingerprint
+}
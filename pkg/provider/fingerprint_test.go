@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func fingerprintChunk(content, finishReason, fingerprint string) string {
+	chunk := map[string]any{
+		"id":                 "chunk",
+		"object":             "chat.completion.chunk",
+		"created":            1,
+		"model":              "gpt-4o-mini",
+		"system_fingerprint": fingerprint,
+		"choices": []map[string]any{{
+			"index":         0,
+			"delta":         map[string]any{"content": content},
+			"finish_reason": finishReason,
+		}},
+	}
+
+	buf, _ := json.Marshal(chunk)
+	return fmt.Sprintf("data: %s\n\n", buf)
+}
+
+func TestSystemFingerprint(t *testing.T) {
+	convey.Convey("Given an OpenAI provider whose backend reports a fingerprint", t, func() {
+		fingerprint := "fp_one"
+		var seeds []any
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			seeds = append(seeds, body["seed"])
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, fingerprintChunk("Hello.", "", fingerprint))
+			fmt.Fprint(w, fingerprintChunk("", "stop", fingerprint))
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		run := func(options ...ProviderParamsOption) *a2a.Task {
+			task := a2a.NewTask("test")
+			task.History = append(task.History, *a2a.NewTextMessage("user", "Say hello."))
+
+			for range prvdr.Generate(context.Background(), NewProviderParams(task, options...)) {
+			}
+
+			return task
+		}
+
+		convey.Convey("When a seed is set", func() {
+			task := run(WithSeed(42))
+
+			convey.Convey("Then the seed and fingerprint should be recorded on the task", func() {
+				convey.So(seeds[0], convey.ShouldEqual, float64(42))
+				convey.So(task.Metadata[SeedKey], convey.ShouldEqual, int64(42))
+				convey.So(task.Metadata[SystemFingerprintKey], convey.ShouldEqual, "fp_one")
+			})
+
+			convey.Convey("When the fingerprint changes for the same seed", func() {
+				fingerprint = "fp_two"
+				task := run(WithSeed(42))
+
+				convey.Convey("Then the new fingerprint should be recorded", func() {
+					convey.So(task.Metadata[SystemFingerprintKey], convey.ShouldEqual, "fp_two")
+					convey.So(prvdr.fingerprints.seen[42], convey.ShouldEqual, "fp_two")
+				})
+			})
+		})
+
+		convey.Convey("When no seed is set", func() {
+			task := run()
+
+			convey.Convey("Then no seed should be sent or recorded", func() {
+				convey.So(seeds[0], convey.ShouldBeNil)
+				convey.So(task.Metadata, convey.ShouldNotContainKey, SeedKey)
+				convey.So(task.Metadata[SystemFingerprintKey], convey.ShouldEqual, "fp_one")
+			})
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package provider
+
+/*
+Metadata keys of the terminal details of a run: why the model stopped, the
+tokens the task used, and whether one of the stop sequences ended it.
+*/
+const (
+	FinishReasonKey = "finish_reason"
+	UsageKey        = "usage"
+	StopSequenceKey = "stop_sequence"
+)
+
+/*
+recordFinish records why the model stopped generating in its last turn, in
+the provider's own terms, such as "stop", "length" or "end_turn". An empty
+reason keeps the one recorded before.
+*/
+func (params *ProviderParams) recordFinish(reason string, stopSequence bool) {
+	if reason == "" {
+		return
+	}
+
+	params.FinishReason = reason
+	params.StopSequence = stopSequence
+}
+
+/*
+FinishMetadata returns the terminal details of the run, for the final status
+event of a stream, so streaming clients learn what non-streaming callers read
+from the finished task. The finish reason is left out when the provider did
+not report one.
+*/
+func (params *ProviderParams) FinishMetadata() map[string]any {
+	metadata := map[string]any{
+		StopSequenceKey: params.StopSequence,
+	}
+
+	if params.FinishReason != "" {
+		metadata[FinishReasonKey] = params.FinishReason
+	}
+
+	if params.Task != nil && params.Task.Usage != nil {
+		metadata[UsageKey] = *params.Task.Usage
+	}
+
+	return metadata
+}
@@ -2,10 +2,13 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/theapemachine/a2a-go/pkg/a2a"
 	"github.com/theapemachine/a2a-go/pkg/errors"
@@ -39,6 +42,7 @@ GoogleProvider is a provider for the Google AI API.
 */
 type GoogleProvider struct {
 	client *genai.Client
+	dump   *debugDump
 }
 
 type GoogleProviderOption func(*GoogleProvider)
@@ -48,6 +52,21 @@ func NewGoogleProvider(options ...GoogleProviderOption) *GoogleProvider {
 	for _, option := range options {
 		option(prvdr)
 	}
+
+	// The client takes its HTTP client when it is created, so it is created
+	// again with the dump in between.
+	if prvdr.dump != nil && prvdr.client != nil {
+		config := prvdr.client.ClientConfig()
+		config.HTTPClient = prvdr.dump.wrap(config.HTTPClient)
+
+		client, err := genai.NewClient(context.Background(), &config)
+		if err != nil {
+			log.Error("failed to add the debug dump to the Google GenAI client", "error", err)
+		} else {
+			prvdr.client = client
+		}
+	}
+
 	return prvdr
 }
 
@@ -91,6 +110,10 @@ func (prvdr *GoogleProvider) Generate(
 		}
 
 		for { // Main loop for multi-turn conversation (including tool calls)
+			if !waitLimiter(ctx, params, ch) {
+				return
+			}
+
 			if params.Stream {
 				// Assumes client.Models.GenerateContentStream can take []*Content and *GenerateContentConfig
 				// The variadic parts argument might be an issue if history is []*Content.
@@ -124,9 +147,15 @@ func (prvdr *GoogleProvider) Generate(
 								log.Info("Google Provider (Streaming): Tool call", "name", fc.Name)
 								geminiContents = append(geminiContents, resp.Candidates[0].Content)
 
+								// Gemini delivers function calls whole, so there is
+								// only ever a single, complete event to preview.
+								if params.StreamToolCalls {
+									ch <- newToolCallStream(params.Task.ID).done(googleCallID(fc), fc.Name, googleCallArgs(fc))
+								}
+
 								updatedTask, llmToolMsg, toolExecErr := ExecuteAndProcessToolCall(
 									ctx, fc.Name, fmt.Sprintf("%v", fc.Args),
-									fc.Name, params.Task, googleToolResponseGenerator,
+									fc.Name, params, googleToolResponseGenerator,
 								)
 								params.Task = updatedTask
 								toolResponseContent := &genai.Content{
@@ -157,6 +186,14 @@ func (prvdr *GoogleProvider) Generate(
 					params.Task.AddMessage("assistant", accumulatedTextForThisTurn, "")
 				}
 
+				if lastCandidateWithContent != nil {
+					params.recordFinish(string(lastCandidateWithContent.FinishReason), false)
+				}
+
+				if finishTerminated(params, ch) {
+					return
+				}
+
 				if processedFunctionCallInThisStreamSegment {
 					continue // Continue main `for` loop to send updated `geminiContents` with tool response
 				}
@@ -190,7 +227,11 @@ func (prvdr *GoogleProvider) Generate(
 
 			} else { // Non-streaming path
 				// Assumes client.Models.GenerateContent can take []*Content and *GenerateContentConfig
-				resp, err := prvdr.client.Models.GenerateContent(ctx, params.Model, geminiContents, generateContentConfig)
+				var resp *genai.GenerateContentResponse
+				err := retry(ctx, params, func() (err error) {
+					resp, err = prvdr.client.Models.GenerateContent(ctx, params.Model, geminiContents, generateContentConfig)
+					return err
+				})
 				if err != nil {
 					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: errors.ErrInternal.Code, Message: err.Error()}}
 					return
@@ -202,6 +243,7 @@ func (prvdr *GoogleProvider) Generate(
 				}
 
 				assistantContent := resp.Candidates[0].Content
+				params.recordFinish(string(resp.Candidates[0].FinishReason), false)
 				geminiContents = append(geminiContents, assistantContent) // Add assistant's response to history for next potential turn
 
 				var textResponse string
@@ -224,7 +266,7 @@ func (prvdr *GoogleProvider) Generate(
 						log.Info("Google Provider (Non-Streaming): Tool call", "name", fc.Name)
 						updatedTask, llmToolMsg, toolExecErr := ExecuteAndProcessToolCall(
 							ctx, fc.Name, fmt.Sprintf("%v", fc.Args),
-							fc.Name, params.Task, googleToolResponseGenerator,
+							fc.Name, params, googleToolResponseGenerator,
 						)
 						params.Task = updatedTask
 						toolResponseContent := &genai.Content{
@@ -241,6 +283,9 @@ func (prvdr *GoogleProvider) Generate(
 							ch <- jsonrpc.Response{Result: params.Task}
 						}
 					}
+					if finishTerminated(params, ch) {
+						return
+					}
 					continue // Continue main `for` loop to send updated `geminiContents` with tool response(s)
 				} else {
 					if textResponse != "" {
@@ -275,6 +320,18 @@ func (prvdr *GoogleProvider) getSystemInstruction(task *a2a.Task) *genai.Content
 	return nil
 }
 
+/*
+Capabilities implements Interface.
+*/
+func (prvdr *GoogleProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming:  true,
+		Tools:      true,
+		Vision:     true,
+		Embeddings: true,
+	}
+}
+
 func (prvdr *GoogleProvider) convertMessages(task *a2a.Task) []*genai.Content {
 	out := make([]*genai.Content, 0, len(task.History))
 	startIdx := 0
@@ -404,7 +461,75 @@ func (prvdr *GoogleProvider) convertTools(tools []*mcp.Tool) []*genai.Tool {
 	return out
 }
 
-// GoogleEmbedder and related code would go here if needed.
+/*
+GoogleEmbedBatchLimit is the most texts the Gemini API embeds in a single
+batchEmbedContents request. EmbedBatch splits larger inputs into chunks.
+*/
+const GoogleEmbedBatchLimit = 100
+
+type GoogleEmbedder struct {
+	api   *genai.Client
+	Model string
+}
+
+type GoogleEmbedderOption func(*GoogleEmbedder)
+
+func NewGoogleEmbedder(options ...GoogleEmbedderOption) *GoogleEmbedder {
+	embedder := &GoogleEmbedder{Model: "text-embedding-004"}
+
+	for _, option := range options {
+		option(embedder)
+	}
+
+	return embedder
+}
+
+/*
+EmbeddingModel implements memory.ModelEmbedder.
+*/
+func (e *GoogleEmbedder) EmbeddingModel() string {
+	return e.Model
+}
+
+func (e *GoogleEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+/*
+EmbedBatch embeds the texts in chunks of GoogleEmbedBatchLimit, returning the
+vectors in the order of the input.
+*/
+func (e *GoogleEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+
+	for start := 0; start < len(texts); start += GoogleEmbedBatchLimit {
+		chunk := texts[start:min(start+GoogleEmbedBatchLimit, len(texts))]
+
+		contents := make([]*genai.Content, len(chunk))
+		for i, text := range chunk {
+			contents[i] = &genai.Content{Role: "user", Parts: []*genai.Part{{Text: text}}}
+		}
+
+		resp, err := e.api.Models.EmbedContent(ctx, e.Model, contents, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resp.Embeddings) != len(chunk) {
+			return nil, fmt.Errorf("google returned %d embeddings for %d texts", len(resp.Embeddings), len(chunk))
+		}
+
+		for _, embedding := range resp.Embeddings {
+			out = append(out, embedding.Values)
+		}
+	}
+
+	return out, nil
+}
 
 func WithGoogleClient() GoogleProviderOption {
 	return func(prvdr *GoogleProvider) {
@@ -424,4 +549,55 @@ func WithGoogleClient() GoogleProviderOption {
 	}
 }
 
-// Other options like WithGoogleEmbedderModel, WithGoogleEmbedderClient would go here.
+/*
+WithGoogleDebugDump writes every request sent to the API, and the raw
+response to it, to w. The API key is always redacted, the message content
+only when redact is set.
+*/
+func WithGoogleDebugDump(w io.Writer, redact bool) GoogleProviderOption {
+	return func(prvdr *GoogleProvider) {
+		prvdr.dump = newDebugDump(w, redact)
+	}
+}
+
+func WithGoogleEmbedderModel(model string) GoogleEmbedderOption {
+	return func(e *GoogleEmbedder) {
+		e.Model = model
+	}
+}
+
+func WithGoogleEmbedderClient(client *genai.Client) GoogleEmbedderOption {
+	return func(e *GoogleEmbedder) {
+		e.api = client
+	}
+}
+
+/*
+googleCallID returns the ID Gemini gave a function call, or a new one, as
+older models leave it out.
+*/
+func googleCallID(fc *genai.FunctionCall) string {
+	if fc.ID != "" {
+		return fc.ID
+	}
+
+	return "call_" + uuid.NewString()
+}
+
+/*
+googleCallArgs renders the arguments of a function call as JSON, the way
+the other providers stream them.
+*/
+func googleCallArgs(fc *genai.FunctionCall) string {
+	if fc.Args == nil {
+		return "{}"
+	}
+
+	args, err := json.Marshal(fc.Args)
+
+	if err != nil {
+		return "{}"
+	}
+
+	return string(args)
+}
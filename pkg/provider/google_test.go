@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/genai"
+)
+
+func TestGoogleEmbedder(t *testing.T) {
+	convey.Convey("Given a Google embedder behind a stub server", t, func() {
+		var (
+			paths []string
+			sizes []int
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Requests []json.RawMessage `json:"requests"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			paths = append(paths, r.URL.Path)
+			sizes = append(sizes, len(body.Requests))
+
+			embeddings := make([]string, len(body.Requests))
+			for i := range body.Requests {
+				embeddings[i] = fmt.Sprintf(`{"values":[%d]}`, len(paths)*1000+i)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"embeddings":[%s]}`, strings.Join(embeddings, ","))
+		}))
+		defer ts.Close()
+
+		client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+			APIKey:      "test",
+			Backend:     genai.BackendGeminiAPI,
+			HTTPOptions: genai.HTTPOptions{BaseURL: ts.URL},
+		})
+		convey.So(err, convey.ShouldBeNil)
+
+		embedder := NewGoogleEmbedder(WithGoogleEmbedderClient(client))
+
+		convey.Convey("When more texts are embedded than fit in one request", func() {
+			texts := make([]string, GoogleEmbedBatchLimit+10)
+			for i := range texts {
+				texts[i] = fmt.Sprintf("text %d", i)
+			}
+
+			vectors, err := embedder.EmbedBatch(context.Background(), texts)
+
+			convey.Convey("Then they should be sent in chunks under the limit", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(sizes, convey.ShouldResemble, []int{GoogleEmbedBatchLimit, 10})
+				convey.So(paths[0], convey.ShouldEndWith, "text-embedding-004:batchEmbedContents")
+			})
+
+			convey.Convey("Then the vectors should be returned in the order of the input", func() {
+				convey.So(vectors, convey.ShouldHaveLength, len(texts))
+				convey.So(vectors[0], convey.ShouldResemble, []float32{1000})
+				convey.So(vectors[GoogleEmbedBatchLimit], convey.ShouldResemble, []float32{2000})
+			})
+		})
+	})
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -11,6 +12,38 @@ import (
 
 type Interface interface {
 	Generate(context.Context, *ProviderParams) chan jsonrpc.Response
+	Capabilities() ProviderCapabilities
+}
+
+/*
+ModelLister is implemented by providers that can report the models they
+serve, so a requested model can be checked before the provider is called.
+*/
+type ModelLister interface {
+	Models(ctx context.Context) ([]string, error)
+}
+
+/*
+ToolApprovalFunc decides whether a tool call may run. It is consulted on the
+tool-dispatch path before every execution and may block until an operator
+has made a decision.
+*/
+type ToolApprovalFunc func(ctx context.Context, toolName string, args map[string]any) (bool, error)
+
+/*
+ToolCallHook observes every tool call that was actually executed, once it
+has finished, with the time it took and the error it returned, if any.
+*/
+type ToolCallHook func(toolName string, args map[string]any, duration time.Duration, err error)
+
+/*
+PlannedToolCall is a tool call captured in dry-run mode instead of being
+executed.
+*/
+type PlannedToolCall struct {
+	ID        string         `json:"id,omitempty"`
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
 }
 
 type ProviderParams struct {
@@ -27,7 +60,36 @@ type ProviderParams struct {
 	Seed              int64
 	Stop              []string
 	Stream            bool
+	StreamToolCalls   bool
 	ParallelToolCalls bool
+	ToolApproval      ToolApprovalFunc
+	DryRun            bool
+	PlannedToolCalls  []PlannedToolCall
+	ToolTranscript    []ToolCallRecord
+	OnToolCall        ToolCallHook
+	StreamRetries     int
+	ToolRetries       int
+	ToolRetryBackoff  time.Duration
+	RetryAttempts     int
+	RetryBaseDelay    time.Duration
+	RetryAfterMax     time.Duration
+	Limiter           Limiter
+	ToolTerminators   []ToolTerminator
+	TotalToolBudget   time.Duration
+	FinishReason      string
+	StopSequence      bool
+
+	// terminated is set once a tool result matched a terminator.
+	terminated bool
+
+	// toolTime is the time spent executing tools so far, and
+	// toolBudgetNotified is set once the model was told it ran out of it.
+	toolTime           time.Duration
+	toolBudgetNotified bool
+
+	// clock is what retries read the time from and wait on, the real one
+	// when nil.
+	clock clock
 }
 
 type ProviderParamsOption func(*ProviderParams)
@@ -49,6 +111,12 @@ func NewProviderParams(
 		Stop:              []string{},
 		Stream:            true,
 		ParallelToolCalls: true,
+		StreamRetries:     2,
+		ToolRetries:       2,
+		ToolRetryBackoff:  500 * time.Millisecond,
+		RetryAttempts:     3,
+		RetryBaseDelay:    time.Second,
+		RetryAfterMax:     time.Minute,
 	}
 
 	for _, option := range options {
@@ -133,8 +201,78 @@ func WithStream(stream bool) ProviderParamsOption {
 	}
 }
 
+func WithStreamToolCalls(streamToolCalls bool) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.StreamToolCalls = streamToolCalls
+	}
+}
+
 func WithParallelToolCalls(parallelToolCalls bool) ProviderParamsOption {
 	return func(params *ProviderParams) {
 		params.ParallelToolCalls = parallelToolCalls
 	}
 }
+
+func WithToolApproval(approval ToolApprovalFunc) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.ToolApproval = approval
+	}
+}
+
+func WithDryRun(dryRun bool) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.DryRun = dryRun
+	}
+}
+
+func WithToolCallHook(hook ToolCallHook) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.OnToolCall = hook
+	}
+}
+
+/*
+WithStreamRetries bounds how many times a streaming provider reconnects when
+the connection drops mid-response. Each reconnect continues from the content
+streamed so far. Zero disables reconnecting.
+*/
+func WithStreamRetries(retries int) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.StreamRetries = retries
+	}
+}
+
+/*
+WithToolRetries sets how many times a tool call that failed with a retriable
+error is retried before the error is handed to the model, and the backoff
+before the first retry, which doubles on every further attempt.
+*/
+func WithToolRetries(retries int, backoff time.Duration) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.ToolRetries = retries
+		params.ToolRetryBackoff = backoff
+	}
+}
+
+/*
+WithRetry sets how many attempts a provider makes at a call that failed with
+a rate limit or server error, and the delay before the second attempt, which
+doubles on every further attempt. Other errors are never retried.
+*/
+func WithRetry(maxAttempts int, baseDelay time.Duration) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.RetryAttempts = maxAttempts
+		params.RetryBaseDelay = baseDelay
+	}
+}
+
+/*
+WithRetryAfterMax caps how long a retry waits when the provider says when to
+come back with a Retry-After header, so a far-off date does not stall the
+task. A longer wait is cut down to the cap.
+*/
+func WithRetryAfterMax(limit time.Duration) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.RetryAfterMax = limit
+	}
+}
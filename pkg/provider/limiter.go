@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+Limiter paces the API calls a provider makes. Wait blocks until another call
+may be made, or returns the context's error as soon as it is done.
+*/
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+/*
+TokenRecorder is implemented by limiters that also budget tokens. Providers
+record the tokens of every call once its usage is known.
+*/
+type TokenRecorder interface {
+	RecordTokens(tokens int64)
+}
+
+/*
+WithLimiter paces every API call of the provider run, including each turn of
+a tool loop, through the limiter. Share one limiter between the agents that
+use the same key to keep them under its rate limits together.
+*/
+func WithLimiter(limiter Limiter) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.Limiter = limiter
+	}
+}
+
+/*
+TokenBucketLimiter limits requests per minute and tokens per minute with a
+bucket for each, refilled continuously. As the tokens of a call are only
+known after it, they are taken from the bucket afterwards, and later calls
+wait until it has refilled.
+*/
+type TokenBucketLimiter struct {
+	mu       sync.Mutex
+	requests bucket
+	tokens   bucket
+}
+
+/*
+NewTokenBucketLimiter returns a limiter allowing rpm requests and tpm tokens
+per minute. Zero leaves that dimension unlimited.
+*/
+func NewTokenBucketLimiter(rpm, tpm int) *TokenBucketLimiter {
+	now := time.Now()
+
+	return &TokenBucketLimiter{
+		requests: newBucket(rpm, now),
+		tokens:   newBucket(tpm, now),
+	}
+}
+
+/*
+Wait takes a request from the bucket, waiting for one to refill if needed,
+and for the token bucket to be out of debt.
+*/
+func (limiter *TokenBucketLimiter) Wait(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		wait := limiter.reserve(time.Now())
+
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+/*
+RecordTokens takes the tokens of a finished call from the token bucket.
+*/
+func (limiter *TokenBucketLimiter) RecordTokens(tokens int64) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.tokens.refill(time.Now())
+	limiter.tokens.level -= float64(tokens)
+}
+
+/*
+reserve takes a request when both buckets allow it, and otherwise returns
+how long to wait before trying again.
+*/
+func (limiter *TokenBucketLimiter) reserve(now time.Time) time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.requests.refill(now)
+	limiter.tokens.refill(now)
+
+	// A request needs a whole request, but only a token bucket out of debt.
+	wait := max(limiter.requests.until(1), limiter.tokens.until(0))
+
+	if wait > 0 {
+		return wait
+	}
+
+	if limiter.requests.limited() {
+		limiter.requests.level--
+	}
+
+	return 0
+}
+
+/*
+bucket holds up to capacity units and refills at capacity per minute.
+*/
+type bucket struct {
+	capacity float64
+	level    float64
+	last     time.Time
+}
+
+func newBucket(perMinute int, now time.Time) bucket {
+	return bucket{capacity: float64(perMinute), level: float64(perMinute), last: now}
+}
+
+func (b *bucket) limited() bool {
+	return b.capacity > 0
+}
+
+func (b *bucket) refill(now time.Time) {
+	if !b.limited() {
+		return
+	}
+
+	b.level = min(b.capacity, b.level+now.Sub(b.last).Minutes()*b.capacity)
+	b.last = now
+}
+
+/*
+until returns how long until the bucket holds at least need units.
+*/
+func (b *bucket) until(need float64) time.Duration {
+	if !b.limited() || b.level >= need {
+		return 0
+	}
+
+	return time.Duration((need - b.level) / b.capacity * float64(time.Minute))
+}
+
+/*
+waitLimiter waits on the limiter of params, if any, before an API call. When
+the wait fails the error is sent on ch and false is returned, so the caller
+stops the run.
+*/
+func waitLimiter(ctx context.Context, params *ProviderParams, ch chan<- jsonrpc.Response) bool {
+	if params.Limiter == nil {
+		return true
+	}
+
+	if err := params.Limiter.Wait(ctx); err != nil {
+		// A canceled caller may have stopped reading, so do not block on it.
+		select {
+		case ch <- jsonrpc.Response{Error: &jsonrpc.Error{
+			Code:    errors.ErrInternal.Code,
+			Message: "rate limiter: " + err.Error(),
+		}}:
+		case <-ctx.Done():
+		}
+
+		return false
+	}
+
+	return true
+}
+
+/*
+addUsage adds the tokens of an API call to the task, and charges them to the
+limiter when it budgets tokens.
+*/
+func (params *ProviderParams) addUsage(promptTokens, completionTokens int64) {
+	params.Task.AddUsage(promptTokens, completionTokens)
+
+	if recorder, ok := params.Limiter.(TokenRecorder); ok {
+		recorder.RecordTokens(promptTokens + completionTokens)
+	}
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+countingLimiter lets every call through and records what it was asked.
+*/
+type countingLimiter struct {
+	waits  int
+	tokens int64
+}
+
+func (limiter *countingLimiter) Wait(ctx context.Context) error {
+	limiter.waits++
+	return nil
+}
+
+func (limiter *countingLimiter) RecordTokens(tokens int64) {
+	limiter.tokens += tokens
+}
+
+func TestTokenBucketLimiter(t *testing.T) {
+	convey.Convey("Given a limiter of two requests per minute", t, func() {
+		limiter := NewTokenBucketLimiter(2, 0)
+
+		convey.Convey("When a third request is made within the minute", func() {
+			convey.So(limiter.Wait(context.Background()), convey.ShouldBeNil)
+			convey.So(limiter.Wait(context.Background()), convey.ShouldBeNil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := limiter.Wait(ctx)
+
+			convey.Convey("Then it should wait until its context is done", func() {
+				convey.So(err, convey.ShouldEqual, context.DeadlineExceeded)
+				convey.So(time.Since(start), convey.ShouldBeLessThan, time.Second)
+			})
+		})
+	})
+
+	convey.Convey("Given a limiter of a hundred tokens per minute", t, func() {
+		limiter := NewTokenBucketLimiter(0, 100)
+
+		convey.Convey("When a call used more tokens than the budget", func() {
+			convey.So(limiter.Wait(context.Background()), convey.ShouldBeNil)
+			limiter.RecordTokens(150)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := limiter.Wait(ctx)
+
+			convey.Convey("Then the next call should wait, until it is canceled", func() {
+				convey.So(err, convey.ShouldEqual, context.Canceled)
+				convey.So(time.Since(start), convey.ShouldBeLessThan, time.Second)
+			})
+		})
+	})
+
+	convey.Convey("Given an unlimited limiter", t, func() {
+		limiter := NewTokenBucketLimiter(0, 0)
+		limiter.RecordTokens(1_000_000)
+
+		convey.Convey("Then it should never wait", func() {
+			for range 10 {
+				convey.So(limiter.Wait(context.Background()), convey.ShouldBeNil)
+			}
+		})
+	})
+
+	convey.Convey("Given an OpenAI provider that calls a tool before answering", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			return "42", nil
+		}
+
+		var requests int
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "text/event-stream")
+
+			if requests == 1 {
+				fmt.Fprint(w, toolCallChunk("call_1", "calculator", `{"expression":"6*7"}`))
+				fmt.Fprint(w, contentChunk("", "tool_calls"))
+			} else {
+				fmt.Fprint(w, contentChunk("42", ""))
+				fmt.Fprint(w, contentChunk("", "stop"))
+			}
+
+			fmt.Fprint(w, usageChunk(10, 5))
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "What is 6 * 7?"))
+
+		limiter := &countingLimiter{}
+
+		convey.Convey("When it runs with a limiter", func() {
+			for range prvdr.Generate(context.Background(), NewProviderParams(task, WithLimiter(limiter))) {
+			}
+
+			convey.Convey("Then every API call should wait on the limiter", func() {
+				convey.So(requests, convey.ShouldEqual, 2)
+				convey.So(limiter.waits, convey.ShouldEqual, 2)
+			})
+
+			convey.Convey("Then the tokens of every call should be recorded", func() {
+				convey.So(limiter.tokens, convey.ShouldEqual, 30)
+			})
+		})
+
+		convey.Convey("When its context is canceled while waiting", func() {
+			limiter := NewTokenBucketLimiter(1, 0)
+			convey.So(limiter.Wait(context.Background()), convey.ShouldBeNil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+
+			for range prvdr.Generate(ctx, NewProviderParams(task, WithLimiter(limiter))) {
+			}
+
+			convey.Convey("Then it should stop without calling the API", func() {
+				convey.So(requests, convey.ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -0,0 +1,503 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/utils"
+)
+
+/*
+DefaultMistralBaseURL is la Plateforme's API. Self-hosted deployments serve
+the same API under their own base URL.
+*/
+const DefaultMistralBaseURL = "https://api.mistral.ai/v1"
+
+/*
+MistralProvider is a provider for the Mistral API. The Mistral chat API is
+compatible with OpenAI's, so it is spoken through the OpenAI client, but
+only with the request fields Mistral accepts, as it rejects unknown ones.
+*/
+type MistralProvider struct {
+	client *openai.Client
+	params *openai.ChatCompletionNewParams
+	dump   *debugDump
+}
+
+type MistralProviderOption func(*MistralProvider)
+
+func NewMistralProvider(options ...MistralProviderOption) *MistralProvider {
+	prvdr := &MistralProvider{}
+
+	for _, option := range options {
+		option(prvdr)
+	}
+
+	return prvdr
+}
+
+func (prvdr *MistralProvider) Generate(
+	ctx context.Context, params *ProviderParams,
+) chan jsonrpc.Response {
+	ch := make(chan jsonrpc.Response)
+
+	mistralToolResponseGenerator := func(toolCallID string, content string, isError bool) any {
+		return openai.ToolMessage(content, toolCallID)
+	}
+
+	go func() {
+		defer close(ch)
+
+		prvdr.params = prvdr.newParams(params)
+		requestOptions := prvdr.requestOptions(params)
+
+		isFinished := false
+		resume := newStreamResume(params.StreamRetries)
+
+		for !isFinished {
+			if !waitLimiter(ctx, params, ch) {
+				return
+			}
+
+			if params.Stream {
+				stream := prvdr.client.Chat.Completions.NewStreaming(ctx, *prvdr.params, requestOptions...)
+				acc := openai.ChatCompletionAccumulator{}
+				toolCalls := newToolCallStream(params.Task.ID)
+				messages := prvdr.params.Messages
+				calledTool := false
+
+				for stream.Next() {
+					chunk := stream.Current()
+					acc.AddChunk(chunk)
+					addOpenAIUsage(params, chunk.Usage)
+
+					if params.StreamToolCalls && len(chunk.Choices) > 0 {
+						for _, delta := range chunk.Choices[0].Delta.ToolCalls {
+							ch <- toolCalls.delta(delta.Index, delta.ID, delta.Function.Name, delta.Function.Arguments)
+						}
+					}
+
+					if _, ok := acc.JustFinishedContent(); ok {
+						ch <- a2a.NewArtifactResult(
+							params.Task.ID,
+							a2a.NewTextPart(chunk.Choices[0].Delta.Content),
+						)
+						params.Task.AddFinalPart(a2a.NewTextPart(chunk.Choices[0].Delta.Content))
+						break
+					}
+
+					if toolCall, ok := acc.JustFinishedToolCall(); ok {
+						if params.StreamToolCalls {
+							ch <- toolCalls.done(toolCall.ID, toolCall.Name, toolCall.Arguments)
+						}
+
+						prvdr.params.Messages = append(prvdr.params.Messages, openai.ChatCompletionMessageParamUnion{
+							OfAssistant: &openai.ChatCompletionAssistantMessageParam{
+								ToolCalls: []openai.ChatCompletionMessageToolCallParam{{
+									ID: toolCall.ID,
+									Function: openai.ChatCompletionMessageToolCallFunctionParam{
+										Name:      toolCall.Name,
+										Arguments: toolCall.Arguments,
+									},
+								}},
+							},
+						})
+
+						updatedTask, llmToolMsg, toolExecErr := ExecuteAndProcessToolCall(
+							ctx,
+							toolCall.Name,
+							toolCall.Arguments,
+							toolCall.ID,
+							params,
+							mistralToolResponseGenerator,
+						)
+						params.Task = updatedTask
+						prvdr.params.Messages = append(prvdr.params.Messages, llmToolMsg.(openai.ChatCompletionMessageParamUnion))
+
+						if toolExecErr != nil {
+							ch <- jsonrpc.Response{
+								Result: params.Task,
+								Error: &jsonrpc.Error{
+									Code:    errors.ErrInternal.Code,
+									Message: fmt.Sprintf("Streaming: Error executing tool %s: %v", toolCall.Name, toolExecErr),
+								},
+							}
+						} else {
+							ch <- jsonrpc.Response{Result: params.Task}
+						}
+
+						calledTool = true
+						break
+					}
+
+					if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+						if text := resume.add(chunk.Choices[0].Delta.Content); text != "" {
+							ch <- a2a.NewArtifactResult(params.Task.ID, a2a.NewTextPart(text))
+						}
+					}
+				}
+
+				drainOpenAIUsage(stream, params)
+				recordOpenAIFinish(params, acc.Choices)
+
+				if err := stream.Err(); err != nil {
+					if resume.retry(ctx, params.Task, err) {
+						resume.restart()
+						prvdr.params.Messages = append(
+							messages[:len(messages):len(messages)],
+							openai.AssistantMessage(resume.partial()),
+						)
+						continue
+					}
+
+					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: errors.ErrInternal.Code, Message: err.Error()}}
+				}
+
+				isFinished = !calledTool || finishTerminated(params, ch)
+				resume.nextTurn()
+			} else {
+				var completion *openai.ChatCompletion
+				err := retry(ctx, params, func() (err error) {
+					completion, err = prvdr.client.Chat.Completions.New(ctx, *prvdr.params, requestOptions...)
+					return err
+				})
+				if err != nil {
+					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: errors.ErrInternal.Code, Message: err.Error()}}
+					break
+				}
+				if len(completion.Choices) == 0 {
+					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: errors.ErrInternal.Code, Message: "Mistral completion returned no choices"}}
+					break
+				}
+
+				addOpenAIUsage(params, completion.Usage)
+				recordOpenAIFinish(params, completion.Choices)
+
+				messageFromAssistant := completion.Choices[0].Message
+
+				if len(messageFromAssistant.ToolCalls) == 0 {
+					params.Task.AddFinalPart(a2a.NewTextPart(messageFromAssistant.Content))
+
+					shouldComplete, evaluationReason, evalErr := EvaluateBeforeCompletion(
+						ctx,
+						params.Task,
+						messageFromAssistant.Content,
+						"mistral",
+					)
+
+					if evalErr != nil || shouldComplete {
+						if evalErr != nil {
+							log.Warn("Mistral: Evaluation error, proceeding with completion", "error", evalErr)
+						}
+						params.Task.ToStatus(a2a.TaskStateCompleted, a2a.NewTextMessage("assistant", messageFromAssistant.Content))
+						ch <- jsonrpc.Response{Result: params.Task}
+						break
+					}
+
+					log.Info("Mistral: Task needs iteration", "reason", evaluationReason)
+					iterationPrompt := fmt.Sprintf("The evaluator reviewed your response and determined it needs improvement. Feedback: %s\n\nPlease revise your response to better address the original task.", evaluationReason)
+					prvdr.params.Messages = append(prvdr.params.Messages, openai.UserMessage(iterationPrompt))
+					continue
+				}
+
+				messageFromAssistant.ToolCalls = sequentialToolCalls(params, messageFromAssistant.ToolCalls)
+				prvdr.params.Messages = append(prvdr.params.Messages, messageFromAssistant.ToParam())
+
+				for _, toolCall := range messageFromAssistant.ToolCalls {
+					updatedTask, llmToolMsg, toolExecErr := ExecuteAndProcessToolCall(
+						ctx,
+						toolCall.Function.Name,
+						toolCall.Function.Arguments,
+						toolCall.ID,
+						params,
+						mistralToolResponseGenerator,
+					)
+					params.Task = updatedTask
+					prvdr.params.Messages = append(prvdr.params.Messages, llmToolMsg.(openai.ChatCompletionMessageParamUnion))
+
+					if toolExecErr != nil {
+						ch <- jsonrpc.Response{
+							Result: params.Task,
+							Error: &jsonrpc.Error{
+								Code:    errors.ErrInternal.Code,
+								Message: fmt.Sprintf("Error executing tool %s: %v", toolCall.Function.Name, toolExecErr),
+							},
+						}
+					} else {
+						ch <- jsonrpc.Response{Result: params.Task}
+					}
+				}
+
+				if finishTerminated(params, ch) {
+					break
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+/*
+newParams builds the chat request from the provider params, leaving out the
+fields Mistral does not accept, and the sampling fields left at zero.
+*/
+func (prvdr *MistralProvider) newParams(params *ProviderParams) *openai.ChatCompletionNewParams {
+	request := &openai.ChatCompletionNewParams{
+		Model:       openai.ChatModel(params.Model),
+		Messages:    prvdr.convertMessages(params.Task),
+		Tools:       prvdr.convertTools(params.Tools),
+		Temperature: openai.Float(params.Temperature),
+		Stop:        openai.ChatCompletionNewParamsStopUnion{OfStringArray: params.Stop},
+	}
+
+	if len(request.Tools) > 0 {
+		request.ParallelToolCalls = openai.Bool(params.ParallelToolCalls)
+	}
+
+	if params.TopP > 0 {
+		request.TopP = openai.Float(params.TopP)
+	}
+
+	if params.MaxTokens > 0 {
+		request.MaxTokens = openai.Int(params.MaxTokens)
+	}
+
+	if params.FrequencyPenalty != 0 {
+		request.FrequencyPenalty = openai.Float(params.FrequencyPenalty)
+	}
+
+	if params.PresencePenalty != 0 {
+		request.PresencePenalty = openai.Float(params.PresencePenalty)
+	}
+
+	return request
+}
+
+/*
+requestOptions carries the request fields that Mistral names differently
+from OpenAI, and the debug dump when there is one.
+*/
+func (prvdr *MistralProvider) requestOptions(params *ProviderParams) []option.RequestOption {
+	var options []option.RequestOption
+
+	if prvdr.dump != nil {
+		options = append(options, option.WithMiddleware(prvdr.dump.roundTrip))
+	}
+
+	if params.Seed != 0 {
+		options = append(options, option.WithJSONSet("random_seed", params.Seed))
+	}
+
+	return options
+}
+
+/*
+Models lists the models the Mistral API serves to the configured key.
+*/
+func (prvdr *MistralProvider) Models(ctx context.Context) ([]string, error) {
+	page, err := prvdr.client.Models.List(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]string, 0, len(page.Data))
+
+	for _, model := range page.Data {
+		models = append(models, model.ID)
+	}
+
+	return models, nil
+}
+
+/*
+Capabilities implements Interface.
+*/
+func (prvdr *MistralProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming:  true,
+		Tools:      true,
+		Embeddings: true,
+	}
+}
+
+/*
+ModelCapabilities implements ModelCapabilityReporter for the model families
+that differ from the defaults.
+*/
+func (prvdr *MistralProvider) ModelCapabilities(model string) ProviderCapabilities {
+	caps := prvdr.Capabilities()
+	caps.Vision = hasAnyPrefix(model, "pixtral", "mistral-medium", "mistral-small-2503", "mistral-small-latest")
+	caps.Reasoning = hasAnyPrefix(model, "magistral")
+	return caps
+}
+
+func (prvdr *MistralProvider) convertMessages(
+	task *a2a.Task,
+) []openai.ChatCompletionMessageParamUnion {
+	out := make([]openai.ChatCompletionMessageParamUnion, 0, len(task.History))
+
+	for _, msg := range task.History {
+		var text string
+
+		for _, p := range msg.Parts {
+			if p.Type == a2a.PartTypeText {
+				text = p.Text
+				break
+			}
+		}
+
+		if fn, ok := roleMap[msg.Role]; ok {
+			out = append(out, fn(text))
+		}
+	}
+	return out
+}
+
+func (prvdr *MistralProvider) convertTools(
+	tools []*mcp.Tool,
+) []openai.ChatCompletionToolParam {
+	out := make([]openai.ChatCompletionToolParam, 0, len(tools))
+
+	for _, tool := range tools {
+		if tool == nil {
+			continue
+		}
+
+		out = append(out, openai.ChatCompletionToolParam{
+			Function: openai.FunctionDefinitionParam{
+				Name:        tool.Name,
+				Description: openai.String(tool.Description),
+				Parameters: openai.FunctionParameters(map[string]any{
+					"type":       tool.InputSchema.Type,
+					"properties": tool.InputSchema.Properties,
+					"required":   tool.InputSchema.Required,
+				}),
+			},
+		})
+	}
+
+	return out
+}
+
+type MistralEmbedder struct {
+	api   *openai.Client
+	Model string
+}
+
+type MistralEmbedderOption func(*MistralEmbedder)
+
+func NewMistralEmbedder(options ...MistralEmbedderOption) *MistralEmbedder {
+	embedder := &MistralEmbedder{Model: "mistral-embed"}
+
+	for _, option := range options {
+		option(embedder)
+	}
+
+	return embedder
+}
+
+/*
+EmbeddingModel implements memory.ModelEmbedder.
+*/
+func (e *MistralEmbedder) EmbeddingModel() string {
+	return e.Model
+}
+
+func (e *MistralEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (e *MistralEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := e.api.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Model: openai.EmbeddingModel(e.Model),
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: texts},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("mistral returned %d embeddings for %d texts", len(resp.Data), len(texts))
+	}
+
+	out := make([][]float32, len(resp.Data))
+	for _, d := range resp.Data {
+		out[d.Index] = utils.ConvertToFloat32(d.Embedding)
+	}
+	return out, nil
+}
+
+/*
+newMistralClient creates an OpenAI client for the Mistral API, using the
+MISTRAL_API_KEY environment variable and the first non-empty base URL, or
+la Plateforme's.
+*/
+func newMistralClient(baseURL ...string) *openai.Client {
+	base := DefaultMistralBaseURL
+
+	for _, url := range baseURL {
+		if url != "" {
+			base = url
+			break
+		}
+	}
+
+	client := openai.NewClient(
+		option.WithAPIKey(os.Getenv("MISTRAL_API_KEY")),
+		option.WithBaseURL(base),
+	)
+
+	return &client
+}
+
+/*
+WithMistralClient configures the provider with the MISTRAL_API_KEY
+environment variable and an optional base URL for self-hosted models.
+*/
+func WithMistralClient(baseURL ...string) MistralProviderOption {
+	return func(prvdr *MistralProvider) {
+		prvdr.client = newMistralClient(baseURL...)
+	}
+}
+
+/*
+WithMistralDebugDump writes every chat request sent to the API, and the raw
+response to it, to w. The API key is always redacted, the message content
+only when redact is set.
+*/
+func WithMistralDebugDump(w io.Writer, redact bool) MistralProviderOption {
+	return func(prvdr *MistralProvider) {
+		prvdr.dump = newDebugDump(w, redact)
+	}
+}
+
+func WithMistralEmbedderModel(model string) MistralEmbedderOption {
+	return func(e *MistralEmbedder) {
+		e.Model = model
+	}
+}
+
+/*
+WithMistralEmbedderClient configures the embedder the same way
+WithMistralClient configures the provider.
+*/
+func WithMistralEmbedderClient(baseURL ...string) MistralEmbedderOption {
+	return func(e *MistralEmbedder) {
+		e.api = newMistralClient(baseURL...)
+	}
+}
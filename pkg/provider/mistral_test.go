@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+toolCallChunk renders one streamed chat completion chunk carrying a
+complete tool call, the way Mistral sends them.
+*/
+func toolCallChunk(id, name, arguments string) string {
+	chunk := map[string]any{
+		"id":      "chunk",
+		"object":  "chat.completion.chunk",
+		"created": 1,
+		"model":   "mistral-small-latest",
+		"choices": []map[string]any{{
+			"index": 0,
+			"delta": map[string]any{"tool_calls": []map[string]any{{
+				"index":    0,
+				"id":       id,
+				"type":     "function",
+				"function": map[string]any{"name": name, "arguments": arguments},
+			}}},
+		}},
+	}
+
+	buf, _ := json.Marshal(chunk)
+	return fmt.Sprintf("data: %s\n\n", buf)
+}
+
+func TestMistralProvider(t *testing.T) {
+	convey.Convey("Given a Mistral provider that calls a tool before answering", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		var toolCalls []string
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			toolCalls = append(toolCalls, name+" "+args)
+			return "sunny", nil
+		}
+
+		var requests []map[string]any
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]any{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, body)
+
+			w.Header().Set("Content-Type", "text/event-stream")
+
+			if len(requests) == 1 {
+				fmt.Fprint(w, toolCallChunk("abc123def", "weather", `{"city":"Paris"}`))
+				fmt.Fprint(w, contentChunk("", "tool_calls"))
+			} else {
+				fmt.Fprint(w, contentChunk("It is sunny.", ""))
+				fmt.Fprint(w, contentChunk("", "stop"))
+			}
+
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		prvdr := NewMistralProvider(WithMistralClient(ts.URL))
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "What is the weather in Paris?"))
+
+		params := NewProviderParams(task)
+		params.Model = "mistral-small-latest"
+		params.Seed = 42
+
+		convey.Convey("When the provider streams a response", func() {
+			var content string
+
+			for response := range prvdr.Generate(context.Background(), params) {
+				convey.So(response.Error, convey.ShouldBeNil)
+
+				if artifact, ok := response.Result.(a2a.ArtifactResult); ok {
+					for _, part := range artifact.Artifact.Parts {
+						content += part.Text
+					}
+				}
+			}
+
+			convey.Convey("Then the tool should run through the shared helper", func() {
+				convey.So(toolCalls, convey.ShouldResemble, []string{`weather {"city":"Paris"}`})
+
+				var names []string
+				for _, artifact := range task.Artifacts {
+					if artifact.Name != nil {
+						names = append(names, *artifact.Name)
+					}
+				}
+				convey.So(names, convey.ShouldContain, "weather")
+			})
+
+			convey.Convey("Then the tool result should be sent back for the answer", func() {
+				convey.So(requests, convey.ShouldHaveLength, 2)
+				convey.So(content, convey.ShouldEqual, "It is sunny.")
+
+				messages := requests[1]["messages"].([]any)
+				call := messages[len(messages)-2].(map[string]any)
+				result := messages[len(messages)-1].(map[string]any)
+				convey.So(call["role"], convey.ShouldEqual, "assistant")
+				convey.So(call["tool_calls"], convey.ShouldHaveLength, 1)
+				convey.So(result["role"], convey.ShouldEqual, "tool")
+				convey.So(result["tool_call_id"], convey.ShouldEqual, "abc123def")
+				convey.So(result["content"], convey.ShouldEqual, "sunny")
+			})
+
+			convey.Convey("Then only fields Mistral accepts should be sent", func() {
+				convey.So(requests[0]["random_seed"], convey.ShouldEqual, 42)
+				convey.So(requests[0], convey.ShouldNotContainKey, "seed")
+				convey.So(requests[0], convey.ShouldNotContainKey, "frequency_penalty")
+				convey.So(requests[0], convey.ShouldNotContainKey, "presence_penalty")
+			})
+		})
+	})
+}
@@ -4,12 +4,13 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"strconv"
+	"io"
 	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/ollama/ollama/api"
+	"github.com/ollama/ollama/envconfig"
 	"github.com/theapemachine/a2a-go/pkg/a2a"
 	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
 )
@@ -56,6 +57,7 @@ OllamaProvider is a provider for the Ollama API.
 type OllamaProvider struct {
 	client *api.Client
 	params *api.ChatRequest
+	dump   *debugDump
 }
 
 type OllamaProviderOption func(*OllamaProvider)
@@ -67,6 +69,12 @@ func NewOllamaProvider(options ...OllamaProviderOption) *OllamaProvider {
 		option(prvdr)
 	}
 
+	// The client keeps its HTTP client private, so one talking to the same
+	// host is created with the dump in between.
+	if prvdr.dump != nil && prvdr.client != nil {
+		prvdr.client = api.NewClient(envconfig.Host(), prvdr.dump.wrap(nil))
+	}
+
 	return prvdr
 }
 
@@ -119,6 +127,10 @@ func (prvdr *OllamaProvider) Generate(
 		}
 
 		for !isDone {
+			if !waitLimiter(ctx, params, ch) {
+				return
+			}
+
 			if params.Stream {
 				// For streaming, use GenerateRequest
 				var prompt string
@@ -139,6 +151,10 @@ func (prvdr *OllamaProvider) Generate(
 				}
 
 				respFunc := func(resp api.GenerateResponse) error {
+					if resp.Done {
+						params.recordFinish(resp.DoneReason, false)
+					}
+
 					if resp.Response != "" {
 						ch <- a2a.NewArtifactResult(
 							params.Task.ID,
@@ -148,7 +164,9 @@ func (prvdr *OllamaProvider) Generate(
 					return nil
 				}
 
-				err := prvdr.client.Generate(ctx, req, respFunc)
+				err := retry(ctx, params, func() error {
+					return prvdr.client.Generate(ctx, req, respFunc)
+				})
 				if err != nil {
 					log.Error("failed to create stream", "error", err)
 					ch <- jsonrpc.Response{
@@ -183,6 +201,10 @@ func (prvdr *OllamaProvider) Generate(
 				var calledToolNames []string // To keep track of tools called in this iteration
 
 				respFunc := func(resp api.ChatResponse) error {
+					if resp.Done {
+						params.recordFinish(resp.DoneReason, false)
+					}
+
 					if resp.Message.ToolCalls != nil {
 						// This part will be tricky as handleToolCall used to modify prvdr.params.Messages directly.
 						// We now need to collect tool calls, execute them, get LLM messages, and then make a new Chat call.
@@ -203,7 +225,7 @@ func (prvdr *OllamaProvider) Generate(
 								ollamaToolCall.Function.Name,
 								ollamaToolCall.Function.Arguments.String(), // Arguments is json.RawMessage
 								"", // Ollama doesn't seem to use a tool_call_id in its response message structure for tools.
-								params.Task,
+								params,
 								ollamaToolResponseGenerator,
 							)
 							params.Task = updatedTask
@@ -231,7 +253,9 @@ func (prvdr *OllamaProvider) Generate(
 					return nil
 				}
 
-				err := prvdr.client.Chat(ctx, req, respFunc)
+				err := retry(ctx, params, func() error {
+					return prvdr.client.Chat(ctx, req, respFunc)
+				})
 				if err != nil {
 					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: int(a2a.ErrorCodeInternalError), Message: err.Error()}}
 					return // fatal error for this call
@@ -239,8 +263,9 @@ func (prvdr *OllamaProvider) Generate(
 
 				if len(calledToolNames) > 0 {
 					// Tools were called. The next iteration of `for !isDone` will pick up messages from task.History
-					// which now includes the tool results, and make a new call to prvdr.client.Chat.
-					isDone = false
+					// which now includes the tool results, and make a new call to prvdr.client.Chat,
+					// unless a tool result ended the task.
+					isDone = finishTerminated(params, ch)
 				} else if fullMessageText != "" {
 					params.Task.AddFinalPart(a2a.NewTextPart(fullMessageText))
 					ch <- a2a.NewArtifactResult(params.Task.ID, a2a.NewTextPart(fullMessageText))
@@ -259,6 +284,30 @@ func (prvdr *OllamaProvider) Generate(
 	return ch
 }
 
+/*
+Capabilities implements Interface.
+*/
+func (prvdr *OllamaProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming:  true,
+		Tools:      true,
+		JSONMode:   true,
+		Embeddings: true,
+	}
+}
+
+/*
+ModelCapabilities implements ModelCapabilityReporter, recognizing the vision
+and reasoning models by name.
+*/
+func (prvdr *OllamaProvider) ModelCapabilities(model string) ProviderCapabilities {
+	caps := prvdr.Capabilities()
+	caps.Vision = hasAnyPrefix(model, "llava", "bakllava", "moondream", "minicpm-v", "gemma3") ||
+		strings.Contains(model, "vision")
+	caps.Reasoning = hasAnyPrefix(model, "deepseek-r1", "qwq", "qwen3")
+	return caps
+}
+
 func (prvdr *OllamaProvider) convertMessages(
 	task *a2a.Task,
 ) []api.Message {
@@ -362,7 +411,7 @@ type OllamaEmbedder struct {
 type OllamaEmbedderOption func(*OllamaEmbedder)
 
 func NewOllamaEmbedder(options ...OllamaEmbedderOption) *OllamaEmbedder {
-	embedder := &OllamaEmbedder{}
+	embedder := &OllamaEmbedder{Model: "nomic-embed-text"}
 
 	for _, option := range options {
 		option(embedder)
@@ -371,46 +420,39 @@ func NewOllamaEmbedder(options ...OllamaEmbedderOption) *OllamaEmbedder {
 	return embedder
 }
 
-func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
-	// Ollama doesn't have a direct embedding API, so we'll use the chat completion API
-	// to generate embeddings-like output
-	req := &api.GenerateRequest{
-		Model:  e.Model,
-		Prompt: text,
-	}
+/*
+EmbeddingModel implements memory.ModelEmbedder.
+*/
+func (e *OllamaEmbedder) EmbeddingModel() string {
+	return e.Model
+}
 
-	var fullResponse string
-	respFunc := func(resp api.GenerateResponse) error {
-		fullResponse += resp.Response
-		return nil
+func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
 	}
+	return vectors[0], nil
+}
 
-	err := e.api.Generate(ctx, req, respFunc)
+/*
+EmbedBatch embeds all texts in one call to Ollama's /api/embed endpoint,
+which returns the model's actual vectors in the order of the input.
+*/
+func (e *OllamaEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := e.api.Embed(ctx, &api.EmbedRequest{
+		Model: e.Model,
+		Input: texts,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert the response to a vector
-	// This is a placeholder - you might want to use a different approach
-	// depending on your needs
-	vector := make([]float32, 0)
-	for _, r := range fullResponse {
-		vector = append(vector, float32(r))
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d texts", len(resp.Embeddings), len(texts))
 	}
 
-	return vector, nil
-}
-
-func (e *OllamaEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
-	vectors := make([][]float32, len(texts))
-	for i, text := range texts {
-		vector, err := e.Embed(ctx, text)
-		if err != nil {
-			return nil, err
-		}
-		vectors[i] = vector
-	}
-	return vectors, nil
+	return resp.Embeddings, nil
 }
 
 func WithOllamaClient() OllamaProviderOption {
@@ -424,6 +466,17 @@ func WithOllamaClient() OllamaProviderOption {
 	}
 }
 
+/*
+WithOllamaDebugDump writes every request sent to the Ollama server, and the
+raw response to it, to w. The message content is redacted when redact is
+set.
+*/
+func WithOllamaDebugDump(w io.Writer, redact bool) OllamaProviderOption {
+	return func(prvdr *OllamaProvider) {
+		prvdr.dump = newDebugDump(w, redact)
+	}
+}
+
 func WithOllamaEmbedderModel(model string) OllamaEmbedderOption {
 	return func(e *OllamaEmbedder) {
 		e.Model = model
@@ -436,6 +489,25 @@ func WithOllamaEmbedderClient(client *api.Client) OllamaEmbedderOption {
 	}
 }
 
+/*
+Models lists the models pulled on the Ollama server.
+*/
+func (prvdr *OllamaProvider) Models(ctx context.Context) ([]string, error) {
+	res, err := prvdr.client.List(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]string, 0, len(res.Models))
+
+	for _, model := range res.Models {
+		models = append(models, model.Name)
+	}
+
+	return models, nil
+}
+
 /*
 GenerateImage uses the model to generate an image and returns it as a base64-encoded string.
 */
@@ -611,64 +683,16 @@ func (prvdr *OllamaProvider) FineTune(
 }
 
 /*
-Embed generates a vector representation of the input text.
+Embed generates a vector representation of the input text with the default
+embedding model.
 */
 func (prvdr *OllamaProvider) Embed(ctx context.Context, text string) ([]float32, error) {
-	// Create a special prompt for embedding
-	embedPrompt := fmt.Sprintf(`Generate a vector representation for this text: %s
-Respond with only a comma-separated list of numbers representing the vector.`, text)
-
-	// Create provider params with default values
-	params := NewProviderParams(nil)
-
-	req := &api.GenerateRequest{
-		Model:  params.Model,
-		Prompt: embedPrompt,
-		Options: map[string]any{
-			"temperature": 0.0,
-			"top_p":       1.0,
-		},
-	}
-
-	var vectorStr string
-	respFunc := func(resp api.GenerateResponse) error {
-		vectorStr += resp.Response
-		return nil
-	}
-
-	err := prvdr.client.Generate(ctx, req, respFunc)
-	if err != nil {
-		return nil, fmt.Errorf("error generating embedding: %w", err)
-	}
-
-	// Parse the comma-separated numbers into a float32 slice
-	vector := make([]float32, 0)
-	for _, numStr := range strings.Split(vectorStr, ",") {
-		numStr = strings.TrimSpace(numStr)
-		if numStr == "" {
-			continue
-		}
-		num, err := strconv.ParseFloat(numStr, 32)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing vector number: %w", err)
-		}
-		vector = append(vector, float32(num))
-	}
-
-	return vector, nil
+	return NewOllamaEmbedder(WithOllamaEmbedderClient(prvdr.client)).Embed(ctx, text)
 }
 
 /*
 EmbedBatch generates vector representations for multiple input texts.
 */
 func (prvdr *OllamaProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
-	vectors := make([][]float32, len(texts))
-	for i, text := range texts {
-		vector, err := prvdr.Embed(ctx, text)
-		if err != nil {
-			return nil, fmt.Errorf("error generating embedding for text %d: %w", i, err)
-		}
-		vectors[i] = vector
-	}
-	return vectors, nil
+	return NewOllamaEmbedder(WithOllamaEmbedderClient(prvdr.client)).EmbedBatch(ctx, texts)
 }
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestOllamaEmbedder(t *testing.T) {
+	convey.Convey("Given an Ollama embedder behind a stub server", t, func() {
+		var (
+			path string
+			body map[string]any
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"model":"nomic-embed-text","embeddings":[[0.1,0.2,0.3],[0.4,0.5,0.6]]}`))
+		}))
+		defer ts.Close()
+
+		base, _ := url.Parse(ts.URL)
+		embedder := NewOllamaEmbedder(WithOllamaEmbedderClient(api.NewClient(base, http.DefaultClient)))
+
+		convey.Convey("When a batch of texts is embedded", func() {
+			vectors, err := embedder.EmbedBatch(context.Background(), []string{"first", "second"})
+
+			convey.Convey("Then it should call the embed endpoint with the model and inputs", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(path, convey.ShouldEqual, "/api/embed")
+				convey.So(body["model"], convey.ShouldEqual, "nomic-embed-text")
+				convey.So(body["input"], convey.ShouldResemble, []any{"first", "second"})
+			})
+
+			convey.Convey("Then it should return the vectors of the response", func() {
+				convey.So(vectors, convey.ShouldResemble, [][]float32{{0.1, 0.2, 0.3}, {0.4, 0.5, 0.6}})
+			})
+		})
+
+		convey.Convey("When the server returns fewer vectors than texts", func() {
+			_, err := embedder.EmbedBatch(context.Background(), []string{"first", "second", "third"})
+
+			convey.Convey("Then it should fail", func() {
+				convey.So(err, convey.ShouldNotBeNil)
+			})
+		})
+	})
+}
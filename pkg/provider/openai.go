@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/packages/ssestream"
 	"github.com/theapemachine/a2a-go/pkg/a2a"
 	"github.com/theapemachine/a2a-go/pkg/errors"
 	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
@@ -36,12 +39,19 @@ var roleMap = map[string]func(string) openai.ChatCompletionMessageParamUnion{
 OpenAIProvider is a provider for the OpenAI API.
 */
 type OpenAIProvider struct {
-	client *openai.Client
-	params *openai.ChatCompletionNewParams
+	client        *openai.Client
+	params        *openai.ChatCompletionNewParams
+	clientOptions []option.RequestOption
+	fingerprints  fingerprints
 }
 
 type OpenAIProviderOption func(*OpenAIProvider)
 
+/*
+NewOpenAIProvider creates the provider. The client options, such as the base
+URL, HTTP client and API key, can be given in any order, and the client is
+created once all of them have been applied.
+*/
 func NewOpenAIProvider(options ...OpenAIProviderOption) *OpenAIProvider {
 	prvdr := &OpenAIProvider{}
 
@@ -49,6 +59,11 @@ func NewOpenAIProvider(options ...OpenAIProviderOption) *OpenAIProvider {
 		option(prvdr)
 	}
 
+	if len(prvdr.clientOptions) > 0 {
+		client := openai.NewClient(prvdr.clientOptions...)
+		prvdr.client = &client
+	}
+
 	return prvdr
 }
 
@@ -75,10 +90,14 @@ func (prvdr *OpenAIProvider) Generate(
 			PresencePenalty:   openai.Float(params.PresencePenalty),
 			MaxTokens:         openai.Int(params.MaxTokens),
 			TopP:              openai.Float(params.TopP),
-			Seed:              openai.Int(params.Seed),
 			Stop:              openai.ChatCompletionNewParamsStopUnion{OfStringArray: params.Stop},
 		}
 
+		// A zero seed means none was set, rather than a seed of zero.
+		if params.Seed != 0 {
+			prvdr.params.Seed = openai.Int(params.Seed)
+		}
+
 		schema := params.Task.History[len(params.Task.History)-1].Metadata["schema"]
 
 		if schema != nil {
@@ -86,16 +105,33 @@ func (prvdr *OpenAIProvider) Generate(
 		}
 
 		isFinished := false
+		resume := newStreamResume(params.StreamRetries)
 
 		for !isFinished {
+			if !waitLimiter(ctx, params, ch) {
+				return
+			}
+
 			if params.Stream {
 				fmt.Println(prvdr.String())
-				stream := prvdr.client.Chat.Completions.NewStreaming(ctx, *prvdr.params)
+				streamParams := *prvdr.params
+				streamParams.StreamOptions = openai.ChatCompletionStreamOptionsParam{IncludeUsage: openai.Bool(true)}
+				stream := prvdr.client.Chat.Completions.NewStreaming(ctx, streamParams)
 				acc := openai.ChatCompletionAccumulator{}
+				toolCalls := newToolCallStream(params.Task.ID)
+				messages := prvdr.params.Messages
+				calledTool := false
 
 				for stream.Next() {
 					chunk := stream.Current()
 					acc.AddChunk(chunk)
+					addOpenAIUsage(params, chunk.Usage)
+
+					if params.StreamToolCalls && len(chunk.Choices) > 0 {
+						for _, delta := range chunk.Choices[0].Delta.ToolCalls {
+							ch <- toolCalls.delta(delta.Index, delta.ID, delta.Function.Name, delta.Function.Arguments)
+						}
+					}
 
 					if _, ok := acc.JustFinishedContent(); ok {
 						ch <- a2a.NewArtifactResult(
@@ -122,12 +158,16 @@ func (prvdr *OpenAIProvider) Generate(
 					}
 
 					if toolCall, ok := acc.JustFinishedToolCall(); ok { // toolCall is openai.FinishedChatCompletionToolCall
+						if params.StreamToolCalls {
+							ch <- toolCalls.done(toolCall.ID, toolCall.Name, toolCall.Arguments)
+						}
+
 						updatedTask, llmToolMsg, toolExecErr := ExecuteAndProcessToolCall(
 							ctx,
 							toolCall.Name,
 							toolCall.Arguments,
 							toolCall.ID,
-							params.Task,
+							params,
 							openAIToolResponseGenerator,
 						)
 						params.Task = updatedTask // Persist changes to task
@@ -145,19 +185,49 @@ func (prvdr *OpenAIProvider) Generate(
 							ch <- jsonrpc.Response{Result: params.Task} // Send updated task with success artifact
 						}
 
+						calledTool = true
 						break
 					}
 
 					if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
-						ch <- a2a.NewArtifactResult(
-							params.Task.ID,
-							a2a.NewTextPart(chunk.Choices[0].Delta.Content),
+						if text := resume.add(chunk.Choices[0].Delta.Content); text != "" {
+							ch <- a2a.NewArtifactResult(params.Task.ID, a2a.NewTextPart(text))
+						}
+					}
+				}
+
+				// The usage of the turn arrives in the last chunk, after the ones above.
+				drainOpenAIUsage(stream, params)
+				prvdr.fingerprints.record(params.Task, params.Seed, acc.SystemFingerprint)
+				recordOpenAIFinish(params, acc.Choices)
+
+				if err := stream.Err(); err != nil {
+					if resume.retry(ctx, params.Task, err) {
+						// Continue from the content streamed so far rather than starting over.
+						// OpenAI has no prefill, so the model sees the partial reply but may
+						// still answer from the start; the part it repeats is not sent again.
+						resume.restart()
+						prvdr.params.Messages = append(
+							messages[:len(messages):len(messages)],
+							openai.AssistantMessage(resume.partial()),
 						)
+						continue
 					}
+
+					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: errors.ErrInternal.Code, Message: err.Error()}}
 				}
+
+				// A tool call hands its result back to the model for another turn,
+				// unless its result ended the task.
+				isFinished = !calledTool || finishTerminated(params, ch)
+				resume.nextTurn()
 			} else { // Non-streaming path
 				log.Debug("non-streaming", "params", prvdr.params)
-				completion, err := prvdr.client.Chat.Completions.New(ctx, *prvdr.params)
+				var completion *openai.ChatCompletion
+				err := retry(ctx, params, func() (err error) {
+					completion, err = prvdr.client.Chat.Completions.New(ctx, *prvdr.params)
+					return err
+				})
 				if err != nil {
 					ch <- jsonrpc.Response{Error: &jsonrpc.Error{Code: errors.ErrInternal.Code, Message: err.Error()}}
 					break
@@ -167,6 +237,10 @@ func (prvdr *OpenAIProvider) Generate(
 					break
 				}
 
+				addOpenAIUsage(params, completion.Usage)
+				prvdr.fingerprints.record(params.Task, params.Seed, completion.SystemFingerprint)
+				recordOpenAIFinish(params, completion.Choices)
+
 				messageFromAssistant := completion.Choices[0].Message
 				llmToolCalls := messageFromAssistant.ToolCalls // These are openai.ChatCompletionMessageToolCall
 
@@ -201,6 +275,8 @@ func (prvdr *OpenAIProvider) Generate(
 						// Continue loop for another iteration
 					}
 				} else {
+					llmToolCalls = sequentialToolCalls(params, llmToolCalls)
+					messageFromAssistant.ToolCalls = llmToolCalls
 					prvdr.params.Messages = append(prvdr.params.Messages, messageFromAssistant.ToParam())
 					anyToolFailed := false
 					for _, toolCall := range llmToolCalls { // toolCall is openai.ChatCompletionMessageToolCall
@@ -209,7 +285,7 @@ func (prvdr *OpenAIProvider) Generate(
 							toolCall.Function.Name,
 							toolCall.Function.Arguments,
 							toolCall.ID, // Use .ID for ChatCompletionMessageToolCall
-							params.Task,
+							params,
 							openAIToolResponseGenerator,
 						)
 						params.Task = updatedTask // Persist changes to task
@@ -232,6 +308,9 @@ func (prvdr *OpenAIProvider) Generate(
 							ch <- jsonrpc.Response{Result: params.Task} // Send updated task with success artifact
 						}
 					}
+					if finishTerminated(params, ch) {
+						break
+					}
 					if anyToolFailed {
 						// If any tool failed, we might not want to proceed to the next LLM call immediately.
 						// The task status would have been updated by the helper if we decide to fail it there.
@@ -247,6 +326,54 @@ func (prvdr *OpenAIProvider) Generate(
 	return ch
 }
 
+/*
+recordOpenAIFinish records the finish reason of the first choice. OpenAI
+reports a stop sequence as a plain "stop", so it cannot be told apart from
+the model ending its answer.
+*/
+func recordOpenAIFinish(params *ProviderParams, choices []openai.ChatCompletionChoice) {
+	if len(choices) > 0 {
+		params.recordFinish(string(choices[0].FinishReason), false)
+	}
+}
+
+/*
+addOpenAIUsage adds the token usage of a completion, or of the streamed chunk
+carrying it, to the task.
+*/
+func addOpenAIUsage(params *ProviderParams, usage openai.CompletionUsage) {
+	params.addUsage(usage.PromptTokens, usage.CompletionTokens)
+}
+
+/*
+drainOpenAIUsage reads what is left of a stream the loop stopped reading
+early, for the usage chunk at its end.
+*/
+func drainOpenAIUsage(stream *ssestream.Stream[openai.ChatCompletionChunk], params *ProviderParams) {
+	for stream.Next() {
+		addOpenAIUsage(params, stream.Current().Usage)
+	}
+}
+
+/*
+Models lists the models the OpenAI API serves to the configured key.
+*/
+func (prvdr *OpenAIProvider) Models(ctx context.Context) ([]string, error) {
+	page, err := prvdr.client.Models.List(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]string, 0, len(page.Data))
+
+	for _, model := range page.Data {
+		models = append(models, model.ID)
+	}
+
+	return models, nil
+}
+
 /*
 GenerateImage delegates to DALL‑E 3 and returns the URL.
 */
@@ -307,22 +434,6 @@ func (prvdr *OpenAIProvider) AudioTranscript(ctx context.Context, audio []byte)
 	return tr.Text, nil
 }
 
-// func (prvdr *OpenAIProvider) TTS(ctx context.Context, text string) error {
-// 	res, err := prvdr.client.Audio.Speech.New(ctx, openai.AudioSpeechNewParams{
-// 		Model:          openai.SpeechModelTTS1,
-// 		Input:          text,
-// 		ResponseFormat: openai.AudioSpeechNewParamsResponseFormatPCM,
-// 		Voice:          openai.AudioSpeechNewParamsVoiceAlloy,
-// 	})
-
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	defer res.Body.Close()
-// 	return utils.PlayPCM(res.Body)
-// }
-
 func (prvdr *OpenAIProvider) FineTune(ctx context.Context, fileID string) error {
 	job, err := prvdr.client.FineTuning.Jobs.New(ctx, openai.FineTuningJobNewParams{
 		Model:        openai.FineTuningJobNewParamsModelGPT4oMini,
@@ -487,12 +598,55 @@ func (prvdr *OpenAIProvider) String() string {
 	return sb.String()
 }
 
+/*
+Capabilities implements Interface. Every feature is supported by the
+default models.
+*/
+func (prvdr *OpenAIProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming:  true,
+		Tools:      true,
+		Vision:     true,
+		JSONMode:   true,
+		Embeddings: true,
+	}
+}
+
+/*
+ModelCapabilities implements ModelCapabilityReporter for the model families
+that differ from the defaults.
+*/
+func (prvdr *OpenAIProvider) ModelCapabilities(model string) ProviderCapabilities {
+	caps := prvdr.Capabilities()
+
+	if hasAnyPrefix(model, "gpt-3.5") {
+		caps.Vision = false
+	}
+
+	if hasAnyPrefix(model, "o1", "o3", "o4") {
+		caps.Reasoning = true
+	}
+
+	if hasAnyPrefix(model, "o1-mini", "o1-preview") {
+		caps.Tools = false
+		caps.Vision = false
+		caps.JSONMode = false
+	}
+
+	return caps
+}
+
 func (prvdr *OpenAIProvider) convertMessages(
 	task *a2a.Task,
 ) []openai.ChatCompletionMessageParamUnion {
 	out := make([]openai.ChatCompletionMessageParamUnion, 0, len(task.History))
 
 	for _, msg := range task.History {
+		if msg.Role == "user" && hasImagePart(msg) {
+			out = append(out, openai.UserMessage(convertContentParts(msg)))
+			continue
+		}
+
 		var text string
 
 		for _, p := range msg.Parts {
@@ -509,6 +663,78 @@ func (prvdr *OpenAIProvider) convertMessages(
 	return out
 }
 
+/*
+convertContentParts turns the text and image parts of a message into the
+parts of one multipart user message, in their original order.
+*/
+func convertContentParts(msg a2a.Message) []openai.ChatCompletionContentPartUnionParam {
+	parts := make([]openai.ChatCompletionContentPartUnionParam, 0, len(msg.Parts))
+
+	for _, p := range msg.Parts {
+		if url, ok := imageURL(p); ok {
+			parts = append(parts, openai.ImageContentPart(
+				openai.ChatCompletionContentPartImageImageURLParam{URL: url},
+			))
+			continue
+		}
+
+		if p.Type == a2a.PartTypeText && p.Text != "" {
+			parts = append(parts, openai.TextContentPart(p.Text))
+		}
+	}
+
+	return parts
+}
+
+func hasImagePart(msg a2a.Message) bool {
+	for _, p := range msg.Parts {
+		if _, ok := imageURL(p); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+imageURL returns the URL a vision model can read an image part from: the
+part's URI, or a data URL of its base64 bytes. File parts carry an image
+when their MIME type says so, and data parts when they hold the same
+mimeType, bytes and uri fields as a file part.
+*/
+func imageURL(p a2a.Part) (string, bool) {
+	var mimeType, data, uri string
+
+	switch p.Type {
+	case a2a.PartTypeFile:
+		if p.File == nil || p.File.MimeType == nil {
+			return "", false
+		}
+
+		mimeType, data, uri = *p.File.MimeType, p.File.Data, p.File.URI
+	case a2a.PartTypeData:
+		mimeType, _ = p.Data["mimeType"].(string)
+		data, _ = p.Data["bytes"].(string)
+		uri, _ = p.Data["uri"].(string)
+	default:
+		return "", false
+	}
+
+	if !strings.HasPrefix(mimeType, "image/") {
+		return "", false
+	}
+
+	if uri != "" {
+		return uri, true
+	}
+
+	if data != "" {
+		return "data:" + mimeType + ";base64," + data, true
+	}
+
+	return "", false
+}
+
 func (prvdr *OpenAIProvider) convertTools(
 	tools []*mcp.Tool,
 ) []openai.ChatCompletionToolParam {
@@ -566,6 +792,13 @@ func NewOpenAIEmbedder(options ...OpenAIEmbedderOption) *OpenAIEmbedder {
 	return embedder
 }
 
+/*
+EmbeddingModel implements memory.ModelEmbedder.
+*/
+func (e *OpenAIEmbedder) EmbeddingModel() string {
+	return e.Model
+}
+
 func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
 	resp, err := e.api.Embeddings.New(ctx, openai.EmbeddingNewParams{
 		Model: openai.EmbeddingModel(e.Model),
@@ -593,13 +826,59 @@ func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]fl
 	return out, nil
 }
 
+/*
+WithOpenAIClient configures the client with the OPENAI_API_KEY environment
+variable. An explicit WithOpenAIAPIKey takes precedence, whatever the order.
+*/
 func WithOpenAIClient() OpenAIProviderOption {
 	return func(prvdr *OpenAIProvider) {
-		client := openai.NewClient(
-			option.WithAPIKey(os.Getenv("OPENAI_API_KEY")),
+		prvdr.clientOptions = append(
+			[]option.RequestOption{option.WithAPIKey(os.Getenv("OPENAI_API_KEY"))},
+			prvdr.clientOptions...,
 		)
+	}
+}
 
-		prvdr.client = &client
+/*
+WithOpenAIBaseURL points the client at another server that speaks the OpenAI
+protocol, such as an Azure OpenAI deployment, a gateway or a local vLLM.
+*/
+func WithOpenAIBaseURL(url string) OpenAIProviderOption {
+	return func(prvdr *OpenAIProvider) {
+		prvdr.clientOptions = append(prvdr.clientOptions, option.WithBaseURL(url))
+	}
+}
+
+/*
+WithOpenAIHTTPClient sends all requests through the given HTTP client, for
+custom transports, proxies and timeouts.
+*/
+func WithOpenAIHTTPClient(client *http.Client) OpenAIProviderOption {
+	return func(prvdr *OpenAIProvider) {
+		prvdr.clientOptions = append(prvdr.clientOptions, option.WithHTTPClient(client))
+	}
+}
+
+/*
+WithOpenAIAPIKey sets the API key programmatically instead of reading it from
+the environment.
+*/
+func WithOpenAIAPIKey(key string) OpenAIProviderOption {
+	return func(prvdr *OpenAIProvider) {
+		prvdr.clientOptions = append(prvdr.clientOptions, option.WithAPIKey(key))
+	}
+}
+
+/*
+WithOpenAIDebugDump writes every request sent to the API, and the raw
+response to it, to w. The API key is always redacted, the message content
+only when redact is set.
+*/
+func WithOpenAIDebugDump(w io.Writer, redact bool) OpenAIProviderOption {
+	return func(prvdr *OpenAIProvider) {
+		prvdr.clientOptions = append(
+			prvdr.clientOptions, option.WithMiddleware(newDebugDump(w, redact).roundTrip),
+		)
 	}
 }
 
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+recordingTransport counts the requests sent through a custom HTTP client.
+*/
+type recordingTransport struct {
+	requests int
+}
+
+func (transport *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOpenAIClientOptions(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "from-env")
+
+	convey.Convey("Given an OpenAI compatible server behind a gateway", t, func() {
+		var authorization string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"local-model","object":"model","created":1,"owned_by":"vllm"}]}`))
+		}))
+		defer ts.Close()
+
+		transport := &recordingTransport{}
+
+		convey.Convey("When the provider is configured with a base URL, HTTP client and key", func() {
+			prvdr := NewOpenAIProvider(
+				WithOpenAIAPIKey("from-code"),
+				WithOpenAIBaseURL(ts.URL),
+				WithOpenAIHTTPClient(&http.Client{Transport: transport}),
+				WithOpenAIClient(),
+			)
+
+			models, err := prvdr.Models(context.Background())
+
+			convey.Convey("Then requests should go to the base URL through the HTTP client", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(models, convey.ShouldResemble, []string{"local-model"})
+				convey.So(transport.requests, convey.ShouldEqual, 1)
+			})
+
+			convey.Convey("Then the explicit key should win over the environment", func() {
+				convey.So(authorization, convey.ShouldEqual, "Bearer from-code")
+			})
+		})
+
+		convey.Convey("When only the environment key is configured", func() {
+			prvdr := NewOpenAIProvider(WithOpenAIClient(), WithOpenAIBaseURL(ts.URL))
+
+			_, err := prvdr.Models(context.Background())
+
+			convey.Convey("Then the environment key should be used", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(authorization, convey.ShouldEqual, "Bearer from-env")
+			})
+		})
+	})
+}
+
+func TestOpenAIConvertMessagesVision(t *testing.T) {
+	convey.Convey("Given a user message with a text part and a base64 PNG", t, func() {
+		mimeType := "image/png"
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, a2a.Message{
+			Role: "user",
+			Parts: []a2a.Part{
+				a2a.NewTextPart("What is in this picture?"),
+				{Type: a2a.PartTypeFile, File: &a2a.FilePart{MimeType: &mimeType, Data: "iVBORw0KGgo="}},
+			},
+		})
+
+		convey.Convey("When it is converted", func() {
+			messages := (&OpenAIProvider{}).convertMessages(task)
+
+			convey.Convey("Then it should become one multipart user message", func() {
+				// NewTask seeds the history with a system message.
+				convey.So(messages, convey.ShouldHaveLength, 2)
+
+				message := messages[len(messages)-1]
+				convey.So(message.OfUser, convey.ShouldNotBeNil)
+
+				parts := message.OfUser.Content.OfArrayOfContentParts
+				convey.So(parts, convey.ShouldHaveLength, 2)
+				convey.So(parts[0].OfText.Text, convey.ShouldEqual, "What is in this picture?")
+				convey.So(parts[1].OfImageURL.ImageURL.URL, convey.ShouldEqual, "data:image/png;base64,iVBORw0KGgo=")
+			})
+		})
+	})
+
+	convey.Convey("Given a user message with only text", t, func() {
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "Hello"))
+
+		convey.Convey("Then it should stay a plain text message", func() {
+			messages := (&OpenAIProvider{}).convertMessages(task)
+			convey.So(messages[len(messages)-1].OfUser.Content.OfString.Value, convey.ShouldEqual, "Hello")
+		})
+	})
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+
+	"github.com/openai/openai-go"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+TTSFormat is the audio format speech is returned in.
+*/
+type TTSFormat string
+
+const (
+	TTSFormatMP3 TTSFormat = "mp3"
+	TTSFormatWAV TTSFormat = "wav"
+	// TTSFormatPCM is raw 24kHz 16-bit signed little-endian mono audio.
+	TTSFormatPCM TTSFormat = "pcm"
+)
+
+/*
+MimeType returns the MIME type of audio in the format.
+*/
+func (format TTSFormat) MimeType() string {
+	switch format {
+	case TTSFormatWAV:
+		return "audio/wav"
+	case TTSFormatPCM:
+		return "audio/L16;rate=24000;channels=1"
+	default:
+		return "audio/mpeg"
+	}
+}
+
+/*
+AudioPlayer plays generated speech.
+*/
+type AudioPlayer func(audio []byte, format TTSFormat) error
+
+type ttsConfig struct {
+	model  string
+	voice  string
+	format TTSFormat
+	task   *a2a.Task
+	player AudioPlayer
+}
+
+type TTSOption func(*ttsConfig)
+
+/*
+WithTTSModel selects the speech model, tts-1 by default.
+*/
+func WithTTSModel(model string) TTSOption {
+	return func(config *ttsConfig) {
+		config.model = model
+	}
+}
+
+/*
+WithTTSVoice selects the voice, such as alloy, echo or shimmer. The default
+is alloy.
+*/
+func WithTTSVoice(voice string) TTSOption {
+	return func(config *ttsConfig) {
+		config.voice = voice
+	}
+}
+
+/*
+WithTTSFormat selects the audio format, mp3 by default.
+*/
+func WithTTSFormat(format TTSFormat) TTSOption {
+	return func(config *ttsConfig) {
+		config.format = format
+	}
+}
+
+/*
+WithTTSArtifact attaches the speech to the task as a file artifact.
+*/
+func WithTTSArtifact(task *a2a.Task) TTSOption {
+	return func(config *ttsConfig) {
+		config.task = task
+	}
+}
+
+/*
+WithTTSPlayback plays the speech once it is generated. Playback is off by
+default, so servers without audio hardware only get the bytes.
+*/
+func WithTTSPlayback(player AudioPlayer) TTSOption {
+	return func(config *ttsConfig) {
+		config.player = player
+	}
+}
+
+/*
+TTS generates speech from text with the OpenAI speech endpoint and returns
+the audio bytes.
+*/
+func (prvdr *OpenAIProvider) TTS(ctx context.Context, text string, options ...TTSOption) ([]byte, error) {
+	config := &ttsConfig{
+		model:  openai.SpeechModelTTS1,
+		voice:  string(openai.AudioSpeechNewParamsVoiceAlloy),
+		format: TTSFormatMP3,
+	}
+
+	for _, option := range options {
+		option(config)
+	}
+
+	res, err := prvdr.client.Audio.Speech.New(ctx, openai.AudioSpeechNewParams{
+		Model:          config.model,
+		Input:          text,
+		Voice:          openai.AudioSpeechNewParamsVoice(config.voice),
+		ResponseFormat: openai.AudioSpeechNewParamsResponseFormat(config.format),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	audio, err := io.ReadAll(res.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if config.task != nil {
+		config.task.AddArtifact(a2a.NewFileArtifact(
+			"speech."+string(config.format),
+			config.format.MimeType(),
+			base64.StdEncoding.EncodeToString(audio),
+		))
+	}
+
+	if config.player != nil {
+		if err := config.player(audio, config.format); err != nil {
+			return audio, err
+		}
+	}
+
+	return audio, nil
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestOpenAITTS(t *testing.T) {
+	convey.Convey("Given an OpenAI provider behind a stub speech endpoint", t, func() {
+		var (
+			path string
+			body map[string]any
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			w.Header().Set("Content-Type", "audio/wav")
+			_, _ = w.Write([]byte("RIFF-audio"))
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		convey.Convey("When speech is generated for a task", func() {
+			task := a2a.NewTask("test")
+			var played []byte
+
+			audio, err := prvdr.TTS(
+				context.Background(), "Hello there",
+				WithTTSVoice("echo"),
+				WithTTSFormat(TTSFormatWAV),
+				WithTTSArtifact(task),
+				WithTTSPlayback(func(audio []byte, format TTSFormat) error {
+					played = audio
+					return nil
+				}),
+			)
+
+			convey.Convey("Then the speech endpoint should be called with the voice and format", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(path, convey.ShouldEqual, "/audio/speech")
+				convey.So(body["input"], convey.ShouldEqual, "Hello there")
+				convey.So(body["voice"], convey.ShouldEqual, "echo")
+				convey.So(body["response_format"], convey.ShouldEqual, "wav")
+			})
+
+			convey.Convey("Then the audio should be returned, attached and played", func() {
+				convey.So(string(audio), convey.ShouldEqual, "RIFF-audio")
+				convey.So(played, convey.ShouldResemble, audio)
+
+				convey.So(task.Artifacts, convey.ShouldHaveLength, 1)
+				file := task.Artifacts[0].Parts[0].File
+				convey.So(*file.MimeType, convey.ShouldEqual, "audio/wav")
+				convey.So(file.Data, convey.ShouldEqual, base64.StdEncoding.EncodeToString(audio))
+			})
+		})
+
+		convey.Convey("When no options are given", func() {
+			audio, err := prvdr.TTS(context.Background(), "Hello there")
+
+			convey.Convey("Then it should return mp3 bytes without attaching or playing them", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(audio, convey.ShouldNotBeEmpty)
+				convey.So(body["response_format"], convey.ShouldEqual, "mp3")
+				convey.So(body["model"], convey.ShouldEqual, "tts-1")
+			})
+		})
+	})
+}
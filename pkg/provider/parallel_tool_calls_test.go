@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+weatherCall renders a tool call of the weather tool in a chat completion.
+*/
+func weatherCall(id, city string) map[string]any {
+	return map[string]any{
+		"id":       id,
+		"type":     "function",
+		"function": map[string]any{"name": "weather", "arguments": fmt.Sprintf(`{"city":%q}`, city)},
+	}
+}
+
+func TestParallelToolCallsDisabled(t *testing.T) {
+	convey.Convey("Given an OpenAI provider whose model calls two tools in one turn", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		var executed []string
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			executed = append(executed, args)
+			return "sunny in " + parseToolArguments(args)["city"].(string), nil
+		}
+
+		var requests []map[string]any
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]any{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, body)
+
+			toolCalls := []map[string]any{weatherCall("call_1", "Paris"), weatherCall("call_2", "Rome")}
+
+			// The model calls the tool it was not answered for again.
+			if len(requests) > 1 {
+				toolCalls = toolCalls[1:]
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      "completion",
+				"object":  "chat.completion",
+				"created": 1,
+				"model":   "gpt-4o-mini",
+				"choices": []map[string]any{{
+					"index":         0,
+					"finish_reason": "tool_calls",
+					"message":       map[string]any{"role": "assistant", "tool_calls": toolCalls},
+				}},
+			})
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "What is the weather in Paris and Rome?"))
+
+		run := func(parallel bool) {
+			params := NewProviderParams(
+				task,
+				WithStream(false),
+				WithParallelToolCalls(parallel),
+				WithToolTerminators(ToolTerminator{
+					Tool:      "weather",
+					Predicate: func(result string) bool { return result == "sunny in Rome" },
+				}),
+			)
+
+			for range prvdr.Generate(context.Background(), params) {
+			}
+		}
+
+		convey.Convey("When parallel tool calls are disabled", func() {
+			run(false)
+
+			convey.Convey("Then the request should disable them", func() {
+				convey.So(requests[0]["parallel_tool_calls"], convey.ShouldEqual, false)
+			})
+
+			convey.Convey("Then the tools should run one per turn", func() {
+				convey.So(executed, convey.ShouldResemble, []string{`{"city":"Paris"}`, `{"city":"Rome"}`})
+				convey.So(requests, convey.ShouldHaveLength, 2)
+			})
+
+			convey.Convey("Then the model should only see the call that ran", func() {
+				messages := requests[1]["messages"].([]any)
+				assistant := messages[len(messages)-2].(map[string]any)
+
+				convey.So(assistant["tool_calls"], convey.ShouldHaveLength, 1)
+				convey.So(messages[len(messages)-1].(map[string]any)["tool_call_id"], convey.ShouldEqual, "call_1")
+			})
+		})
+
+		convey.Convey("When parallel tool calls are allowed", func() {
+			run(true)
+
+			convey.Convey("Then both tools should run in the same turn", func() {
+				convey.So(requests[0]["parallel_tool_calls"], convey.ShouldEqual, true)
+				convey.So(executed, convey.ShouldHaveLength, 2)
+				convey.So(requests, convey.ShouldHaveLength, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	anthropic "github.com/anthropics/anthropic-sdk-go"
+	"github.com/charmbracelet/log"
+	"github.com/ollama/ollama/api"
+	"github.com/openai/openai-go"
+	"google.golang.org/genai"
+)
+
+/*
+clock is the time source retries wait on, replaced in tests so a wait can be
+checked without taking it.
+*/
+type clock interface {
+	Now() time.Time
+	Sleep(ctx context.Context, d time.Duration) bool
+}
+
+/*
+realClock waits on timers.
+*/
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+/*
+Sleep waits for d, and reports false when ctx is done first.
+*/
+func (realClock) Sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+/*
+retry runs call until it succeeds, fails with an error that retrying cannot
+fix, or the attempts configured on params run out. When the provider says
+how long to wait with a Retry-After header, the next attempt waits exactly
+that long, capped at params.RetryAfterMax. Otherwise attempts are spaced by
+an exponential backoff from params.RetryBaseDelay, with jitter so concurrent
+tasks that were throttled together do not all come back at once.
+*/
+func retry(ctx context.Context, params *ProviderParams, call func() error) error {
+	delay := params.RetryBaseDelay
+	clock := params.clock
+
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := call()
+
+		if err == nil || attempt >= params.RetryAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		status, retryable := retryableStatus(err)
+
+		if !retryable {
+			return err
+		}
+
+		wait := delay/2 + rand.N(delay/2+1)
+
+		if after, ok := retryAfter(err, clock.Now()); ok {
+			wait = after
+
+			if params.RetryAfterMax > 0 {
+				wait = min(wait, params.RetryAfterMax)
+			}
+		}
+
+		log.Warn(
+			"provider call failed, retrying",
+			"status", status,
+			"attempt", attempt,
+			"wait", wait,
+			"error", err,
+		)
+
+		if !clock.Sleep(ctx, wait) {
+			return err
+		}
+
+		delay *= 2
+	}
+}
+
+/*
+retryableStatus returns the HTTP status of a provider error, and whether it
+is one a later attempt may succeed on: rate limiting, timeouts and server
+errors. Anything else, such as a bad request or a rejected key, fails fast.
+*/
+func retryableStatus(err error) (int, bool) {
+	var status int
+
+	var openaiErr *openai.Error
+	var anthropicErr *anthropic.Error
+	var googleErr genai.APIError
+	var ollamaErr api.StatusError
+
+	switch {
+	case errors.As(err, &openaiErr):
+		status = openaiErr.StatusCode
+	case errors.As(err, &anthropicErr):
+		status = anthropicErr.StatusCode
+	case errors.As(err, &googleErr):
+		status = googleErr.Code
+	case errors.As(err, &ollamaErr):
+		status = ollamaErr.StatusCode
+	default:
+		return 0, false
+	}
+
+	switch {
+	case status == http.StatusTooManyRequests, status == http.StatusRequestTimeout:
+		return status, true
+	case status >= http.StatusInternalServerError:
+		return status, true
+	}
+
+	return status, false
+}
+
+/*
+retryAfter returns how long the provider asked to wait before the next
+attempt, from the Retry-After header of the failed response, given either
+in seconds or as an HTTP date. Only the OpenAI and Anthropic clients expose
+the response of an error.
+*/
+func retryAfter(err error, now time.Time) (time.Duration, bool) {
+	var response *http.Response
+
+	var openaiErr *openai.Error
+	var anthropicErr *anthropic.Error
+
+	switch {
+	case errors.As(err, &openaiErr):
+		response = openaiErr.Response
+	case errors.As(err, &anthropicErr):
+		response = anthropicErr.Response
+	}
+
+	if response == nil {
+		return 0, false
+	}
+
+	header := strings.TrimSpace(response.Header.Get("Retry-After"))
+
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseFloat(header, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+
+	date, err := http.ParseTime(header)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestRetry(t *testing.T) {
+	convey.Convey("Given an OpenAI client behind a server that fails before it succeeds", t, func() {
+		var requests int
+		failures := 2
+		status := http.StatusTooManyRequests
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+
+			if requests <= failures {
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"error":{"message":"try again","type":"error"}}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"id":"c","object":"chat.completion","created":1,"model":"gpt-4o-mini","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"hello"}}]}`))
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+
+		params := NewProviderParams(a2a.NewTask("test"), WithRetry(3, time.Millisecond))
+
+		call := func() (*openai.ChatCompletion, error) {
+			var completion *openai.ChatCompletion
+
+			err := retry(context.Background(), params, func() (err error) {
+				completion, err = client.Chat.Completions.New(context.Background(), openai.ChatCompletionNewParams{
+					Model:    "gpt-4o-mini",
+					Messages: []openai.ChatCompletionMessageParamUnion{openai.UserMessage("hi")},
+				})
+				return err
+			})
+
+			return completion, err
+		}
+
+		convey.Convey("When the call is rate limited twice", func() {
+			completion, err := call()
+
+			convey.Convey("Then it should be retried until it succeeds", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(requests, convey.ShouldEqual, 3)
+				convey.So(completion.Choices[0].Message.Content, convey.ShouldEqual, "hello")
+			})
+		})
+
+		convey.Convey("When the call fails more often than there are attempts", func() {
+			failures = 5
+			_, err := call()
+
+			convey.Convey("Then the last error should be returned", func() {
+				convey.So(err, convey.ShouldNotBeNil)
+				convey.So(requests, convey.ShouldEqual, 3)
+			})
+		})
+
+		convey.Convey("When the request is rejected as invalid", func() {
+			status = http.StatusBadRequest
+			_, err := call()
+
+			convey.Convey("Then it should fail without retrying", func() {
+				convey.So(err, convey.ShouldNotBeNil)
+				convey.So(requests, convey.ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+/*
+fakeClock records the waits asked of it and returns at once.
+*/
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (clock *fakeClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *fakeClock) Sleep(ctx context.Context, d time.Duration) bool {
+	clock.sleeps = append(clock.sleeps, d)
+	clock.now = clock.now.Add(d)
+	return ctx.Err() == nil
+}
+
+func TestRetryAfter(t *testing.T) {
+	convey.Convey("Given an OpenAI client behind a server that rate limits once with Retry-After", t, func() {
+		var requests int
+		retryAfter := "2"
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+
+			if requests == 1 {
+				if retryAfter != "" {
+					w.Header().Set("Retry-After", retryAfter)
+				}
+
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":{"message":"slow down","type":"error"}}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"id":"c","object":"chat.completion","created":1,"model":"gpt-4o-mini","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"hello"}}]}`))
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+
+		clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+		params := NewProviderParams(a2a.NewTask("test"), WithRetry(3, time.Millisecond), WithRetryAfterMax(10*time.Second))
+		params.clock = clock
+
+		call := func() error {
+			return retry(context.Background(), params, func() error {
+				_, err := client.Chat.Completions.New(context.Background(), openai.ChatCompletionNewParams{
+					Model:    "gpt-4o-mini",
+					Messages: []openai.ChatCompletionMessageParamUnion{openai.UserMessage("hi")},
+				})
+				return err
+			})
+		}
+
+		convey.Convey("When the header asks for two seconds", func() {
+			err := call()
+
+			convey.Convey("Then the retry should wait exactly that long before it succeeds", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(requests, convey.ShouldEqual, 2)
+				convey.So(clock.sleeps, convey.ShouldResemble, []time.Duration{2 * time.Second})
+			})
+		})
+
+		convey.Convey("When the header is an HTTP date", func() {
+			retryAfter = clock.now.Add(5 * time.Second).Format(http.TimeFormat)
+			err := call()
+
+			convey.Convey("Then the retry should wait until that date", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(clock.sleeps, convey.ShouldResemble, []time.Duration{5 * time.Second})
+			})
+		})
+
+		convey.Convey("When the header asks for longer than the cap", func() {
+			retryAfter = "3600"
+			err := call()
+
+			convey.Convey("Then the wait should be cut down to the cap", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(clock.sleeps, convey.ShouldResemble, []time.Duration{10 * time.Second})
+			})
+		})
+
+		convey.Convey("When there is no header", func() {
+			retryAfter = ""
+			err := call()
+
+			convey.Convey("Then the retry should fall back to the backoff", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(clock.sleeps, convey.ShouldHaveLength, 1)
+				convey.So(clock.sleeps[0], convey.ShouldBeLessThanOrEqualTo, time.Millisecond)
+			})
+		})
+	})
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/viper"
+)
+
+/*
+ModelRoute sends the models whose name matches Pattern to the provider
+registered as Provider. Patterns are globs, as in path.Match, so "gpt-*"
+matches every GPT model and an exact name matches just that model.
+*/
+type ModelRoute struct {
+	Pattern  string `mapstructure:"pattern" json:"pattern"`
+	Provider string `mapstructure:"provider" json:"provider"`
+}
+
+/*
+DefaultModelRoutes route the model families of the providers in this
+package to them, by the names the router is given them under.
+*/
+var DefaultModelRoutes = []ModelRoute{
+	{Pattern: "gpt-*", Provider: "openai"},
+	{Pattern: "o[0-9]*", Provider: "openai"},
+	{Pattern: "gemini-*", Provider: "google"},
+	{Pattern: "claude-*", Provider: "anthropic"},
+	{Pattern: "llama*", Provider: "ollama"},
+	{Pattern: "mistral-*", Provider: "mistral"},
+	{Pattern: "deepseek-*", Provider: "deepseek"},
+	{Pattern: "command*", Provider: "cohere"},
+}
+
+/*
+ModelRouter picks the provider serving a model, for deployments with more
+than one provider, where a task may ask for any model by name.
+*/
+type ModelRouter struct {
+	providers map[string]Interface
+	routes    []ModelRoute
+	fallback  string
+}
+
+type ModelRouterOption func(*ModelRouter)
+
+/*
+NewModelRouter returns a router over the providers, keyed on the names the
+routes refer to them by. It uses DefaultModelRoutes, unless routes are
+configured under provider.routes or given with WithModelRoutes, and falls
+back to the openai provider for models no route matches.
+*/
+func NewModelRouter(providers map[string]Interface, options ...ModelRouterOption) *ModelRouter {
+	router := &ModelRouter{
+		providers: providers,
+		routes:    DefaultModelRoutes,
+		fallback:  "openai",
+	}
+
+	var configured []ModelRoute
+
+	if err := viper.GetViper().UnmarshalKey("provider.routes", &configured); err != nil {
+		log.Warn("failed to read provider.routes, using the default routes", "error", err)
+	} else if len(configured) > 0 {
+		router.routes = configured
+	}
+
+	for _, option := range options {
+		option(router)
+	}
+
+	return router
+}
+
+/*
+WithModelRoutes replaces the routes of the router. They are tried in order,
+and the first one matching the model and naming a registered provider wins.
+*/
+func WithModelRoutes(routes ...ModelRoute) ModelRouterOption {
+	return func(router *ModelRouter) {
+		router.routes = routes
+	}
+}
+
+/*
+WithFallbackProvider names the provider serving models no route matches.
+*/
+func WithFallbackProvider(name string) ModelRouterOption {
+	return func(router *ModelRouter) {
+		router.fallback = name
+	}
+}
+
+/*
+Route returns the provider serving the model, and the name it is
+registered under. Models no route matches, including an empty one, go to
+the fallback provider, which is nil when it is not registered.
+*/
+func (router *ModelRouter) Route(model string) (Interface, string) {
+	model = strings.ToLower(model)
+
+	for _, route := range router.routes {
+		matched, err := path.Match(strings.ToLower(route.Pattern), model)
+
+		if err != nil {
+			log.Warn("invalid model route pattern", "pattern", route.Pattern, "error", err)
+			continue
+		}
+
+		if prvdr, ok := router.providers[route.Provider]; matched && ok {
+			return prvdr, route.Provider
+		}
+	}
+
+	return router.Fallback(), router.fallback
+}
+
+/*
+Fallback returns the provider serving models no route matches.
+*/
+func (router *ModelRouter) Fallback() Interface {
+	return router.providers[router.fallback]
+}
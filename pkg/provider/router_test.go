@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestModelRouter(t *testing.T) {
+	convey.Convey("Given a router over OpenAI, Google and Ollama providers", t, func() {
+		openaiProvider := &OpenAIProvider{}
+		googleProvider := &GoogleProvider{}
+		ollamaProvider := &OllamaProvider{}
+
+		router := NewModelRouter(map[string]Interface{
+			"openai": openaiProvider,
+			"google": googleProvider,
+			"ollama": ollamaProvider,
+		})
+
+		convey.Convey("Then models should go to the provider serving their family", func() {
+			prvdr, name := router.Route("gemini-1.5-flash")
+			convey.So(prvdr, convey.ShouldEqual, googleProvider)
+			convey.So(name, convey.ShouldEqual, "google")
+
+			prvdr, name = router.Route("gpt-4o")
+			convey.So(prvdr, convey.ShouldEqual, openaiProvider)
+			convey.So(name, convey.ShouldEqual, "openai")
+
+			prvdr, _ = router.Route("llama3.2:latest")
+			convey.So(prvdr, convey.ShouldEqual, ollamaProvider)
+		})
+
+		convey.Convey("Then models of unregistered or unknown providers should fall back", func() {
+			prvdr, name := router.Route("claude-3-5-sonnet")
+			convey.So(prvdr, convey.ShouldEqual, openaiProvider)
+			convey.So(name, convey.ShouldEqual, "openai")
+
+			prvdr, _ = router.Route("")
+			convey.So(prvdr, convey.ShouldEqual, openaiProvider)
+		})
+
+		convey.Convey("When the routes and fallback are configured", func() {
+			router := NewModelRouter(
+				map[string]Interface{"openai": openaiProvider, "ollama": ollamaProvider},
+				WithModelRoutes(ModelRoute{Pattern: "qwen*", Provider: "ollama"}),
+				WithFallbackProvider("ollama"),
+			)
+
+			convey.Convey("Then only those routes should apply", func() {
+				prvdr, _ := router.Route("qwen2.5")
+				convey.So(prvdr, convey.ShouldEqual, ollamaProvider)
+
+				prvdr, _ = router.Route("gpt-4o")
+				convey.So(prvdr, convey.ShouldEqual, ollamaProvider)
+			})
+		})
+	})
+}
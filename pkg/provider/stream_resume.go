@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+StreamReconnectsKey is the task metadata key recording how many times a
+streaming provider had to reconnect to finish its response.
+*/
+const StreamReconnectsKey = "stream_reconnects"
+
+/*
+streamResume keeps the assistant content streamed so far, so a connection
+that drops mid-response can be resumed from where it stopped instead of
+restarting the generation and losing what the client already received.
+*/
+type streamResume struct {
+	retries  int
+	attempts int
+	content  strings.Builder
+	replay   string
+}
+
+func newStreamResume(retries int) *streamResume {
+	return &streamResume{retries: retries}
+}
+
+/*
+add records a fragment of streamed assistant content and returns the part
+of it the client has not received yet. That is all of it, unless a reply
+that restarted after a reconnect is still repeating what was streamed
+before it.
+*/
+func (resume *streamResume) add(text string) string {
+	if resume.replay != "" {
+		n := 0
+
+		for n < len(text) && n < len(resume.replay) && text[n] == resume.replay[n] {
+			n++
+		}
+
+		if n == len(text) {
+			resume.replay = resume.replay[n:]
+			return ""
+		}
+
+		// The reply went on from, or away from, the streamed content, so
+		// everything from here on is new.
+		text, resume.replay = text[n:], ""
+	}
+
+	resume.content.WriteString(text)
+
+	return text
+}
+
+/*
+restart is called after a reconnect to a provider that cannot prefill the
+reply with the partial content, so the model may start its answer over.
+The streamed content it repeats is then dropped by add, rather than sent to
+the client twice.
+*/
+func (resume *streamResume) restart() {
+	resume.replay = resume.content.String()
+}
+
+/*
+partial returns the assistant content streamed so far, across reconnects.
+*/
+func (resume *streamResume) partial() string {
+	return resume.content.String()
+}
+
+/*
+nextTurn starts a new model turn, whose content is resumed on its own.
+*/
+func (resume *streamResume) nextTurn() {
+	resume.content.Reset()
+	resume.replay = ""
+}
+
+/*
+retry decides whether a stream that ended with err should be resumed. It
+gives up once the retry budget is spent, or when the context itself was
+canceled, since reconnecting cannot help then. Each reconnect is recorded
+on the task metadata.
+*/
+func (resume *streamResume) retry(ctx context.Context, task *a2a.Task, err error) bool {
+	if err == nil || ctx.Err() != nil || resume.attempts >= resume.retries {
+		return false
+	}
+
+	resume.attempts++
+
+	log.Warn(
+		"stream interrupted, resuming",
+		"task", task.ID,
+		"attempt", resume.attempts,
+		"streamed", resume.content.Len(),
+		"error", err,
+	)
+
+	task.MergeMetadata(map[string]any{StreamReconnectsKey: resume.attempts})
+
+	return true
+}
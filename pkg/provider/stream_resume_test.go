@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+contentChunk renders one streamed chat completion chunk carrying content.
+*/
+func contentChunk(content, finishReason string) string {
+	chunk := map[string]any{
+		"id":      "chunk",
+		"object":  "chat.completion.chunk",
+		"created": 1,
+		"model":   "gpt-4o-mini",
+		"choices": []map[string]any{{
+			"index":         0,
+			"delta":         map[string]any{"content": content},
+			"finish_reason": finishReason,
+		}},
+	}
+
+	buf, _ := json.Marshal(chunk)
+	return fmt.Sprintf("data: %s\n\n", buf)
+}
+
+func TestStreamResume(t *testing.T) {
+	Convey("Given an OpenAI stream that drops partway through", t, func() {
+		var (
+			mu       sync.Mutex
+			requests []map[string]any
+			resumed  []string
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]any{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			mu.Lock()
+			requests = append(requests, body)
+			first := len(requests) == 1
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			flusher := w.(http.Flusher)
+
+			if first {
+				fmt.Fprint(w, contentChunk("Hello", ""))
+				fmt.Fprint(w, contentChunk(", wor", ""))
+				flusher.Flush()
+
+				// Drop the connection before the stream is terminated.
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+
+			for _, content := range resumed {
+				fmt.Fprint(w, contentChunk(content, ""))
+			}
+
+			fmt.Fprint(w, contentChunk("", "stop"))
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			flusher.Flush()
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "Say hello"))
+
+		generate := func() string {
+			var content string
+
+			for response := range prvdr.Generate(context.Background(), NewProviderParams(task)) {
+				So(response.Error, ShouldBeNil)
+
+				if artifact, ok := response.Result.(a2a.ArtifactResult); ok {
+					for _, part := range artifact.Artifact.Parts {
+						content += part.Text
+					}
+				}
+			}
+
+			return content
+		}
+
+		Convey("When the model continues from the streamed content", func() {
+			resumed = []string{"ld!"}
+			content := generate()
+
+			Convey("Then it should resume and produce the full content", func() {
+				So(content, ShouldEqual, "Hello, world!")
+			})
+
+			Convey("Then the retry should continue from the streamed content", func() {
+				So(requests, ShouldHaveLength, 2)
+
+				messages := requests[1]["messages"].([]any)
+				last := messages[len(messages)-1].(map[string]any)
+				So(last["role"], ShouldEqual, "assistant")
+				So(last["content"], ShouldEqual, "Hello, wor")
+			})
+
+			Convey("Then the reconnect should be recorded on the task", func() {
+				So(task.Metadata[StreamReconnectsKey], ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the model starts its answer over", func() {
+			resumed = []string{"Hel", "lo, world", "!"}
+			content := generate()
+
+			Convey("Then the repeated content should not be streamed twice", func() {
+				So(content, ShouldEqual, "Hello, world!")
+			})
+		})
+	})
+}
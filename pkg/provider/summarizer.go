@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/tools"
+)
+
+/*
+NewSummarizer adapts a provider to the tools.Summarizer the web_summarize
+tool sends pages to. Every prompt runs as a single streamed turn without
+tools, and the streamed content is the reply.
+*/
+func NewSummarizer(prvdr Interface, model string) tools.Summarizer {
+	return func(ctx context.Context, prompt string) (string, error) {
+		task := a2a.NewTask("web_summarize")
+		task.History = append(task.History, *a2a.NewTextMessage("user", prompt))
+
+		params := NewProviderParams(task, WithModel(model), WithStream(true))
+
+		var reply strings.Builder
+
+		for response := range prvdr.Generate(ctx, params) {
+			if response.Error != nil {
+				return "", fmt.Errorf("summarizer: %s", response.Error.Message)
+			}
+
+			if result, ok := response.Result.(a2a.ArtifactResult); ok {
+				for _, part := range result.Artifact.Parts {
+					reply.WriteString(part.Text)
+				}
+			}
+		}
+
+		return reply.String(), nil
+	}
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+ToolTerminator ends a run as soon as the named tool returns a result the
+predicate accepts, completing the task with that result instead of handing
+it back to the model for another turn. A nil predicate accepts any
+successful result.
+*/
+type ToolTerminator struct {
+	Tool      string
+	Predicate func(result string) bool
+}
+
+/*
+WithToolTerminators sets the tool results that end the run, for workflows
+that are done once a tool such as "submit" succeeds.
+*/
+func WithToolTerminators(terminators ...ToolTerminator) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.ToolTerminators = terminators
+	}
+}
+
+/*
+terminate completes the task with the result of a successful tool call when
+a terminator matches it, and reports whether one did.
+*/
+func (params *ProviderParams) terminate(toolName, result string) bool {
+	for _, terminator := range params.ToolTerminators {
+		if terminator.Tool != toolName || (terminator.Predicate != nil && !terminator.Predicate(result)) {
+			continue
+		}
+
+		log.Info("tool result terminates the task", "tool_name", toolName, "task_id", params.Task.ID)
+
+		params.terminated = true
+		params.Task.AddFinalPart(a2a.NewTextPart(result))
+		params.Task.ToStatus(a2a.TaskStateCompleted, a2a.NewTextMessage("assistant", result))
+
+		return true
+	}
+
+	return false
+}
+
+/*
+finishTerminated sends the final status of a task a tool result completed,
+and reports whether it did, in which case the provider must not call the
+model again.
+*/
+func finishTerminated(params *ProviderParams, ch chan jsonrpc.Response) bool {
+	if !params.terminated {
+		return false
+	}
+
+	ch <- jsonrpc.Response{
+		Result: a2a.TaskStatusUpdateResult{
+			ID:     params.Task.ID,
+			Status: params.Task.Status,
+			Final:  true,
+		},
+	}
+
+	return true
+}
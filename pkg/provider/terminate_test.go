@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestToolTerminators(t *testing.T) {
+	convey.Convey("Given an OpenAI provider that calls a finish tool", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			return "submitted", nil
+		}
+
+		var requests int
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "text/event-stream")
+
+			if requests == 1 {
+				fmt.Fprint(w, toolCallChunk("call_1", "finish", `{"answer":"42"}`))
+				fmt.Fprint(w, contentChunk("", "tool_calls"))
+			} else {
+				fmt.Fprint(w, contentChunk("Done.", ""))
+				fmt.Fprint(w, contentChunk("", "stop"))
+			}
+
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "Submit the answer."))
+
+		run := func(terminator ToolTerminator) (final bool) {
+			params := NewProviderParams(task, WithToolTerminators(terminator))
+
+			for response := range prvdr.Generate(context.Background(), params) {
+				if update, ok := response.Result.(a2a.TaskStatusUpdateResult); ok && update.Final {
+					final = true
+				}
+			}
+
+			return final
+		}
+
+		convey.Convey("When the finish tool terminates on success", func() {
+			final := run(ToolTerminator{
+				Tool:      "finish",
+				Predicate: func(result string) bool { return result == "submitted" },
+			})
+
+			convey.Convey("Then the task should complete without another provider call", func() {
+				convey.So(requests, convey.ShouldEqual, 1)
+				convey.So(final, convey.ShouldBeTrue)
+				convey.So(task.Status.State, convey.ShouldEqual, a2a.TaskStateCompleted)
+				convey.So(task.Status.Message.Parts[0].Text, convey.ShouldEqual, "submitted")
+			})
+		})
+
+		convey.Convey("When the predicate rejects the result", func() {
+			run(ToolTerminator{
+				Tool:      "finish",
+				Predicate: func(result string) bool { return result == "failed" },
+			})
+
+			convey.Convey("Then the result should go back to the model", func() {
+				convey.So(requests, convey.ShouldEqual, 2)
+			})
+		})
+
+		convey.Convey("When a different tool terminates", func() {
+			run(ToolTerminator{Tool: "submit"})
+
+			convey.Convey("Then the result should go back to the model", func() {
+				convey.So(requests, convey.ShouldEqual, 2)
+			})
+		})
+	})
+}
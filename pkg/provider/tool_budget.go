@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/tools"
+)
+
+/*
+WithTotalToolBudget caps the time a task may spend executing tools, summed
+over all of its tool calls. Once the budget is spent, further tool calls are
+not executed and the model is asked to finalize its answer. A zero budget
+leaves the time unbounded.
+*/
+func WithTotalToolBudget(budget time.Duration) ProviderParamsOption {
+	return func(params *ProviderParams) {
+		params.TotalToolBudget = budget
+	}
+}
+
+/*
+spendToolTime adds the duration of an executed tool call to the time spent
+against the budget.
+*/
+func (params *ProviderParams) spendToolTime(duration time.Duration) {
+	params.toolTime += duration
+}
+
+/*
+toolBudgetSpent reports whether the task has used up its tool budget.
+*/
+func (params *ProviderParams) toolBudgetSpent() bool {
+	return params.TotalToolBudget > 0 && params.toolTime >= params.TotalToolBudget
+}
+
+/*
+toolTimeout returns the timeout of the next call of the named tool, which is
+the tool's own timeout cut down to what is left of the budget.
+*/
+func (params *ProviderParams) toolTimeout(toolName string) time.Duration {
+	timeout := tools.TimeoutFor(toolName)
+
+	if params.TotalToolBudget > 0 {
+		timeout = min(timeout, params.TotalToolBudget-params.toolTime)
+	}
+
+	return timeout
+}
+
+/*
+overToolBudget handles a tool call made after the budget was spent. The
+first one is answered with a budget error asking the model to finalize. A
+model that calls another tool instead ends the task with the budget notice,
+the same way a terminating tool does.
+*/
+func (params *ProviderParams) overToolBudget(toolName string) string {
+	notice := fmt.Sprintf(
+		"The total tool budget of %s for this task is exhausted after %s of tool calls.",
+		params.TotalToolBudget, params.toolTime.Round(time.Millisecond),
+	)
+
+	if params.toolBudgetNotified {
+		log.Warn("tool budget exhausted, ending the task", "tool_name", toolName, "task_id", params.Task.ID)
+
+		params.terminated = true
+		params.Task.AddFinalPart(a2a.NewTextPart(notice))
+		params.Task.ToStatus(a2a.TaskStateCompleted, a2a.NewTextMessage("assistant", notice))
+
+		return notice
+	}
+
+	log.Warn("tool budget exhausted, asking the model to finalize", "tool_name", toolName, "task_id", params.Task.ID)
+
+	params.toolBudgetNotified = true
+
+	buf, _ := json.Marshal(map[string]any{
+		"error":     "budget_exceeded",
+		"tool":      toolName,
+		"budget":    params.TotalToolBudget.String(),
+		"retriable": false,
+		"message":   notice + " Do not call any more tools, finalize your answer with what you have.",
+	})
+
+	return string(buf)
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestTotalToolBudget(t *testing.T) {
+	convey.Convey("Given an OpenAI provider that keeps calling a slow tool", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		var executions int
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			executions++
+
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return "searched", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		var (
+			requests  int
+			finalizes bool
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "text/event-stream")
+
+			if finalizes && strings.Contains(string(body), "budget_exceeded") {
+				fmt.Fprint(w, contentChunk("Final answer.", ""))
+				fmt.Fprint(w, contentChunk("", "stop"))
+			} else {
+				fmt.Fprint(w, toolCallChunk(fmt.Sprintf("call_%d", requests), "search", `{"query":"more"}`))
+				fmt.Fprint(w, contentChunk("", "tool_calls"))
+			}
+
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "Research the topic."))
+
+		run := func() {
+			params := NewProviderParams(task, WithTotalToolBudget(80*time.Millisecond))
+
+			for range prvdr.Generate(context.Background(), params) {
+			}
+		}
+
+		convey.Convey("When the model finalizes once the budget is exhausted", func() {
+			finalizes = true
+			run()
+
+			convey.Convey("Then no more tools should run and the model should get to answer", func() {
+				convey.So(executions, convey.ShouldEqual, 2)
+				convey.So(requests, convey.ShouldEqual, 4)
+
+				var notices int
+				for _, artifact := range task.Artifacts {
+					if artifact.Description != nil && *artifact.Description == "Tool budget exceeded." {
+						notices++
+					}
+				}
+				convey.So(notices, convey.ShouldEqual, 1)
+			})
+		})
+
+		convey.Convey("When the model keeps calling tools after the budget is exhausted", func() {
+			run()
+
+			convey.Convey("Then the task should stop invoking tools and complete with a budget notice", func() {
+				convey.So(executions, convey.ShouldEqual, 2)
+				convey.So(requests, convey.ShouldEqual, 4)
+				convey.So(task.Status.State, convey.ShouldEqual, a2a.TaskStateCompleted)
+				convey.So(task.Status.Message.String(), convey.ShouldContainSubstring, "total tool budget of 80ms")
+			})
+		})
+	})
+}
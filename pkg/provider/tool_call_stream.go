@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+toolCallStream accumulates tool-call argument deltas as they arrive from a
+streaming provider, so clients can preview a tool call before it runs.
+Deltas are keyed on the index the provider assigns to each tool call.
+*/
+type toolCallStream struct {
+	taskID string
+	ids    map[int64]string
+	names  map[int64]string
+	args   map[int64]*strings.Builder
+}
+
+func newToolCallStream(taskID string) *toolCallStream {
+	return &toolCallStream{
+		taskID: taskID,
+		ids:    make(map[int64]string),
+		names:  make(map[int64]string),
+		args:   make(map[int64]*strings.Builder),
+	}
+}
+
+/*
+delta records an argument fragment and returns an event carrying the
+arguments accumulated so far for that tool call.
+*/
+func (stream *toolCallStream) delta(index int64, id, name, argsDelta string) jsonrpc.Response {
+	if id != "" {
+		stream.ids[index] = id
+	}
+
+	if name != "" {
+		stream.names[index] = name
+	}
+
+	if _, ok := stream.args[index]; !ok {
+		stream.args[index] = &strings.Builder{}
+	}
+
+	stream.args[index].WriteString(argsDelta)
+
+	return jsonrpc.Response{
+		Result: a2a.TaskToolCallEvent{
+			ID:          stream.taskID,
+			ToolCallID:  stream.ids[index],
+			ToolName:    stream.names[index],
+			PartialArgs: stream.args[index].String(),
+		},
+	}
+}
+
+/*
+done returns the event for a fully assembled tool call. It is sent right
+before the tool is executed.
+*/
+func (stream *toolCallStream) done(id, name, args string) jsonrpc.Response {
+	return jsonrpc.Response{
+		Result: a2a.TaskToolCallEvent{
+			ID:          stream.taskID,
+			ToolCallID:  id,
+			ToolName:    name,
+			PartialArgs: args,
+			Done:        true,
+		},
+	}
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+toolCallDeltaChunk renders one streamed chat completion chunk carrying a
+fragment of a tool call.
+*/
+func toolCallDeltaChunk(id, name, args string) string {
+	function := map[string]any{"arguments": args}
+
+	if name != "" {
+		function["name"] = name
+	}
+
+	call := map[string]any{"index": 0, "function": function}
+
+	if id != "" {
+		call["id"] = id
+		call["type"] = "function"
+	}
+
+	chunk := map[string]any{
+		"id":      "chunk",
+		"object":  "chat.completion.chunk",
+		"created": 1,
+		"model":   "gpt-4o-mini",
+		"choices": []map[string]any{{
+			"index":         0,
+			"delta":         map[string]any{"tool_calls": []map[string]any{call}},
+			"finish_reason": nil,
+		}},
+	}
+
+	buf, _ := json.Marshal(chunk)
+	return fmt.Sprintf("data: %s\n\n", buf)
+}
+
+func TestToolCallStream(t *testing.T) {
+	Convey("Given an OpenAI stream building a tool call across chunks", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		var (
+			mu       sync.Mutex
+			requests int
+			executed []string
+		)
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			executed = append(executed, args)
+			return "total 0", nil
+		}
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests++
+			first := requests == 1
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "text/event-stream")
+
+			if first {
+				fmt.Fprint(w, toolCallDeltaChunk("call_1", "docker", `{"cmd":`))
+				fmt.Fprint(w, toolCallDeltaChunk("", "", ` "ls`))
+				fmt.Fprint(w, toolCallDeltaChunk("", "", ` -la"}`))
+				fmt.Fprint(w, contentChunk("", "tool_calls"))
+			} else {
+				fmt.Fprint(w, contentChunk("Nothing there.", ""))
+				fmt.Fprint(w, contentChunk("", "stop"))
+			}
+
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		generate := func(options ...ProviderParamsOption) []jsonrpc.Response {
+			task := a2a.NewTask("test")
+			task.History = append(task.History, *a2a.NewTextMessage("user", "List the files."))
+
+			var responses []jsonrpc.Response
+
+			for response := range prvdr.Generate(context.Background(), NewProviderParams(task, options...)) {
+				responses = append(responses, response)
+			}
+
+			return responses
+		}
+
+		Convey("When tool-call streaming is enabled", func() {
+			responses := generate(WithStreamToolCalls(true))
+
+			Convey("Then partial-argument events should precede the execution", func() {
+				var events []a2a.TaskToolCallEvent
+				executedAt := -1
+
+				for i, response := range responses {
+					if event, ok := response.Result.(a2a.TaskToolCallEvent); ok {
+						events = append(events, event)
+					}
+
+					if _, ok := response.Result.(*a2a.Task); ok && executedAt < 0 {
+						executedAt = i
+					}
+				}
+
+				So(events, ShouldHaveLength, 4)
+
+				expected := []string{`{"cmd":`, `{"cmd": "ls`, `{"cmd": "ls -la"}`}
+				for i, partial := range expected {
+					So(events[i].ToolCallID, ShouldEqual, "call_1")
+					So(events[i].ToolName, ShouldEqual, "docker")
+					So(events[i].PartialArgs, ShouldEqual, partial)
+					So(events[i].Done, ShouldBeFalse)
+				}
+
+				So(events[3].Done, ShouldBeTrue)
+				So(events[3].ToolCallID, ShouldEqual, "call_1")
+				So(events[3].PartialArgs, ShouldEqual, `{"cmd": "ls -la"}`)
+
+				So(executedAt, ShouldBeGreaterThan, 0)
+
+				last, ok := responses[executedAt-1].Result.(a2a.TaskToolCallEvent)
+				So(ok, ShouldBeTrue)
+				So(last.Done, ShouldBeTrue)
+
+				So(executed, ShouldResemble, []string{`{"cmd": "ls -la"}`})
+			})
+		})
+
+		Convey("When tool-call streaming is disabled", func() {
+			responses := generate()
+
+			Convey("Then no tool-call events should be sent", func() {
+				for _, response := range responses {
+					_, ok := response.Result.(a2a.TaskToolCallEvent)
+					So(ok, ShouldBeFalse)
+				}
+
+				So(executed, ShouldHaveLength, 1)
+			})
+		})
+	})
+}
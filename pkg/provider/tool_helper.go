@@ -2,7 +2,10 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/theapemachine/a2a-go/pkg/a2a"
@@ -16,29 +19,111 @@ import (
 // isError indicates if the content represents an error.
 type LLMToolResponseGenerator func(toolCallID string, content string, isError bool) any
 
+// executeTool runs a tool by name. It is a variable so tests can swap in
+// tools without a running MCP server.
+var executeTool = tools.NewExecutor
+
 // ExecuteAndProcessToolCall centralizes the logic for executing a tool,
 // updating the task with an artifact, and preparing the tool response message for the LLM.
-// It modifies the input 'task' in place by adding an artifact.
+// It modifies params.Task in place by adding an artifact.
 // It returns the (modified) task, the generated LLM-specific tool response message,
 // and any error encountered during tool execution.
+// Every call is bounded by the tool's timeout (see tools.TimeoutFor). A timeout
+// is reported to the LLM as a retriable tool error and is not returned as an
+// execution error, so the task carries on. Other retriable failures (see
+// tools.IsRetriable) are retried with backoff, up to params.ToolRetries times,
+// before the LLM gets to see them. Once params.TotalToolBudget is spent, calls
+// are not executed at all (see overToolBudget). Outside dry runs, every call
+// is appended to params.ToolTranscript, whatever its outcome.
 func ExecuteAndProcessToolCall(
 	ctx context.Context,
 	toolName string,
 	toolArguments string,
 	toolCallID string, // The ID from the LLM's tool request, used by some providers for constructing the response.
-	task *a2a.Task, // Input task, will be modified in place.
+	params *ProviderParams, // params.Task will be modified in place.
 	generateLLMToolResponse LLMToolResponseGenerator,
 ) (updatedTask *a2a.Task, llmToolResponse any, executionError error) {
+	task := params.Task
+	started := time.Now()
 
 	log.Debug("Executing tool via helper", "tool_name", toolName, "arguments", toolArguments)
 
-	resultContent, err := tools.NewExecutor(ctx, toolName, toolArguments)
+	if params.DryRun {
+		// Capture the call as part of the plan and let the model carry on as if
+		// the tool had run, so it can reveal the rest of its intended calls.
+		log.Info("Dry-run, tool call not executed", "tool_name", toolName)
+		params.PlannedToolCalls = append(params.PlannedToolCalls, PlannedToolCall{
+			ID:        toolCallID,
+			Name:      toolName,
+			Arguments: parseToolArguments(toolArguments),
+		})
+		return task, generateLLMToolResponse(toolCallID, "(dry-run, not executed)", false), nil
+	}
+
+	if params.toolBudgetSpent() {
+		notice := params.overToolBudget(toolName)
+		artifactName := toolName
+		artifactDescription := "Tool budget exceeded."
+		task.AddArtifact(a2a.Artifact{
+			Name:        &artifactName,
+			Description: &artifactDescription,
+			Parts:       []a2a.Part{a2a.NewTextPart(notice)},
+		})
+		params.recordToolCall(toolCallID, toolName, toolArguments, started, "", notice)
+		return task, generateLLMToolResponse(toolCallID, notice, true), nil
+	}
+
+	if params.ToolApproval != nil {
+		approved, err := params.ToolApproval(ctx, toolName, parseToolArguments(toolArguments))
+
+		if err != nil {
+			log.Error("Tool approval failed", "tool_name", toolName, "error", err)
+			errorMsg := fmt.Sprintf("Error: tool approval failed: %s", err.Error())
+			artifactName := toolName
+			artifactDescription := "Tool approval failed."
+			task.AddArtifact(a2a.Artifact{
+				Name:        &artifactName,
+				Description: &artifactDescription,
+				Parts:       []a2a.Part{a2a.NewTextPart(errorMsg)},
+			})
+			params.recordToolCall(toolCallID, toolName, toolArguments, started, "", errorMsg)
+			return task, generateLLMToolResponse(toolCallID, errorMsg, true), err
+		}
+
+		if !approved {
+			// A denial is an operator decision, not an execution failure, so the
+			// model is told about it and the loop carries on.
+			log.Info("Tool call denied", "tool_name", toolName)
+			deniedMsg := fmt.Sprintf("The %s tool call was denied by the operator and was not executed.", toolName)
+			artifactName := toolName
+			artifactDescription := "Tool call denied."
+			task.AddArtifact(a2a.Artifact{
+				Name:        &artifactName,
+				Description: &artifactDescription,
+				Parts:       []a2a.Part{a2a.NewTextPart(deniedMsg)},
+			})
+			params.recordToolCall(toolCallID, toolName, toolArguments, started, "", deniedMsg)
+			return task, generateLLMToolResponse(toolCallID, deniedMsg, true), nil
+		}
+	}
+
+	resultContent, err := executeWithRetries(ctx, toolName, toolArguments, params)
 
 	artifactName := toolName
 	var artifactDescription string
 	var artifactParts []a2a.Part
 
-	if err != nil {
+	var timeoutErr *toolTimeoutError
+
+	if errors.As(err, &timeoutErr) {
+		log.Warn("Tool execution timed out", "tool_name", toolName, "timeout", timeoutErr.Timeout)
+		errorMsg := timeoutErr.Payload()
+		artifactDescription = "Tool execution timed out."
+		artifactParts = []a2a.Part{a2a.NewTextPart(errorMsg)}
+		llmToolResponse = generateLLMToolResponse(toolCallID, errorMsg, true)
+		executionError = nil
+		params.recordToolCall(toolCallID, toolName, toolArguments, started, "", errorMsg)
+	} else if err != nil {
 		log.Error("Error executing tool via helper", "tool_name", toolName, "error", err)
 		errorMsg := fmt.Sprintf("Error: %s", err.Error())
 		artifactDescription = "Tool execution failed."
@@ -47,6 +132,7 @@ func ExecuteAndProcessToolCall(
 		// Generate the LLM-specific response message indicating an error.
 		llmToolResponse = generateLLMToolResponse(toolCallID, errorMsg, true)
 		executionError = err // Preserve the original error from the executor.
+		params.recordToolCall(toolCallID, toolName, toolArguments, started, "", errorMsg)
 	} else {
 		log.Debug("Tool executed successfully via helper", "tool_name", toolName, "result_length", len(resultContent))
 		artifactDescription = fmt.Sprintf("Output from %s tool.", toolName)
@@ -55,6 +141,9 @@ func ExecuteAndProcessToolCall(
 		// Generate the LLM-specific response message with the successful result.
 		llmToolResponse = generateLLMToolResponse(toolCallID, resultContent, false)
 		executionError = nil
+		params.recordToolCall(toolCallID, toolName, toolArguments, started, resultContent, "")
+
+		params.terminate(toolName, resultContent)
 	}
 
 	task.AddArtifact(a2a.Artifact{
@@ -67,3 +156,121 @@ func ExecuteAndProcessToolCall(
 	updatedTask = task
 	return
 }
+
+// parseToolArguments decodes the raw JSON arguments of a tool call, falling
+// back to the raw string when they are not a JSON object.
+func parseToolArguments(toolArguments string) map[string]any {
+	args := map[string]any{}
+
+	if err := json.Unmarshal([]byte(toolArguments), &args); err != nil {
+		return map[string]any{"raw": toolArguments}
+	}
+
+	return args
+}
+
+// toolTimeoutError reports a tool call that ran past its timeout.
+type toolTimeoutError struct {
+	Tool    string
+	Timeout time.Duration
+}
+
+func (err *toolTimeoutError) Error() string {
+	return fmt.Sprintf("tool %s timed out after %s", err.Tool, err.Timeout)
+}
+
+// Payload renders the structured error sent back to the LLM, marking the
+// call as safe to retry.
+func (err *toolTimeoutError) Payload() string {
+	buf, _ := json.Marshal(map[string]any{
+		"error":     "timeout",
+		"tool":      err.Tool,
+		"timeout":   err.Timeout.String(),
+		"retriable": true,
+		"message":   err.Error(),
+	})
+
+	return string(buf)
+}
+
+// executeWithRetries executes the tool, retrying with exponential backoff for
+// as long as it fails with a retriable error and retries are left. The last
+// attempt's result is returned as is. Timeouts are not retried here, as the
+// tool already used its full time budget, and neither are failures that spent
+// what was left of the task's tool budget.
+func executeWithRetries(ctx context.Context, toolName, toolArguments string, params *ProviderParams) (string, error) {
+	backoff := params.ToolRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		started := time.Now()
+		content, err := executeWithTimeout(ctx, toolName, toolArguments, params.toolTimeout(toolName))
+		duration := time.Since(started)
+
+		params.spendToolTime(duration)
+
+		if params.OnToolCall != nil {
+			params.OnToolCall(toolName, parseToolArguments(toolArguments), duration, err)
+		}
+
+		if attempt >= params.ToolRetries || params.toolBudgetSpent() || !tools.IsRetriable(content, err) {
+			return content, err
+		}
+
+		log.Warn("Retriable tool error, retrying",
+			"tool_name", toolName, "attempt", attempt+1, "backoff", backoff, "error", err,
+		)
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return content, err
+		}
+	}
+}
+
+// executeWithTimeout runs the tool under a child context bounded by timeout.
+// The tool runs in its own goroutine so a tool that ignores its context still
+// cannot wedge the task.
+func executeWithTimeout(ctx context.Context, toolName, toolArguments string, timeout time.Duration) (string, error) {
+	toolCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		content string
+		err     error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		content, err := executeTool(toolCtx, toolName, toolArguments)
+		done <- result{content: content, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil && ctx.Err() == nil && errors.Is(toolCtx.Err(), context.DeadlineExceeded) {
+			return "", &toolTimeoutError{Tool: toolName, Timeout: timeout}
+		}
+		return res.content, res.err
+	case <-toolCtx.Done():
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", &toolTimeoutError{Tool: toolName, Timeout: timeout}
+	}
+}
+
+// sequentialToolCalls keeps only the first of the tool calls a model made in
+// one turn when params.ParallelToolCalls is disabled, for providers that
+// cannot be told to make one call at a time. The model sees the result before
+// making the next call, in the turn that follows.
+func sequentialToolCalls[T any](params *ProviderParams, calls []T) []T {
+	if params.ParallelToolCalls || len(calls) <= 1 {
+		return calls
+	}
+
+	log.Debug("parallel tool calls disabled, deferring tool calls", "deferred", len(calls)-1)
+	return calls[:1]
+}
@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/tools"
+)
+
+/*
+fakeToolResponse records what the helper sends back to the model.
+*/
+type fakeToolResponse struct {
+	content string
+	isError bool
+}
+
+func fakeToolResponseGenerator(toolCallID string, content string, isError bool) any {
+	return fakeToolResponse{content: content, isError: isError}
+}
+
+func TestExecuteAndProcessToolCall(t *testing.T) {
+	convey.Convey("Given a tool that sleeps past its timeout", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			time.Sleep(200 * time.Millisecond)
+			return "too late", nil
+		}
+
+		tools.RegisterTimeout("slow_tool", 20*time.Millisecond)
+		defer tools.RegisterTimeout("slow_tool", 0)
+
+		task := &a2a.Task{ID: "task-1"}
+		params := NewProviderParams(task)
+
+		convey.Convey("When the tool call is executed", func() {
+			start := time.Now()
+			updated, response, err := ExecuteAndProcessToolCall(
+				context.Background(), "slow_tool", `{}`, "call-1", params, fakeToolResponseGenerator,
+			)
+
+			convey.Convey("Then it should return a retriable timeout error to the model", func() {
+				convey.So(time.Since(start), convey.ShouldBeLessThan, 200*time.Millisecond)
+
+				toolResponse := response.(fakeToolResponse)
+				convey.So(toolResponse.isError, convey.ShouldBeTrue)
+
+				payload := map[string]any{}
+				convey.So(json.Unmarshal([]byte(toolResponse.content), &payload), convey.ShouldBeNil)
+				convey.So(payload["error"], convey.ShouldEqual, "timeout")
+				convey.So(payload["retriable"], convey.ShouldBeTrue)
+			})
+
+			convey.Convey("Then the task should continue", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(len(updated.Artifacts), convey.ShouldEqual, 1)
+				convey.So(*updated.Artifacts[0].Description, convey.ShouldEqual, "Tool execution timed out.")
+			})
+		})
+	})
+
+	convey.Convey("Given a tool that finishes within its timeout", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			return "done", nil
+		}
+
+		params := NewProviderParams(&a2a.Task{ID: "task-2"})
+
+		convey.Convey("When the tool call is executed", func() {
+			_, response, err := ExecuteAndProcessToolCall(
+				context.Background(), "fast_tool", `{}`, "call-2", params, fakeToolResponseGenerator,
+			)
+
+			convey.Convey("Then the result should be passed through", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(response, convey.ShouldResemble, fakeToolResponse{content: "done"})
+			})
+		})
+	})
+}
+
+func TestToolRetries(t *testing.T) {
+	convey.Convey("Given a tool that fails twice with a retriable error", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		calls := 0
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			calls++
+			if calls < 3 {
+				return "", tools.Retriable(errors.New("rate limited"))
+			}
+			return "done", nil
+		}
+
+		params := NewProviderParams(&a2a.Task{ID: "task-3"}, WithToolRetries(2, time.Millisecond))
+
+		convey.Convey("When the tool call is executed", func() {
+			updated, response, err := ExecuteAndProcessToolCall(
+				context.Background(), "flaky_tool", `{}`, "call-3", params, fakeToolResponseGenerator,
+			)
+
+			convey.Convey("Then the tool should have run three times", func() {
+				convey.So(calls, convey.ShouldEqual, 3)
+			})
+
+			convey.Convey("Then the model should only see the success", func() {
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(response, convey.ShouldResemble, fakeToolResponse{content: "done"})
+				convey.So(len(updated.Artifacts), convey.ShouldEqual, 1)
+				convey.So(*updated.Artifacts[0].Description, convey.ShouldEqual, "Output from flaky_tool tool.")
+			})
+		})
+	})
+
+	convey.Convey("Given a tool that reports a retriable error payload", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		calls := 0
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			calls++
+			return `{"error":"unavailable","retriable":true}`, nil
+		}
+
+		params := NewProviderParams(&a2a.Task{ID: "task-4"}, WithToolRetries(2, time.Millisecond))
+
+		convey.Convey("When the retries run out", func() {
+			_, response, err := ExecuteAndProcessToolCall(
+				context.Background(), "down_tool", `{}`, "call-4", params, fakeToolResponseGenerator,
+			)
+
+			convey.Convey("Then the last result should be surfaced to the model", func() {
+				convey.So(calls, convey.ShouldEqual, 3)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(response.(fakeToolResponse).content, convey.ShouldContainSubstring, "unavailable")
+			})
+		})
+	})
+
+	convey.Convey("Given a tool that fails with a non-retriable error", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		calls := 0
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			calls++
+			return "", errors.New("invalid arguments")
+		}
+
+		params := NewProviderParams(&a2a.Task{ID: "task-5"}, WithToolRetries(2, time.Millisecond))
+
+		convey.Convey("When the tool call is executed", func() {
+			_, response, err := ExecuteAndProcessToolCall(
+				context.Background(), "broken_tool", `{}`, "call-5", params, fakeToolResponseGenerator,
+			)
+
+			convey.Convey("Then the error should pass through immediately", func() {
+				convey.So(calls, convey.ShouldEqual, 1)
+				convey.So(err, convey.ShouldNotBeNil)
+				convey.So(response.(fakeToolResponse).isError, convey.ShouldBeTrue)
+			})
+		})
+	})
+}
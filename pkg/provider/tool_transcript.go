@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"time"
+)
+
+/*
+ToolCallRecord is a tool call the provider executed, or tried to, as it
+appears in the transcript of a task.
+*/
+type ToolCallRecord struct {
+	ID        string         `json:"id,omitempty"`
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+	Result    string         `json:"result,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	StartedAt time.Time      `json:"startedAt"`
+	Duration  time.Duration  `json:"duration"`
+}
+
+/*
+recordToolCall appends a call to the transcript, timed from started. A call
+that did not produce a result is recorded with the message the model got in
+its place as the error.
+*/
+func (params *ProviderParams) recordToolCall(
+	toolCallID, toolName, toolArguments string, started time.Time, result, errorMsg string,
+) {
+	params.ToolTranscript = append(params.ToolTranscript, ToolCallRecord{
+		ID:        toolCallID,
+		Name:      toolName,
+		Arguments: parseToolArguments(toolArguments),
+		Result:    result,
+		Error:     errorMsg,
+		StartedAt: started,
+		Duration:  time.Since(started),
+	})
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestToolTranscript(t *testing.T) {
+	convey.Convey("Given an OpenAI provider that calls two tools before answering", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			if name == "fetch" {
+				return "", fmt.Errorf("connection refused")
+			}
+
+			return "4", nil
+		}
+
+		var requests int
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "text/event-stream")
+
+			switch requests {
+			case 1:
+				fmt.Fprint(w, toolCallChunk("call_1", "calculator", `{"expression":"2+2"}`))
+				fmt.Fprint(w, contentChunk("", "tool_calls"))
+			case 2:
+				fmt.Fprint(w, toolCallChunk("call_2", "fetch", `{"url":"https://example.com"}`))
+				fmt.Fprint(w, contentChunk("", "tool_calls"))
+			default:
+				fmt.Fprint(w, contentChunk("The answer is 4.", ""))
+				fmt.Fprint(w, contentChunk("", "stop"))
+			}
+
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "What is 2+2?"))
+
+		convey.Convey("When the task runs", func() {
+			params := NewProviderParams(task, WithToolRetries(0, 0))
+
+			for range prvdr.Generate(context.Background(), params) {
+			}
+
+			convey.Convey("Then both calls should be in the transcript, in order", func() {
+				convey.So(len(params.ToolTranscript), convey.ShouldEqual, 2)
+
+				first := params.ToolTranscript[0]
+				convey.So(first.ID, convey.ShouldEqual, "call_1")
+				convey.So(first.Name, convey.ShouldEqual, "calculator")
+				convey.So(first.Arguments, convey.ShouldResemble, map[string]any{"expression": "2+2"})
+				convey.So(first.Result, convey.ShouldEqual, "4")
+				convey.So(first.Error, convey.ShouldBeEmpty)
+
+				second := params.ToolTranscript[1]
+				convey.So(second.Name, convey.ShouldEqual, "fetch")
+				convey.So(second.Arguments, convey.ShouldResemble, map[string]any{"url": "https://example.com"})
+				convey.So(second.Result, convey.ShouldBeEmpty)
+				convey.So(second.Error, convey.ShouldContainSubstring, "connection refused")
+				convey.So(second.StartedAt.Before(first.StartedAt), convey.ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+/*
+usageChunk renders the trailing chunk OpenAI sends with the token usage of a
+streamed turn when stream_options.include_usage is set.
+*/
+func usageChunk(prompt, completion int) string {
+	chunk := map[string]any{
+		"id":      "chunk",
+		"object":  "chat.completion.chunk",
+		"created": 1,
+		"model":   "gpt-4o-mini",
+		"choices": []map[string]any{},
+		"usage": map[string]any{
+			"prompt_tokens":     prompt,
+			"completion_tokens": completion,
+			"total_tokens":      prompt + completion,
+		},
+	}
+
+	buf, _ := json.Marshal(chunk)
+	return fmt.Sprintf("data: %s\n\n", buf)
+}
+
+func TestTokenUsage(t *testing.T) {
+	convey.Convey("Given an OpenAI provider that calls a tool twice before answering", t, func() {
+		original := executeTool
+		defer func() { executeTool = original }()
+
+		executeTool = func(ctx context.Context, name, args string) (string, error) {
+			return "42", nil
+		}
+
+		var requests []map[string]any
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]any{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, body)
+
+			w.Header().Set("Content-Type", "text/event-stream")
+
+			switch len(requests) {
+			case 1, 2:
+				fmt.Fprint(w, toolCallChunk(fmt.Sprintf("call_%d", len(requests)), "calculator", `{"expression":"6*7"}`))
+				fmt.Fprint(w, contentChunk("", "tool_calls"))
+			default:
+				fmt.Fprint(w, contentChunk("The answer is 42.", ""))
+				fmt.Fprint(w, contentChunk("", "stop"))
+			}
+
+			fmt.Fprint(w, usageChunk(10*len(requests), 5))
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer ts.Close()
+
+		client := openai.NewClient(
+			option.WithBaseURL(ts.URL),
+			option.WithAPIKey("test"),
+			option.WithMaxRetries(0),
+		)
+		prvdr := &OpenAIProvider{client: &client}
+
+		task := a2a.NewTask("test")
+		task.History = append(task.History, *a2a.NewTextMessage("user", "What is 6 * 7, twice?"))
+
+		convey.Convey("When the provider streams the response", func() {
+			for response := range prvdr.Generate(context.Background(), NewProviderParams(task)) {
+				convey.So(response.Error, convey.ShouldBeNil)
+			}
+
+			convey.Convey("Then the usage of every round should be added up on the task", func() {
+				convey.So(requests, convey.ShouldHaveLength, 3)
+				convey.So(task.Usage, convey.ShouldResemble, &a2a.Usage{
+					PromptTokens:     60,
+					CompletionTokens: 15,
+					TotalTokens:      75,
+				})
+			})
+
+			convey.Convey("Then the usage should be asked for on the stream", func() {
+				options := requests[0]["stream_options"].(map[string]any)
+				convey.So(options["include_usage"], convey.ShouldEqual, true)
+			})
+		})
+	})
+}
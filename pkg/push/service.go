@@ -14,6 +14,10 @@ import (
 )
 
 // Service represents a push notification service
+//
+// Deprecated: the A2A server delivers push notifications itself, through the
+// notifier it builds from its push config store and signer. Service is kept
+// for existing callers and is no longer used by this module.
 type Service struct {
 	mu            sync.RWMutex
 	configs       map[string]*a2a.TaskPushNotificationConfig
@@ -21,6 +25,18 @@ type Service struct {
 	retryQueue    chan *notificationRequest
 	maxRetries    int
 	retryInterval time.Duration
+	signer        *Signer
+}
+
+// ServiceOption configures a Service
+type ServiceOption func(*Service)
+
+// WithSigner signs every notification, so receivers can verify them against
+// the signer's JWKS
+func WithSigner(signer *Signer) ServiceOption {
+	return func(s *Service) {
+		s.signer = signer
+	}
 }
 
 // notificationRequest represents a notification to be sent
@@ -32,7 +48,9 @@ type notificationRequest struct {
 }
 
 // NewService creates a new push notification service
-func NewService() *Service {
+//
+// Deprecated: configure push notifications on the A2A server instead.
+func NewService(options ...ServiceOption) *Service {
 	service := &Service{
 		configs:       make(map[string]*a2a.TaskPushNotificationConfig),
 		clients:       make(map[string]*http.Client),
@@ -41,6 +59,10 @@ func NewService() *Service {
 		retryInterval: time.Second * 5,
 	}
 
+	for _, option := range options {
+		option(service)
+	}
+
 	// Start the retry worker
 	go service.retryWorker()
 
@@ -104,6 +126,16 @@ func (s *Service) SendNotification(taskID string, event any) error {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
+	// Sign the body, so the receiver can tell it came from us unchanged
+	if s.signer != nil {
+		signature, err := s.signer.Sign(eventData)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set(SignatureHeader, "Bearer "+signature)
+	}
+
 	req.Header.Set("Content-Type", "application/json")
 	req.Body = io.NopCloser(bytes.NewReader(eventData))
 
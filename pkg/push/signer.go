@@ -0,0 +1,159 @@
+package push
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+)
+
+// SignatureHeader carries the signed JWT of a push notification, which
+// receivers verify against the sender's JWKS with VerifyPushNotification.
+const SignatureHeader = "X-A2A-Notification-Signature"
+
+// JWKSPath is where the server publishes the keys of its Signer.
+const JWKSPath = "/.well-known/jwks.json"
+
+// bodyHashClaim binds a signature to the notification it was made for.
+const bodyHashClaim = "request_body_sha256"
+
+// signatureLifetime bounds how long after sending a notification verifies.
+const signatureLifetime = 5 * time.Minute
+
+// jwk is the public half of an RSA signing key, as published in a JWKS.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// Signer signs push notifications with an RSA private key, and publishes
+// the public key as a JWKS so receivers can verify them.
+type Signer struct {
+	key  *rsa.PrivateKey
+	kid  string
+	jwks []byte
+}
+
+// NewSigner returns a signer for the key. The kid identifies the key in the
+// JWKS and in the header of the signatures, and is derived from the key
+// when empty.
+func NewSigner(key *rsa.PrivateKey, kid string) *Signer {
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+
+	if kid == "" {
+		sum := sha256.Sum256(key.N.Bytes())
+		kid = base64.RawURLEncoding.EncodeToString(sum[:8])
+	}
+
+	jwks, _ := json.Marshal(jwkSet{Keys: []jwk{{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   n,
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+
+	return &Signer{key: key, kid: kid, jwks: jwks}
+}
+
+// LoadSigner reads a PEM encoded RSA private key, in PKCS#1 or PKCS#8 form.
+func LoadSigner(path, kid string) (*Signer, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read push signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return nil, fmt.Errorf("push signing key %s is not PEM encoded", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return NewSigner(key, kid), nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse push signing key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("push signing key %s is not an RSA key", path)
+	}
+
+	return NewSigner(key, kid), nil
+}
+
+// SignerFromConfig loads the key configured under push.signing_key, with
+// the kid under push.kid. It returns nil when no key is configured, in which
+// case notifications go out unsigned.
+func SignerFromConfig() (*Signer, error) {
+	v := viper.GetViper()
+	path := v.GetString("push.signing_key")
+
+	if path == "" {
+		return nil, nil
+	}
+
+	return LoadSigner(path, v.GetString("push.kid"))
+}
+
+// Kid returns the id of the signing key.
+func (signer *Signer) Kid() string {
+	return signer.kid
+}
+
+// Sign returns an RS256 JWT, with the kid in its header, that covers the
+// SHA-256 hash of the notification body and expires after a few minutes.
+func (signer *Signer) Sign(body []byte) (string, error) {
+	sum := sha256.Sum256(body)
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iat":         now.Unix(),
+		"exp":         now.Add(signatureLifetime).Unix(),
+		bodyHashClaim: hex.EncodeToString(sum[:]),
+	})
+	token.Header["kid"] = signer.kid
+
+	signed, err := token.SignedString(signer.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign push notification: %w", err)
+	}
+
+	return signed, nil
+}
+
+// JWKS returns the public key set of the signer.
+func (signer *Signer) JWKS() []byte {
+	return signer.jwks
+}
+
+// JWKSHandler serves the public key set, for receivers to verify with.
+func (signer *Signer) JWKSHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(signer.jwks)
+	}
+}
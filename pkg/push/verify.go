@@ -0,0 +1,100 @@
+package push
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksClient fetches the key sets notifications are verified against.
+var jwksClient = &http.Client{Timeout: 10 * time.Second}
+
+// VerifyPushNotification checks that a push notification was signed by the
+// agent publishing the JWKS at jwksURL, and that its body was not changed on
+// the way. The token is the value of the SignatureHeader of the request,
+// with or without a "Bearer " prefix.
+func VerifyPushNotification(token string, body []byte, jwksURL string) error {
+	keys, err := fetchJWKS(jwksURL)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := jwt.Parse(
+		strings.TrimPrefix(token, "Bearer "),
+		func(token *jwt.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+
+			if key, ok := keys[kid]; ok {
+				return key, nil
+			}
+
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return fmt.Errorf("invalid push notification signature: %w", err)
+	}
+
+	claims, _ := parsed.Claims.(jwt.MapClaims)
+	signed, _ := claims[bodyHashClaim].(string)
+	sum := sha256.Sum256(body)
+
+	if subtle.ConstantTimeCompare([]byte(signed), []byte(hex.EncodeToString(sum[:]))) != 1 {
+		return fmt.Errorf("invalid push notification signature: body does not match")
+	}
+
+	return nil
+}
+
+// fetchJWKS returns the RSA keys of a key set, by kid.
+func fetchJWKS(jwksURL string) (map[string]*rsa.PublicKey, error) {
+	resp, err := jwksClient.Get(jwksURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status code: %d", resp.StatusCode)
+	}
+
+	var set jwkSet
+
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+
+		n, errN := base64.RawURLEncoding.DecodeString(key.N)
+		e, errE := base64.RawURLEncoding.DecodeString(key.E)
+
+		if errN != nil || errE != nil {
+			continue
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
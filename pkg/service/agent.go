@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/gofiber/fiber/v3"
@@ -15,7 +18,9 @@ import (
 	"github.com/theapemachine/a2a-go/pkg/ai"
 	"github.com/theapemachine/a2a-go/pkg/errors"
 	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/push"
 	"github.com/theapemachine/a2a-go/pkg/service/sse"
+	"github.com/theapemachine/a2a-go/pkg/stores"
 )
 
 /*
@@ -23,37 +28,109 @@ A2AServer is safe for concurrent use by default because
 RPCServer & SSEBroker are.
 */
 type A2AServer struct {
-	app    *fiber.App
-	agent  *ai.Agent
-	broker *sse.SSEBroker
+	app        *fiber.App
+	agent      *ai.Agent
+	broker     *sse.SSEBroker
+	sessions   *sse.SSEBroker
+	pushSigner *push.Signer
+	pushStore  stores.PushNotificationStore
+	receipts   stores.PushReceiptStore
+	push       *pushNotifier
+	wsOrigins  []string
+}
+
+type A2AServerOption func(*A2AServer)
+
+/*
+WithPushSigner publishes the keys of the signer push notifications are
+signed with, as a JWKS under /.well-known/jwks.json, so receivers can
+verify them with push.VerifyPushNotification.
+*/
+func WithPushSigner(signer *push.Signer) A2AServerOption {
+	return func(srv *A2AServer) {
+		srv.pushSigner = signer
+	}
+}
+
+/*
+WithWebSocketOrigins lets pages of the given origins, such as
+"https://app.example.com", open a socket on /ws besides pages of the
+server's own origin.
+*/
+func WithWebSocketOrigins(origins ...string) A2AServerOption {
+	return func(srv *A2AServer) {
+		srv.wsOrigins = append(srv.wsOrigins, origins...)
+	}
+}
+
+/*
+WithPushNotificationStore keeps the push notification configs of tasks in
+the store, instead of in memory.
+*/
+func WithPushNotificationStore(store stores.PushNotificationStore) A2AServerOption {
+	return func(srv *A2AServer) {
+		srv.pushStore = store
+	}
+}
+
+/*
+WithPushReceiptStore records the delivery of push notifications in the
+store, instead of in memory.
+*/
+func WithPushReceiptStore(store stores.PushReceiptStore) A2AServerOption {
+	return func(srv *A2AServer) {
+		srv.receipts = store
+	}
 }
 
 /*
 NewA2AServer constructs a server with the supplied Agent.
 */
-func NewAgentServer(agent *ai.Agent) *A2AServer {
-	return &A2AServer{
+func NewAgentServer(agent *ai.Agent, options ...A2AServerOption) *A2AServer {
+	srv := &A2AServer{
 		app: fiber.New(fiber.Config{
 			AppName:           agent.Name(),
 			ServerHeader:      "A2A-Agent-Server",
 			StreamRequestBody: true,
 		}),
-		agent:  agent,
-		broker: sse.NewSSEBroker(),
+		agent:     agent,
+		broker:    sse.NewSSEBroker(),
+		sessions:  sse.NewSSEBroker(sse.WithBufferSize(64)),
+		pushStore: stores.NewInMemoryPushNotificationStore(),
+		receipts:  stores.NewInMemoryPushReceiptStore(defaultPushReceiptLimit),
 	}
+
+	for _, option := range options {
+		option(srv)
+	}
+
+	srv.push = newPushNotifier(srv.pushStore, srv.receipts, srv.pushSigner)
+
+	agent.AddEventSink(srv.publishSessionEvent)
+	agent.AddEventSink(srv.push.notify)
+
+	return srv
 }
 
 func (srv *A2AServer) Start() error {
 	srv.app.Use(logger.New(logger.Config{
 		// Skip logging for the /events endpoint to reduce noise
 		Next: func(c fiber.Ctx) bool {
-			return c.Path() == "/events"
+			return strings.HasSuffix(c.Path(), "/events")
 		},
 	}), healthcheck.New())
 	srv.app.Get("/", srv.handleRoot)
 	srv.app.Get("/.well-known/agent.json", srv.handleAgentCard)
 	srv.app.Get("/events", srv.handleEvents)
+	srv.app.Get("/sessions/:id/events", srv.handleSessionEvents)
+	srv.app.Get("/tasks/:id/events", srv.handleTaskEvents)
 	srv.app.Post("/rpc", srv.handleRPC)
+	srv.app.Get("/ws", fiberadaptor.HTTPHandler(srv.Handlers()))
+
+	if srv.pushSigner != nil {
+		srv.app.Get(push.JWKSPath, fiberadaptor.HTTPHandler(srv.Handlers()))
+	}
+
 	return srv.app.Listen(":3210", fiber.ListenConfig{DisableStartupMessage: true})
 }
 
@@ -76,6 +153,123 @@ func (srv *A2AServer) handleEvents(ctx fiber.Ctx) error {
 	return fiberadaptor.HTTPHandler(http.HandlerFunc(handler))(ctx)
 }
 
+/*
+handleSessionEvents streams the lifecycle events of every task in a session,
+for debugging a live session across its tasks. It requires authentication.
+*/
+func (srv *A2AServer) handleSessionEvents(ctx fiber.Ctx) error {
+	if rpcErr := srv.authenticate(ctx, "session events"); rpcErr != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(rpcErr)
+	}
+
+	id := ctx.Params("id")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		srv.sessions.ServeTask(id, w, r)
+	}
+	return fiberadaptor.HTTPHandler(http.HandlerFunc(handler))(ctx)
+}
+
+/*
+handleTaskEvents streams the events of a task started with
+tasks/sendSubscribe. Any number of clients can follow the same task, each
+receiving the full sequence in order, and a client reconnecting with the
+Last-Event-ID header resumes after the last event it saw.
+*/
+func (srv *A2AServer) handleTaskEvents(ctx fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	lastEventID, err := strconv.Atoi(ctx.Get("Last-Event-ID", "0"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).SendString("invalid Last-Event-ID")
+	}
+
+	if !srv.agent.HasStream(id) {
+		return ctx.Status(fiber.StatusNotFound).JSON(errors.ErrTaskNotFound)
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		events, rpcErr := srv.agent.SubscribeStream(r.Context(), id, lastEventID)
+		if rpcErr != nil {
+			http.Error(w, rpcErr.Message, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+
+		for event := range events {
+			data, err := json.Marshal(event.Response)
+			if err != nil {
+				log.Error("failed to marshal task event", "task_id", id, "error", err)
+				continue
+			}
+
+			_, _ = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.ID, data)
+			flusher.Flush()
+		}
+	}
+	return fiberadaptor.HTTPHandler(http.HandlerFunc(handler))(ctx)
+}
+
+/*
+setPushNotification stores the push notification config a task was sent
+with, if it has one.
+*/
+func (srv *A2AServer) setPushNotification(ctx context.Context, params a2a.TaskSendParams) *errors.RpcError {
+	if params.PushNotification == nil {
+		return nil
+	}
+
+	return srv.pushStore.Set(ctx, a2a.TaskPushNotificationConfig{
+		ID: params.ID, PushNotificationConfig: *params.PushNotification,
+	})
+}
+
+/*
+pushStatus returns the delivery receipts of the push notifications of a
+task. A task that neither has a config nor had notifications delivered
+fails with ErrPushNotificationConfigNotFound.
+*/
+func (srv *A2AServer) pushStatus(ctx context.Context, id string) (a2a.TaskPushNotificationStatus, *errors.RpcError) {
+	receipts, rpcErr := srv.receipts.List(ctx, id)
+	if rpcErr != nil {
+		return a2a.TaskPushNotificationStatus{}, rpcErr
+	}
+
+	if len(receipts) == 0 {
+		if _, rpcErr := srv.pushStore.Get(ctx, id); rpcErr != nil {
+			return a2a.TaskPushNotificationStatus{}, rpcErr
+		}
+	}
+
+	return a2a.TaskPushNotificationStatus{ID: id, Receipts: receipts}, nil
+}
+
+/*
+publishSessionEvent is the event sink that forwards task events to the
+subscribers of the task's session, if there are any.
+*/
+func (srv *A2AServer) publishSessionEvent(event ai.TaskEvent) {
+	if event.SessionID == "" {
+		return
+	}
+
+	if err := srv.sessions.BroadcastToTask(event.SessionID, event); err != nil {
+		log.Error("failed to broadcast session event", "session_id", event.SessionID, "error", err)
+	}
+}
+
 func (srv *A2AServer) parseParamsWithDecoding(params any) ([]byte, error) {
 	var paramsBytes []byte
 	var err error
@@ -103,6 +297,33 @@ func (srv *A2AServer) parseParamsWithDecoding(params any) ([]byte, error) {
 	return paramsBytes, nil
 }
 
+// authenticate checks the credentials of the RPC request, for methods that
+// expose data beyond the regular task lifecycle. The resource names what the
+// caller asked for in the log and the error.
+func (srv *A2AServer) authenticate(ctx fiber.Ctx, resource string) *errors.RpcError {
+	req, err := fiberadaptor.ConvertRequest(ctx, false)
+	if err != nil {
+		return errors.ErrInternal.WithMessagef("failed to read request: %v", err)
+	}
+
+	if err := srv.agent.Authenticate(req); err != nil {
+		log.Warn("unauthenticated request", "resource", resource, "error", err)
+		return errors.ErrInvalidRequest.WithMessagef("%s requires authentication: %v", resource, err)
+	}
+
+	return nil
+}
+
+// requester identifies the authenticated caller of the RPC request, if any.
+func (srv *A2AServer) requester(ctx fiber.Ctx) string {
+	req, err := fiberadaptor.ConvertRequest(ctx, false)
+	if err != nil {
+		return ""
+	}
+
+	return srv.agent.Requester(req)
+}
+
 // parseAndUnmarshalParams handles decoding and unmarshalling of RPC parameters.
 func (srv *A2AServer) parseAndUnmarshalParams(rawParams any, out any) *errors.RpcError {
 	paramsBytes, err := srv.parseParamsWithDecoding(rawParams)
@@ -180,11 +401,16 @@ func forwardTaskStreamAdapter(ctx context.Context, input <-chan a2a.Task) <-chan
 }
 
 /*
-handleRPC acts as the central routing for all a2a RPC methods.
+handleRPC acts as the central routing for all a2a RPC methods. A body that
+holds a JSON array is a batch, answered by handleBatch.
 */
 func (srv *A2AServer) handleRPC(ctx fiber.Ctx) error {
 	ctx.Set("Content-Type", "application/json")
 
+	if body := ctx.Body(); jsonrpc.IsBatch(body) {
+		return srv.handleBatch(ctx, body)
+	}
+
 	var request jsonrpc.Request
 
 	if err := ctx.Bind().Body(&request); err != nil {
@@ -192,7 +418,7 @@ func (srv *A2AServer) handleRPC(ctx fiber.Ctx) error {
 			fiber.StatusBadRequest,
 		).JSON(jsonrpc.Response{ // Send structured error
 			Message: jsonrpc.Message{
-				MessageIdentifier: jsonrpc.MessageIdentifier{ID: nil}, // ID might not be available if body is invalid
+				MessageIdentifier: jsonrpc.MessageIdentifier{}, // ID might not be available if body is invalid
 				JSONRPC:           "2.0",
 			},
 			Error: &jsonrpc.Error{
@@ -202,33 +428,88 @@ func (srv *A2AServer) handleRPC(ctx fiber.Ctx) error {
 		})
 	}
 
+	status, response := srv.dispatch(ctx, request)
+
+	return ctx.Status(status).JSON(response)
+}
+
+/*
+handleBatch answers a batch of requests with an array of the responses to
+the requests that are not notifications. The requests run one after the
+other, as they share the HTTP request they came in. A batch of only
+notifications is answered with an empty body.
+*/
+func (srv *A2AServer) handleBatch(ctx fiber.Ctx, body []byte) error {
+	responses, rpcErr := jsonrpc.ServeBatch(body, func(request jsonrpc.Request) jsonrpc.Response {
+		_, response := srv.dispatch(ctx, request)
+		return response
+	})
+
+	if rpcErr != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(jsonrpc.Response{
+			Message: jsonrpc.Message{JSONRPC: "2.0"},
+			Error:   rpcErr,
+		})
+	}
+
+	if len(responses) == 0 {
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses)
+}
+
+/*
+rpcMethods are the methods /rpc serves, in the order dispatch handles them.
+They are listed by rpc/methods and in the data of a Method not found error,
+so clients can discover what the agent supports.
+*/
+var rpcMethods = []string{
+	"tasks/send",
+	"tasks/sendSubscribe",
+	"tasks/get",
+	"tasks/diff",
+	"tasks/wait",
+	"tasks/cancel",
+	"tasks/resubscribe",
+	"tasks/pushNotification/set",
+	"tasks/pushNotification/get",
+	"tasks/pushNotification/delete",
+	"tasks/pushNotification/status",
+	"rpc/methods",
+}
+
+/*
+dispatch runs a single request and returns its response together with the
+HTTP status it is answered with when it is sent on its own.
+*/
+func (srv *A2AServer) dispatch(ctx fiber.Ctx, request jsonrpc.Request) (int, jsonrpc.Response) {
 	switch request.Method {
 	case "tasks/send":
-		return srv.handleTaskOperation(ctx, request.ID, func() (any, error) {
-			var params a2a.TaskSendParams
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeSendParams(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
 
-			if rpcErr := srv.parseAndUnmarshalParams(request.Params, &params); rpcErr != nil {
+			if rpcErr := srv.setPushNotification(ctx.RequestCtx(), params); rpcErr != nil {
 				return nil, rpcErr
 			}
 
 			return srv.agent.SendTask(ctx.RequestCtx(), params)
 		})
 	case "tasks/sendSubscribe":
-		return srv.handleTaskOperation(ctx, request.ID, func() (any, error) {
-			var params a2a.TaskSendParams
-
-			if rpcErr := srv.parseAndUnmarshalParams(request.Params, &params); rpcErr != nil {
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeSendParams(request.Params)
+			if rpcErr != nil {
 				return nil, rpcErr
 			}
 
-			// Convert send parameters into a task for streaming
-			task := a2a.NewTask(srv.agent.Name())
-			task.ID = params.ID
-			if params.SessionID != "" {
-				task.SessionID = params.SessionID
+			if rpcErr := srv.setPushNotification(ctx.RequestCtx(), params); rpcErr != nil {
+				return nil, rpcErr
 			}
-			task.History = append(task.History, params.Message)
-			task.Metadata = params.Metadata
+
+			task := srv.newStreamTask(params)
 
 			stream, rpcErr := srv.agent.StreamTask(ctx.RequestCtx(), task)
 			if rpcErr != nil {
@@ -253,39 +534,108 @@ func (srv *A2AServer) handleRPC(ctx fiber.Ctx) error {
 			return firstResultPayload, nil // Return the payload of the first stream message
 		})
 	case "tasks/get":
-		return srv.handleTaskOperation(ctx, request.ID, func() (any, error) {
-			var params a2a.TaskQueryParams
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeQueryParams(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			if params.IncludeDebug {
+				if rpcErr := srv.authenticate(ctx, "debug output"); rpcErr != nil {
+					return nil, rpcErr
+				}
+			}
+
+			task, rpcErr := srv.agent.GetTask(ctx.RequestCtx(), params.ID, *params.HistoryLength)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			if !params.IncludeDebug {
+				task.Debug = nil
+			}
+
+			return task, nil
+		})
+	case "tasks/diff":
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeDiffParams(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			return srv.agent.DiffTask(ctx.RequestCtx(), params.ID, params.Revision)
+		})
+	case "tasks/wait":
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, timeout, rpcErr := srv.decodeWaitParams(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
 
-			if rpcErr := srv.parseAndUnmarshalParams(request.Params, &params); rpcErr != nil {
+			if params.IncludeDebug {
+				if rpcErr := srv.authenticate(ctx, "debug output"); rpcErr != nil {
+					return nil, rpcErr
+				}
+			}
+
+			task, rpcErr := srv.agent.Wait(ctx.RequestCtx(), params.ID, timeout)
+			if rpcErr != nil {
 				return nil, rpcErr
 			}
 
-			return srv.agent.GetTask(ctx.RequestCtx(), params.ID, *params.HistoryLength)
+			if length := params.HistoryLength; length != nil && *length > 0 && len(task.History) > *length {
+				task.History = task.History[len(task.History)-*length:]
+			}
+
+			if !params.IncludeDebug {
+				task.Debug = nil
+			}
+
+			return task, nil
 		})
 	case "tasks/cancel":
-		return srv.handleTaskOperation(ctx, request.ID, func() (any, error) {
-			var params a2a.TaskIDParams
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeCancelParams(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
 
-			if rpcErr := srv.parseAndUnmarshalParams(request.Params, &params); rpcErr != nil {
+			event, rpcErr := srv.agent.CancelTaskWithReason(
+				ctx.RequestCtx(), params.ID, params.Reason, srv.requester(ctx),
+			)
+			if rpcErr != nil {
 				return nil, rpcErr
 			}
-			// CancelTask specifically returns nil result on success, and an error on failure.
+
+			if err := srv.broker.Broadcast(event); err != nil {
+				log.Error("failed to broadcast canceled event", "task_id", params.ID, "error", err)
+			}
+
+			// Cancel returns a nil result on success, and an error on failure.
 			// The handleTaskOperation will correctly wrap this in a JSON-RPC response.
-			return nil, srv.agent.CancelTask(ctx.RequestCtx(), params.ID)
+			return nil, nil
 		})
 	case "tasks/resubscribe":
-		return srv.handleTaskOperation(ctx, request.ID, func() (any, error) {
-			var params a2a.TaskQueryParams
-
-			if rpcErr := srv.parseAndUnmarshalParams(request.Params, &params); rpcErr != nil {
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeQueryParams(request.Params)
+			if rpcErr != nil {
 				return nil, rpcErr
 			}
 
-			hl := 0
-			if params.HistoryLength != nil {
-				hl = *params.HistoryLength
+			// A task that is still streaming already reaches the broker once;
+			// forwarding it again would deliver every event twice. Clients
+			// follow it on /tasks/{id}/events instead.
+			if srv.agent.HasStream(params.ID) {
+				task, rpcErr := srv.agent.GetTask(ctx.RequestCtx(), params.ID, *params.HistoryLength)
+				if rpcErr != nil {
+					return nil, rpcErr
+				}
+
+				return task, nil
 			}
-			stream, rpcErr := srv.agent.ResubscribeTask(ctx.RequestCtx(), params.ID, hl)
+
+			stream, rpcErr := srv.agent.ResubscribeTask(ctx.RequestCtx(), params.ID, *params.HistoryLength)
 			if rpcErr != nil {
 				return nil, rpcErr
 			}
@@ -302,8 +652,61 @@ func (srv *A2AServer) handleRPC(ctx fiber.Ctx) error {
 
 			return first, nil
 		})
+	case "tasks/pushNotification/set":
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodePushNotificationConfig(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			if rpcErr := srv.pushStore.Set(ctx.RequestCtx(), params); rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			return params, nil
+		})
+	case "tasks/pushNotification/get":
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeTaskIDParams(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			config, rpcErr := srv.pushStore.Get(ctx.RequestCtx(), params.ID)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			return config, nil
+		})
+	case "tasks/pushNotification/delete":
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeTaskIDParams(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			if rpcErr := srv.pushStore.Delete(ctx.RequestCtx(), params.ID); rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			return nil, nil
+		})
+	case "tasks/pushNotification/status":
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			params, rpcErr := srv.decodeTaskIDParams(request.Params)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			return srv.pushStatus(ctx.RequestCtx(), params.ID)
+		})
+	case "rpc/methods":
+		return srv.handleTaskOperation(request.ID, func() (any, error) {
+			return map[string]any{"methods": rpcMethods}, nil
+		})
 	default:
-		return ctx.Status(fiber.StatusBadRequest).JSON(jsonrpc.Response{
+		return fiber.StatusBadRequest, jsonrpc.Response{
 			Message: jsonrpc.Message{
 				MessageIdentifier: jsonrpc.MessageIdentifier{ID: request.ID},
 				JSONRPC:           "2.0",
@@ -311,12 +714,37 @@ func (srv *A2AServer) handleRPC(ctx fiber.Ctx) error {
 			Error: &jsonrpc.Error{
 				Code:    errors.ErrMethodNotFound.Code,
 				Message: errors.ErrMethodNotFound.Message + ": " + request.Method,
+				Data:    map[string]any{"methods": rpcMethods},
 			},
-		})
+		}
+	}
+}
+
+/*
+newStreamTask converts the params of tasks/sendSubscribe into the task to
+stream.
+*/
+func (srv *A2AServer) newStreamTask(params a2a.TaskSendParams) *a2a.Task {
+	task := a2a.NewTask(srv.agent.Name())
+	task.ID = params.ID
+	if params.SessionID != "" {
+		task.SessionID = params.SessionID
+	}
+	task.History = append(task.History, params.Message)
+	task.Metadata = params.Metadata
+
+	if params.DryRun {
+		task.Metadata = a2a.MergeMetadata(task.Metadata, map[string]any{"dryRun": true})
+	}
+
+	if params.Model != "" {
+		task.Metadata = a2a.MergeMetadata(task.Metadata, map[string]any{"model": params.Model})
 	}
+
+	return task
 }
 
-func (srv *A2AServer) handleTaskOperation(ctx fiber.Ctx, requestID any, op func() (any, error)) error {
+func (srv *A2AServer) handleTaskOperation(requestID jsonrpc.ID, op func() (any, error)) (int, jsonrpc.Response) {
 	result, errOp := op()
 
 	// First, explicitly check if errOp is an interface holding (*errors.RpcError)(nil).
@@ -343,7 +771,7 @@ func (srv *A2AServer) handleTaskOperation(ctx fiber.Ctx, requestID any, op func(
 			respErrorMessage = e.Message // Prefer the direct message for clarity in JSON response
 		}
 
-		return ctx.Status(fiber.StatusInternalServerError).JSON(jsonrpc.Response{
+		return fiber.StatusInternalServerError, jsonrpc.Response{
 			Message: jsonrpc.Message{
 				MessageIdentifier: jsonrpc.MessageIdentifier{ID: requestID},
 				JSONRPC:           "2.0",
@@ -352,28 +780,28 @@ func (srv *A2AServer) handleTaskOperation(ctx fiber.Ctx, requestID any, op func(
 				Code:    respErrorCode,
 				Message: respErrorMessage,
 			},
-		})
+		}
 	}
 
 	// Success cases (errOp is now guaranteed to be plain nil here)
 	// If result is nil (and errOp was nil), return JSON-RPC null result
 	// This handles cases like successful task cancellation that might return (nil, nil) from the op.
 	if result == nil {
-		return ctx.Status(fiber.StatusOK).JSON(jsonrpc.Response{
+		return fiber.StatusOK, jsonrpc.Response{
 			Message: jsonrpc.Message{
 				MessageIdentifier: jsonrpc.MessageIdentifier{ID: requestID},
 				JSONRPC:           "2.0",
 			},
 			Result: nil, // Explicit null result
-		})
+		}
 	}
 
 	// Success with a non-nil result
-	return ctx.Status(fiber.StatusOK).JSON(jsonrpc.Response{
+	return fiber.StatusOK, jsonrpc.Response{
 		Message: jsonrpc.Message{
 			MessageIdentifier: jsonrpc.MessageIdentifier{ID: requestID},
 			JSONRPC:           "2.0",
 		},
 		Result: result,
-	})
+	}
 }
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/service/sse"
+)
+
+func TestTaskEventFiltering(t *testing.T) {
+	Convey("Given an agent server with subscribers of one task and of every task", t, func() {
+		srv := newTestServer(t, &artifactProvider{texts: []string{"hello", "world"}})
+
+		taskA, unsubscribeA := srv.broker.SubscribeTask("task-a")
+		defer unsubscribeA()
+
+		all, unsubscribeAll := srv.broker.SubscribeTask(sse.AllTasks)
+		defer unsubscribeAll()
+
+		// collect reads the task ids of the responses broadcast to a subscriber
+		// until the stream goes quiet.
+		collect := func(ch <-chan []byte) chan []string {
+			out := make(chan []string, 1)
+
+			go func() {
+				var ids []string
+
+				for {
+					select {
+					case msg, ok := <-ch:
+						if !ok {
+							out <- ids
+							return
+						}
+
+						_, data, _ := strings.Cut(string(msg), "\n{")
+
+						var event struct {
+							Result struct {
+								ID string `json:"id"`
+							} `json:"result"`
+						}
+
+						if err := json.Unmarshal([]byte("{"+data), &event); err == nil {
+							ids = append(ids, event.Result.ID)
+						}
+					case <-time.After(500 * time.Millisecond):
+						out <- ids
+						return
+					}
+				}
+			}()
+
+			return out
+		}
+
+		Convey("When two tasks are streamed with tasks/sendSubscribe", func() {
+			idsA, idsAll := collect(taskA), collect(all)
+
+			for _, id := range []string{"task-a", "task-b"} {
+				body := `{"jsonrpc":"2.0","id":1,"method":"tasks/sendSubscribe","params":{
+					"id":"` + id + `",
+					"message":{"role":"user","parts":[{"type":"text","text":"hi"}]}
+				}}`
+				req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				res, err := srv.app.Test(req)
+				So(err, ShouldBeNil)
+				res.Body.Close()
+			}
+
+			Convey("Then the task subscriber should only receive the events of its task", func() {
+				ids := <-idsA
+				So(ids, ShouldNotBeEmpty)
+
+				for _, id := range ids {
+					So(id, ShouldEqual, "task-a")
+				}
+
+				ids = <-idsAll
+				So(ids, ShouldContain, "task-a")
+				So(ids, ShouldContain, "task-b")
+			})
+		})
+	})
+}
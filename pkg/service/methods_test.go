@@ -0,0 +1,66 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+func TestRPCMethods(t *testing.T) {
+	Convey("Given an agent server", t, func() {
+		srv := newTestServer(t)
+
+		call := func(method string) jsonrpc.Response {
+			body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":{}}`
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			res, err := srv.app.Test(req)
+			So(err, ShouldBeNil)
+			defer res.Body.Close()
+
+			var response jsonrpc.Response
+			So(json.NewDecoder(res.Body).Decode(&response), ShouldBeNil)
+			return response
+		}
+
+		Convey("When an unknown method is called", func() {
+			response := call("tasks/unknown")
+
+			Convey("Then it should fail with Method not found, listing the supported methods", func() {
+				So(response.Error, ShouldNotBeNil)
+				So(response.Error.Code, ShouldEqual, errors.ErrMethodNotFound.Code)
+				So(response.Error.Message, ShouldContainSubstring, "tasks/unknown")
+
+				data, ok := response.Error.Data.(map[string]any)
+				So(ok, ShouldBeTrue)
+				So(data["methods"], ShouldContain, "tasks/send")
+				So(data["methods"], ShouldContain, "tasks/get")
+				So(data["methods"], ShouldContain, "tasks/cancel")
+				So(data["methods"], ShouldContain, "rpc/methods")
+			})
+		})
+
+		Convey("When rpc/methods is called", func() {
+			response := call("rpc/methods")
+
+			Convey("Then it should list every supported method", func() {
+				So(response.Error, ShouldBeNil)
+
+				result, ok := response.Result.(map[string]any)
+				So(ok, ShouldBeTrue)
+				So(len(result["methods"].([]any)), ShouldEqual, len(rpcMethods))
+
+				for _, method := range rpcMethods {
+					So(result["methods"], ShouldContain, method)
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+// invalidParam reports a missing or invalid field of the RPC params.
+func invalidParam(field, format string, args ...any) *errors.RpcError {
+	return errors.ErrInvalidParams.WithMessagef("invalid params: %s %s", field, fmt.Sprintf(format, args...))
+}
+
+// decodeSendParams decodes and validates the params of tasks/send and
+// tasks/sendSubscribe.
+func (srv *A2AServer) decodeSendParams(raw any) (a2a.TaskSendParams, *errors.RpcError) {
+	var params a2a.TaskSendParams
+
+	if rpcErr := srv.parseAndUnmarshalParams(raw, &params); rpcErr != nil {
+		return params, rpcErr
+	}
+
+	if params.ID == "" {
+		return params, invalidParam("id", "is required")
+	}
+
+	// An approval decision may come as metadata alone, without a message.
+	if _, approval := params.Metadata["approved"].(bool); !approval || len(params.Message.Parts) > 0 {
+		if rpcErr := validateMessage(params.Message); rpcErr != nil {
+			return params, rpcErr
+		}
+	}
+
+	if params.HistoryLength != nil && *params.HistoryLength < 0 {
+		return params, invalidParam("historyLength", "must not be negative, got %d", *params.HistoryLength)
+	}
+
+	if params.PushNotification != nil && params.PushNotification.URL == "" {
+		return params, invalidParam("pushNotification.url", "is required")
+	}
+
+	return params, nil
+}
+
+// validateMessage checks the message of a send request.
+func validateMessage(message a2a.Message) *errors.RpcError {
+	if message.Role == "" {
+		return invalidParam("message.role", "is required")
+	}
+
+	if len(message.Parts) == 0 {
+		return invalidParam("message.parts", "must contain at least one part")
+	}
+
+	for i, part := range message.Parts {
+		switch part.Type {
+		case a2a.PartTypeText, a2a.PartTypeFile, a2a.PartTypeData:
+		default:
+			return invalidParam(
+				fmt.Sprintf("message.parts[%d].type", i),
+				"must be text, file or data, got %q", part.Type,
+			)
+		}
+	}
+
+	return nil
+}
+
+// decodeQueryParams decodes and validates the params of tasks/get and
+// tasks/resubscribe. A missing historyLength defaults to zero.
+func (srv *A2AServer) decodeQueryParams(raw any) (a2a.TaskQueryParams, *errors.RpcError) {
+	var params a2a.TaskQueryParams
+
+	if rpcErr := srv.parseAndUnmarshalParams(raw, &params); rpcErr != nil {
+		return params, rpcErr
+	}
+
+	if params.ID == "" {
+		return params, invalidParam("id", "is required")
+	}
+
+	if params.HistoryLength == nil {
+		params.HistoryLength = new(int)
+	}
+
+	if *params.HistoryLength < 0 {
+		return params, invalidParam("historyLength", "must not be negative, got %d", *params.HistoryLength)
+	}
+
+	return params, nil
+}
+
+// defaultWaitTimeout bounds a tasks/wait request that does not set its own
+// timeout.
+const defaultWaitTimeout = 30 * time.Second
+
+// decodeWaitParams decodes and validates the params of tasks/wait and
+// returns how long to wait.
+func (srv *A2AServer) decodeWaitParams(raw any) (a2a.TaskWaitParams, time.Duration, *errors.RpcError) {
+	var params a2a.TaskWaitParams
+
+	if rpcErr := srv.parseAndUnmarshalParams(raw, &params); rpcErr != nil {
+		return params, 0, rpcErr
+	}
+
+	if params.ID == "" {
+		return params, 0, invalidParam("id", "is required")
+	}
+
+	if params.HistoryLength != nil && *params.HistoryLength < 0 {
+		return params, 0, invalidParam("historyLength", "must not be negative, got %d", *params.HistoryLength)
+	}
+
+	if params.Timeout < 0 {
+		return params, 0, invalidParam("timeout", "must not be negative, got %d", params.Timeout)
+	}
+
+	if params.Timeout == 0 {
+		return params, defaultWaitTimeout, nil
+	}
+
+	return params, time.Duration(params.Timeout) * time.Second, nil
+}
+
+// decodeCancelParams decodes and validates the params of tasks/cancel.
+func (srv *A2AServer) decodeCancelParams(raw any) (a2a.TaskCancelParams, *errors.RpcError) {
+	var params a2a.TaskCancelParams
+
+	if rpcErr := srv.parseAndUnmarshalParams(raw, &params); rpcErr != nil {
+		return params, rpcErr
+	}
+
+	if params.ID == "" {
+		return params, invalidParam("id", "is required")
+	}
+
+	return params, nil
+}
+
+// decodePushNotificationConfig decodes and validates the params of
+// tasks/pushNotification/set.
+func (srv *A2AServer) decodePushNotificationConfig(raw any) (a2a.TaskPushNotificationConfig, *errors.RpcError) {
+	var params a2a.TaskPushNotificationConfig
+
+	if rpcErr := srv.parseAndUnmarshalParams(raw, &params); rpcErr != nil {
+		return params, rpcErr
+	}
+
+	if params.ID == "" {
+		return params, invalidParam("id", "is required")
+	}
+
+	if params.PushNotificationConfig.URL == "" {
+		return params, invalidParam("pushNotificationConfig.url", "is required")
+	}
+
+	return params, nil
+}
+
+// decodeTaskIDParams decodes and validates params that only name a task,
+// such as those of tasks/pushNotification/get.
+func (srv *A2AServer) decodeTaskIDParams(raw any) (a2a.TaskIDParams, *errors.RpcError) {
+	var params a2a.TaskIDParams
+
+	if rpcErr := srv.parseAndUnmarshalParams(raw, &params); rpcErr != nil {
+		return params, rpcErr
+	}
+
+	if params.ID == "" {
+		return params, invalidParam("id", "is required")
+	}
+
+	return params, nil
+}
+
+// decodeDiffParams decodes and validates the params of tasks/diff.
+func (srv *A2AServer) decodeDiffParams(raw any) (a2a.TaskDiffParams, *errors.RpcError) {
+	var params a2a.TaskDiffParams
+
+	if rpcErr := srv.parseAndUnmarshalParams(raw, &params); rpcErr != nil {
+		return params, rpcErr
+	}
+
+	if params.ID == "" {
+		return params, invalidParam("id", "is required")
+	}
+
+	if params.Revision < 0 {
+		return params, invalidParam("revision", "must not be negative, got %d", params.Revision)
+	}
+
+	return params, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/ai"
+	"github.com/theapemachine/a2a-go/pkg/catalog"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+type stubTaskStore struct{}
+
+func (store *stubTaskStore) Get(ctx context.Context, id string, historyLength int) ([]a2a.Task, *errors.RpcError) {
+	return nil, errors.ErrTaskNotFound
+}
+func (store *stubTaskStore) Subscribe(ctx context.Context, id string, tasks chan a2a.Task) *errors.RpcError {
+	return nil
+}
+func (store *stubTaskStore) Create(ctx context.Context, task *a2a.Task, optionals ...string) *errors.RpcError {
+	return nil
+}
+func (store *stubTaskStore) Update(ctx context.Context, task *a2a.Task, optionals ...string) *errors.RpcError {
+	return nil
+}
+func (store *stubTaskStore) Delete(ctx context.Context, id string) *errors.RpcError { return nil }
+func (store *stubTaskStore) Cancel(ctx context.Context, id string) *errors.RpcError { return nil }
+
+type stubProvider struct{}
+
+func (prvdr *stubProvider) Generate(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+	ch := make(chan jsonrpc.Response)
+	close(ch)
+	return ch
+}
+
+func (prvdr *stubProvider) Capabilities() provider.ProviderCapabilities {
+	return provider.ProviderCapabilities{Streaming: true}
+}
+
+/*
+newTestServer returns a server whose agent registers with a stub catalog,
+generating with the given provider or, without one, the stub provider.
+*/
+func newTestServer(t *testing.T, prvdrs ...provider.Interface) *A2AServer {
+	var prvdr provider.Interface = &stubProvider{}
+
+	if len(prvdrs) > 0 {
+		prvdr = prvdrs[0]
+	}
+
+	catalogServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(catalogServer.Close)
+
+	card := &a2a.AgentCard{Name: "TestAgent"}
+
+	manager, err := ai.NewTaskManager(card, ai.WithTaskStore(&stubTaskStore{}), ai.WithProvider(prvdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agent, err := ai.NewAgentFromCard(
+		card, ai.WithTaskManager(manager), ai.WithCatalogClient(catalog.NewCatalogClient(catalogServer.URL)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewAgentServer(agent)
+	srv.app.Post("/rpc", srv.handleRPC)
+
+	return srv
+}
+
+func TestParamsValidation(t *testing.T) {
+	Convey("Given an agent server", t, func() {
+		srv := newTestServer(t)
+
+		call := func(method, params string) jsonrpc.Response {
+			body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":` + params + `}`
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			res, err := srv.app.Test(req)
+			So(err, ShouldBeNil)
+			defer res.Body.Close()
+
+			var response jsonrpc.Response
+			So(json.NewDecoder(res.Body).Decode(&response), ShouldBeNil)
+			So(response.Error, ShouldNotBeNil)
+			So(response.Error.Code, ShouldEqual, errors.ErrInvalidParams.Code)
+
+			return response
+		}
+
+		for _, method := range []string{"tasks/send", "tasks/sendSubscribe"} {
+			Convey("When "+method+" has no id", func() {
+				response := call(method, `{"message":{"role":"user","parts":[{"type":"text","text":"hi"}]}}`)
+				So(response.Error.Message, ShouldContainSubstring, "id is required")
+			})
+
+			Convey("When "+method+" has an empty message", func() {
+				response := call(method, `{"id":"t1","message":{"role":"user","parts":[]}}`)
+				So(response.Error.Message, ShouldContainSubstring, "message.parts must contain at least one part")
+			})
+
+			Convey("When "+method+" has a part of an unknown type", func() {
+				response := call(method, `{"id":"t1","message":{"role":"user","parts":[{"type":"video"}]}}`)
+				So(response.Error.Message, ShouldContainSubstring, "message.parts[0].type")
+			})
+
+			Convey("When "+method+" has a negative historyLength", func() {
+				response := call(method, `{"id":"t1","historyLength":-1,"message":{"role":"user","parts":[{"type":"text","text":"hi"}]}}`)
+				So(response.Error.Message, ShouldContainSubstring, "historyLength must not be negative")
+			})
+		}
+
+		for _, method := range []string{"tasks/get", "tasks/resubscribe"} {
+			Convey("When "+method+" has no id", func() {
+				response := call(method, `{"historyLength":2}`)
+				So(response.Error.Message, ShouldContainSubstring, "id is required")
+			})
+
+			Convey("When "+method+" has a negative historyLength", func() {
+				response := call(method, `{"id":"t1","historyLength":-5}`)
+				So(response.Error.Message, ShouldContainSubstring, "historyLength must not be negative, got -5")
+			})
+		}
+
+		Convey("When tasks/cancel has no id", func() {
+			response := call("tasks/cancel", `{"reason":"no longer needed"}`)
+			So(response.Error.Message, ShouldContainSubstring, "id is required")
+		})
+
+		Convey("When the params are not an object", func() {
+			response := call("tasks/get", `[1,2,3]`)
+			So(response.Error.Message, ShouldContainSubstring, "failed to unmarshal params")
+		})
+	})
+}
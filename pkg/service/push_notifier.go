@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/ai"
+	"github.com/theapemachine/a2a-go/pkg/push"
+	"github.com/theapemachine/a2a-go/pkg/stores"
+)
+
+const (
+	// pushQueueSize bounds the notifications waiting for delivery per task.
+	pushQueueSize = 32
+	// pushMaxAttempts bounds how often a notification is sent before it is
+	// dropped.
+	pushMaxAttempts = 4
+	// pushBaseDelay is the wait before the first retry, doubled after each.
+	pushBaseDelay = 500 * time.Millisecond
+	// defaultPushReceiptLimit bounds the delivery receipts kept per task.
+	defaultPushReceiptLimit = 100
+)
+
+/*
+pushNotifier delivers the status transitions of tasks to the webhook their
+push notification config points at. Every task has its own queue and worker,
+so notifications reach a webhook in the order the task went through them,
+while a slow or failing webhook only holds up its own task. Delivery never
+blocks the task itself: when the queue of a task is full, the notification
+is dropped.
+*/
+type pushNotifier struct {
+	mu          sync.Mutex
+	store       stores.PushNotificationStore
+	receipts    stores.PushReceiptStore
+	queues      map[string]chan a2a.TaskStatusUpdateEvent
+	client      *http.Client
+	signer      *push.Signer
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+func newPushNotifier(
+	store stores.PushNotificationStore, receipts stores.PushReceiptStore, signer *push.Signer,
+) *pushNotifier {
+	return &pushNotifier{
+		store:       store,
+		receipts:    receipts,
+		queues:      make(map[string]chan a2a.TaskStatusUpdateEvent),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		signer:      signer,
+		maxAttempts: pushMaxAttempts,
+		baseDelay:   pushBaseDelay,
+	}
+}
+
+/*
+notify is the event sink that queues a TaskStatusUpdateEvent for every
+status transition of a task with a push notification config. The queue of
+a task is closed after its final status, which ends its worker.
+*/
+func (notifier *pushNotifier) notify(event ai.TaskEvent) {
+	if event.Kind != ai.TaskEventStatus && event.Kind != ai.TaskEventTerminal {
+		return
+	}
+
+	final := event.Kind == ai.TaskEventTerminal
+	_, rpcErr := notifier.store.Get(context.Background(), event.TaskID)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+
+	queue, ok := notifier.queues[event.TaskID]
+
+	// Without a config there is nothing to deliver, but the worker of a task
+	// whose config was deleted while it ran still has to end.
+	if rpcErr != nil {
+		if ok && final {
+			delete(notifier.queues, event.TaskID)
+			close(queue)
+		}
+
+		return
+	}
+
+	if !ok {
+		queue = make(chan a2a.TaskStatusUpdateEvent, pushQueueSize)
+		notifier.queues[event.TaskID] = queue
+
+		go notifier.work(queue)
+	}
+
+	select {
+	case queue <- a2a.TaskStatusUpdateEvent{
+		ID: event.TaskID,
+		Status: a2a.TaskStatus{
+			State:     event.State,
+			Message:   event.Message,
+			Timestamp: event.Timestamp,
+		},
+		Final: final,
+	}:
+	default:
+		log.Warn("push notification queue full, dropping notification", "task_id", event.TaskID, "state", event.State)
+	}
+
+	if final {
+		delete(notifier.queues, event.TaskID)
+		close(queue)
+	}
+}
+
+/*
+work delivers the notifications of a single task, one after the other. The
+config is read again for every notification, so a config that is replaced
+or deleted while the task runs takes effect from the next one.
+*/
+func (notifier *pushNotifier) work(queue <-chan a2a.TaskStatusUpdateEvent) {
+	for update := range queue {
+		stored, rpcErr := notifier.store.Get(context.Background(), update.ID)
+		if rpcErr != nil {
+			continue
+		}
+
+		config := stored.PushNotificationConfig
+
+		if err := notifier.deliver(config, update); err != nil {
+			log.Error("failed to deliver push notification", "task_id", update.ID, "url", config.URL, "error", err)
+		}
+	}
+}
+
+/*
+deliver posts a notification to the webhook, retrying with exponential
+backoff while the webhook cannot be reached or answers with a status other
+than 2xx, until maxAttempts is reached. Every attempt is recorded in the
+receipt of the notification, which is stored once delivery ends.
+*/
+func (notifier *pushNotifier) deliver(config a2a.PushNotificationConfig, update a2a.TaskStatusUpdateEvent) error {
+	receipt := a2a.PushNotificationReceipt{State: update.Status.State, Final: update.Final}
+
+	defer func() {
+		if rpcErr := notifier.receipts.Record(context.Background(), update.ID, receipt); rpcErr != nil {
+			log.Error("failed to record push notification receipt", "task_id", update.ID, "error", rpcErr)
+		}
+	}()
+
+	body, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		statusCode, err := notifier.post(config, body)
+
+		record := a2a.PushNotificationAttempt{Timestamp: time.Now().UTC(), StatusCode: statusCode}
+
+		if err != nil {
+			record.Error = err.Error()
+		}
+
+		receipt.Attempts = append(receipt.Attempts, record)
+
+		if err == nil {
+			receipt.Delivered = true
+			return nil
+		}
+
+		if attempt+1 >= notifier.maxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", notifier.maxAttempts, err)
+		}
+
+		log.Warn("push notification failed, retrying", "task_id", update.ID, "attempt", attempt+1, "error", err)
+		time.Sleep(notifier.baseDelay << attempt)
+	}
+}
+
+/*
+post sends a notification once, with the credentials of the config and,
+with a signer, the signature of the body. It returns the status code the
+webhook answered with, zero when it did not answer.
+*/
+func (notifier *pushNotifier) post(config a2a.PushNotificationConfig, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, config.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if auth := config.Authentication; auth != nil && auth.Credentials != nil {
+		for _, scheme := range auth.Schemes {
+			if scheme == "Bearer" {
+				req.Header.Set("Authorization", "Bearer "+*auth.Credentials)
+			}
+		}
+	}
+
+	if config.Token != nil {
+		req.Header.Set("X-Task-Token", *config.Token)
+	}
+
+	if notifier.signer != nil {
+		signature, err := notifier.signer.Sign(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to sign notification: %w", err)
+		}
+
+		req.Header.Set(push.SignatureHeader, "Bearer "+signature)
+	}
+
+	resp, err := notifier.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+func TestPushNotifier(t *testing.T) {
+	Convey("Given an agent server and a webhook that fails the first delivery", t, func() {
+		var attempts atomic.Int32
+
+		type delivery struct {
+			auth  string
+			event a2a.TaskStatusUpdateEvent
+		}
+
+		deliveries := make(chan delivery, 8)
+
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			var event a2a.TaskStatusUpdateEvent
+			_ = json.NewDecoder(r.Body).Decode(&event)
+
+			deliveries <- delivery{auth: r.Header.Get("Authorization"), event: event}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer webhook.Close()
+
+		srv := newTestServer(t, &artifactProvider{texts: []string{"done"}})
+		srv.push.baseDelay = time.Millisecond
+
+		Convey("When a task with a push notification config is sent", func() {
+			body := `{"jsonrpc":"2.0","id":1,"method":"tasks/send","params":{
+				"id":"task-push",
+				"message":{"role":"user","parts":[{"type":"text","text":"hi"}]},
+				"pushNotification":{"url":"` + webhook.URL + `","authentication":{"schemes":["Bearer"],"credentials":"secret"}}
+			}}`
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			res, err := srv.app.Test(req)
+			So(err, ShouldBeNil)
+			res.Body.Close()
+
+			Convey("Then the webhook should receive the completed state, with the configured credentials", func() {
+				var final delivery
+
+			wait:
+				for {
+					select {
+					case received := <-deliveries:
+						if received.event.Final {
+							final = received
+							break wait
+						}
+					case <-time.After(5 * time.Second):
+						t.Fatal("timed out waiting for the completed notification")
+					}
+				}
+
+				So(final.event.ID, ShouldEqual, "task-push")
+				So(final.event.Status.State, ShouldEqual, a2a.TaskStateCompleted)
+				So(final.auth, ShouldEqual, "Bearer secret")
+				So(attempts.Load(), ShouldBeGreaterThan, 1)
+			})
+		})
+	})
+}
+
+func TestPushNotificationRPC(t *testing.T) {
+	Convey("Given an agent server", t, func() {
+		srv := newTestServer(t)
+
+		call := func(method, params string) jsonrpc.Response {
+			body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":` + params + `}`
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			res, err := srv.app.Test(req)
+			So(err, ShouldBeNil)
+			defer res.Body.Close()
+
+			var response jsonrpc.Response
+			So(json.NewDecoder(res.Body).Decode(&response), ShouldBeNil)
+			return response
+		}
+
+		Convey("When a config is set, read back and deleted", func() {
+			set := call("tasks/pushNotification/set",
+				`{"id":"task-1","pushNotificationConfig":{"url":"https://example.com/webhook"}}`)
+			get := call("tasks/pushNotification/get", `{"id":"task-1"}`)
+			deleted := call("tasks/pushNotification/delete", `{"id":"task-1"}`)
+			gone := call("tasks/pushNotification/get", `{"id":"task-1"}`)
+
+			Convey("Then each step should see the result of the one before", func() {
+				So(set.Error, ShouldBeNil)
+
+				So(get.Error, ShouldBeNil)
+				config := get.Result.(map[string]any)["pushNotificationConfig"].(map[string]any)
+				So(config["url"], ShouldEqual, "https://example.com/webhook")
+
+				So(deleted.Error, ShouldBeNil)
+
+				So(gone.Error, ShouldNotBeNil)
+				So(gone.Error.Code, ShouldEqual, errors.ErrPushNotificationConfigNotFound.Code)
+			})
+		})
+
+		Convey("When the config of an unknown task is read or deleted", func() {
+			get := call("tasks/pushNotification/get", `{"id":"unknown"}`)
+			deleted := call("tasks/pushNotification/delete", `{"id":"unknown"}`)
+
+			Convey("Then both should fail with Push notification config not found", func() {
+				So(get.Error, ShouldNotBeNil)
+				So(get.Error.Code, ShouldEqual, errors.ErrPushNotificationConfigNotFound.Code)
+				So(get.Error.Message, ShouldContainSubstring, "unknown")
+
+				So(deleted.Error, ShouldNotBeNil)
+				So(deleted.Error.Code, ShouldEqual, errors.ErrPushNotificationConfigNotFound.Code)
+			})
+		})
+	})
+}
+
+func TestPushNotificationStatus(t *testing.T) {
+	Convey("Given an agent server and a webhook that fails the first delivery", t, func() {
+		var attempts atomic.Int32
+
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer webhook.Close()
+
+		srv := newTestServer(t, &artifactProvider{texts: []string{"done"}})
+		srv.push.baseDelay = time.Millisecond
+
+		call := func(method, params string) jsonrpc.Response {
+			body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":` + params + `}`
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			res, err := srv.app.Test(req)
+			So(err, ShouldBeNil)
+			defer res.Body.Close()
+
+			var response jsonrpc.Response
+			So(json.NewDecoder(res.Body).Decode(&response), ShouldBeNil)
+			return response
+		}
+
+		Convey("When a task with a push notification config has run", func() {
+			call("tasks/send", `{
+				"id":"task-receipts",
+				"message":{"role":"user","parts":[{"type":"text","text":"hi"}]},
+				"pushNotification":{"url":"`+webhook.URL+`"}
+			}`)
+
+			var status a2a.TaskPushNotificationStatus
+
+			deadline := time.Now().Add(5 * time.Second)
+
+			for {
+				response := call("tasks/pushNotification/status", `{"id":"task-receipts"}`)
+				So(response.Error, ShouldBeNil)
+
+				raw, err := json.Marshal(response.Result)
+				So(err, ShouldBeNil)
+				So(json.Unmarshal(raw, &status), ShouldBeNil)
+
+				if n := len(status.Receipts); n > 0 && status.Receipts[n-1].Final {
+					break
+				}
+
+				if time.Now().After(deadline) {
+					t.Fatal("timed out waiting for the final receipt")
+				}
+
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			Convey("Then the first notification should record the failed attempt before the delivered one", func() {
+				first := status.Receipts[0]
+
+				So(first.Delivered, ShouldBeTrue)
+				So(first.Attempts, ShouldHaveLength, 2)
+				So(first.Attempts[0].StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+				So(first.Attempts[0].Error, ShouldNotBeEmpty)
+				So(first.Attempts[1].StatusCode, ShouldEqual, http.StatusOK)
+				So(first.Attempts[1].Error, ShouldBeEmpty)
+			})
+
+			Convey("Then the final notification should be delivered with the completed state", func() {
+				last := status.Receipts[len(status.Receipts)-1]
+
+				So(last.State, ShouldEqual, a2a.TaskStateCompleted)
+				So(last.Delivered, ShouldBeTrue)
+			})
+		})
+
+		Convey("When the status of a task without push notifications is asked for", func() {
+			response := call("tasks/pushNotification/status", `{"id":"unknown"}`)
+
+			Convey("Then it should fail with Push notification config not found", func() {
+				So(response.Error, ShouldNotBeNil)
+				So(response.Error.Code, ShouldEqual, errors.ErrPushNotificationConfigNotFound.Code)
+			})
+		})
+	})
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/push"
+)
+
+func TestPushNotificationSignature(t *testing.T) {
+	Convey("Given an agent server publishing the key its push notifications are signed with", t, func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		So(err, ShouldBeNil)
+
+		signer := push.NewSigner(key, "push-key-1")
+
+		srv := newTestServer(t)
+		WithPushSigner(signer)(srv)
+
+		agentServer := httptest.NewServer(srv.Handlers())
+		defer agentServer.Close()
+
+		jwksURL := agentServer.URL + push.JWKSPath
+
+		var (
+			signature string
+			body      []byte
+		)
+
+		received := make(chan struct{}, 1)
+
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature = r.Header.Get(push.SignatureHeader)
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+			received <- struct{}{}
+		}))
+		defer receiver.Close()
+
+		notifier := newPushNotifier(srv.pushStore, srv.receipts, signer)
+
+		So(notifier.deliver(a2a.PushNotificationConfig{URL: receiver.URL}, a2a.TaskStatusUpdateEvent{
+			ID:     "task-1",
+			Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+			Final:  true,
+		}), ShouldBeNil)
+		<-received
+
+		Convey("Then the notification should carry a signature", func() {
+			So(signature, ShouldStartWith, "Bearer ")
+		})
+
+		Convey("Then the receiver should verify it against the JWKS", func() {
+			So(push.VerifyPushNotification(signature, body, jwksURL), ShouldBeNil)
+		})
+
+		Convey("Then a tampered body should fail verification", func() {
+			tampered := []byte(string(body[:len(body)-1]) + `,"injected":true}`)
+
+			err := push.VerifyPushNotification(signature, tampered, jwksURL)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "body does not match")
+		})
+
+		Convey("Then a signature made with another key should fail verification", func() {
+			other, err := rsa.GenerateKey(rand.Reader, 2048)
+			So(err, ShouldBeNil)
+
+			forged, err := push.NewSigner(other, "push-key-1").Sign(body)
+			So(err, ShouldBeNil)
+
+			So(push.VerifyPushNotification(forged, body, jwksURL), ShouldNotBeNil)
+		})
+	})
+}
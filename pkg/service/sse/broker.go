@@ -2,44 +2,197 @@ package sse
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+/*
+AllTasks is the task ID of subscribers that receive the events of every
+task, which is what a subscription without a task ID gets.
+*/
+const AllTasks = "*"
+
+/*
+OverflowPolicy decides what happens when a subscriber's buffer is full
+because the client reads slower than events are broadcast.
+*/
+type OverflowPolicy string
+
+const (
+	// OverflowBlock applies backpressure to the broadcaster until the block
+	// timeout expires, after which the slow client is disconnected.
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropOldest discards the oldest buffered event to make room.
+	OverflowDropOldest OverflowPolicy = "dropOldest"
+	// OverflowDropNewest discards the event being broadcast.
+	OverflowDropNewest OverflowPolicy = "dropNewest"
 )
 
+/*
+subscriber is a single connected client with its own bounded buffer, the
+task it follows and a count of the events it lost to the overflow policy.
+*/
+type subscriber struct {
+	ch       chan []byte
+	taskID   string
+	dropped  atomic.Uint64
+	reported uint64
+}
+
+/*
+wants reports whether the subscriber follows the task an event belongs to.
+Events that belong to no task reach every subscriber.
+*/
+func (sub *subscriber) wants(taskID string) bool {
+	return taskID == "" || sub.taskID == AllTasks || sub.taskID == taskID
+}
+
+/*
+replayEntry is a broadcast event kept for clients that reconnect.
+*/
+type replayEntry struct {
+	id  uint64
+	msg []byte
+}
+
 /*
 SSEBroker maintains a list of subscribers and broadcasts JSON‑encoded events
 to them.  Each event is sent as a single‑line SSE message of the form:
 
-data: {json}\n\n
+id: {n}\nevent: {type}\ndata: {json}\n\n
+
+The most recent events of every task are kept in a bounded ring, so a client
+that reconnects with a Last-Event-ID header gets the events it missed.
 */
 type SSEBroker struct {
-	mu          sync.RWMutex
-	clients     map[chan []byte]struct{}
-	taskBrokers map[string]*SSEBroker // Map of task-specific brokers
-	closed      bool
-	testMode    bool
+	mu           sync.RWMutex
+	clients      map[chan []byte]*subscriber
+	taskBrokers  map[string]*SSEBroker // Map of task-specific brokers
+	taskClients  map[string]int        // Clients served by ServeTask, per task broker
+	closed       bool
+	testMode     bool
+	bufferSize   int
+	policy       OverflowPolicy
+	blockTimeout time.Duration
+	heartbeat    time.Duration
+	replaySize   int
+	replayGrace  time.Duration
+	lastID       uint64
+	replay       map[string][]replayEntry // Recent events per task, "" for events of no task
 }
 
+type SSEBrokerOption func(*SSEBroker)
+
 /*
 NewSSEBroker creates a new SSEBroker.
 */
-func NewSSEBroker() *SSEBroker {
-	return &SSEBroker{
-		clients:     make(map[chan []byte]struct{}),
-		taskBrokers: make(map[string]*SSEBroker),
+func NewSSEBroker(options ...SSEBrokerOption) *SSEBroker {
+	broker := &SSEBroker{
+		clients:      make(map[chan []byte]*subscriber),
+		taskBrokers:  make(map[string]*SSEBroker),
+		taskClients:  make(map[string]int),
+		bufferSize:   8,
+		policy:       OverflowDropNewest,
+		blockTimeout: time.Second,
+		heartbeat:    15 * time.Second,
+		replaySize:   64,
+		replayGrace:  30 * time.Second,
+		replay:       make(map[string][]replayEntry),
 	}
+
+	for _, option := range options {
+		option(broker)
+	}
+
+	return broker
 }
 
 /*
-NewTestSSEBroker creates a broker with a shorter ticker interval for testing
+NewTestSSEBroker creates a broker with a shorter heartbeat interval for
+testing, which the options may still override.
 */
-func NewTestSSEBroker() *SSEBroker {
-	return &SSEBroker{
-		clients:     make(map[chan []byte]struct{}),
-		taskBrokers: make(map[string]*SSEBroker),
-		testMode:    true,
+func NewTestSSEBroker(options ...SSEBrokerOption) *SSEBroker {
+	broker := NewSSEBroker(append([]SSEBrokerOption{WithHeartbeat(100 * time.Millisecond)}, options...)...)
+	broker.testMode = true
+	return broker
+}
+
+/*
+WithBufferSize sets how many events are buffered per subscriber.
+*/
+func WithBufferSize(size int) SSEBrokerOption {
+	return func(broker *SSEBroker) {
+		if size > 0 {
+			broker.bufferSize = size
+		}
+	}
+}
+
+/*
+WithOverflowPolicy sets what happens when a subscriber's buffer is full.
+*/
+func WithOverflowPolicy(policy OverflowPolicy) SSEBrokerOption {
+	return func(broker *SSEBroker) {
+		broker.policy = policy
+	}
+}
+
+/*
+WithBlockTimeout sets how long a broadcast may block on slow clients under
+the block policy before those clients are disconnected.
+*/
+func WithBlockTimeout(timeout time.Duration) SSEBrokerOption {
+	return func(broker *SSEBroker) {
+		broker.blockTimeout = timeout
+	}
+}
+
+/*
+WithHeartbeat sets how long a stream may be idle before a comment line is
+written to it, which keeps proxies from closing quiet connections. Zero
+disables the heartbeat.
+*/
+func WithHeartbeat(interval time.Duration) SSEBrokerOption {
+	return func(broker *SSEBroker) {
+		if interval >= 0 {
+			broker.heartbeat = interval
+		}
+	}
+}
+
+/*
+WithReplaySize sets how many recent events are kept per task for clients
+that reconnect with a Last-Event-ID header. Zero disables the replay.
+*/
+func WithReplaySize(size int) SSEBrokerOption {
+	return func(broker *SSEBroker) {
+		if size >= 0 {
+			broker.replaySize = size
+		}
+	}
+}
+
+/*
+WithReplayGrace sets how long the events of a task stay available for replay
+after its final event, so a client that dropped just before the end can still
+catch up. Zero forgets them as soon as the final event is sent.
+*/
+func WithReplayGrace(grace time.Duration) SSEBrokerOption {
+	return func(broker *SSEBroker) {
+		if grace >= 0 {
+			broker.replayGrace = grace
+		}
 	}
 }
 
@@ -55,19 +208,74 @@ func (broker *SSEBroker) GetOrCreateTaskBroker(taskID string) any {
 		return nil
 	}
 
+	return broker.taskBroker(taskID)
+}
+
+/*
+taskBroker returns the broker of a task, creating it if it doesn't exist.
+The caller holds the lock of the broker.
+*/
+func (broker *SSEBroker) taskBroker(taskID string) *SSEBroker {
 	if taskBroker, exists := broker.taskBrokers[taskID]; exists {
 		return taskBroker
 	}
 
 	// Create a new broker for this task
 	taskBroker := &SSEBroker{
-		clients:  make(map[chan []byte]struct{}),
-		testMode: broker.testMode,
+		clients:      make(map[chan []byte]*subscriber),
+		testMode:     broker.testMode,
+		bufferSize:   broker.bufferSize,
+		policy:       broker.policy,
+		blockTimeout: broker.blockTimeout,
+		heartbeat:    broker.heartbeat,
+		replaySize:   broker.replaySize,
+		replayGrace:  broker.replayGrace,
+		replay:       make(map[string][]replayEntry),
 	}
 	broker.taskBrokers[taskID] = taskBroker
 	return taskBroker
 }
 
+/*
+ServeTask streams the events broadcast with BroadcastToTask to an HTTP
+client, as Subscribe does, and blocks until the client disconnects. The task
+broker is created for the first client and closed once the last one leaves,
+so brokers of tasks nobody follows anymore do not pile up.
+*/
+func (broker *SSEBroker) ServeTask(taskID string, w http.ResponseWriter, r *http.Request) {
+	broker.mu.Lock()
+
+	if broker.closed {
+		broker.mu.Unlock()
+		http.Error(w, "broker closed", http.StatusGone)
+		return
+	}
+
+	taskBroker := broker.taskBroker(taskID)
+	broker.taskClients[taskID]++
+	broker.mu.Unlock()
+
+	defer func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+
+		broker.taskClients[taskID]--
+
+		if broker.taskClients[taskID] > 0 {
+			return
+		}
+
+		delete(broker.taskClients, taskID)
+
+		if broker.taskBrokers[taskID] == taskBroker {
+			taskBroker.Close()
+			delete(broker.taskBrokers, taskID)
+		}
+	}()
+
+	taskBroker.Subscribe(w, r)
+}
+
 /*
 BroadcastToTask sends a message to all clients subscribed to a specific task.
 */
@@ -96,11 +304,31 @@ func (broker *SSEBroker) CloseTaskBroker(taskID string) {
 	}
 }
 
+/*
+SubscribeTask registers a subscriber that only receives the status and
+artifact updates of the given task, along with events that belong to no
+task. AllTasks subscribes to every task. The returned function unsubscribes
+and closes the channel, which is nil when the broker is closed.
+*/
+func (broker *SSEBroker) SubscribeTask(taskID string) (<-chan []byte, func()) {
+	sub := broker.add(taskID)
+
+	if sub == nil {
+		return nil, func() {}
+	}
+
+	return sub.ch, func() { broker.remove(sub.ch) }
+}
+
 /*
 Subscribe upgrades the HTTP connection to an SSE stream and blocks until the
 client disconnects.  Use from an HTTP handler:
 
 broker.Subscribe(w, r)
+
+A taskId query parameter or path value limits the stream to the events of
+that task, as SubscribeTask does. A client reconnecting with a Last-Event-ID
+header first gets the buffered events it missed, then the live stream.
 */
 func (broker *SSEBroker) Subscribe(w http.ResponseWriter, r *http.Request) {
 	flusher, ok := w.(http.Flusher)
@@ -122,36 +350,39 @@ func (broker *SSEBroker) Subscribe(w http.ResponseWriter, r *http.Request) {
 		header.Set("Connection", "keep-alive")
 	}
 
-	// Create channel for this client
-	ch := make(chan []byte, 8)
-	broker.mu.Lock()
+	// Create a buffered subscriber for this client
+	sub, missed := broker.resume(requestTaskID(r), lastEventID(r))
 
-	if broker.closed {
-		broker.mu.Unlock()
+	if sub == nil {
 		http.Error(w, "broker closed", http.StatusGone)
 		return
 	}
 
-	broker.clients[ch] = struct{}{}
-	broker.mu.Unlock()
+	ch := sub.ch
 
 	// Ensure channel is always cleaned up
 	defer broker.remove(ch)
 
 	// Write initial comment to establish SSE connection
 	_, _ = w.Write([]byte(": SSE connection established\n\n"))
+
+	for _, msg := range missed {
+		writeMessage(w, msg)
+	}
+
 	flusher.Flush()
 
-	// heartbeat ticker to keep connection alive in the presence of proxies.
-	tickerInterval := 25 * time.Second
+	// heartbeat ticker to keep connection alive in the presence of proxies,
+	// reset by every event so it only fires on an idle stream.
+	var ticker *time.Ticker
+	var heartbeat <-chan time.Time
 
-	if broker.testMode {
-		tickerInterval = 100 * time.Millisecond
+	if broker.heartbeat > 0 {
+		ticker = time.NewTicker(broker.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
 	}
 
-	ticker := time.NewTicker(tickerInterval)
-	defer ticker.Stop()
-
 	for {
 		select {
 		case <-r.Context().Done():
@@ -162,41 +393,52 @@ func (broker *SSEBroker) Subscribe(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			// Handle messages that already have event prefixes
-			if bytes.HasPrefix(msg, []byte("event:")) {
-				// This is a message with event type already set
-				parts := bytes.SplitN(msg, []byte("\n"), 2)
-				if len(parts) == 2 {
-					// Write the event line
-					_, _ = w.Write(parts[0])
-					_, _ = w.Write([]byte("\n"))
-					// Write the data line with "data: " prefix
-					_, _ = w.Write([]byte("data: "))
-					_, _ = w.Write(parts[1])
-					_, _ = w.Write([]byte("\n\n"))
-				} else {
-					// Malformed message, just write it with data: prefix
-					_, _ = w.Write([]byte("data: "))
-					_, _ = w.Write(msg)
-					_, _ = w.Write([]byte("\n\n"))
-				}
-			} else {
-				// Standard message with just data
-				_, _ = w.Write([]byte("data: "))
-				_, _ = w.Write(msg)
-				_, _ = w.Write([]byte("\n\n"))
+			// Let the client know how many events it missed since the last report
+			if dropped := sub.dropped.Load(); dropped > sub.reported {
+				_, _ = fmt.Fprintf(w, "event: dropped\ndata: {\"dropped\":%d}\n\n", dropped-sub.reported)
+				sub.reported = dropped
 			}
 
+			writeMessage(w, msg)
+
 			// Flush after every message
 			flusher.Flush()
-		case <-ticker.C:
+
+			if ticker != nil {
+				ticker.Reset(broker.heartbeat)
+			}
+		case <-heartbeat:
 			// comment heartbeat
-			_, _ = w.Write([]byte(": heartbeat\n\n"))
+			_, _ = w.Write([]byte(":\n\n"))
 			flusher.Flush()
 		}
 	}
 }
 
+/*
+writeMessage writes a broadcast message as an SSE event. The id and event
+lines the message starts with are written as they are, the rest becomes the
+data line.
+*/
+func writeMessage(w http.ResponseWriter, msg []byte) {
+	for bytes.HasPrefix(msg, []byte("id:")) || bytes.HasPrefix(msg, []byte("event:")) {
+		line, rest, found := bytes.Cut(msg, []byte("\n"))
+
+		if !found {
+			// Malformed message, just write it with data: prefix
+			break
+		}
+
+		_, _ = w.Write(line)
+		_, _ = w.Write([]byte("\n"))
+		msg = rest
+	}
+
+	_, _ = w.Write([]byte("data: "))
+	_, _ = w.Write(msg)
+	_, _ = w.Write([]byte("\n\n"))
+}
+
 /*
 Broadcast marshals v to JSON and sends it to all connected clients.
 */
@@ -224,20 +466,7 @@ func (broker *SSEBroker) Broadcast(v any) error {
 		return err
 	}
 
-	broker.mu.RLock()
-	defer broker.mu.RUnlock()
-
-	if broker.closed {
-		return nil
-	}
-
-	for ch := range broker.clients {
-		select {
-		case ch <- append([]byte("event: "+eventType+"\n"), msg...):
-		default:
-			// slow client – drop message to avoid blocking.
-		}
-	}
+	broker.send(append([]byte("event: "+eventType+"\n"), msg...), eventTaskID(v), eventFinal(v))
 
 	return nil
 }
@@ -249,22 +478,282 @@ func (broker *SSEBroker) BroadcastWithEventType(eventType string, v any) error {
 		return err
 	}
 
-	broker.mu.RLock()
-	defer broker.mu.RUnlock()
+	broker.send(append([]byte("event: "+eventType+"\n"), msg...), eventTaskID(v), eventFinal(v))
+
+	return nil
+}
+
+/*
+eventTaskID returns the task an event belongs to, or an empty string for an
+event of no task. The server broadcasts the responses of a streamed task as
+they come, so a jsonrpc.Response belongs to the task of its result.
+*/
+func eventTaskID(v any) string {
+	switch event := v.(type) {
+	case jsonrpc.Response:
+		return eventTaskID(event.Result)
+	case *jsonrpc.Response:
+		return eventTaskID(event.Result)
+	case a2a.TaskStatusUpdateEvent:
+		return event.ID
+	case *a2a.TaskStatusUpdateEvent:
+		return event.ID
+	case a2a.TaskArtifactUpdateEvent:
+		return event.ID
+	case *a2a.TaskArtifactUpdateEvent:
+		return event.ID
+	case a2a.TaskStatusUpdateResult:
+		return event.ID
+	case *a2a.TaskStatusUpdateResult:
+		return event.ID
+	case a2a.ArtifactResult:
+		return event.ID
+	case *a2a.ArtifactResult:
+		return event.ID
+	case a2a.Task:
+		return event.ID
+	case *a2a.Task:
+		return event.ID
+	}
+
+	return ""
+}
+
+/*
+eventFinal reports whether an event is the final status update of its task,
+after which the task sends no more events.
+*/
+func eventFinal(v any) bool {
+	switch event := v.(type) {
+	case jsonrpc.Response:
+		return eventFinal(event.Result)
+	case *jsonrpc.Response:
+		return eventFinal(event.Result)
+	case a2a.TaskStatusUpdateEvent:
+		return event.Final
+	case *a2a.TaskStatusUpdateEvent:
+		return event.Final
+	case a2a.TaskStatusUpdateResult:
+		return event.Final
+	case *a2a.TaskStatusUpdateResult:
+		return event.Final
+	}
+
+	return false
+}
+
+/*
+requestTaskID returns the task a request subscribes to, from its taskId query
+parameter or path value, or AllTasks when it names none.
+*/
+func requestTaskID(r *http.Request) string {
+	if taskID := r.URL.Query().Get("taskId"); taskID != "" {
+		return taskID
+	}
+
+	if taskID := r.PathValue("taskId"); taskID != "" {
+		return taskID
+	}
+
+	return AllTasks
+}
+
+/*
+lastEventID returns the id of the last event a reconnecting client received,
+from its Last-Event-ID header, or zero for a new client.
+*/
+func lastEventID(r *http.Request) uint64 {
+	id, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
+
+/*
+send numbers msg, keeps it for replay and delivers it to every subscriber of
+the task according to the overflow policy.
+Broadcasts are serialized, so every client sees the events in the order of
+their ids. Under the block policy all subscribers share a single deadline,
+so one broadcast never blocks longer than the block timeout, and the clients
+that could not keep up are disconnected afterwards.
+The final event of a task lets go of its replay ring after the replay grace.
+*/
+func (broker *SSEBroker) send(msg []byte, taskID string, final bool) {
+	var slow []chan []byte
+
+	broker.mu.Lock()
 
 	if broker.closed {
-		return nil
+		broker.mu.Unlock()
+		return
 	}
 
-	for ch := range broker.clients {
+	broker.lastID++
+	msg = append([]byte("id: "+strconv.FormatUint(broker.lastID, 10)+"\n"), msg...)
+	broker.record(taskID, msg)
+
+	if final && taskID != "" {
+		broker.forget(taskID, broker.lastID)
+	}
+
+	// A timer channel fires only once, so the deadline is a context, which
+	// stays done for every slow subscriber after the first.
+	var deadline <-chan struct{}
+
+	if broker.policy == OverflowBlock {
+		ctx, cancel := context.WithTimeout(context.Background(), broker.blockTimeout)
+		defer cancel()
+		deadline = ctx.Done()
+	}
+
+	for ch, sub := range broker.clients {
+		if !sub.wants(taskID) {
+			continue
+		}
+
 		select {
-		case ch <- append([]byte("event: "+eventType+"\n"), msg...):
+		case ch <- msg:
+			continue
 		default:
-			// slow client – drop message to avoid blocking.
+		}
+
+		switch broker.policy {
+		case OverflowBlock:
+			select {
+			case ch <- msg:
+			case <-deadline:
+				slow = append(slow, ch)
+			}
+		case OverflowDropOldest:
+			select {
+			case <-ch:
+			default:
+			}
+
+			select {
+			case ch <- msg:
+			default:
+			}
+
+			sub.dropped.Add(1)
+		default:
+			sub.dropped.Add(1)
 		}
 	}
 
-	return nil
+	broker.mu.Unlock()
+
+	for _, ch := range slow {
+		broker.remove(ch)
+	}
+}
+
+/*
+record keeps msg in the replay ring of its task, dropping the oldest event
+once the ring is full. The caller must hold the write lock.
+*/
+func (broker *SSEBroker) record(taskID string, msg []byte) {
+	if broker.replaySize == 0 {
+		return
+	}
+
+	ring := append(broker.replay[taskID], replayEntry{id: broker.lastID, msg: msg})
+
+	if len(ring) > broker.replaySize {
+		ring = slices.Clone(ring[len(ring)-broker.replaySize:])
+	}
+
+	broker.replay[taskID] = ring
+}
+
+/*
+forget drops the replay ring of a finished task once the replay grace has
+passed, unless the task broadcast again after the event with the given id.
+Without it the rings of every task ever streamed would stay in memory.
+The caller must hold the write lock.
+*/
+func (broker *SSEBroker) forget(taskID string, id uint64) {
+	drop := func() {
+		if ring := broker.replay[taskID]; len(ring) > 0 && ring[len(ring)-1].id == id {
+			delete(broker.replay, taskID)
+		}
+	}
+
+	if broker.replayGrace == 0 {
+		drop()
+		return
+	}
+
+	time.AfterFunc(broker.replayGrace, func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+
+		drop()
+	})
+}
+
+/*
+add registers a new subscriber of the task, or returns nil when the broker
+is closed.
+*/
+func (broker *SSEBroker) add(taskID string) *subscriber {
+	sub, _ := broker.resume(taskID, 0)
+	return sub
+}
+
+/*
+resume registers a new subscriber of the task together with the buffered
+events it wants that came after the given id, oldest first. Both happen under
+the lock broadcasts take, so no event is missed or delivered twice between
+the replay and the live stream. A zero id replays nothing.
+*/
+func (broker *SSEBroker) resume(taskID string, after uint64) (*subscriber, [][]byte) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	if broker.closed {
+		return nil, nil
+	}
+
+	if taskID == "" {
+		taskID = AllTasks
+	}
+
+	sub := &subscriber{ch: make(chan []byte, broker.bufferSize), taskID: taskID}
+	broker.clients[sub.ch] = sub
+
+	if after == 0 {
+		return sub, nil
+	}
+
+	var missed []replayEntry
+
+	for ringTaskID, ring := range broker.replay {
+		if !sub.wants(ringTaskID) {
+			continue
+		}
+
+		for _, entry := range ring {
+			if entry.id > after {
+				missed = append(missed, entry)
+			}
+		}
+	}
+
+	slices.SortFunc(missed, func(a, b replayEntry) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	msgs := make([][]byte, 0, len(missed))
+
+	for _, entry := range missed {
+		msgs = append(msgs, entry.msg)
+	}
+
+	return sub, msgs
 }
 
 /*
@@ -284,7 +773,7 @@ func (broker *SSEBroker) Close() {
 		close(ch)
 	}
 
-	broker.clients = map[chan []byte]struct{}{}
+	broker.clients = map[chan []byte]*subscriber{}
 }
 
 /*
@@ -2,6 +2,7 @@ package sse
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -134,3 +135,480 @@ func newTestServerSSE(h http.Handler) (*httptest.Server, error) {
 	}()
 	return srv, err
 }
+
+// eventData strips the id and event lines off a broadcast message.
+func eventData(msg []byte) string {
+	data := string(msg)
+	for strings.HasPrefix(data, "id:") || strings.HasPrefix(data, "event:") {
+		_, data, _ = strings.Cut(data, "\n")
+	}
+	return data
+}
+
+func TestSSEBrokerOverflowPolicies(t *testing.T) {
+	drain := func(ch chan []byte) []string {
+		out := []string{}
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return out
+				}
+				out = append(out, eventData(msg))
+			default:
+				return out
+			}
+		}
+	}
+
+	t.Run("dropNewest keeps the oldest events and counts drops", func(t *testing.T) {
+		broker := NewTestSSEBroker(WithBufferSize(2), WithOverflowPolicy(OverflowDropNewest))
+		sub := broker.add(AllTasks)
+
+		for i := 1; i <= 5; i++ {
+			if err := broker.Broadcast(i); err != nil {
+				t.Fatalf("broadcast: %v", err)
+			}
+		}
+
+		if got := drain(sub.ch); strings.Join(got, ",") != "1,2" {
+			t.Fatalf("expected events 1,2, got %v", got)
+		}
+
+		if dropped := sub.dropped.Load(); dropped != 3 {
+			t.Fatalf("expected 3 dropped events, got %d", dropped)
+		}
+	})
+
+	t.Run("dropOldest keeps the newest events and counts drops", func(t *testing.T) {
+		broker := NewTestSSEBroker(WithBufferSize(2), WithOverflowPolicy(OverflowDropOldest))
+		sub := broker.add(AllTasks)
+
+		for i := 1; i <= 5; i++ {
+			if err := broker.Broadcast(i); err != nil {
+				t.Fatalf("broadcast: %v", err)
+			}
+		}
+
+		if got := drain(sub.ch); strings.Join(got, ",") != "4,5" {
+			t.Fatalf("expected events 4,5, got %v", got)
+		}
+
+		if dropped := sub.dropped.Load(); dropped != 3 {
+			t.Fatalf("expected 3 dropped events, got %d", dropped)
+		}
+	})
+
+	t.Run("block disconnects the slow client after the timeout", func(t *testing.T) {
+		broker := NewTestSSEBroker(
+			WithBufferSize(1),
+			WithOverflowPolicy(OverflowBlock),
+			WithBlockTimeout(50*time.Millisecond),
+		)
+		sub := broker.add(AllTasks)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			for i := 1; i <= 3; i++ {
+				_ = broker.Broadcast(i)
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("broadcaster blocked indefinitely")
+		}
+
+		broker.mu.RLock()
+		_, connected := broker.clients[sub.ch]
+		broker.mu.RUnlock()
+
+		if connected {
+			t.Fatal("expected the slow client to be disconnected")
+		}
+
+		if got := drain(sub.ch); strings.Join(got, ",") != "1" {
+			t.Fatalf("expected only the buffered event 1, got %v", got)
+		}
+	})
+
+	t.Run("block disconnects every slow client after the timeout", func(t *testing.T) {
+		broker := NewTestSSEBroker(
+			WithBufferSize(1),
+			WithOverflowPolicy(OverflowBlock),
+			WithBlockTimeout(50*time.Millisecond),
+		)
+		first := broker.add(AllTasks)
+		second := broker.add(AllTasks)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			for i := 1; i <= 3; i++ {
+				_ = broker.Broadcast(i)
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("broadcaster blocked indefinitely on the second slow client")
+		}
+
+		broker.mu.RLock()
+		clients := len(broker.clients)
+		broker.mu.RUnlock()
+
+		if clients != 0 {
+			t.Fatalf("expected both slow clients to be disconnected, %d left", clients)
+		}
+
+		for _, sub := range []*subscriber{first, second} {
+			if got := drain(sub.ch); strings.Join(got, ",") != "1" {
+				t.Fatalf("expected only the buffered event 1, got %v", got)
+			}
+		}
+
+		if sub := broker.add(AllTasks); sub == nil {
+			t.Fatal("expected the broker to accept new subscribers")
+		}
+
+		broker.Close()
+	})
+
+	t.Run("block waits for a client that catches up", func(t *testing.T) {
+		broker := NewTestSSEBroker(
+			WithBufferSize(1),
+			WithOverflowPolicy(OverflowBlock),
+			WithBlockTimeout(time.Second),
+		)
+		sub := broker.add(AllTasks)
+		received := make(chan string, 3)
+
+		go func() {
+			for msg := range sub.ch {
+				time.Sleep(10 * time.Millisecond)
+				received <- eventData(msg)
+			}
+		}()
+
+		for i := 1; i <= 3; i++ {
+			if err := broker.Broadcast(i); err != nil {
+				t.Fatalf("broadcast: %v", err)
+			}
+		}
+
+		got := []string{<-received, <-received, <-received}
+		if strings.Join(got, ",") != "1,2,3" {
+			t.Fatalf("expected every event to be delivered, got %v", got)
+		}
+
+		broker.Close()
+	})
+}
+
+func TestSSEBrokerSubscribeTask(t *testing.T) {
+	broker := NewTestSSEBroker()
+	defer broker.Close()
+
+	taskA, unsubscribeA := broker.SubscribeTask("task-a")
+	defer unsubscribeA()
+
+	taskB, unsubscribeB := broker.SubscribeTask("task-b")
+	defer unsubscribeB()
+
+	all, unsubscribeAll := broker.SubscribeTask(AllTasks)
+	defer unsubscribeAll()
+
+	events := []any{
+		a2a.TaskStatusUpdateEvent{ID: "task-a", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}},
+		&a2a.TaskArtifactUpdateEvent{ID: "task-b", Artifact: a2a.Artifact{Index: 1}},
+		a2a.TaskStatusUpdateEvent{ID: "task-a", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}, Final: true},
+	}
+
+	for _, event := range events {
+		if err := broker.Broadcast(event); err != nil {
+			t.Fatalf("broadcast: %v", err)
+		}
+	}
+
+	ids := func(ch <-chan []byte) []string {
+		out := []string{}
+		for {
+			select {
+			case msg := <-ch:
+				var event struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal([]byte(eventData(msg)), &event); err != nil {
+					t.Fatalf("unmarshal: %v", err)
+				}
+				out = append(out, event.ID)
+			default:
+				return out
+			}
+		}
+	}
+
+	if got := ids(taskA); strings.Join(got, ",") != "task-a,task-a" {
+		t.Fatalf("expected only the events of task-a, got %v", got)
+	}
+
+	if got := ids(taskB); strings.Join(got, ",") != "task-b" {
+		t.Fatalf("expected only the events of task-b, got %v", got)
+	}
+
+	if got := ids(all); strings.Join(got, ",") != "task-a,task-b,task-a" {
+		t.Fatalf("expected the wildcard subscriber to receive every event, got %v", got)
+	}
+}
+
+func TestSSEBrokerLastEventIDReplay(t *testing.T) {
+	broker := NewTestSSEBroker(WithReplaySize(8))
+	defer broker.Close()
+
+	ts, errTS := newTestServerSSE(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		broker.Subscribe(w, r)
+	}))
+	if errTS != nil {
+		t.Skip("network disabled; skipping SSE test")
+	}
+	defer ts.Close()
+
+	for i := 1; i <= 3; i++ {
+		if err := broker.Broadcast(i); err != nil {
+			t.Fatalf("broadcast: %v", err)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Last-Event-ID", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("client get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	var ids, data []string
+
+	for len(data) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read error: %v", err)
+		}
+
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "id:"):
+			ids = append(ids, strings.TrimSpace(strings.TrimPrefix(line, "id:")))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+
+	if strings.Join(ids, ",") != "2,3" || strings.Join(data, ",") != "2,3" {
+		t.Fatalf("expected only events 2,3 to be replayed, got ids %v with data %v", ids, data)
+	}
+
+	// The live stream continues after the replay
+	if err := broker.Broadcast(4); err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read error: %v", err)
+		}
+
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "data:") {
+			if got := strings.TrimSpace(strings.TrimPrefix(line, "data:")); got != "4" {
+				t.Fatalf("expected live event 4, got %q", got)
+			}
+			break
+		}
+	}
+}
+
+func TestSSEBrokerReplayForgetsFinishedTasks(t *testing.T) {
+	replayed := func(broker *SSEBroker, taskID string) bool {
+		broker.mu.RLock()
+		defer broker.mu.RUnlock()
+
+		_, exists := broker.replay[taskID]
+		return exists
+	}
+
+	broker := NewTestSSEBroker(WithReplayGrace(0))
+	defer broker.Close()
+
+	for _, event := range []a2a.TaskStatusUpdateEvent{
+		{ID: "task-a", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}},
+		{ID: "task-b", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}},
+		{ID: "task-a", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}, Final: true},
+	} {
+		if err := broker.Broadcast(event); err != nil {
+			t.Fatalf("broadcast: %v", err)
+		}
+	}
+
+	if replayed(broker, "task-a") {
+		t.Fatal("expected the replay of the finished task to be dropped")
+	}
+
+	if !replayed(broker, "task-b") {
+		t.Fatal("expected the replay of the running task to be kept")
+	}
+
+	grace := 50 * time.Millisecond
+	graced := NewTestSSEBroker(WithReplayGrace(grace))
+	defer graced.Close()
+
+	if err := graced.Broadcast(a2a.TaskStatusUpdateEvent{
+		ID: "task-a", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}, Final: true,
+	}); err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+
+	if !replayed(graced, "task-a") {
+		t.Fatal("expected the replay to be kept during the grace period")
+	}
+
+	deadline := time.Now().Add(time.Second)
+
+	for replayed(graced, "task-a") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the replay to be dropped after the grace period")
+		}
+
+		time.Sleep(grace / 5)
+	}
+}
+
+func TestSSEBrokerHeartbeat(t *testing.T) {
+	interval := 50 * time.Millisecond
+	broker := NewSSEBroker(WithHeartbeat(interval))
+	defer broker.Close()
+
+	ts, errTS := newTestServerSSE(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		broker.Subscribe(w, r)
+	}))
+	if errTS != nil {
+		t.Skip("network disabled; skipping SSE test")
+	}
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("client get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	lines := make(chan string)
+
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReader(resp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			lines <- strings.TrimRight(line, "\n")
+		}
+	}()
+
+	deadline := time.After(3 * interval)
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("stream closed before a heartbeat arrived")
+			}
+
+			if line == ":" {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("no heartbeat within %s of an idle stream", 3*interval)
+		}
+	}
+}
+
+func TestSSEBrokerServeTask(t *testing.T) {
+	broker := NewTestSSEBroker()
+	defer broker.Close()
+
+	ts, errTS := newTestServerSSE(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		broker.ServeTask("session-1", w, r)
+	}))
+	if errTS != nil {
+		t.Skip("network disabled; skipping SSE test")
+	}
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("client get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatalf("read connection comment: %v", err)
+	}
+
+	if err := broker.BroadcastToTask("session-1", map[string]string{"msg": "hello"}); err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+
+		if strings.HasPrefix(line, "data: ") {
+			if !strings.Contains(line, "hello") {
+				t.Fatalf("unexpected event %q", line)
+			}
+			break
+		}
+	}
+
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+
+	for {
+		broker.mu.RLock()
+		_, exists := broker.taskBrokers["session-1"]
+		broker.mu.RUnlock()
+
+		if !exists {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("task broker still open after its last client left")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
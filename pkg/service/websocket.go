@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/gorilla/websocket"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/ai"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/push"
+)
+
+/*
+wsUpgrader upgrades requests on /ws. The origin of a browser is checked by
+the server the request reached, see checkOrigin.
+*/
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+/*
+checkOrigin lets a socket be opened by clients that are not browsers, which
+send no Origin header, by pages of the server's own origin and by the
+origins allowed with WithWebSocketOrigins. Any other page would otherwise
+run tasks on behalf of whoever visits it.
+*/
+func (srv *A2AServer) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+
+	if origin == "" {
+		return true
+	}
+
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	return slices.Contains(srv.wsOrigins, origin)
+}
+
+/*
+Handlers returns the net/http handlers of the transports fiber does not
+serve itself, so they can also be mounted on a plain http.Server. It holds
+the WebSocket transport under /ws and, with a push signer, the JWKS push
+notifications are verified against under /.well-known/jwks.json.
+*/
+func (srv *A2AServer) Handlers() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", srv.handleWebSocket)
+
+	if srv.pushSigner != nil {
+		mux.HandleFunc(push.JWKSPath, srv.pushSigner.JWKSHandler())
+	}
+
+	return mux
+}
+
+/*
+wsConn serializes the writes to a socket, which the streams of several
+requests share.
+*/
+type wsConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (ws *wsConn) write(response jsonrpc.Response) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	response.JSONRPC = "2.0"
+
+	if err := ws.conn.WriteJSON(response); err != nil {
+		log.Error("failed to write websocket frame", "id", response.ID, "error", err)
+	}
+}
+
+func (ws *wsConn) fail(id jsonrpc.ID, rpcErr *errors.RpcError) {
+	ws.write(jsonrpc.Response{
+		Message: jsonrpc.Message{MessageIdentifier: jsonrpc.MessageIdentifier{ID: id}},
+		Error:   &jsonrpc.Error{Code: rpcErr.Code, Message: rpcErr.Message},
+	})
+}
+
+/*
+handleWebSocket is the WebSocket transport, for clients that cannot use SSE.
+Every text frame the client sends is a JSON-RPC request, and the frames the
+server sends back carry the id of the request they answer. tasks/send is
+answered with the task, while tasks/sendSubscribe and tasks/resubscribe push
+a frame per TaskStatusUpdateEvent and TaskArtifactUpdateEvent, ending with
+the final status. Requests run concurrently, and their streams end when the
+client disconnects.
+*/
+func (srv *A2AServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	upgrader := wsUpgrader
+	upgrader.CheckOrigin = srv.checkOrigin
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Error("failed to upgrade websocket", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	ws := &wsConn{conn: conn}
+
+	for {
+		_, frame, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Warn("websocket closed unexpectedly", "error", err)
+			}
+			return
+		}
+
+		var request jsonrpc.Request
+
+		if err := json.Unmarshal(frame, &request); err != nil {
+			ws.fail(jsonrpc.ID{}, errors.ErrParseError.WithMessagef("invalid request frame: %v", err))
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			srv.serveWebSocket(ctx, ws, request)
+		}()
+	}
+}
+
+/*
+serveWebSocket answers a single request received on a socket.
+*/
+func (srv *A2AServer) serveWebSocket(ctx context.Context, ws *wsConn, request jsonrpc.Request) {
+	switch request.Method {
+	case "tasks/send":
+		params, rpcErr := srv.decodeSendParams(request.Params)
+		if rpcErr != nil {
+			ws.fail(request.ID, rpcErr)
+			return
+		}
+
+		if rpcErr := srv.setPushNotification(ctx, params); rpcErr != nil {
+			ws.fail(request.ID, rpcErr)
+			return
+		}
+
+		task, rpcErr := srv.agent.SendTask(ctx, params)
+		if rpcErr != nil {
+			ws.fail(request.ID, rpcErr)
+			return
+		}
+
+		ws.write(jsonrpc.Response{
+			Message: jsonrpc.Message{MessageIdentifier: jsonrpc.MessageIdentifier{ID: request.ID}},
+			Result:  task,
+		})
+	case "tasks/sendSubscribe":
+		params, rpcErr := srv.decodeSendParams(request.Params)
+		if rpcErr != nil {
+			ws.fail(request.ID, rpcErr)
+			return
+		}
+
+		if rpcErr := srv.setPushNotification(ctx, params); rpcErr != nil {
+			ws.fail(request.ID, rpcErr)
+			return
+		}
+
+		stream, rpcErr := srv.agent.StreamTask(ctx, srv.newStreamTask(params))
+		if rpcErr != nil {
+			ws.fail(request.ID, rpcErr)
+			return
+		}
+
+		for chunk := range stream {
+			ws.write(wsFrame(request.ID, params.ID, chunk))
+		}
+	case "tasks/resubscribe":
+		params, rpcErr := srv.decodeQueryParams(request.Params)
+		if rpcErr != nil {
+			ws.fail(request.ID, rpcErr)
+			return
+		}
+
+		srv.resubscribeWebSocket(ctx, ws, request.ID, params)
+	default:
+		ws.fail(request.ID, errors.ErrMethodNotFound.WithMessagef(
+			"%s: %s, the websocket serves tasks/send, tasks/sendSubscribe and tasks/resubscribe",
+			errors.ErrMethodNotFound.Message, request.Method,
+		))
+	}
+}
+
+/*
+resubscribeWebSocket follows a task on a socket: a task that is streaming
+replays its events from the start, any other task pushes its status every
+time it is stored, until it reaches a final state.
+*/
+func (srv *A2AServer) resubscribeWebSocket(
+	ctx context.Context, ws *wsConn, id jsonrpc.ID, params a2a.TaskQueryParams,
+) {
+	if srv.agent.HasStream(params.ID) {
+		events, rpcErr := srv.agent.SubscribeStream(ctx, params.ID, 0)
+		if rpcErr != nil {
+			ws.fail(id, rpcErr)
+			return
+		}
+
+		for event := range events {
+			ws.write(wsFrame(id, params.ID, event.Response))
+		}
+
+		return
+	}
+
+	tasks, rpcErr := srv.agent.ResubscribeTask(ctx, params.ID, *params.HistoryLength)
+	if rpcErr != nil {
+		ws.fail(id, rpcErr)
+		return
+	}
+
+	// The store does not close the subscription when ctx is done, so the
+	// socket going away has to end the loop.
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task, ok := <-tasks:
+			if !ok {
+				return
+			}
+
+			final := ai.IsTerminalState(task.Status.State)
+
+			ws.write(jsonrpc.Response{
+				Message: jsonrpc.Message{MessageIdentifier: jsonrpc.MessageIdentifier{ID: id}},
+				Result:  a2a.TaskStatusUpdateEvent{ID: task.ID, Status: task.Status, Final: final},
+			})
+
+			if final {
+				return
+			}
+		}
+	}
+}
+
+/*
+wsFrame turns a chunk of a task stream into the frame answering the request,
+with status updates sent as TaskStatusUpdateEvent.
+*/
+func wsFrame(id jsonrpc.ID, taskID string, chunk jsonrpc.Response) jsonrpc.Response {
+	frame := jsonrpc.Response{
+		Message: jsonrpc.Message{MessageIdentifier: jsonrpc.MessageIdentifier{ID: id}},
+		Result:  chunk.Result,
+		Error:   chunk.Error,
+	}
+
+	if status, ok := chunk.Result.(a2a.TaskStatusUpdateResult); ok {
+		if status.ID == "" {
+			status.ID = taskID
+		}
+
+		frame.Result = a2a.TaskStatusUpdateEvent{
+			ID: status.ID, Status: status.Status, Final: status.Final, Metadata: status.Metadata,
+		}
+	}
+
+	return frame
+}
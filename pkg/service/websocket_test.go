@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/provider"
+)
+
+/*
+artifactProvider streams an artifact per text, then completes the task.
+*/
+type artifactProvider struct {
+	texts []string
+}
+
+func (prvdr *artifactProvider) Generate(ctx context.Context, params *provider.ProviderParams) chan jsonrpc.Response {
+	ch := make(chan jsonrpc.Response)
+
+	go func() {
+		defer close(ch)
+
+		for i, text := range prvdr.texts {
+			ch <- jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+				ID:       params.Task.ID,
+				Artifact: a2a.Artifact{Index: i, Parts: []a2a.Part{a2a.NewTextPart(text)}},
+			}}
+		}
+
+		ch <- jsonrpc.Response{Result: a2a.TaskStatusUpdateResult{
+			ID:     params.Task.ID,
+			Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+			Final:  true,
+		}}
+	}()
+
+	return ch
+}
+
+func (prvdr *artifactProvider) Capabilities() provider.ProviderCapabilities {
+	return provider.ProviderCapabilities{Streaming: true}
+}
+
+func TestWebSocketTransport(t *testing.T) {
+	Convey("Given an agent server whose provider streams two artifacts", t, func() {
+		srv := newTestServer(t, &artifactProvider{texts: []string{"hello", "world"}})
+
+		ts := httptest.NewServer(srv.Handlers())
+		defer ts.Close()
+
+		Convey("When a client streams a task over the websocket", func() {
+			events := make(chan any, 16)
+
+			err := a2a.NewClient(ts.URL).SendTaskStreamingWS(a2a.TaskSendParams{
+				ID:      "ws-task",
+				Message: *a2a.NewTextMessage("user", "say hello"),
+			}, events)
+			So(err, ShouldBeNil)
+			close(events)
+
+			var received []map[string]any
+			for event := range events {
+				received = append(received, event.(map[string]any))
+			}
+
+			Convey("Then it should receive the artifacts in order", func() {
+				So(len(received), ShouldBeGreaterThanOrEqualTo, 3)
+
+				var texts []any
+				for _, event := range received {
+					if artifact, ok := event["artifact"].(map[string]any); ok {
+						texts = append(texts, artifact["parts"].([]any)[0].(map[string]any)["text"])
+					}
+				}
+
+				So(texts, ShouldResemble, []any{"hello", "world"})
+			})
+
+			Convey("Then the stream should end with the final status of the task", func() {
+				last := received[len(received)-1]
+				So(last["id"], ShouldEqual, "ws-task")
+				So(last["final"], ShouldEqual, true)
+				So(last["status"].(map[string]any)["state"], ShouldEqual, string(a2a.TaskStateCompleted))
+			})
+		})
+
+		Convey("When a client resubscribing to an idle task disconnects", func() {
+			done := make(chan struct{})
+
+			idle := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer close(done)
+				srv.Handlers().ServeHTTP(w, r)
+			}))
+			defer idle.Close()
+
+			conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(idle.URL, "http")+"/ws", nil)
+			So(err, ShouldBeNil)
+
+			So(conn.WriteJSON(map[string]any{
+				"jsonrpc": "2.0", "id": 1, "method": "tasks/resubscribe", "params": map[string]any{"id": "idle-task"},
+			}), ShouldBeNil)
+
+			time.Sleep(50 * time.Millisecond)
+			conn.Close()
+
+			Convey("Then the handler should return", func() {
+				select {
+				case <-done:
+				case <-time.After(2 * time.Second):
+					t.Fatal("websocket handler still running after the client left")
+				}
+			})
+		})
+
+		Convey("When pages of other origins open a socket", func() {
+			WithWebSocketOrigins("https://allowed.example")(srv)
+
+			dial := func(origin string) error {
+				conn, _, err := websocket.DefaultDialer.Dial(
+					"ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", http.Header{"Origin": {origin}},
+				)
+				if err == nil {
+					conn.Close()
+				}
+				return err
+			}
+
+			Convey("Then only the server's own origin and the allowed ones should be let in", func() {
+				So(dial(ts.URL), ShouldBeNil)
+				So(dial("https://allowed.example"), ShouldBeNil)
+				So(dial("https://evil.example"), ShouldEqual, websocket.ErrBadHandshake)
+			})
+		})
+
+		Convey("When a raw websocket client calls a method the websocket does not serve", func() {
+			conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+			So(err, ShouldBeNil)
+			defer conn.Close()
+
+			So(conn.WriteJSON(jsonrpc.Request{
+				Message: jsonrpc.Message{MessageIdentifier: jsonrpc.MessageIdentifier{ID: jsonrpc.NumberID(7)}, JSONRPC: "2.0"},
+				Method:  "tasks/cancel",
+			}), ShouldBeNil)
+
+			var response jsonrpc.Response
+			So(conn.ReadJSON(&response), ShouldBeNil)
+
+			Convey("Then the error should answer the request and name the methods it serves", func() {
+				So(response.ID.Equal(jsonrpc.NumberID(7)), ShouldBeTrue)
+				So(response.Error, ShouldNotBeNil)
+				So(response.Error.Code, ShouldEqual, errors.ErrMethodNotFound.Code)
+				So(response.Error.Message, ShouldContainSubstring, "tasks/sendSubscribe")
+			})
+		})
+	})
+}
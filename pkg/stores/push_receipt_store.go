@@ -0,0 +1,64 @@
+package stores
+
+import (
+	"context"
+	"sync"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+/*
+PushReceiptStore records the outcome of every push notification delivered
+for a task, so operators can tell whether its webhook got them.
+*/
+type PushReceiptStore interface {
+	Record(context.Context, string, a2a.PushNotificationReceipt) *errors.RpcError
+	List(context.Context, string) ([]a2a.PushNotificationReceipt, *errors.RpcError)
+}
+
+/*
+InMemoryPushReceiptStore is the default PushReceiptStore. It keeps the most
+recent receipts of each task, up to its limit, or all of them when the limit
+is not positive.
+*/
+type InMemoryPushReceiptStore struct {
+	mu       sync.RWMutex
+	limit    int
+	receipts map[string][]a2a.PushNotificationReceipt
+}
+
+func NewInMemoryPushReceiptStore(limit int) *InMemoryPushReceiptStore {
+	return &InMemoryPushReceiptStore{
+		limit:    limit,
+		receipts: make(map[string][]a2a.PushNotificationReceipt),
+	}
+}
+
+/*
+Record appends a receipt to those of the task, dropping the oldest once the
+limit is reached.
+*/
+func (store *InMemoryPushReceiptStore) Record(ctx context.Context, id string, receipt a2a.PushNotificationReceipt) *errors.RpcError {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	receipts := append(store.receipts[id], receipt)
+
+	if store.limit > 0 && len(receipts) > store.limit {
+		receipts = receipts[len(receipts)-store.limit:]
+	}
+
+	store.receipts[id] = receipts
+	return nil
+}
+
+/*
+List returns the receipts of a task, oldest first.
+*/
+func (store *InMemoryPushReceiptStore) List(ctx context.Context, id string) ([]a2a.PushNotificationReceipt, *errors.RpcError) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return append([]a2a.PushNotificationReceipt{}, store.receipts[id]...), nil
+}
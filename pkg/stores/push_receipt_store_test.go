@@ -0,0 +1,30 @@
+package stores
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestPushReceiptStoreLimit(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryPushReceiptStore(2)
+
+	// Test listing a task without receipts
+	receipts, err := store.List(ctx, "task1")
+	assert.Nil(t, err)
+	assert.Empty(t, receipts)
+
+	for _, state := range []a2a.TaskState{a2a.TaskStateSubmitted, a2a.TaskStateWorking, a2a.TaskStateCompleted} {
+		assert.Nil(t, store.Record(ctx, "task1", a2a.PushNotificationReceipt{State: state}))
+	}
+
+	// Only the most recent receipts should be kept, oldest first
+	receipts, err = store.List(ctx, "task1")
+	assert.Nil(t, err)
+	assert.Len(t, receipts, 2)
+	assert.Equal(t, a2a.TaskStateWorking, receipts[0].State)
+	assert.Equal(t, a2a.TaskStateCompleted, receipts[1].State)
+}
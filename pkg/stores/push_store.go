@@ -0,0 +1,105 @@
+package stores
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+/*
+PushNotificationStore holds the push notification config of every task that
+asked for one, keyed by task id. Get and Delete fail with
+ErrPushNotificationConfigNotFound for a task without a config.
+*/
+type PushNotificationStore interface {
+	Set(context.Context, a2a.TaskPushNotificationConfig) *errors.RpcError
+	Get(context.Context, string) (a2a.TaskPushNotificationConfig, *errors.RpcError)
+	Delete(context.Context, string) *errors.RpcError
+	List(context.Context) ([]a2a.TaskPushNotificationConfig, *errors.RpcError)
+}
+
+/*
+InMemoryPushNotificationStore is the default PushNotificationStore. Its
+configs are lost when the process stops, so tasks that outlive it need a
+persistent implementation.
+*/
+type InMemoryPushNotificationStore struct {
+	mu      sync.RWMutex
+	configs map[string]a2a.TaskPushNotificationConfig
+}
+
+func NewInMemoryPushNotificationStore() *InMemoryPushNotificationStore {
+	return &InMemoryPushNotificationStore{configs: make(map[string]a2a.TaskPushNotificationConfig)}
+}
+
+/*
+Set stores the config of a task, replacing the one it had.
+*/
+func (store *InMemoryPushNotificationStore) Set(ctx context.Context, config a2a.TaskPushNotificationConfig) *errors.RpcError {
+	if config.ID == "" {
+		return errors.ErrInvalidParams.WithMessagef("invalid params: id is required")
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.configs[config.ID] = config
+	return nil
+}
+
+/*
+Get returns the config of a task.
+*/
+func (store *InMemoryPushNotificationStore) Get(ctx context.Context, id string) (a2a.TaskPushNotificationConfig, *errors.RpcError) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	config, ok := store.configs[id]
+	if !ok {
+		return config, pushConfigNotFound(id)
+	}
+
+	return config, nil
+}
+
+/*
+Delete removes the config of a task, which stops its push notifications.
+*/
+func (store *InMemoryPushNotificationStore) Delete(ctx context.Context, id string) *errors.RpcError {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, ok := store.configs[id]; !ok {
+		return pushConfigNotFound(id)
+	}
+
+	delete(store.configs, id)
+	return nil
+}
+
+/*
+List returns every stored config, ordered by task id.
+*/
+func (store *InMemoryPushNotificationStore) List(ctx context.Context) ([]a2a.TaskPushNotificationConfig, *errors.RpcError) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	configs := make([]a2a.TaskPushNotificationConfig, 0, len(store.configs))
+
+	for _, config := range store.configs {
+		configs = append(configs, config)
+	}
+
+	sort.Slice(configs, func(i, j int) bool { return configs[i].ID < configs[j].ID })
+
+	return configs, nil
+}
+
+func pushConfigNotFound(id string) *errors.RpcError {
+	return errors.ErrPushNotificationConfigNotFound.WithMessagef(
+		"%s: %s", errors.ErrPushNotificationConfigNotFound.Message, id,
+	)
+}
@@ -0,0 +1,58 @@
+package stores
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+func TestPushNotificationStoreLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryPushNotificationStore()
+
+	config := a2a.TaskPushNotificationConfig{
+		ID:                     "task1",
+		PushNotificationConfig: a2a.PushNotificationConfig{URL: "https://example.com/webhook"},
+	}
+
+	assert.Nil(t, store.Set(ctx, config))
+
+	stored, err := store.Get(ctx, "task1")
+	assert.Nil(t, err)
+	assert.Equal(t, config, stored)
+
+	configs, err := store.List(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []a2a.TaskPushNotificationConfig{config}, configs)
+
+	assert.Nil(t, store.Delete(ctx, "task1"))
+
+	_, err = store.Get(ctx, "task1")
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.ErrPushNotificationConfigNotFound.Code, err.Code)
+
+	configs, err = store.List(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, configs)
+}
+
+func TestPushNotificationStoreNotFound(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryPushNotificationStore()
+
+	_, err := store.Get(ctx, "nonexistent")
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.ErrPushNotificationConfigNotFound.Code, err.Code)
+	assert.Contains(t, err.Message, "nonexistent")
+
+	err = store.Delete(ctx, "nonexistent")
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.ErrPushNotificationConfigNotFound.Code, err.Code)
+
+	err = store.Set(ctx, a2a.TaskPushNotificationConfig{})
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.ErrInvalidParams.Code, err.Code)
+}
@@ -21,6 +21,8 @@ type Client struct {
 	connPool    chan *http.Client
 	poolSize    int
 	healthCheck bool
+	wait        bool
+	autoCreate  bool
 }
 
 // ClientOption defines functional options for the Client
@@ -68,6 +70,27 @@ func WithHealthCheck(enabled bool) ClientOption {
 	}
 }
 
+// WithQdrantWait controls whether upserts and deletes wait for the change to
+// be applied before returning. Waiting makes a write immediately visible to a
+// following search, at the cost of added latency on every write. Disable it
+// for bulk ingestion where read-after-write consistency does not matter.
+func WithQdrantWait(wait bool) ClientOption {
+	return func(c *Client) {
+		c.wait = wait
+	}
+}
+
+// WithQdrantAutoCreate controls whether a missing collection is created the
+// first time it is written to, with searches returning no results until then.
+// It is enabled by default. Disable it when collections are provisioned up
+// front, so that a misconfigured collection name fails fast with a clear error
+// instead of silently creating a new one.
+func WithQdrantAutoCreate(autoCreate bool) ClientOption {
+	return func(c *Client) {
+		c.autoCreate = autoCreate
+	}
+}
+
 // New returns a Client with optimized defaults.
 func New(endpoint, collection string, options ...ClientOption) *Client {
 	client := &Client{
@@ -84,6 +107,8 @@ func New(endpoint, collection string, options ...ClientOption) *Client {
 		maxRetries: 3,
 		retryDelay: 500 * time.Millisecond,
 		poolSize:   10,
+		wait:       true,
+		autoCreate: true,
 	}
 
 	// Apply options
@@ -159,6 +184,108 @@ func (client *Client) releaseClient(httpClient *http.Client) {
 	}
 }
 
+// writeURL appends the wait query parameter to write endpoints when enabled.
+func (client *Client) writeURL(url string) string {
+	if client.wait {
+		return url + "?wait=true"
+	}
+
+	return url
+}
+
+// ensureCollection handles a collection that turned out to be missing. With
+// auto-create enabled it creates the collection for vectors of the given size,
+// otherwise it returns an error naming the collection.
+func (client *Client) ensureCollection(ctx context.Context, size int) error {
+	if !client.autoCreate {
+		return client.missingCollection()
+	}
+
+	if size <= 0 {
+		return fmt.Errorf("qdrant: cannot create collection %q without a vector size", client.Collection)
+	}
+
+	b, _ := json.Marshal(map[string]any{
+		"vectors": map[string]any{"size": size, "distance": "Cosine"},
+	})
+
+	url := fmt.Sprintf("%s/collections/%s", client.Endpoint, client.Collection)
+
+	resp, err := client.doRequest(ctx, http.MethodPut, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("qdrant: create collection status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// missingCollection describes a collection that does not exist while auto-create is disabled.
+func (client *Client) missingCollection() error {
+	return fmt.Errorf(
+		"qdrant: collection %q does not exist at %s and auto-create is disabled",
+		client.Collection, client.Endpoint,
+	)
+}
+
+// VectorSize returns the vector size the collection was created with, or zero
+// when the collection does not exist yet.
+func (client *Client) VectorSize(ctx context.Context) (int, error) {
+	url := fmt.Sprintf("%s/collections/%s", client.Endpoint, client.Collection)
+
+	resp, err := client.doRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+
+	if resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("qdrant: get collection status %s", resp.Status)
+	}
+
+	var out struct {
+		Result struct {
+			Config struct {
+				Params struct {
+					Vectors struct {
+						Size int `json:"size"`
+					} `json:"vectors"`
+				} `json:"params"`
+			} `json:"config"`
+		} `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+
+	return out.Result.Config.Params.Vectors.Size, nil
+}
+
+// vectorSize returns the length of the first embedding found in docs.
+func vectorSize(docs []Document) int {
+	for _, d := range docs {
+		switch vec := d.Metadata["embedding"].(type) {
+		case []float32:
+			return len(vec)
+		case []float64:
+			return len(vec)
+		case []any:
+			return len(vec)
+		}
+	}
+
+	return 0
+}
+
 // doRequest performs an HTTP request with retries
 func (client *Client) doRequest(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
 	var (
@@ -272,7 +399,7 @@ func (client *Client) Get(ctx context.Context, id string) (*Document, error) {
 
 // Delete removes a document by ID with retries.
 func (client *Client) Delete(ctx context.Context, id string) error {
-	url := fmt.Sprintf("%s/collections/%s/points/%s", client.Endpoint, client.Collection, id)
+	url := client.writeURL(fmt.Sprintf("%s/collections/%s/points/%s", client.Endpoint, client.Collection, id))
 
 	resp, err := client.doRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
@@ -287,6 +414,128 @@ func (client *Client) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// DeletePoints removes the documents with the given IDs in one request.
+func (client *Client) DeletePoints(ctx context.Context, ids []string) error {
+	return client.deletePoints(ctx, map[string]any{"points": ids}, "delete points")
+}
+
+// DeleteWhere removes every document matching a Qdrant filter, such as
+// {"must": [{"key": "session", "match": {"value": "s1"}}]}.
+func (client *Client) DeleteWhere(ctx context.Context, filter map[string]any) error {
+	return client.deletePoints(ctx, map[string]any{"filter": filter}, "delete by filter")
+}
+
+// deletePoints posts a points/delete body selecting the points to remove.
+func (client *Client) deletePoints(ctx context.Context, body map[string]any, operation string) error {
+	b, _ := json.Marshal(body)
+
+	url := client.writeURL(fmt.Sprintf("%s/collections/%s/points/delete", client.Endpoint, client.Collection))
+
+	resp, err := client.doRequest(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// A collection that was never written to has nothing to delete.
+		if client.autoCreate {
+			return nil
+		}
+
+		return client.missingCollection()
+	}
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("qdrant: %s status %s", operation, resp.Status)
+	}
+
+	return nil
+}
+
+// CreatePayloadIndex indexes a payload field with the given schema, such as
+// "keyword", so filters on the field do not scan the whole collection.
+// Indexing a field that is already indexed succeeds.
+func (client *Client) CreatePayloadIndex(ctx context.Context, field, schema string) error {
+	b, _ := json.Marshal(map[string]any{"field_name": field, "field_schema": schema})
+
+	url := client.writeURL(fmt.Sprintf("%s/collections/%s/index", client.Endpoint, client.Collection))
+
+	resp, err := client.doRequest(ctx, http.MethodPut, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return client.missingCollection()
+	}
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("qdrant: create payload index status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// ScrollWhere returns up to limit documents matching a Qdrant filter, without
+// ranking them against a vector.
+func (client *Client) ScrollWhere(ctx context.Context, filter map[string]any, limit int) ([]Document, error) {
+	b, _ := json.Marshal(map[string]any{
+		"filter":       filter,
+		"limit":        limit,
+		"with_payload": true,
+	})
+
+	url := fmt.Sprintf("%s/collections/%s/points/scroll", client.Endpoint, client.Collection)
+
+	resp, err := client.doRequest(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// The collection is created on the first write, until then there is nothing to find.
+		if client.autoCreate {
+			return []Document{}, nil
+		}
+
+		return nil, client.missingCollection()
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("qdrant: scroll status %s", resp.Status)
+	}
+
+	var out struct {
+		Result struct {
+			Points []struct {
+				ID      string         `json:"id"`
+				Payload map[string]any `json:"payload"`
+			} `json:"points"`
+		} `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("qdrant: failed to decode scroll response: %w", err)
+	}
+
+	docs := make([]Document, 0, len(out.Result.Points))
+
+	for _, p := range out.Result.Points {
+		content, _ := p.Payload["content"].(string)
+
+		docs = append(docs, Document{
+			ID:       p.ID,
+			Content:  content,
+			Metadata: p.Payload,
+		})
+	}
+
+	return docs, nil
+}
+
 // Put upserts a batch of documents as points with retries and connection pooling.
 func (client *Client) Put(ctx context.Context, docs []Document) error {
 	// Build Qdrant "points" payload.
@@ -309,7 +558,7 @@ func (client *Client) Put(ctx context.Context, docs []Document) error {
 	body := map[string]any{"points": points}
 	b, _ := json.Marshal(body)
 
-	url := fmt.Sprintf("%s/collections/%s/points", client.Endpoint, client.Collection)
+	url := client.writeURL(fmt.Sprintf("%s/collections/%s/points", client.Endpoint, client.Collection))
 
 	resp, err := client.doRequest(ctx, http.MethodPut, url, bytes.NewReader(b))
 	if err != nil {
@@ -317,6 +566,17 @@ func (client *Client) Put(ctx context.Context, docs []Document) error {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		if err := client.ensureCollection(ctx, vectorSize(docs)); err != nil {
+			return err
+		}
+
+		if resp, err = client.doRequest(ctx, http.MethodPut, url, bytes.NewReader(b)); err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+	}
+
 	if resp.StatusCode >= 300 {
 		return fmt.Errorf("qdrant: put status %s", resp.Status)
 	}
@@ -366,62 +626,23 @@ func (client *Client) BatchPut(ctx context.Context, docs []Document, batchSize i
 
 // Search performs a vector search with retries and connection pooling.
 func (client *Client) Search(ctx context.Context, queryVec []float32, limit int) ([]Document, error) {
+	return client.SearchFrom(ctx, queryVec, limit, 0)
+}
+
+// SearchFrom performs a vector search that skips the first offset matches,
+// for paging through results.
+func (client *Client) SearchFrom(ctx context.Context, queryVec []float32, limit, offset int) ([]Document, error) {
 	body := map[string]any{
 		"vector":       queryVec,
 		"limit":        limit,
 		"with_payload": true,
 	}
 
-	b, _ := json.Marshal(body)
-
-	url := fmt.Sprintf("%s/collections/%s/points/search", client.Endpoint, client.Collection)
-
-	resp, err := client.doRequest(ctx, http.MethodPost, url, bytes.NewReader(b))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("qdrant: search status %s", resp.Status)
-	}
-
-	var out struct {
-		Result []struct {
-			ID      string         `json:"id"`
-			Payload map[string]any `json:"payload"`
-			Score   float64        `json:"score"`
-		} `json:"result"`
+	if offset > 0 {
+		body["offset"] = offset
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return nil, fmt.Errorf("qdrant: failed to decode search response: %w", err)
-	}
-
-	docs := make([]Document, 0, len(out.Result))
-
-	for _, r := range out.Result {
-		var content string
-		if c, ok := r.Payload["content"].(string); ok {
-			content = c
-		} else if r.Payload["content"] != nil {
-			content = fmt.Sprintf("%v", r.Payload["content"])
-		}
-
-		// Add score to metadata
-		if r.Payload == nil {
-			r.Payload = make(map[string]any)
-		}
-		r.Payload["_score"] = r.Score
-
-		docs = append(docs, Document{
-			ID:       r.ID,
-			Content:  content,
-			Metadata: r.Payload,
-		})
-	}
-
-	return docs, nil
+	return client.search(ctx, body, "search")
 }
 
 // FilterSearch performs a search with filters.
@@ -438,6 +659,33 @@ func (client *Client) FilterSearch(ctx context.Context, queryVec []float32, limi
 		}
 	}
 
+	return client.search(ctx, body, "filter search")
+}
+
+// TextSearch performs a vector search over the points whose payload field
+// contains at least one of the terms, so exact matches such as error codes
+// are found even when they are not among the nearest vectors.
+func (client *Client) TextSearch(ctx context.Context, queryVec []float32, limit int, field string, terms []string) ([]Document, error) {
+	should := make([]map[string]any, 0, len(terms))
+	for _, term := range terms {
+		should = append(should, map[string]any{
+			"key":   field,
+			"match": map[string]any{"text": term},
+		})
+	}
+
+	body := map[string]any{
+		"vector":       queryVec,
+		"limit":        limit,
+		"with_payload": true,
+		"filter":       map[string]any{"should": should},
+	}
+
+	return client.search(ctx, body, "text search")
+}
+
+// search posts a search body and decodes the scored documents it returns.
+func (client *Client) search(ctx context.Context, body map[string]any, operation string) ([]Document, error) {
 	b, _ := json.Marshal(body)
 
 	url := fmt.Sprintf("%s/collections/%s/points/search", client.Endpoint, client.Collection)
@@ -448,8 +696,17 @@ func (client *Client) FilterSearch(ctx context.Context, queryVec []float32, limi
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		// The collection is created on the first write, until then there is nothing to find.
+		if client.autoCreate {
+			return []Document{}, nil
+		}
+
+		return nil, client.missingCollection()
+	}
+
 	if resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("qdrant: filter search status %s", resp.Status)
+		return nil, fmt.Errorf("qdrant: %s status %s", operation, resp.Status)
 	}
 
 	var out struct {
@@ -461,7 +718,7 @@ func (client *Client) FilterSearch(ctx context.Context, queryVec []float32, limi
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return nil, fmt.Errorf("qdrant: failed to decode filter search response: %w", err)
+		return nil, fmt.Errorf("qdrant: failed to decode %s response: %w", operation, err)
 	}
 
 	docs := make([]Document, 0, len(out.Result))
@@ -2,6 +2,7 @@ package qdrant
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -46,3 +47,214 @@ func TestClientSearch(t *testing.T) {
 		})
 	})
 }
+
+func TestClientSearchFrom(t *testing.T) {
+	Convey("Given a qdrant client and a test server recording search bodies", t, func() {
+		var bodies []map[string]any
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			bodies = append(bodies, body)
+			fmt.Fprint(w, `{"result":[]}`)
+		}))
+		defer ts.Close()
+
+		client := New(ts.URL, "mem")
+
+		Convey("When searching from an offset", func() {
+			_, err := client.SearchFrom(context.Background(), []float32{0.1}, 2, 4)
+
+			Convey("Then the offset should be sent", func() {
+				So(err, ShouldBeNil)
+				So(bodies[0]["offset"], ShouldEqual, float64(4))
+				So(bodies[0]["limit"], ShouldEqual, float64(2))
+			})
+		})
+
+		Convey("When searching from the start", func() {
+			_, err := client.Search(context.Background(), []float32{0.1}, 2)
+
+			Convey("Then no offset should be sent", func() {
+				So(err, ShouldBeNil)
+				So(bodies[0], ShouldNotContainKey, "offset")
+			})
+		})
+	})
+}
+
+func TestWithQdrantWait(t *testing.T) {
+	Convey("Given a test server recording write query strings", t, func() {
+		var queries []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.RawQuery)
+			fmt.Fprint(w, `{"result":{}}`)
+		}))
+		defer ts.Close()
+
+		docs := []Document{*NewDocument("1", "a", nil)}
+
+		Convey("When wait is left at its default", func() {
+			client := New(ts.URL, "mem")
+			So(client.Put(context.Background(), docs), ShouldBeNil)
+			So(client.Delete(context.Background(), "1"), ShouldBeNil)
+
+			Convey("Then upserts and deletes should wait", func() {
+				So(queries, ShouldResemble, []string{"wait=true", "wait=true"})
+			})
+		})
+
+		Convey("When wait is disabled", func() {
+			client := New(ts.URL, "mem", WithQdrantWait(false))
+			So(client.Put(context.Background(), docs), ShouldBeNil)
+			So(client.Delete(context.Background(), "1"), ShouldBeNil)
+
+			Convey("Then the wait parameter should be absent", func() {
+				So(queries, ShouldResemble, []string{"", ""})
+			})
+		})
+	})
+}
+
+func TestWithQdrantAutoCreate(t *testing.T) {
+	Convey("Given a test server without the collection", t, func() {
+		var requests []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			if r.Method == http.MethodPut && r.URL.Path == "/collections/mem" {
+				fmt.Fprint(w, `{"result":true}`)
+				return
+			}
+
+			if len(requests) == 1 || r.URL.Path == "/collections/mem/points/search" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			fmt.Fprint(w, `{"result":{}}`)
+		}))
+		defer ts.Close()
+
+		docs := []Document{*NewDocument("1", "a", map[string]any{"embedding": []float32{0.1, 0.2}})}
+
+		Convey("When auto-create is left at its default", func() {
+			client := New(ts.URL, "mem")
+			err := client.Put(context.Background(), docs)
+
+			Convey("Then the collection should be created and the write retried", func() {
+				So(err, ShouldBeNil)
+				So(requests, ShouldResemble, []string{
+					"PUT /collections/mem/points",
+					"PUT /collections/mem",
+					"PUT /collections/mem/points",
+				})
+			})
+		})
+
+		Convey("When auto-create is disabled", func() {
+			client := New(ts.URL, "mem", WithQdrantAutoCreate(false))
+			err := client.Put(context.Background(), docs)
+
+			Convey("Then the store should fail with a descriptive error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, `collection "mem" does not exist`)
+				So(err.Error(), ShouldContainSubstring, "auto-create is disabled")
+			})
+
+			Convey("Then no create request should be issued", func() {
+				So(requests, ShouldResemble, []string{"PUT /collections/mem/points"})
+			})
+
+			Convey("Then searches should fail fast too", func() {
+				_, err := client.Search(context.Background(), []float32{0.1, 0.2}, 1)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "auto-create is disabled")
+			})
+		})
+	})
+}
+
+func TestClientDeletePoints(t *testing.T) {
+	Convey("Given a test server recording delete requests", t, func() {
+		var (
+			paths  []string
+			bodies []map[string]any
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			paths = append(paths, r.Method+" "+r.URL.Path)
+			bodies = append(bodies, body)
+			fmt.Fprint(w, `{"result":{}}`)
+		}))
+		defer ts.Close()
+
+		client := New(ts.URL, "mem")
+
+		Convey("When deleting points by id", func() {
+			So(client.DeletePoints(context.Background(), []string{"1", "2"}), ShouldBeNil)
+
+			Convey("Then the ids should be posted to points/delete", func() {
+				So(paths, ShouldResemble, []string{"POST /collections/mem/points/delete"})
+				So(bodies[0]["points"], ShouldResemble, []any{"1", "2"})
+			})
+		})
+
+		Convey("When deleting points by filter", func() {
+			filter := map[string]any{
+				"must": []map[string]any{{"key": "session", "match": map[string]any{"value": "s1"}}},
+			}
+			So(client.DeleteWhere(context.Background(), filter), ShouldBeNil)
+
+			Convey("Then the filter should be posted to points/delete", func() {
+				So(paths, ShouldResemble, []string{"POST /collections/mem/points/delete"})
+				So(bodies[0], ShouldContainKey, "filter")
+				So(bodies[0], ShouldNotContainKey, "points")
+			})
+		})
+	})
+}
+
+func TestClientScrollWhere(t *testing.T) {
+	Convey("Given a test server answering scroll requests", t, func() {
+		var (
+			paths  []string
+			bodies []map[string]any
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			paths = append(paths, r.Method+" "+r.URL.Path)
+			bodies = append(bodies, body)
+			fmt.Fprint(w, `{"result":{"points":[{"id":"1","payload":{"content":"hello","content_hash":"abc"}}]}}`)
+		}))
+		defer ts.Close()
+
+		client := New(ts.URL, "mem")
+
+		Convey("When scrolling by filter", func() {
+			filter := map[string]any{
+				"must": []map[string]any{{"key": "content_hash", "match": map[string]any{"value": "abc"}}},
+			}
+			docs, err := client.ScrollWhere(context.Background(), filter, 1)
+
+			Convey("Then the filter should be posted to points/scroll", func() {
+				So(err, ShouldBeNil)
+				So(paths, ShouldResemble, []string{"POST /collections/mem/points/scroll"})
+				So(bodies[0], ShouldContainKey, "filter")
+				So(bodies[0]["limit"], ShouldEqual, 1)
+			})
+
+			Convey("Then the matching documents should be returned", func() {
+				So(docs, ShouldHaveLength, 1)
+				So(docs[0].ID, ShouldEqual, "1")
+				So(docs[0].Content, ShouldEqual, "hello")
+			})
+		})
+	})
+}
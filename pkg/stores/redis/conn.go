@@ -0,0 +1,299 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+ErrNil is returned when Redis replies with a nil bulk string, which is what
+GET returns for a key that does not exist.
+*/
+var ErrNil = errors.New("redis: nil")
+
+/*
+Conn is a minimal client for the Redis serialization protocol (RESP). It
+keeps a single connection for commands, dialing lazily and redialing after
+a failure, and opens a dedicated connection for every subscription.
+*/
+type Conn struct {
+	addr     string
+	password string
+	db       int
+	dialer   net.Dialer
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+/*
+NewConn creates a connection to the Redis server at addr, authenticating
+with password when it is not empty and selecting the database db.
+*/
+func NewConn(addr, password string, db int) *Conn {
+	return &Conn{
+		addr:     addr,
+		password: password,
+		db:       db,
+		dialer:   net.Dialer{Timeout: 5 * time.Second},
+	}
+}
+
+/*
+Do sends a command and returns its reply: a string for simple strings, an
+int64 for integers, a []byte for bulk strings and a []any for arrays.
+*/
+func (conn *Conn) Do(ctx context.Context, args ...string) (any, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.conn == nil {
+		nc, rd, err := conn.dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		conn.conn, conn.rd = nc, rd
+	}
+
+	reply, err := roundTrip(ctx, conn.conn, conn.rd, args...)
+
+	var replyErr replyError
+	if err != nil && !errors.As(err, &replyErr) && !errors.Is(err, ErrNil) {
+		// The connection is in an unknown state, so start over next time.
+		conn.conn.Close()
+		conn.conn, conn.rd = nil, nil
+	}
+
+	return reply, err
+}
+
+/*
+Subscribe listens on channel over a dedicated connection and delivers every
+published message until ctx is done, after which the returned channel is
+closed.
+*/
+func (conn *Conn) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	nc, rd, err := conn.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := roundTrip(ctx, nc, rd, "SUBSCRIBE", channel); err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	messages := make(chan []byte)
+
+	go func() {
+		defer close(messages)
+		defer nc.Close()
+
+		nc.SetDeadline(time.Time{})
+
+		// Unblock the pending read once the subscriber goes away.
+		stop := context.AfterFunc(ctx, func() { nc.SetReadDeadline(time.Now()) })
+		defer stop()
+
+		for {
+			reply, err := readReply(rd)
+			if err != nil {
+				return
+			}
+
+			// Pushed messages look like ["message", channel, payload].
+			push, ok := reply.([]any)
+			if !ok || len(push) != 3 {
+				continue
+			}
+
+			if kind, _ := push[0].([]byte); string(kind) != "message" {
+				continue
+			}
+
+			payload, _ := push[2].([]byte)
+
+			select {
+			case messages <- payload:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+/*
+Close closes the command connection. Subscriptions end with their context.
+*/
+func (conn *Conn) Close() error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.conn == nil {
+		return nil
+	}
+
+	err := conn.conn.Close()
+	conn.conn, conn.rd = nil, nil
+
+	return err
+}
+
+/*
+dial opens a connection, then authenticates and selects the database.
+*/
+func (conn *Conn) dial(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	nc, err := conn.dialer.DialContext(ctx, "tcp", conn.addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rd := bufio.NewReader(nc)
+
+	if conn.password != "" {
+		if _, err := roundTrip(ctx, nc, rd, "AUTH", conn.password); err != nil {
+			nc.Close()
+			return nil, nil, err
+		}
+	}
+
+	if conn.db != 0 {
+		if _, err := roundTrip(ctx, nc, rd, "SELECT", strconv.Itoa(conn.db)); err != nil {
+			nc.Close()
+			return nil, nil, err
+		}
+	}
+
+	return nc, rd, nil
+}
+
+/*
+roundTrip writes a command and reads its reply, bounded by the deadline of
+ctx and aborted when ctx is cancelled.
+*/
+func roundTrip(ctx context.Context, nc net.Conn, rd *bufio.Reader, args ...string) (any, error) {
+	deadline, _ := ctx.Deadline()
+	nc.SetDeadline(deadline)
+
+	stop := context.AfterFunc(ctx, func() { nc.SetDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := nc.Write(encodeCommand(args...)); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	reply, err := readReply(rd)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	return reply, nil
+}
+
+/*
+contextError prefers the context's error over the network error it caused.
+*/
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+/*
+encodeCommand encodes args as a RESP array of bulk strings.
+*/
+func encodeCommand(args ...string) []byte {
+	buf := fmt.Appendf(nil, "*%d\r\n", len(args))
+
+	for _, arg := range args {
+		buf = fmt.Appendf(buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	return buf
+}
+
+/*
+replyError is an error reply sent by the server, as opposed to a failure
+of the connection.
+*/
+type replyError string
+
+func (err replyError) Error() string {
+	return "redis: " + string(err)
+}
+
+/*
+readReply reads a single RESP reply.
+*/
+func readReply(rd *bufio.Reader) (any, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, replyError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+
+		if size < 0 {
+			return nil, ErrNil
+		}
+
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(rd, data); err != nil {
+			return nil, err
+		}
+
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+
+		if count < 0 {
+			return nil, ErrNil
+		}
+
+		items := make([]any, count)
+
+		for i := range items {
+			// A nil element is a value in an array, not a failed reply.
+			if items[i], err = readReply(rd); err != nil && !errors.Is(err, ErrNil) {
+				return nil, err
+			}
+		}
+
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReadReply(t *testing.T) {
+	Convey("Given RESP replies", t, func() {
+		read := func(raw string) (any, error) {
+			return readReply(bufio.NewReader(strings.NewReader(raw)))
+		}
+
+		Convey("Then simple strings, integers and bulk strings should be decoded", func() {
+			reply, err := read("+OK\r\n")
+			So(err, ShouldBeNil)
+			So(reply, ShouldEqual, "OK")
+
+			reply, err = read(":42\r\n")
+			So(err, ShouldBeNil)
+			So(reply, ShouldEqual, int64(42))
+
+			reply, err = read("$12\r\nhello\r\nworld\r\n")
+			So(err, ShouldBeNil)
+			So(string(reply.([]byte)), ShouldEqual, "hello\r\nworld")
+		})
+
+		Convey("Then a nil bulk string should be ErrNil", func() {
+			_, err := read("$-1\r\n")
+			So(err, ShouldEqual, ErrNil)
+		})
+
+		Convey("Then an error reply should be returned as an error", func() {
+			_, err := read("-WRONGPASS invalid password\r\n")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "redis: WRONGPASS invalid password")
+		})
+
+		Convey("Then a pushed message should be decoded as an array", func() {
+			reply, err := read("*3\r\n$7\r\nmessage\r\n$4\r\nchan\r\n$2\r\n{}\r\n")
+			So(err, ShouldBeNil)
+
+			push := reply.([]any)
+			So(push, ShouldHaveLength, 3)
+			So(string(push[0].([]byte)), ShouldEqual, "message")
+			So(string(push[2].([]byte)), ShouldEqual, "{}")
+		})
+	})
+
+	Convey("Given a command", t, func() {
+		Convey("Then it should be encoded as an array of bulk strings", func() {
+			So(
+				string(encodeCommand("SET", "key", "a b")), ShouldEqual,
+				"*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$3\r\na b\r\n",
+			)
+		})
+	})
+}
@@ -0,0 +1,210 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+/*
+keyPrefix namespaces the task keys, and the channels their updates are
+published on, from anything else in the database.
+*/
+const keyPrefix = "a2a:task:"
+
+/*
+RedisTaskStore provides a Redis implementation of the TaskStore interface.
+Each task is stored as JSON under a key made of the optional prefixes
+(typically the agent name) and its ID, and every write is published on a
+channel of the same name, so subscribers in any process receive it.
+*/
+type RedisTaskStore struct {
+	conn *Conn
+}
+
+/*
+NewRedisTaskStore creates a task store backed by the Redis server at addr,
+using the database db.
+*/
+func NewRedisTaskStore(addr, password string, db int) *RedisTaskStore {
+	return &RedisTaskStore{conn: NewConn(addr, password, db)}
+}
+
+/*
+Get retrieves a task by its prefixed ID. A positive historyLength trims the
+history to that many of the most recent messages.
+*/
+func (store *RedisTaskStore) Get(
+	ctx context.Context, prefix string, historyLength int,
+) ([]a2a.Task, *errors.RpcError) {
+	reply, err := store.conn.Do(ctx, "GET", keyPrefix+prefix)
+
+	if stderrors.Is(err, ErrNil) {
+		return nil, errors.ErrTaskNotFound
+	}
+
+	if err != nil {
+		log.Error("failed to get task", "error", err)
+		return nil, errors.ErrInternal.WithMessagef("failed to get task: %v", err)
+	}
+
+	data, _ := reply.([]byte)
+
+	var task a2a.Task
+
+	if err := json.Unmarshal(data, &task); err != nil {
+		log.Error("failed to unmarshal task", "error", err)
+		return nil, errors.ErrInternal.WithMessagef("failed to unmarshal task: %v", err)
+	}
+
+	if historyLength > 0 && len(task.History) > historyLength {
+		task.History = task.History[len(task.History)-historyLength:]
+	}
+
+	return []a2a.Task{task}, nil
+}
+
+/*
+Subscribe delivers every subsequent write of the task to ch, until ctx is
+done. The channel is not closed, as it belongs to the caller.
+*/
+func (store *RedisTaskStore) Subscribe(
+	ctx context.Context, prefix string, ch chan a2a.Task,
+) *errors.RpcError {
+	messages, err := store.conn.Subscribe(ctx, keyPrefix+prefix)
+
+	if err != nil {
+		log.Error("failed to subscribe to task", "error", err)
+		return errors.ErrInternal.WithMessagef("failed to subscribe to task: %v", err)
+	}
+
+	go func() {
+		for data := range messages {
+			var task a2a.Task
+
+			if err := json.Unmarshal(data, &task); err != nil {
+				log.Error("failed to unmarshal task update", "error", err)
+				continue
+			}
+
+			select {
+			case ch <- task:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+/*
+Create stores a new task in Redis.
+*/
+func (store *RedisTaskStore) Create(
+	ctx context.Context, task *a2a.Task, optionals ...string,
+) *errors.RpcError {
+	if err := store.put(ctx, task, optionals...); err != nil {
+		log.Error("failed to store task", "error", err, "task", task.ID)
+		return errors.ErrInternal.WithMessagef("failed to store task: %v", err)
+	}
+
+	return nil
+}
+
+/*
+Update replaces the stored task, merging onto the metadata of the stored
+version to keep keys this update does not carry.
+*/
+func (store *RedisTaskStore) Update(
+	ctx context.Context, task *a2a.Task, optionals ...string,
+) *errors.RpcError {
+	if previous, rpcErr := store.Get(ctx, taskKey(task, optionals...), 0); rpcErr == nil {
+		task.Metadata = a2a.MergeMetadata(
+			a2a.MergeMetadata(nil, previous[0].Metadata), task.Metadata,
+		)
+	}
+
+	if err := store.put(ctx, task, optionals...); err != nil {
+		log.Error("failed to update task", "error", err, "task", task.ID)
+		return errors.ErrInternal.WithMessagef("failed to update task: %v", err)
+	}
+
+	return nil
+}
+
+/*
+Delete removes a task from Redis.
+*/
+func (store *RedisTaskStore) Delete(ctx context.Context, prefix string) *errors.RpcError {
+	if _, err := store.conn.Do(ctx, "DEL", keyPrefix+prefix); err != nil {
+		log.Error("failed to delete task", "error", err)
+		return errors.ErrInternal.WithMessagef("failed to delete task: %v", err)
+	}
+
+	return nil
+}
+
+/*
+Cancel marks a task as cancelled.
+*/
+func (store *RedisTaskStore) Cancel(ctx context.Context, prefix string) *errors.RpcError {
+	tasks, rpcErr := store.Get(ctx, prefix, 0)
+
+	if rpcErr != nil {
+		log.Error("failed to get task", "error", rpcErr)
+		return rpcErr
+	}
+
+	task := tasks[0]
+	task.ToStatus(a2a.TaskStateCanceled, nil)
+
+	var optionals []string
+
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		optionals = strings.Split(prefix[:i], "/")
+	}
+
+	return store.Update(ctx, &task, optionals...)
+}
+
+/*
+Close closes the connection to Redis.
+*/
+func (store *RedisTaskStore) Close() error {
+	return store.conn.Close()
+}
+
+/*
+put stores the task and publishes it to its subscribers.
+*/
+func (store *RedisTaskStore) put(
+	ctx context.Context, task *a2a.Task, optionals ...string,
+) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	key := keyPrefix + taskKey(task, optionals...)
+
+	if _, err := store.conn.Do(ctx, "SET", key, string(data)); err != nil {
+		return err
+	}
+
+	_, err = store.conn.Do(ctx, "PUBLISH", key, string(data))
+	return err
+}
+
+/*
+taskKey joins the optional prefixes and the task ID the way the task
+manager addresses tasks, e.g. "agent/id".
+*/
+func taskKey(task *a2a.Task, optionals ...string) string {
+	return strings.Join(append(append([]string{}, optionals...), task.ID), "/")
+}
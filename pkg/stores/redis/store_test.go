@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/errors"
+)
+
+/*
+newTestStore returns a store on the Redis server at REDIS_ADDR, skipping
+the test when it is not set.
+*/
+func newTestStore(t *testing.T) *RedisTaskStore {
+	addr := os.Getenv("REDIS_ADDR")
+
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set; skipping Redis integration test")
+	}
+
+	store := NewRedisTaskStore(addr, os.Getenv("REDIS_PASSWORD"), 0)
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func TestRedisTaskStore(t *testing.T) {
+	store := newTestStore(t)
+
+	Convey("Given a task with a history", t, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		task := a2a.NewTask("tester")
+		task.AddMessage("user", "u", "first")
+		task.AddMessage("assistant", "a", "second")
+		task.AddMessage("user", "u", "third")
+		task.Metadata = map[string]any{"origin": "test"}
+
+		prefix := "tester/" + task.ID
+		defer store.Delete(ctx, prefix)
+
+		So(store.Create(ctx, task, "tester"), ShouldBeNil)
+
+		Convey("When it is retrieved with a history length", func() {
+			tasks, err := store.Get(ctx, prefix, 2)
+
+			Convey("Then only the most recent messages should be returned", func() {
+				So(err, ShouldBeNil)
+				So(tasks, ShouldHaveLength, 1)
+				So(tasks[0].History, ShouldHaveLength, 2)
+				So(tasks[0].History[1].String(), ShouldEqual, "third")
+			})
+		})
+
+		Convey("When it is updated without its metadata", func() {
+			update := *task
+			update.Metadata = map[string]any{"step": float64(2)}
+			So(store.Update(ctx, &update, "tester"), ShouldBeNil)
+
+			Convey("Then the stored metadata should be merged", func() {
+				tasks, err := store.Get(ctx, prefix, 0)
+				So(err, ShouldBeNil)
+				So(tasks[0].Metadata["origin"], ShouldEqual, "test")
+				So(tasks[0].Metadata["step"], ShouldEqual, 2)
+			})
+		})
+
+		Convey("When a subscriber is listening and the task is cancelled", func() {
+			updates := make(chan a2a.Task, 1)
+			So(store.Subscribe(ctx, prefix, updates), ShouldBeNil)
+			So(store.Cancel(ctx, prefix), ShouldBeNil)
+
+			Convey("Then the subscriber should receive the cancelled task", func() {
+				select {
+				case update := <-updates:
+					So(update.ID, ShouldEqual, task.ID)
+					So(update.Status.State, ShouldEqual, a2a.TaskStateCanceled)
+				case <-ctx.Done():
+					So(ctx.Err(), ShouldBeNil)
+				}
+			})
+		})
+
+		Convey("When it is deleted", func() {
+			So(store.Delete(ctx, prefix), ShouldBeNil)
+
+			Convey("Then it should no longer be found", func() {
+				_, err := store.Get(ctx, prefix, 0)
+				So(err, ShouldEqual, errors.ErrTaskNotFound)
+			})
+		})
+	})
+}
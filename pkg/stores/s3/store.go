@@ -19,15 +19,28 @@ It uses an S3 connection to store and retrieve task data.
 */
 type Store struct {
 	conn *Conn
+	mu   sync.Mutex
 	// subscriptions tracks active subscriptions by task ID
-	subscriptions sync.Map
+	subscriptions map[string][]subscription
+}
+
+/*
+subscription is a channel that receives the writes of a task until its
+context is done. Writes wait in pending, which holds only the latest one, so
+a subscriber that reads slowly skips to the newest task instead of holding
+up the writer.
+*/
+type subscription struct {
+	ctx     context.Context
+	ch      chan a2a.Task
+	pending chan []byte
 }
 
 /*
 NewStore creates a new S3-based task store with the given connection.
 */
 func NewStore(conn *Conn) *Store {
-	return &Store{conn: conn}
+	return &Store{conn: conn, subscriptions: make(map[string][]subscription)}
 }
 
 /*
@@ -54,22 +67,90 @@ func (store *Store) Get(
 }
 
 /*
-Subscribe sets up a channel to receive task updates from S3.
+Subscribe delivers every subsequent write of the task to ch, until ctx is
+done. The channel is not closed, as it belongs to the caller. A subscriber
+that falls behind receives the latest write and misses the ones before it.
 */
 func (store *Store) Subscribe(
 	ctx context.Context, prefix string, ch chan a2a.Task,
 ) *errors.RpcError {
-	actual, loaded := store.subscriptions.LoadOrStore(prefix, []chan a2a.Task{ch})
+	sub := subscription{ctx: ctx, ch: ch, pending: make(chan []byte, 1)}
 
-	if loaded {
-		subscribers := actual.([]chan a2a.Task)
-		subscribers = append(subscribers, ch)
-		store.subscriptions.Store(prefix, subscribers)
-	}
+	store.mu.Lock()
+	store.subscriptions[prefix] = append(store.subscriptions[prefix], sub)
+	store.mu.Unlock()
+
+	go func() {
+		defer store.unsubscribe(prefix, ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data := <-sub.pending:
+				// Every subscriber decodes its own copy, so none of them
+				// shares state with the writer.
+				var task a2a.Task
+
+				if err := json.Unmarshal(data, &task); err != nil {
+					log.Error("failed to decode task update", "error", err)
+					continue
+				}
+
+				select {
+				case ch <- task:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
 
 	return nil
 }
 
+/*
+unsubscribe removes the subscription of ch from the prefix.
+*/
+func (store *Store) unsubscribe(prefix string, ch chan a2a.Task) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	subscribers := store.subscriptions[prefix]
+
+	for i, candidate := range subscribers {
+		if candidate.ch == ch {
+			subscribers = append(subscribers[:i:i], subscribers[i+1:]...)
+			break
+		}
+	}
+
+	if len(subscribers) == 0 {
+		delete(store.subscriptions, prefix)
+	} else {
+		store.subscriptions[prefix] = subscribers
+	}
+}
+
+/*
+publish hands a written task to the subscribers of its prefix without
+waiting on any of them. A write still pending for a subscriber is replaced,
+as the stored task is complete and the newer one supersedes it.
+*/
+func (store *Store) publish(prefix string, data []byte) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, sub := range store.subscriptions[prefix] {
+		select {
+		case <-sub.pending:
+		default:
+		}
+
+		sub.pending <- data
+	}
+}
+
 /*
 Create stores a new task in S3.
 */
@@ -86,6 +167,8 @@ func (store *Store) Create(ctx context.Context, task *a2a.Task, optionals ...str
 		return errors.ErrInternal.WithMessagef("failed to store task: %v", err)
 	}
 
+	store.publish(strings.Join(append(append([]string{}, optionals...), task.ID), "/"), data)
+
 	return nil
 }
 
@@ -93,6 +176,24 @@ func (store *Store) Create(ctx context.Context, task *a2a.Task, optionals ...str
 Update modifies an existing task in S3.
 */
 func (store *Store) Update(ctx context.Context, task *a2a.Task, optionals ...string) *errors.RpcError {
+	// Updates are append-only, so merge onto the metadata of the latest
+	// stored version to keep keys this update does not carry.
+	if previous, rpcErr := store.Get(
+		ctx, strings.Join(append(append([]string{}, optionals...), task.ID), "/"), 0,
+	); rpcErr == nil && len(previous) > 0 {
+		latest := previous[0]
+
+		for _, candidate := range previous[1:] {
+			if candidate.Status.Timestamp.After(latest.Status.Timestamp) {
+				latest = candidate
+			}
+		}
+
+		task.Metadata = a2a.MergeMetadata(
+			a2a.MergeMetadata(nil, latest.Metadata), task.Metadata,
+		)
+	}
+
 	data, err := json.Marshal(task)
 	if err != nil {
 		log.Error("failed to marshal task", "error", err)
@@ -104,6 +205,8 @@ func (store *Store) Update(ctx context.Context, task *a2a.Task, optionals ...str
 		return errors.ErrInternal.WithMessagef("failed to update task: %v", err)
 	}
 
+	store.publish(strings.Join(append(append([]string{}, optionals...), task.ID), "/"), data)
+
 	return nil
 }
 
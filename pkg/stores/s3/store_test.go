@@ -0,0 +1,68 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+)
+
+func TestStorePublish(t *testing.T) {
+	Convey("Given a subscriber that does not read", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		store := NewStore(nil)
+		updates := make(chan a2a.Task)
+		So(store.Subscribe(ctx, "tester/task-1", updates), ShouldBeNil)
+
+		Convey("When the task is written several times", func() {
+			var writes [][]byte
+
+			for _, state := range []a2a.TaskState{
+				a2a.TaskStateWorking, a2a.TaskStateInputReq, a2a.TaskStateCompleted,
+			} {
+				data, err := json.Marshal(a2a.Task{ID: "task-1", Status: a2a.TaskStatus{State: state}})
+				So(err, ShouldBeNil)
+
+				writes = append(writes, data)
+			}
+
+			published := make(chan struct{})
+
+			go func() {
+				defer close(published)
+
+				for _, data := range writes {
+					store.publish("tester/task-1", data)
+				}
+			}()
+
+			Convey("Then the writes should not wait for the subscriber", func() {
+				select {
+				case <-published:
+				case <-time.After(time.Second):
+					t.Fatal("publish blocked on a slow subscriber")
+				}
+
+				Convey("And the subscriber should catch up to the latest task", func() {
+					deadline := time.After(time.Second)
+
+					for {
+						select {
+						case task := <-updates:
+							if task.Status.State == a2a.TaskStateCompleted {
+								return
+							}
+						case <-deadline:
+							t.Fatal("the latest task never reached the subscriber")
+						}
+					}
+				})
+			})
+		})
+	})
+}
@@ -163,11 +163,31 @@ type AzureSearchWorkItemsTool struct {
 }
 
 func NewAzureSearchWorkItemsTool() *mcp.Tool {
-	// This will need to be customized based on the actual tool parameters
 	tool := mcp.NewTool(
-		"azure_SearchWorkItems",
-		mcp.WithDescription("Azure DevOps SearchWorkItems tool"),
-		// TODO: Add specific parameters for this tool
+		"azure_search_work_items",
+		mcp.WithDescription("Search for work items in Azure DevOps by keywords, with optional type and state filters."),
+		mcp.WithString(
+			"search_term",
+			mcp.Required(),
+			mcp.Description("The keyword or phrase to search for in work item titles, descriptions, and tags."),
+		),
+		mcp.WithString(
+			"work_item_types",
+			mcp.Description("Optional. Comma-separated list of work item types to filter by (e.g., 'User Story,Bug')."),
+		),
+		mcp.WithString(
+			"states",
+			mcp.Description("Optional. Comma-separated list of states to filter by (e.g., 'Active,Resolved')."),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Optional. Maximum number of items to return (default: 50)."),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Response format: 'text' (default) or 'json'."),
+			mcp.Enum("text", "json"),
+		),
 	)
 
 	return &tool
@@ -176,7 +196,14 @@ func NewAzureSearchWorkItemsTool() *mcp.Tool {
 func (at *AzureSearchWorkItemsTool) Handle(
 	ctx context.Context, req mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	log.Info("azure_SearchWorkItems tool executing")
+	log.Info("azure_search_work_items tool executing")
+
+	args, errResult := searchWorkItemsArgs(req.GetArguments())
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	req.Params.Arguments = args
 
 	// Get Azure DevOps configuration from environment
 	orgName := os.Getenv("AZURE_DEVOPS_ORG")
@@ -207,6 +234,51 @@ func (at *AzureSearchWorkItemsTool) Handle(
 	return azureTool.Handler(ctx, req)
 }
 
+/*
+searchWorkItemsArgs validates the arguments of azure_search_work_items and
+converts them to the strings the Azure DevOps tool reads, as the limit
+arrives as a JSON number.
+*/
+func searchWorkItemsArgs(arguments map[string]any) (map[string]any, *mcp.CallToolResult) {
+	searchTerm, _ := arguments["search_term"].(string)
+
+	if strings.TrimSpace(searchTerm) == "" {
+		return nil, mcp.NewToolResultError("Missing required parameter: search_term")
+	}
+
+	args := map[string]any{"search_term": searchTerm}
+
+	for _, key := range []string{"work_item_types", "states", "format"} {
+		if value, ok := arguments[key].(string); ok && value != "" {
+			args[key] = value
+		}
+	}
+
+	if format, ok := args["format"].(string); ok && format != "text" && format != "json" {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid format %q: must be 'text' or 'json'", format))
+	}
+
+	switch limit := arguments["limit"].(type) {
+	case nil:
+	case float64:
+		if limit < 1 || limit != float64(int(limit)) {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid limit %v: must be a positive whole number", limit))
+		}
+
+		args["limit"] = strconv.Itoa(int(limit))
+	case string:
+		if n, err := strconv.Atoi(limit); err != nil || n < 1 {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid limit %q: must be a positive whole number", limit))
+		}
+
+		args["limit"] = limit
+	default:
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid limit %v: must be a positive whole number", limit))
+	}
+
+	return args, nil
+}
+
 type AzureSprintItemsTool struct {
 	tool *mcp.Tool
 }
@@ -359,11 +431,34 @@ type AzureGetWorkItemsTool struct {
 }
 
 func NewAzureGetWorkItemsTool() *mcp.Tool {
-	// This will need to be customized based on the actual tool parameters
 	tool := mcp.NewTool(
-		"azure_GetWorkItems",
-		mcp.WithDescription("Azure DevOps GetWorkItems tool"),
-		// TODO: Add specific parameters for this tool
+		"azure_get_work_items",
+		mcp.WithDescription("Get detailed information about one or more work items in Azure DevOps, including fields, tags, relations, and comments."),
+		mcp.WithString(
+			"ids",
+			mcp.Required(),
+			mcp.Description("Work item IDs, comma-separated (e.g., '123,456,789') or as a JSON array (e.g., '[123,456,789]')."),
+		),
+		mcp.WithArray(
+			"fields",
+			mcp.Description("Optional. Field reference names to return (e.g., ['System.Title','System.State']). Default: all fields with relations, common fields without."),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString(
+			"expand",
+			mcp.Description("Whether to include relations (parent, child, related): 'relations' (default) or 'none'."),
+			mcp.Enum("relations", "none"),
+		),
+		mcp.WithString(
+			"include_comments",
+			mcp.Description("Whether to include comments. Default: true"),
+			mcp.Enum("true", "false"),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Response format: 'text' (default) or 'json'."),
+			mcp.Enum("text", "json"),
+		),
 	)
 
 	return &tool
@@ -372,7 +467,11 @@ func NewAzureGetWorkItemsTool() *mcp.Tool {
 func (at *AzureGetWorkItemsTool) Handle(
 	ctx context.Context, req mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	log.Info("azure_GetWorkItems tool executing")
+	log.Info("azure_get_work_items tool executing")
+
+	if _, err := azuretools.ParseWorkItemIDs(req.GetArguments()["ids"]); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid ids parameter: %v", err)), nil
+	}
 
 	// Get Azure DevOps configuration from environment
 	orgName := os.Getenv("AZURE_DEVOPS_ORG")
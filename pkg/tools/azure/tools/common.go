@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -102,6 +103,61 @@ func ParseIDs(idsStr string) ([]int, error) {
 	return ids, nil
 }
 
+// ParseWorkItemIDs reads work item IDs given as a comma-separated string, a
+// JSON array in a string, or an array of numbers. Duplicates are dropped.
+func ParseWorkItemIDs(value any) ([]int, error) {
+	var ids []int
+
+	switch raw := value.(type) {
+	case nil:
+		return nil, fmt.Errorf("missing IDs")
+	case string:
+		trimmed := strings.TrimSpace(raw)
+
+		if strings.HasPrefix(trimmed, "[") {
+			if err := json.Unmarshal([]byte(trimmed), &ids); err != nil {
+				return nil, fmt.Errorf("invalid JSON array of IDs: %s", trimmed)
+			}
+			break
+		}
+
+		parsed, err := ParseIDs(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		ids = parsed
+	case []any:
+		for _, item := range raw {
+			id, ok := item.(float64)
+			if !ok || id != float64(int(id)) {
+				return nil, fmt.Errorf("invalid ID: %v", item)
+			}
+			ids = append(ids, int(id))
+		}
+	default:
+		return nil, fmt.Errorf("expected comma-separated IDs or an array of them")
+	}
+
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+
+	for _, id := range ids {
+		if id < 1 {
+			return nil, fmt.Errorf("invalid ID: %d", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	if len(unique) == 0 {
+		return nil, fmt.Errorf("no IDs given")
+	}
+
+	return unique, nil
+}
+
 // Format a list of strings for WIQL queries
 func FormatStringList(items []string) string {
 	quoted := make([]string, len(items))
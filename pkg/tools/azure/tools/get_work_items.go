@@ -41,6 +41,20 @@ type DetailedWorkItemOutput struct {
 	// Tags      string `json:"-"` // Already defined above for JSON output as well
 }
 
+// maxWorkItemsPerRequest is the most work items the Azure DevOps API returns
+// for a single GetWorkItems or GetCommentsBatch request.
+const maxWorkItemsPerRequest = 200
+
+// defaultWorkItemFields are fetched when no relations are expanded and no
+// fields were asked for.
+var defaultWorkItemFields = []string{
+	"System.Id", "System.Title", "System.WorkItemType", "System.State",
+	"System.AssignedTo", "System.IterationPath", "System.AreaPath",
+	"System.Description", "System.Tags", "System.CreatedDate", "System.CreatedBy",
+	"System.ChangedDate", "System.ChangedBy", "Microsoft.VSTS.Common.Priority",
+	"Microsoft.VSTS.Common.Severity",
+}
+
 // AzureGetWorkItemsTool provides functionality to get work item details
 type AzureGetWorkItemsTool struct { // Renamed from AzureGetWorkItemTool
 	handle mcp.Tool
@@ -56,23 +70,32 @@ func NewAzureGetWorkItemsTool(conn *azuredevops.Connection, config AzureDevOpsCo
 		return nil
 	}
 
-	tool := &AzureGetWorkItemsTool{ // Renamed
+	return &AzureGetWorkItemsTool{ // Renamed
+		handle: newGetWorkItemsHandle(),
 		client: client,
 		config: config,
 	}
+}
 
-	tool.handle = mcp.NewTool(
+// newGetWorkItemsHandle defines the azure_get_work_items tool.
+func newGetWorkItemsHandle() mcp.Tool {
+	return mcp.NewTool(
 		"azure_get_work_items",
 		mcp.WithDescription("Get detailed information about one or more work items in Azure DevOps, including fields, tags, relations, and comments."),
 		mcp.WithString(
 			"ids",
 			mcp.Required(),
-			mcp.Description("Comma-separated list of work item IDs (e.g., '123,456,789')."),
+			mcp.Description("Work item IDs, comma-separated (e.g., '123,456,789') or as a JSON array (e.g., '[123,456,789]')."),
+		),
+		mcp.WithArray(
+			"fields",
+			mcp.Description("Optional. Field reference names to return (e.g., ['System.Title','System.State']). Default: all fields with relations, common fields without."),
+			mcp.Items(map[string]any{"type": "string"}),
 		),
 		mcp.WithString(
-			"include_relations",
-			mcp.Description("Whether to include relations (parent, child, related). Default: true"),
-			mcp.Enum("true", "false"),
+			"expand",
+			mcp.Description("Whether to include relations (parent, child, related): 'relations' (default) or 'none'."),
+			mcp.Enum("relations", "none"),
 		),
 		mcp.WithString(
 			"include_comments",
@@ -85,8 +108,6 @@ func NewAzureGetWorkItemsTool(conn *azuredevops.Connection, config AzureDevOpsCo
 			mcp.Enum("text", "json"),
 		),
 	)
-
-	return tool
 }
 
 func (tool *AzureGetWorkItemsTool) Handle() mcp.Tool {
@@ -164,104 +185,56 @@ func intSliceToStringSlice(intSlice []int) []string {
 }
 
 func (tool *AzureGetWorkItemsTool) Handler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	idsStr, err := GetStringArg(request, "ids")
+	parsedIDs, err := ParseWorkItemIDs(request.GetArguments()["ids"])
 	if err != nil {
-		return mcp.NewToolResultError("Missing \"ids\" parameter. Provide comma-separated work item IDs."), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid \"ids\" parameter: %v. Provide comma-separated work item IDs or a JSON array of them.", err)), nil
 	}
 
-	parsedIDs, err := ParseIDs(idsStr)
-	if err != nil || len(parsedIDs) == 0 {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid or empty IDs provided: '%s'. Error: %v", idsStr, err)), nil
+	requestedFields, err := parseFieldNames(request.GetArguments()["fields"])
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid \"fields\" parameter: %v", err)), nil
 	}
 
-	includeRelationsStr, _ := GetStringArg(request, "include_relations")
+	expand, _ := GetStringArg(request, "expand")
 	includeCommentsStr, _ := GetStringArg(request, "include_comments")
 	format, _ := GetStringArg(request, "format")
 
-	includeRelations := true // Default to true as per new description
-	if includeRelationsStr == "false" {
-		includeRelations = false
+	if expand != "" && expand != "relations" && expand != "none" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid expand %q: must be 'relations' or 'none'", expand)), nil
 	}
 
+	includeRelations := expand != "none" // Relations are included by default
+
 	includeComments := true // Default to true as per new description
 	if includeCommentsStr == "false" {
 		includeComments = false
 	}
 
-	var finalFieldsToFetch *[]string
-	expandValue := workitemtracking.WorkItemExpandValues.None
-
-	if includeRelations {
-		expandValue = workitemtracking.WorkItemExpandValues.All // Fetch all, including relations and links
-		finalFieldsToFetch = nil                                // Do not specify fields when expand is All
-	} else {
-		// When not expanding relations, explicitly fetch desired fields.
-		// Expand.Fields might be an option if only fields are needed without relations.
-		// However, to be safe and explicit, using Expand.None and specifying fields is clearer.
-		expandValue = workitemtracking.WorkItemExpandValues.None
-		fieldsToFetch := &[]string{
-			"System.Id", "System.Title", "System.WorkItemType", "System.State",
-			"System.AssignedTo", "System.IterationPath", "System.AreaPath",
-			"System.Description", "System.Tags", "System.CreatedDate", "System.CreatedBy",
-			"System.ChangedDate", "System.ChangedBy", "Microsoft.VSTS.Common.Priority",
-			"Microsoft.VSTS.Common.Severity",
-		}
-		finalFieldsToFetch = fieldsToFetch
-	}
-
-	workItems, err := tool.client.GetWorkItems(ctx, workitemtracking.GetWorkItemsArgs{
-		Ids:     &parsedIDs,
-		Project: &tool.config.Project,
-		Expand:  &expandValue,
-		Fields:  finalFieldsToFetch,
-	})
-
+	workItems, err := tool.getWorkItems(ctx, parsedIDs, requestedFields, includeRelations)
 	if err != nil {
 		return HandleError(err, "Failed to get work items"), nil
 	}
 
-	if workItems == nil || len(*workItems) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No work items found for IDs: %s", idsStr)), nil
+	if len(workItems) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No work items found for IDs: %s", strings.Join(intSliceToStringSlice(parsedIDs), ","))), nil
 	}
 
 	commentsMap := make(map[int][]string)
 	if includeComments {
-		// GetCommentsBatch API might be limited or less efficient for many items.
-		// The workitem expand might already bring some comment data or latest N comments if API supports.
-		// For now, using GetCommentsBatch as it was.
-		commentsBatchResult, err := tool.client.GetCommentsBatch(ctx, workitemtracking.GetCommentsBatchArgs{
-			Project: &tool.config.Project,
-			Ids:     &parsedIDs, // Max 200 IDs for GetCommentsBatch
-		})
-		if err != nil {
-			// Log error but continue, comments might be missing for some.
-			fmt.Printf("Warning: Failed to get comments batch: %v\n", err)
-		} else if commentsBatchResult != nil && commentsBatchResult.Comments != nil {
-			for _, comment := range *commentsBatchResult.Comments {
-				if comment.WorkItemId == nil || comment.Text == nil {
-					continue
-				}
-				workItemID := *comment.WorkItemId
-				author := "Unknown" // Default if CreatedBy or DisplayName is nil
-				if comment.CreatedBy != nil && comment.CreatedBy.DisplayName != nil {
-					author = *comment.CreatedBy.DisplayName
-				}
-				dateStr := "Unknown Date"
-				if comment.CreatedDate != nil {
-					dateStr = comment.CreatedDate.Time.Format("2006-01-02 15:04")
-				}
-				formattedComment := fmt.Sprintf("Author: %s | Date: %s\n%s", author, dateStr, *comment.Text)
-				commentsMap[workItemID] = append(commentsMap[workItemID], formattedComment)
-			}
-		}
+		commentsMap = tool.getComments(ctx, parsedIDs)
 	}
 
 	outputItems := []DetailedWorkItemOutput{}
-	for _, item := range *workItems {
+	for _, item := range workItems {
 		if item.Id == nil || item.Fields == nil {
 			continue
 		}
 		fields := *item.Fields
+
+		// Fields cannot be requested alongside relations, so they are selected here.
+		if includeRelations && len(requestedFields) > 0 {
+			fields = selectFields(fields, requestedFields)
+		}
 		id := *item.Id
 
 		outputItem := DetailedWorkItemOutput{
@@ -343,5 +316,137 @@ func (tool *AzureGetWorkItemsTool) Handler(ctx context.Context, request mcp.Call
 	return mcp.NewToolResultText(textString), nil
 }
 
-// Ensure ParseIDs, GetStringArg, HandleError, GetWorkItemURL, ExtractWorkItemIDFromURL
-// are defined in common.go or accessible.
+// getWorkItems fetches the work items in chunks of maxWorkItemsPerRequest,
+// expanding their relations or fetching only the requested fields, as the API
+// does not accept both at once.
+func (tool *AzureGetWorkItemsTool) getWorkItems(
+	ctx context.Context, ids []int, fields []string, includeRelations bool,
+) ([]workitemtracking.WorkItem, error) {
+	expand := workitemtracking.WorkItemExpandValues.None
+	fieldsToFetch := &defaultWorkItemFields
+
+	if includeRelations {
+		expand = workitemtracking.WorkItemExpandValues.Relations
+		fieldsToFetch = nil
+	} else if len(fields) > 0 {
+		fieldsToFetch = &fields
+	}
+
+	var workItems []workitemtracking.WorkItem
+
+	for _, chunk := range chunkIDs(ids, maxWorkItemsPerRequest) {
+		batch, err := tool.client.GetWorkItems(ctx, workitemtracking.GetWorkItemsArgs{
+			Ids:     &chunk,
+			Project: &tool.config.Project,
+			Expand:  &expand,
+			Fields:  fieldsToFetch,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if batch != nil {
+			workItems = append(workItems, *batch...)
+		}
+	}
+
+	return workItems, nil
+}
+
+// getComments fetches the comments of the work items in chunks of
+// maxWorkItemsPerRequest, formatted for output and keyed by work item ID.
+// Failures are logged and leave the comments of that chunk out.
+func (tool *AzureGetWorkItemsTool) getComments(ctx context.Context, ids []int) map[int][]string {
+	commentsMap := make(map[int][]string)
+
+	for _, chunk := range chunkIDs(ids, maxWorkItemsPerRequest) {
+		commentsBatchResult, err := tool.client.GetCommentsBatch(ctx, workitemtracking.GetCommentsBatchArgs{
+			Project: &tool.config.Project,
+			Ids:     &chunk,
+		})
+		if err != nil {
+			// Log error but continue, comments might be missing for some.
+			fmt.Printf("Warning: Failed to get comments batch: %v\n", err)
+			continue
+		}
+
+		if commentsBatchResult == nil || commentsBatchResult.Comments == nil {
+			continue
+		}
+
+		for _, comment := range *commentsBatchResult.Comments {
+			if comment.WorkItemId == nil || comment.Text == nil {
+				continue
+			}
+			workItemID := *comment.WorkItemId
+			author := "Unknown" // Default if CreatedBy or DisplayName is nil
+			if comment.CreatedBy != nil && comment.CreatedBy.DisplayName != nil {
+				author = *comment.CreatedBy.DisplayName
+			}
+			dateStr := "Unknown Date"
+			if comment.CreatedDate != nil {
+				dateStr = comment.CreatedDate.Time.Format("2006-01-02 15:04")
+			}
+			formattedComment := fmt.Sprintf("Author: %s | Date: %s\n%s", author, dateStr, *comment.Text)
+			commentsMap[workItemID] = append(commentsMap[workItemID], formattedComment)
+		}
+	}
+
+	return commentsMap
+}
+
+// chunkIDs splits IDs into consecutive chunks of at most size IDs.
+func chunkIDs(ids []int, size int) [][]int {
+	var chunks [][]int
+
+	for start := 0; start < len(ids); start += size {
+		chunks = append(chunks, ids[start:Min(start+size, len(ids))])
+	}
+
+	return chunks
+}
+
+// parseFieldNames reads field reference names given as an array or as a
+// comma-separated string.
+func parseFieldNames(value any) ([]string, error) {
+	var names []string
+
+	switch fields := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		names = strings.Split(fields, ",")
+	case []any:
+		for _, field := range fields {
+			name, ok := field.(string)
+			if !ok {
+				return nil, fmt.Errorf("field %v is not a string", field)
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, fmt.Errorf("expected an array of field reference names")
+	}
+
+	var out []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, name)
+		}
+	}
+
+	return out, nil
+}
+
+// selectFields keeps only the named fields of a work item.
+func selectFields(fields map[string]any, names []string) map[string]any {
+	selected := make(map[string]any, len(names))
+
+	for _, name := range names {
+		if value, ok := fields[name]; ok {
+			selected[name] = value
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// stubWorkItemsClient serves a work item for every requested ID and records
+// the requests. Calls to any other method of the client panic.
+type stubWorkItemsClient struct {
+	workitemtracking.Client
+	requests []workitemtracking.GetWorkItemsArgs
+}
+
+func (m *stubWorkItemsClient) GetWorkItems(ctx context.Context, args workitemtracking.GetWorkItemsArgs) (*[]workitemtracking.WorkItem, error) {
+	m.requests = append(m.requests, args)
+
+	items := make([]workitemtracking.WorkItem, 0, len(*args.Ids))
+	for _, id := range *args.Ids {
+		items = append(items, workitemtracking.WorkItem{
+			Id: intPtr(id),
+			Fields: &map[string]interface{}{
+				"System.Title": fmt.Sprintf("Work item %d", id),
+				"System.State": "Active",
+			},
+		})
+	}
+
+	return &items, nil
+}
+
+func (m *stubWorkItemsClient) GetCommentsBatch(ctx context.Context, args workitemtracking.GetCommentsBatchArgs) (*workitemtracking.CommentList, error) {
+	return &workitemtracking.CommentList{}, nil
+}
+
+func TestAzureGetWorkItemsTool(t *testing.T) {
+	Convey("Given the azure_get_work_items tool", t, func() {
+		handle := newGetWorkItemsHandle()
+
+		Convey("Then its input schema should expose ids, fields, expand and format", func() {
+			So(handle.Name, ShouldEqual, "azure_get_work_items")
+			So(handle.InputSchema.Required, ShouldResemble, []string{"ids"})
+
+			for _, property := range []string{"ids", "fields", "expand", "format"} {
+				So(handle.InputSchema.Properties, ShouldContainKey, property)
+			}
+
+			So(handle.InputSchema.Properties["fields"].(map[string]any)["type"], ShouldEqual, "array")
+			So(handle.InputSchema.Properties["expand"].(map[string]any)["enum"], ShouldResemble, []string{"relations", "none"})
+		})
+
+		Convey("When IDs are split into chunks", func() {
+			ids := make([]int, 450)
+			for i := range ids {
+				ids[i] = i + 1
+			}
+
+			chunks := chunkIDs(ids, maxWorkItemsPerRequest)
+
+			Convey("Then no chunk should exceed the API limit", func() {
+				So(chunks, ShouldHaveLength, 3)
+				So(chunks[0], ShouldHaveLength, 200)
+				So(chunks[1], ShouldHaveLength, 200)
+				So(chunks[2], ShouldResemble, ids[400:])
+			})
+		})
+
+		Convey("When more work items are requested than the API returns at once", func() {
+			client := &stubWorkItemsClient{}
+			config := createTestConfig()
+			config.OrganizationURL = "https://dev.azure.com/test-org"
+			tool := &AzureGetWorkItemsTool{handle: handle, client: client, config: config}
+
+			ids := make([]any, 450)
+			for i := range ids {
+				ids[i] = float64(i + 1)
+			}
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{
+				"ids":              ids,
+				"fields":           []any{"System.Title"},
+				"expand":           "none",
+				"include_comments": "false",
+				"format":           "json",
+			}
+
+			result, err := tool.Handler(context.Background(), req)
+			So(err, ShouldBeNil)
+			So(result.IsError, ShouldBeFalse)
+
+			Convey("Then they should be fetched in chunks with the requested fields", func() {
+				So(client.requests, ShouldHaveLength, 3)
+				So(*client.requests[0].Ids, ShouldHaveLength, 200)
+				So(*client.requests[1].Ids, ShouldHaveLength, 200)
+				So(*client.requests[2].Ids, ShouldHaveLength, 50)
+				So(*client.requests[0].Fields, ShouldResemble, []string{"System.Title"})
+				So(*client.requests[0].Expand, ShouldEqual, workitemtracking.WorkItemExpandValues.None)
+			})
+
+			Convey("Then every item should be returned as JSON with its URL", func() {
+				var items []map[string]any
+				So(json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &items), ShouldBeNil)
+				So(items, ShouldHaveLength, 450)
+				So(items[449]["id"], ShouldEqual, float64(450))
+				So(items[449]["url"], ShouldEqual, "https://dev.azure.com/test-org/_workitems/edit/450")
+			})
+		})
+
+		Convey("When relations are expanded together with fields", func() {
+			client := &stubWorkItemsClient{}
+			tool := &AzureGetWorkItemsTool{handle: handle, client: client, config: createTestConfig()}
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{
+				"ids":              "[7]",
+				"fields":           []any{"System.State"},
+				"include_comments": "false",
+				"format":           "json",
+			}
+
+			result, err := tool.Handler(context.Background(), req)
+			So(err, ShouldBeNil)
+
+			Convey("Then the fields should be selected from the expanded items", func() {
+				So(client.requests[0].Fields, ShouldBeNil)
+				So(*client.requests[0].Expand, ShouldEqual, workitemtracking.WorkItemExpandValues.Relations)
+
+				var items []map[string]any
+				So(json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &items), ShouldBeNil)
+				So(items[0]["fields"], ShouldResemble, map[string]any{"System.State": "Active"})
+			})
+		})
+	})
+}
+
+func TestParseWorkItemIDs(t *testing.T) {
+	Convey("Given work item IDs in the forms the tools accept", t, func() {
+		Convey("Then comma-separated IDs should be parsed without duplicates", func() {
+			ids, err := ParseWorkItemIDs("1, 2,2")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []int{1, 2})
+		})
+
+		Convey("Then a JSON array in a string should be parsed", func() {
+			ids, err := ParseWorkItemIDs("[3, 4]")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []int{3, 4})
+		})
+
+		Convey("Then an array of numbers should be parsed", func() {
+			ids, err := ParseWorkItemIDs([]any{float64(5)})
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []int{5})
+		})
+
+		Convey("Then missing, empty or fractional IDs should be rejected", func() {
+			for _, value := range []any{nil, "", "[]", []any{1.5}, "-1"} {
+				_, err := ParseWorkItemIDs(value)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAzureSearchWorkItemsTool(t *testing.T) {
+	Convey("Given the azure_search_work_items tool", t, func() {
+		tool := NewAzureSearchWorkItemsTool()
+
+		Convey("Then its input schema should expose the search parameters", func() {
+			So(tool.Name, ShouldEqual, "azure_search_work_items")
+
+			for _, property := range []string{"search_term", "work_item_types", "states", "limit", "format"} {
+				So(tool.InputSchema.Properties, ShouldContainKey, property)
+			}
+
+			So(tool.InputSchema.Required, ShouldResemble, []string{"search_term"})
+			So(tool.InputSchema.Properties["limit"].(map[string]any)["type"], ShouldEqual, "number")
+			So(tool.InputSchema.Properties["format"].(map[string]any)["enum"], ShouldResemble, []string{"text", "json"})
+		})
+
+		Convey("When it is called without a search term", func() {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"states": "Active"}
+
+			result, err := (&AzureSearchWorkItemsTool{}).Handle(context.Background(), req)
+
+			Convey("Then it should fail with a tool error", func() {
+				So(err, ShouldBeNil)
+				So(result.IsError, ShouldBeTrue)
+				So(result.Content[0].(mcp.TextContent).Text, ShouldContainSubstring, "search_term")
+			})
+		})
+
+		Convey("When its arguments are converted for the Azure DevOps tool", func() {
+			args, errResult := searchWorkItemsArgs(map[string]any{
+				"search_term":     "login",
+				"work_item_types": "Bug",
+				"limit":           float64(10),
+				"format":          "json",
+			})
+
+			Convey("Then they should be passed through as strings", func() {
+				So(errResult, ShouldBeNil)
+				So(args, ShouldResemble, map[string]any{
+					"search_term":     "login",
+					"work_item_types": "Bug",
+					"limit":           "10",
+					"format":          "json",
+				})
+			})
+		})
+
+		Convey("When the limit is not a positive whole number", func() {
+			_, errResult := searchWorkItemsArgs(map[string]any{"search_term": "login", "limit": float64(2.5)})
+
+			Convey("Then it should be rejected", func() {
+				So(errResult, ShouldNotBeNil)
+				So(errResult.IsError, ShouldBeTrue)
+			})
+		})
+	})
+}
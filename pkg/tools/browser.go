@@ -21,6 +21,10 @@ func NewBrowserTool() *mcp.Tool {
 			mcp.Description("The URL to open in the browser"),
 			mcp.Required(),
 		),
+		mcp.WithString("format",
+			mcp.Description("How the page content is returned: clean text, markdown, or the raw html"),
+			mcp.Enum(string(browser.SanitizeText), string(browser.SanitizeMarkdown), string(browser.SanitizeNone)),
+		),
 	)
 
 	return &tool
@@ -35,9 +39,13 @@ func (bt *BrowserTool) Handle(
 ) (*mcp.CallToolResult, error) {
 	log.Info("browser executing")
 
-	browser := browser.NewBrowser()
+	fetcher := browser.NewBrowser()
+
+	format, _ := req.GetArguments()["format"].(string)
 
-	res, err := browser.Fetch(ctx, req.GetArguments()["url"].(string), "", false, "")
+	res, err := fetcher.Fetch(ctx, req.GetArguments()["url"].(string), browser.FetchOptions{
+		Sanitize: browser.SanitizeMode(format),
+	})
 
 	if err != nil {
 		return nil, err
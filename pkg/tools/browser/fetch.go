@@ -4,14 +4,18 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/theapemachine/a2a-go/pkg/tools/netpolicy"
 	"github.com/theapemachine/a2a-go/pkg/vpn"
 )
 
@@ -36,15 +40,17 @@ func NewBrowser() *Browser {
 const maxTextLen = 4 * 1024 // 4 KB cap on extracted text
 
 // Fetch opens pageURL in a headless browser, waits for the load event, and
-// extracts visible text and optionally takes a screenshot.
-// If selector is supplied we only extract that DOM subtree.
-// If takeScreenshot is true, a base64-encoded screenshot will be included in the result.
+// extracts the page content, sanitized to text or markdown or left as raw
+// HTML as the options say, and optionally takes a screenshot.
+// If options.Selector is supplied we only extract that DOM subtree.
+// Hosts the network policy rejects are not fetched (see netpolicy.Check),
+// and neither are the redirects, subresources and script navigations of the
+// page that lead to them.
 // The function is cancellable via ctx.
 func (browser *Browser) Fetch(
 	ctx context.Context,
-	pageURL, selector string,
-	takeScreenshot bool,
-	waitForSelector string,
+	pageURL string,
+	options FetchOptions,
 ) (*Result, error) {
 	log.Info("Fetching page", "pageURL", pageURL)
 	u, err := url.Parse(pageURL)
@@ -57,7 +63,12 @@ func (browser *Browser) Fetch(
 		return nil, errors.New("unsupported URL scheme (allowed: http, https, data)")
 	}
 
+	if err := netpolicy.Check(ctx, pageURL); err != nil {
+		return nil, err
+	}
+
 	launch := launcher.New().Headless(true).Leakless(true)
+	proxied := false
 
 	if vpnConfig := os.Getenv("PROTONVPN_CONFIG"); vpnConfig != "" {
 		vpnClient, err := vpn.NewClient(vpnConfig)
@@ -76,6 +87,7 @@ func (browser *Browser) Fetch(
 		}
 
 		launch = launch.Set("proxy-server", "socks5://"+proxyAddr)
+		proxied = true
 	}
 
 	if deadline, ok := ctx.Deadline(); ok {
@@ -97,26 +109,48 @@ func (browser *Browser) Fetch(
 
 	defer browser.instance.Close()
 
+	guard, err := newPolicyGuard(browser.instance, proxied)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer guard.router.Stop()
+
 	start := time.Now()
 	page := browser.instance.MustPage()
 	if err := page.Navigate(pageURL); err != nil {
+		if blocked := guard.err(); blocked != nil {
+			return nil, blocked
+		}
+
 		return nil, err
 	}
 	page.MustWaitLoad()
 
 	// Wait for specific selector if provided
-	if waitForSelector != "" {
-		page.Timeout(5 * time.Second).MustElement(waitForSelector)
+	if options.WaitForSelector != "" {
+		page.Timeout(5 * time.Second).MustElement(options.WaitForSelector)
 	}
 
-	var txt string
 	var el *rod.Element
-	if selector != "" {
-		el = page.Timeout(2 * time.Second).MustElement(selector)
+	if options.Selector != "" {
+		el = page.Timeout(2 * time.Second).MustElement(options.Selector)
 	} else {
 		el = page.Timeout(2 * time.Second).MustElement("body")
 	}
-	txt = el.MustText()
+
+	mode := options.Sanitize
+	if mode == "" {
+		mode = SanitizeText
+	}
+
+	// Sanitize before truncating, so the cap is spent on content, not markup
+	txt, err := Sanitize(el.MustHTML(), mode)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(txt) > maxTextLen {
 		txt = txt[:maxTextLen]
 	}
@@ -125,7 +159,7 @@ func (browser *Browser) Fetch(
 
 	// Take screenshot if requested
 	var screenshot string
-	if takeScreenshot {
+	if options.Screenshot {
 		// Capture a screenshot of the page
 		screenshotBytes, err := page.Screenshot(true, nil)
 		if err != nil {
@@ -146,3 +180,89 @@ func (browser *Browser) Fetch(
 		HasScreenshot: screenshot != "",
 	}, nil
 }
+
+/*
+policyGuard checks every request the browser makes against the network
+policy, not only the page it is sent to, as redirects, subresources and
+script navigations may lead anywhere. Rejected requests fail. Allowed ones
+are loaded through netpolicy.Client, so the address connected to is checked
+as well, rather than one the browser resolves on its own. Behind the VPN
+proxy the destination is resolved remotely, so requests are only checked by
+their URL there.
+*/
+type policyGuard struct {
+	router  *rod.HijackRouter
+	client  *http.Client
+	mu      sync.Mutex
+	blocked error
+}
+
+func newPolicyGuard(instance *rod.Browser, proxied bool) (*policyGuard, error) {
+	guard := &policyGuard{router: instance.HijackRequests()}
+
+	if !proxied {
+		guard.client = netpolicy.Client()
+		// Redirects go back to the browser, which requests them anew.
+		guard.client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	if err := guard.router.Add("*", "", guard.handle); err != nil {
+		return nil, err
+	}
+
+	go guard.router.Run()
+
+	return guard, nil
+}
+
+func (guard *policyGuard) handle(hijack *rod.Hijack) {
+	req := hijack.Request.Req()
+
+	if err := netpolicy.Check(req.Context(), req.URL.String()); err != nil {
+		guard.reject(hijack, err)
+		return
+	}
+
+	if guard.client == nil || (req.URL.Scheme != "http" && req.URL.Scheme != "https") {
+		hijack.ContinueRequest(&proto.FetchContinueRequest{})
+		return
+	}
+
+	// Leave the compression to the client, which undoes it as well.
+	req.Header.Del("Accept-Encoding")
+
+	if err := hijack.LoadResponse(guard.client, true); err != nil {
+		if errors.Is(err, netpolicy.ErrNotAllowed) {
+			guard.reject(hijack, err)
+			return
+		}
+
+		log.Warn("failed to load request", "url", req.URL.String(), "error", err)
+		hijack.Response.Fail(proto.NetworkErrorReasonConnectionFailed)
+	}
+}
+
+/*
+reject fails the request, keeping the first rejection to explain a failed
+navigation with.
+*/
+func (guard *policyGuard) reject(hijack *rod.Hijack, err error) {
+	log.Warn("request rejected by the network policy", "url", hijack.Request.URL().String(), "error", err)
+
+	guard.mu.Lock()
+	if guard.blocked == nil {
+		guard.blocked = err
+	}
+	guard.mu.Unlock()
+
+	hijack.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+}
+
+func (guard *policyGuard) err() error {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	return guard.blocked
+}
@@ -0,0 +1,82 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/launcher"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/tools/netpolicy"
+)
+
+func TestFetchNetworkPolicy(t *testing.T) {
+	Convey("Given a network policy that only allows example.com", t, func() {
+		netpolicy.Set(&netpolicy.NetworkPolicy{
+			AllowedHosts: []string{"example.com"},
+			DeniedHosts:  []string{"evil.example"},
+			BlockPrivate: true,
+		})
+		defer netpolicy.Set(nil)
+
+		Convey("When pages outside the policy are fetched", func() {
+			for _, pageURL := range []string{
+				"https://evil.example/",
+				"https://example.org/",
+				"http://169.254.169.254/latest/meta-data",
+			} {
+				_, err := NewBrowser().Fetch(context.Background(), pageURL, FetchOptions{})
+
+				Convey("Then "+pageURL+" should be rejected before a browser is launched", func() {
+					So(errors.Is(err, netpolicy.ErrNotAllowed), ShouldBeTrue)
+				})
+			}
+		})
+	})
+}
+
+func TestFetchRedirectPolicy(t *testing.T) {
+	if _, found := launcher.LookPath(); !found {
+		t.Skip("no browser to launch")
+	}
+
+	Convey("Given an allowed page that redirects to a denied host", t, func() {
+		var (
+			reached atomic.Bool
+			ts      *httptest.Server
+		)
+
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/secret" {
+				reached.Store(true)
+				w.Write([]byte("<html><body>secret</body></html>"))
+				return
+			}
+
+			// The same server, under a name the policy denies.
+			target := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1) + "/secret"
+			http.Redirect(w, r, target, http.StatusFound)
+		}))
+		defer ts.Close()
+
+		netpolicy.Set(&netpolicy.NetworkPolicy{DeniedHosts: []string{"localhost"}})
+		defer netpolicy.Set(nil)
+
+		Convey("When the page is fetched", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			_, err := NewBrowser().Fetch(ctx, ts.URL, FetchOptions{})
+
+			Convey("Then the redirect should be rejected before the denied host is reached", func() {
+				So(errors.Is(err, netpolicy.ErrNotAllowed), ShouldBeTrue)
+				So(reached.Load(), ShouldBeFalse)
+			})
+		})
+	})
+}
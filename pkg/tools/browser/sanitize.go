@@ -0,0 +1,276 @@
+package browser
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// SanitizeMode selects how the HTML of a fetched page is turned into the
+// content of the Result.
+type SanitizeMode string
+
+const (
+	// SanitizeText strips the markup and keeps the readable text. It is the
+	// default.
+	SanitizeText SanitizeMode = "text"
+	// SanitizeMarkdown keeps headings, links, lists and emphasis as markdown.
+	SanitizeMarkdown SanitizeMode = "markdown"
+	// SanitizeNone returns the raw HTML, scripts and styles included.
+	SanitizeNone SanitizeMode = "html"
+)
+
+// FetchOptions configures a Fetch.
+type FetchOptions struct {
+	// Selector limits the content to that DOM subtree instead of the body.
+	Selector string
+	// WaitForSelector waits until that element is present before reading.
+	WaitForSelector string
+	// Screenshot adds a base64-encoded screenshot to the Result.
+	Screenshot bool
+	// Sanitize selects text, markdown or raw HTML content, text when empty.
+	Sanitize SanitizeMode
+}
+
+// droppedElements never hold readable content and are removed with
+// everything inside them.
+var droppedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Svg:      true,
+	atom.Math:     true,
+	atom.Head:     true,
+	atom.Form:     true,
+	atom.Button:   true,
+	atom.Select:   true,
+	atom.Input:    true,
+	atom.Textarea: true,
+}
+
+// blockElements start on a line of their own.
+var blockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true,
+	atom.Body: true, atom.Dd: true, atom.Div: true, atom.Dl: true, atom.Dt: true,
+	atom.Figcaption: true, atom.Figure: true, atom.Footer: true, atom.H1: true,
+	atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Header: true, atom.Hr: true, atom.Main: true, atom.Nav: true,
+	atom.Ol: true, atom.P: true, atom.Pre: true, atom.Section: true, atom.Table: true,
+	atom.Tr: true, atom.Ul: true,
+}
+
+var (
+	spaceRun   = regexp.MustCompile(`[ \t\r\f\v]+`)
+	newlineRun = regexp.MustCompile(`\n{3,}`)
+)
+
+// Sanitize turns the HTML of a page into content for the given mode. Scripts,
+// styles and other elements without readable content are stripped from text
+// and markdown, and links that are not http, https, mailto or relative are
+// dropped.
+func Sanitize(src string, mode SanitizeMode) (string, error) {
+	if mode == SanitizeNone {
+		return src, nil
+	}
+
+	doc, err := html.Parse(strings.NewReader(src))
+
+	if err != nil {
+		return "", err
+	}
+
+	r := &renderer{markdown: mode == SanitizeMarkdown}
+	r.render(doc)
+
+	lines := strings.Split(r.sb.String(), "\n")
+
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	return strings.TrimSpace(newlineRun.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")), nil
+}
+
+// renderer writes the readable content of a parsed document.
+type renderer struct {
+	sb       strings.Builder
+	markdown bool
+	pre      int
+	lists    []atom.Atom
+}
+
+func (r *renderer) render(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		r.text(node.Data)
+		return
+	case html.DocumentNode:
+		r.children(node)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if droppedElements[node.DataAtom] {
+		return
+	}
+
+	if blockElements[node.DataAtom] {
+		r.block()
+	}
+
+	switch node.DataAtom {
+	case atom.Br:
+		r.sb.WriteString("\n")
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		if r.markdown {
+			r.sb.WriteString(strings.Repeat("#", int(node.Data[1]-'0')) + " ")
+		}
+		r.children(node)
+	case atom.Ul, atom.Ol:
+		r.lists = append(r.lists, node.DataAtom)
+		r.children(node)
+		r.lists = r.lists[:len(r.lists)-1]
+	case atom.Li:
+		r.sb.WriteString("\n")
+		r.item()
+		r.children(node)
+	case atom.A:
+		r.link(node)
+	case atom.Img:
+		if alt := attr(node, "alt"); r.markdown && alt != "" && safeURL(attr(node, "src")) {
+			r.sb.WriteString("![" + alt + "](" + attr(node, "src") + ")")
+		}
+	case atom.Strong, atom.B:
+		r.wrap(node, "**")
+	case atom.Em, atom.I:
+		r.wrap(node, "_")
+	case atom.Code:
+		if r.pre == 0 {
+			r.wrap(node, "`")
+		} else {
+			r.children(node)
+		}
+	case atom.Pre:
+		r.pre++
+		r.fence()
+		r.children(node)
+		r.fence()
+		r.pre--
+	case atom.Td, atom.Th:
+		r.children(node)
+		r.sb.WriteString(" ")
+	default:
+		r.children(node)
+	}
+
+	if blockElements[node.DataAtom] {
+		r.block()
+	}
+}
+
+func (r *renderer) children(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		r.render(child)
+	}
+}
+
+// text writes a text node, collapsing whitespace outside preformatted text.
+func (r *renderer) text(data string) {
+	if r.pre > 0 {
+		r.sb.WriteString(data)
+		return
+	}
+
+	data = spaceRun.ReplaceAllString(strings.ReplaceAll(data, "\n", " "), " ")
+
+	// A space at the start of a line or after another one is dropped
+	if written := r.sb.String(); written == "" || strings.HasSuffix(written, "\n") || strings.HasSuffix(written, " ") {
+		data = strings.TrimLeft(data, " ")
+	}
+
+	r.sb.WriteString(data)
+}
+
+// block separates a block element from what surrounds it.
+func (r *renderer) block() {
+	r.sb.WriteString("\n\n")
+}
+
+// item starts a list item, numbered when the list is ordered.
+func (r *renderer) item() {
+	if !r.markdown {
+		return
+	}
+
+	indent := strings.Repeat("  ", max(len(r.lists)-1, 0))
+
+	if len(r.lists) > 0 && r.lists[len(r.lists)-1] == atom.Ol {
+		r.sb.WriteString(indent + "1. ")
+		return
+	}
+
+	r.sb.WriteString(indent + "- ")
+}
+
+func (r *renderer) fence() {
+	if r.markdown {
+		r.sb.WriteString("\n```\n")
+	}
+}
+
+func (r *renderer) wrap(node *html.Node, marker string) {
+	if !r.markdown {
+		r.children(node)
+		return
+	}
+
+	r.sb.WriteString(marker)
+	r.children(node)
+	r.sb.WriteString(marker)
+}
+
+// link writes the text of a link, followed by its target in markdown.
+func (r *renderer) link(node *html.Node) {
+	href := attr(node, "href")
+
+	if !r.markdown || href == "" || !safeURL(href) {
+		r.children(node)
+		return
+	}
+
+	r.sb.WriteString("[")
+	r.children(node)
+	r.sb.WriteString("](" + href + ")")
+}
+
+// safeURL reports whether a link target is http, https, mailto or relative,
+// which keeps javascript: and data: targets out of the content.
+func safeURL(target string) bool {
+	target = strings.ToLower(strings.TrimSpace(target))
+
+	scheme, _, found := strings.Cut(target, ":")
+
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+
+	return scheme == "http" || scheme == "https" || scheme == "mailto"
+}
+
+func attr(node *html.Node, key string) string {
+	for _, attribute := range node.Attr {
+		if attribute.Key == key {
+			return attribute.Val
+		}
+	}
+
+	return ""
+}
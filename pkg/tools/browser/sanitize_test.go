@@ -0,0 +1,75 @@
+package browser
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSanitize(t *testing.T) {
+	Convey("Given a data: URL of a page with scripts and styles", t, func() {
+		page := `<html><head><style>body { color: red; }</style></head><body>
+			<script>alert("xss")</script>
+			<h1>Sea otters</h1>
+			<p>They hold <b>hands</b> while   they sleep.</p>
+			<style>.hidden { display: none; }</style>
+			<ul><li>Rocks</li><li>Shellfish</li></ul>
+			<a href="https://example.com/otters">More</a> <a href="javascript:alert(1)">Click</a>
+			<noscript>Enable JavaScript</noscript>
+		</body></html>`
+		pageURL := "data:text/html," + url.PathEscape(page)
+
+		_, data, _ := strings.Cut(pageURL, ",")
+		src, err := url.PathUnescape(data)
+		So(err, ShouldBeNil)
+
+		Convey("When it is sanitized to text", func() {
+			text, err := Sanitize(src, SanitizeText)
+			So(err, ShouldBeNil)
+
+			Convey("Then the scripts and styles should be stripped", func() {
+				So(text, ShouldNotContainSubstring, "alert")
+				So(text, ShouldNotContainSubstring, "color: red")
+				So(text, ShouldNotContainSubstring, "display: none")
+				So(text, ShouldNotContainSubstring, "Enable JavaScript")
+				So(text, ShouldNotContainSubstring, "<")
+			})
+
+			Convey("Then the readable text should be kept", func() {
+				So(text, ShouldStartWith, "Sea otters")
+				So(text, ShouldContainSubstring, "They hold hands while they sleep.")
+				So(text, ShouldContainSubstring, "Rocks")
+				So(text, ShouldContainSubstring, "More Click")
+			})
+		})
+
+		Convey("When it is sanitized to markdown", func() {
+			markdown, err := Sanitize(src, SanitizeMarkdown)
+			So(err, ShouldBeNil)
+
+			Convey("Then the scripts and styles should be stripped", func() {
+				So(markdown, ShouldNotContainSubstring, "alert")
+				So(markdown, ShouldNotContainSubstring, "color: red")
+			})
+
+			Convey("Then the structure should be kept as markdown", func() {
+				So(markdown, ShouldContainSubstring, "# Sea otters")
+				So(markdown, ShouldContainSubstring, "They hold **hands** while they sleep.")
+				So(markdown, ShouldContainSubstring, "- Rocks\n- Shellfish")
+				So(markdown, ShouldContainSubstring, "[More](https://example.com/otters)")
+				So(markdown, ShouldNotContainSubstring, "javascript:")
+			})
+		})
+
+		Convey("When raw HTML is asked for", func() {
+			raw, err := Sanitize(src, SanitizeNone)
+			So(err, ShouldBeNil)
+
+			Convey("Then the page should be returned as it is", func() {
+				So(raw, ShouldEqual, src)
+			})
+		})
+	})
+}
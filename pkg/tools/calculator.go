@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+/*
+ErrDivisionByZero is returned when an expression divides by zero.
+*/
+var ErrDivisionByZero = errors.New("division by zero")
+
+/*
+calculatorConstants are the named constants an expression may use.
+*/
+var calculatorConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+/*
+calculatorFunctions are the only functions an expression may call, keyed on
+name with the number of arguments they take.
+*/
+var calculatorFunctions = map[string]struct {
+	arity int
+	fn    func(args ...float64) float64
+}{
+	"sqrt":  {1, func(args ...float64) float64 { return math.Sqrt(args[0]) }},
+	"abs":   {1, func(args ...float64) float64 { return math.Abs(args[0]) }},
+	"floor": {1, func(args ...float64) float64 { return math.Floor(args[0]) }},
+	"ceil":  {1, func(args ...float64) float64 { return math.Ceil(args[0]) }},
+	"round": {1, func(args ...float64) float64 { return math.Round(args[0]) }},
+	"exp":   {1, func(args ...float64) float64 { return math.Exp(args[0]) }},
+	"log":   {1, func(args ...float64) float64 { return math.Log(args[0]) }},
+	"log10": {1, func(args ...float64) float64 { return math.Log10(args[0]) }},
+	"sin":   {1, func(args ...float64) float64 { return math.Sin(args[0]) }},
+	"cos":   {1, func(args ...float64) float64 { return math.Cos(args[0]) }},
+	"tan":   {1, func(args ...float64) float64 { return math.Tan(args[0]) }},
+	"pow":   {2, func(args ...float64) float64 { return math.Pow(args[0], args[1]) }},
+	"min":   {2, func(args ...float64) float64 { return math.Min(args[0], args[1]) }},
+	"max":   {2, func(args ...float64) float64 { return math.Max(args[0], args[1]) }},
+}
+
+type CalculatorTool struct {
+	tool *mcp.Tool
+}
+
+func NewCalculatorTool() *mcp.Tool {
+	tool := mcp.NewTool(
+		"calculator",
+		mcp.WithDescription("Evaluate an arithmetic expression exactly. Supports + - * / %, parentheses, pi, e and the functions sqrt, abs, floor, ceil, round, exp, log, log10, sin, cos, tan, pow, min and max. Use this instead of doing arithmetic yourself."),
+		mcp.WithString("expression",
+			mcp.Description("The expression to evaluate, for example (3 + 4) * pow(2, 10) / 7"),
+			mcp.Required(),
+		),
+	)
+
+	return &tool
+}
+
+func (ct *CalculatorTool) RegisterCalculatorTools(srv *server.MCPServer) {
+	srv.AddTool(*ct.tool, ct.Handle)
+}
+
+func (ct *CalculatorTool) Handle(
+	ctx context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	expression, ok := req.GetArguments()["expression"].(string)
+
+	if !ok || expression == "" {
+		return mcp.NewToolResultError("expression parameter is required"), nil
+	}
+
+	result, err := Calculate(expression)
+
+	if err != nil {
+		log.Warn("calculator failed", "expression", expression, "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("cannot evaluate %q: %v", expression, err)), nil
+	}
+
+	buf, err := json.Marshal(map[string]any{
+		"expression": expression,
+		"result":     result,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+/*
+Calculate evaluates an arithmetic expression. The expression is parsed with
+the Go expression parser and only numbers, arithmetic operators, parentheses
+and the whitelisted constants and functions are evaluated, anything else is
+rejected, so no code can run.
+*/
+func Calculate(expression string) (float64, error) {
+	expr, err := parser.ParseExpr(expression)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	result, err := evalNode(expr)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, fmt.Errorf("result is not a finite number")
+	}
+
+	return result, nil
+}
+
+func evalNode(node ast.Expr) (float64, error) {
+	switch node := node.(type) {
+	case *ast.BasicLit:
+		if node.Kind != token.INT && node.Kind != token.FLOAT {
+			return 0, fmt.Errorf("unsupported literal %s", node.Value)
+		}
+
+		return strconv.ParseFloat(node.Value, 64)
+	case *ast.ParenExpr:
+		return evalNode(node.X)
+	case *ast.Ident:
+		if value, ok := calculatorConstants[node.Name]; ok {
+			return value, nil
+		}
+
+		return 0, fmt.Errorf("unknown identifier %s", node.Name)
+	case *ast.UnaryExpr:
+		x, err := evalNode(node.X)
+
+		if err != nil {
+			return 0, err
+		}
+
+		switch node.Op {
+		case token.ADD:
+			return x, nil
+		case token.SUB:
+			return -x, nil
+		}
+
+		return 0, fmt.Errorf("unsupported operator %s", node.Op)
+	case *ast.BinaryExpr:
+		return evalBinary(node)
+	case *ast.CallExpr:
+		return evalCall(node)
+	}
+
+	return 0, fmt.Errorf("unsupported expression")
+}
+
+func evalBinary(node *ast.BinaryExpr) (float64, error) {
+	if node.Op == token.XOR {
+		return 0, fmt.Errorf("use pow(x, y) for exponentiation")
+	}
+
+	x, err := evalNode(node.X)
+
+	if err != nil {
+		return 0, err
+	}
+
+	y, err := evalNode(node.Y)
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch node.Op {
+	case token.ADD:
+		return x + y, nil
+	case token.SUB:
+		return x - y, nil
+	case token.MUL:
+		return x * y, nil
+	case token.QUO:
+		if y == 0 {
+			return 0, ErrDivisionByZero
+		}
+
+		return x / y, nil
+	case token.REM:
+		if y == 0 {
+			return 0, ErrDivisionByZero
+		}
+
+		return math.Mod(x, y), nil
+	}
+
+	return 0, fmt.Errorf("unsupported operator %s", node.Op)
+}
+
+func evalCall(node *ast.CallExpr) (float64, error) {
+	ident, ok := node.Fun.(*ast.Ident)
+
+	if !ok {
+		return 0, fmt.Errorf("unsupported function call")
+	}
+
+	function, ok := calculatorFunctions[ident.Name]
+
+	if !ok {
+		return 0, fmt.Errorf("unknown function %s", ident.Name)
+	}
+
+	if len(node.Args) != function.arity || node.Ellipsis.IsValid() {
+		return 0, fmt.Errorf("%s takes %d argument(s)", ident.Name, function.arity)
+	}
+
+	args := make([]float64, len(node.Args))
+
+	for i, arg := range node.Args {
+		value, err := evalNode(arg)
+
+		if err != nil {
+			return 0, err
+		}
+
+		args[i] = value
+	}
+
+	return function.fn(args...), nil
+}
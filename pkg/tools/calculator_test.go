@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCalculate(t *testing.T) {
+	Convey("Given arithmetic expressions", t, func() {
+		Convey("Then operator precedence and parentheses should be respected", func() {
+			result, err := Calculate("(3 + 4) * 2 - 10 / 4")
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, 11.5)
+		})
+
+		Convey("Then constants and whitelisted functions should be evaluated", func() {
+			result, err := Calculate("pow(2, 10) + sqrt(16) - abs(-1) + floor(pi)")
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, 1030)
+		})
+
+		Convey("Then division by zero should be reported", func() {
+			_, err := Calculate("1 / (2 - 2)")
+			So(err, ShouldEqual, ErrDivisionByZero)
+
+			_, err = Calculate("5 % 0")
+			So(err, ShouldEqual, ErrDivisionByZero)
+		})
+
+		Convey("Then anything but arithmetic should be rejected", func() {
+			for _, expression := range []string{
+				`os.Exit(1)`,
+				`"text"`,
+				`undefined + 1`,
+				`printf(1)`,
+				`2 ^ 3`,
+				`sqrt(1, 2)`,
+				`1 +`,
+			} {
+				_, err := Calculate(expression)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestCalculatorTool(t *testing.T) {
+	Convey("Given the calculator tool", t, func() {
+		calculator := &CalculatorTool{}
+
+		request := func(expression string) mcp.CallToolRequest {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "calculator"
+			req.Params.Arguments = map[string]any{"expression": expression}
+			return req
+		}
+
+		Convey("When evaluating a valid expression", func() {
+			result, err := calculator.Handle(context.Background(), request("6 * 7"))
+
+			Convey("Then a structured result should be returned", func() {
+				So(err, ShouldBeNil)
+				So(result.IsError, ShouldBeFalse)
+
+				payload := map[string]any{}
+				So(json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &payload), ShouldBeNil)
+				So(payload["expression"], ShouldEqual, "6 * 7")
+				So(payload["result"], ShouldEqual, 42)
+			})
+		})
+
+		Convey("When dividing by zero", func() {
+			result, err := calculator.Handle(context.Background(), request("1 / 0"))
+
+			Convey("Then a tool error should be returned to the model", func() {
+				So(err, ShouldBeNil)
+				So(result.IsError, ShouldBeTrue)
+				So(result.Content[0].(mcp.TextContent).Text, ShouldContainSubstring, "division by zero")
+			})
+		})
+	})
+}
+
+func TestPythonEvalTool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := dockerPing(ctx); err != nil {
+		t.Skip("docker daemon unreachable; skipping python_eval test")
+	}
+
+	Convey("Given the python_eval tool", t, func() {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "python_eval"
+		req.Params.Arguments = map[string]any{"code": "print(sum(range(10)))"}
+
+		result, err := (&PythonEvalTool{}).Handle(context.Background(), req)
+
+		Convey("Then the snippet's output should be returned", func() {
+			So(err, ShouldBeNil)
+
+			payload := map[string]string{}
+			So(json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &payload), ShouldBeNil)
+			So(payload["stdout"], ShouldEqual, "45")
+		})
+	})
+}
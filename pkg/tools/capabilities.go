@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+	dkr "github.com/theapemachine/a2a-go/pkg/tools/docker"
+)
+
+/*
+CapabilityInfo describes a built-in tool group, the tools it provides,
+and the runtime prerequisites it needs before those tools can work.
+*/
+type CapabilityInfo struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	Tools          []string `json:"tools"`
+	EnvVars        []string `json:"envVars,omitempty"`
+	ConfigKeys     []string `json:"configKeys,omitempty"`
+	RequiresDocker bool     `json:"requiresDocker,omitempty"`
+}
+
+/*
+dockerPing verifies the Docker daemon is reachable. It is a variable so
+tests can swap it out without a running daemon.
+*/
+var dockerPing = func(ctx context.Context) error {
+	env, err := dkr.NewEnvironment()
+
+	if err != nil {
+		return err
+	}
+
+	return env.Ping(ctx)
+}
+
+/*
+Capabilities returns the built-in tool groups along with their
+runtime prerequisites.
+*/
+func Capabilities() []CapabilityInfo {
+	return []CapabilityInfo{
+		{
+			Name:        "agent",
+			Description: "Build and deploy new agents.",
+			Tools:       []string{"agent"},
+		},
+		{
+			Name:           "docker",
+			Description:    "Execute shell commands inside a Debian container.",
+			Tools:          []string{"docker"},
+			RequiresDocker: true,
+		},
+		{
+			Name:        "browser",
+			Description: "Fetch and read web pages.",
+			Tools:       []string{"browser"},
+		},
+		{
+			Name:        "web_summarize",
+			Description: "Fetch a web page and summarize it into key points and links.",
+			Tools:       []string{"web_summarize"},
+			EnvVars:     []string{"OPENAI_API_KEY"},
+		},
+		{
+			Name:        "catalog",
+			Description: "List the agents registered in the catalog.",
+			Tools:       []string{"catalog"},
+			ConfigKeys:  []string{"endpoints.catalog"},
+		},
+		{
+			Name:        "calculator",
+			Description: "Evaluate arithmetic expressions exactly.",
+			Tools:       []string{"calculator"},
+		},
+		{
+			Name:           "python",
+			Description:    "Run short Python snippets in a network-disabled, resource-limited container.",
+			Tools:          []string{"python_eval"},
+			RequiresDocker: true,
+		},
+		{
+			Name:        "evaluation",
+			Description: "Evaluate whether task output meets its requirements.",
+			Tools:       []string{"evaluate_output"},
+		},
+		{
+			Name:        "delegation",
+			Description: "Delegate tasks to other agents.",
+			Tools:       []string{"delegate_task"},
+			ConfigKeys:  []string{"endpoints.catalog"},
+		},
+		{
+			Name:        "azure",
+			Description: "Manage Azure DevOps sprints and work items.",
+			Tools: []string{
+				"azure_get_sprints",
+				"azure_create_sprint",
+				"azure_sprint_items",
+				"azure_sprint_overview",
+				"azure_get_work_items",
+				"azure_create_work_items",
+				"azure_update_work_items",
+				"azure_execute_wiql",
+				"azure_search_work_items",
+				"azure_enrich_work_item",
+				"azure_get_github_file_content",
+				"azure_work_item_comments",
+				"azure_find_items_by_status",
+			},
+			EnvVars: []string{
+				"AZURE_DEVOPS_ORG",
+				"AZDO_PAT",
+				"AZURE_DEVOPS_PROJECT",
+				"AZURE_DEVOPS_TEAM",
+			},
+		},
+	}
+}
+
+/*
+CapabilityForTool returns the tool group that provides the named tool.
+*/
+func CapabilityForTool(name string) (CapabilityInfo, bool) {
+	for _, capability := range Capabilities() {
+		for _, tool := range capability.Tools {
+			if tool == name {
+				return capability, true
+			}
+		}
+	}
+
+	return CapabilityInfo{}, false
+}
+
+/*
+CheckPrerequisites verifies the runtime prerequisites of the given tool
+groups, or of every group when none are given. It returns one error per
+unmet prerequisite, so an empty result means everything is available.
+*/
+func CheckPrerequisites(groups ...string) []error {
+	capabilities := Capabilities()
+	selected := make([]CapabilityInfo, 0, len(capabilities))
+
+	if len(groups) == 0 {
+		selected = capabilities
+	}
+
+	for _, group := range groups {
+		found := false
+
+		for _, capability := range capabilities {
+			if capability.Name == group {
+				selected = append(selected, capability)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			selected = append(selected, CapabilityInfo{Name: group})
+		}
+	}
+
+	errs := make([]error, 0)
+
+	for _, capability := range selected {
+		if len(capability.Tools) == 0 {
+			errs = append(errs, fmt.Errorf("%s: unknown capability group", capability.Name))
+			continue
+		}
+
+		for _, envVar := range capability.EnvVars {
+			if os.Getenv(envVar) == "" {
+				errs = append(errs, fmt.Errorf("%s: missing environment variable %s", capability.Name, envVar))
+			}
+		}
+
+		for _, key := range capability.ConfigKeys {
+			if viper.GetViper().GetString(key) == "" {
+				errs = append(errs, fmt.Errorf("%s: missing configuration key %s", capability.Name, key))
+			}
+		}
+
+		if capability.RequiresDocker {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := dockerPing(ctx)
+			cancel()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: docker daemon unreachable: %w", capability.Name, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+/*
+UnavailableTools checks the prerequisites of the capability groups behind
+the given tool IDs and returns the reason each unavailable tool cannot run.
+Every group is only checked once, and tools without a known group are
+assumed to be available.
+*/
+func UnavailableTools(ids ...string) map[string]error {
+	unavailable := make(map[string]error)
+	checked := make(map[string]error)
+
+	for _, id := range ids {
+		capability, ok := CapabilityForTool(id)
+
+		if !ok {
+			continue
+		}
+
+		err, seen := checked[capability.Name]
+
+		if !seen {
+			err = errors.Join(CheckPrerequisites(capability.Name)...)
+			checked[capability.Name] = err
+		}
+
+		if err != nil {
+			unavailable[id] = err
+		}
+	}
+
+	return unavailable
+}
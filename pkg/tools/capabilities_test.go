@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCapabilities(t *testing.T) {
+	Convey("Given the built-in capability groups", t, func() {
+		capabilities := Capabilities()
+
+		Convey("Then the azure group should report its required environment variables", func() {
+			var azure CapabilityInfo
+			for _, capability := range capabilities {
+				if capability.Name == "azure" {
+					azure = capability
+				}
+			}
+
+			So(azure.Tools, ShouldContain, "azure_get_work_items")
+			So(azure.EnvVars, ShouldResemble, []string{
+				"AZURE_DEVOPS_ORG",
+				"AZDO_PAT",
+				"AZURE_DEVOPS_PROJECT",
+				"AZURE_DEVOPS_TEAM",
+			})
+		})
+
+		Convey("Then the docker group should require a docker daemon", func() {
+			capability, ok := CapabilityForTool("docker")
+			So(ok, ShouldBeTrue)
+			So(capability.RequiresDocker, ShouldBeTrue)
+		})
+	})
+}
+
+func TestCheckPrerequisites(t *testing.T) {
+	Convey("Given the azure capability group", t, func() {
+		Convey("When all environment variables are set", func() {
+			t.Setenv("AZURE_DEVOPS_ORG", "org")
+			t.Setenv("AZDO_PAT", "pat")
+			t.Setenv("AZURE_DEVOPS_PROJECT", "project")
+			t.Setenv("AZURE_DEVOPS_TEAM", "team")
+
+			errs := CheckPrerequisites("azure")
+
+			Convey("Then no errors should be reported", func() {
+				So(errs, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When some environment variables are missing", func() {
+			t.Setenv("AZURE_DEVOPS_ORG", "org")
+			t.Setenv("AZDO_PAT", "")
+			t.Setenv("AZURE_DEVOPS_PROJECT", "project")
+			t.Setenv("AZURE_DEVOPS_TEAM", "")
+
+			errs := CheckPrerequisites("azure")
+
+			Convey("Then each missing variable should be detected", func() {
+				So(len(errs), ShouldEqual, 2)
+				So(errs[0].Error(), ShouldContainSubstring, "AZDO_PAT")
+				So(errs[1].Error(), ShouldContainSubstring, "AZURE_DEVOPS_TEAM")
+			})
+		})
+	})
+
+	Convey("Given the docker capability group", t, func() {
+		original := dockerPing
+		defer func() { dockerPing = original }()
+
+		Convey("When the docker daemon is unreachable", func() {
+			dockerPing = func(ctx context.Context) error {
+				return context.DeadlineExceeded
+			}
+
+			errs := CheckPrerequisites("docker")
+
+			Convey("Then an error should be reported", func() {
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0].Error(), ShouldContainSubstring, "docker daemon unreachable")
+			})
+		})
+	})
+
+	Convey("Given an unknown capability group", t, func() {
+		errs := CheckPrerequisites("does-not-exist")
+
+		Convey("Then an error should be reported", func() {
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Error(), ShouldContainSubstring, "unknown capability group")
+		})
+	})
+}
+
+func TestUnavailableTools(t *testing.T) {
+	Convey("Given the azure environment variables are unset", t, func() {
+		for _, envVar := range []string{"AZURE_DEVOPS_ORG", "AZDO_PAT", "AZURE_DEVOPS_PROJECT", "AZURE_DEVOPS_TEAM"} {
+			t.Setenv(envVar, "")
+		}
+
+		original := dockerPing
+		defer func() { dockerPing = original }()
+
+		pings := 0
+		dockerPing = func(ctx context.Context) error {
+			pings++
+			return nil
+		}
+
+		Convey("When checking a mix of tools", func() {
+			unavailable := UnavailableTools(
+				"azure_get_work_items", "azure_create_sprint", "browser", "evaluate_output", "docker", "docker",
+			)
+
+			Convey("Then the azure tools should be unavailable", func() {
+				So(unavailable, ShouldContainKey, "azure_get_work_items")
+				So(unavailable, ShouldContainKey, "azure_create_sprint")
+				So(unavailable["azure_get_work_items"].Error(), ShouldContainSubstring, "AZDO_PAT")
+			})
+
+			Convey("Then the other tools should stay available", func() {
+				So(unavailable, ShouldNotContainKey, "browser")
+				So(unavailable, ShouldNotContainKey, "evaluate_output")
+				So(unavailable, ShouldNotContainKey, "docker")
+			})
+
+			Convey("Then each capability group should only be checked once", func() {
+				So(pings, ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+	"github.com/theapemachine/a2a-go/pkg/tools/netpolicy"
 )
 
 type DelegateTool struct {
@@ -61,6 +62,11 @@ func (bt *DelegateTool) Handle(
 		return mcp.NewToolResultError(fmt.Sprintf("The provided agent URL '%s' is invalid. Please provide a full, valid URL (e.g., http://manager:3210). Use the 'catalog' tool to find correct agent URLs.", agentURL)), nil
 	}
 
+	if err := netpolicy.Check(ctx, agentURL); err != nil {
+		log.Warn("DelegateTool: 'agent' argument rejected by the network policy", "url", agentURL, "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("The agent URL '%s' may not be reached: %s.", agentURL, err.Error())), nil
+	}
+
 	if !taskMessageOk {
 		log.Warn("DelegateTool: 'message' argument missing")
 		return mcp.NewToolResultError("The 'message' argument (task message content) is missing."), nil
@@ -111,7 +117,7 @@ func (bt *DelegateTool) Handle(
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(httpReq)
+	resp, err := netpolicy.Client().Do(httpReq)
 	if err != nil {
 		log.Error("DelegateTool: HTTP request failed", "url", rpcURL, "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("HTTP request failed for agent URL '%s': %s. Ensure the agent URL is correct and reachable. Use the 'catalog' tool if unsure.", p.Agent, err.Error())), nil
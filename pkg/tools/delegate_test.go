@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/tools/netpolicy"
+)
+
+func TestDelegateNetworkPolicy(t *testing.T) {
+	Convey("Given a remote agent listening on loopback", t, func() {
+		var calls int
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"result":  map[string]any{"id": "delegated", "status": map[string]any{"state": "completed"}},
+			})
+		}))
+		defer ts.Close()
+
+		delegate := func() *mcp.CallToolResult {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "delegate_task"
+			req.Params.Arguments = map[string]any{"agent": ts.URL, "message": "Summarize the sprint."}
+
+			result, err := (&DelegateTool{}).Handle(context.Background(), req)
+			So(err, ShouldBeNil)
+			return result
+		}
+
+		Convey("When the policy allows the agent's host", func() {
+			netpolicy.Set(&netpolicy.NetworkPolicy{AllowedHosts: []string{"127.0.0.1"}})
+			defer netpolicy.Set(nil)
+
+			result := delegate()
+
+			Convey("Then the task should be delegated", func() {
+				So(result.IsError, ShouldBeFalse)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the policy denies the agent's host", func() {
+			netpolicy.Set(&netpolicy.NetworkPolicy{DeniedHosts: []string{"127.0.0.1"}})
+			defer netpolicy.Set(nil)
+
+			result := delegate()
+
+			Convey("Then the call should fail without reaching the agent", func() {
+				So(result.IsError, ShouldBeTrue)
+				So(result.Content[0].(mcp.TextContent).Text, ShouldContainSubstring, `host "127.0.0.1" is denied`)
+				So(calls, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When the policy blocks private addresses", func() {
+			netpolicy.Set(&netpolicy.NetworkPolicy{BlockPrivate: true})
+			defer netpolicy.Set(nil)
+
+			result := delegate()
+
+			Convey("Then the call should fail without reaching the agent", func() {
+				So(result.IsError, ShouldBeTrue)
+				So(result.Content[0].(mcp.TextContent).Text, ShouldContainSubstring, "private address 127.0.0.1")
+				So(calls, ShouldEqual, 0)
+			})
+		})
+	})
+}
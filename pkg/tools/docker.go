@@ -62,7 +62,8 @@ func (dt *DockerTool) Handle(
 		return nil, err
 	}
 
-	res, err := env.Exec(ctx, cmdStr, "a2a-go")
+	// A task that wants to follow the command sets a log func on ctx.
+	res, err := env.ExecStream(ctx, cmdStr, "a2a-go", dkr.LogFuncFrom(ctx))
 
 	if err != nil {
 		log.Error("docker tool error", "error", err)
@@ -3,6 +3,7 @@ package docker
 import (
 	"archive/tar"
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,12 +12,14 @@ import (
 	"os"
 	"path"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
+	"github.com/google/uuid"
 )
 
 type Result struct {
@@ -29,6 +32,155 @@ type Environment struct {
 	containerID string
 }
 
+/*
+ExecOption configures the container an Exec call runs in. Volumes, network
+and resource limits can only be applied when Exec creates the container, so
+an existing container with the same name must already have the ones asked
+for, or Exec fails with ErrContainerMismatch. The user and the working
+directory are applied to every exec.
+*/
+type ExecOption func(*execConfig)
+
+/*
+ErrContainerMismatch is wrapped by the error of an Exec whose options ask for
+volumes, network or resource limits the existing container was not created
+with. Remove the container, or pick another name, to apply them.
+*/
+var ErrContainerMismatch = errors.New("container does not match the requested options")
+
+/*
+execConfig collects the options of an Exec call.
+*/
+type execConfig struct {
+	host         *container.HostConfig
+	volumes      []string
+	allowedPaths []string
+	workingDir   string
+	user         string
+}
+
+/*
+newExecConfig applies the options and validates the volumes they mount.
+*/
+func newExecConfig(options []ExecOption) (*execConfig, error) {
+	config := &execConfig{host: &container.HostConfig{}}
+
+	for _, option := range options {
+		option(config)
+	}
+
+	for _, volume := range config.volumes {
+		bind, err := validateVolume(volume, config.allowedPaths)
+
+		if err != nil {
+			return nil, err
+		}
+
+		config.host.Binds = append(config.host.Binds, bind)
+	}
+
+	return config, nil
+}
+
+/*
+mismatches lists the requested settings the host config of an existing
+container lacks. Settings that were not requested are not compared.
+*/
+func (config *execConfig) mismatches(existing *container.HostConfig) []string {
+	if existing == nil {
+		existing = &container.HostConfig{}
+	}
+
+	var missing []string
+
+	for _, bind := range config.host.Binds {
+		if !slices.Contains(existing.Binds, bind) {
+			missing = append(missing, "volume "+bind)
+		}
+	}
+
+	if mode := config.host.NetworkMode; mode != "" && mode != existing.NetworkMode {
+		missing = append(missing, "network "+string(mode))
+	}
+
+	if config.host.Memory != 0 && config.host.Memory != existing.Memory {
+		missing = append(missing, "memory limit")
+	}
+
+	if config.host.NanoCPUs != 0 && config.host.NanoCPUs != existing.NanoCPUs {
+		missing = append(missing, "cpu limit")
+	}
+
+	if pids := config.host.PidsLimit; pids != nil && (existing.PidsLimit == nil || *pids != *existing.PidsLimit) {
+		missing = append(missing, "pids limit")
+	}
+
+	return missing
+}
+
+/*
+WithResourceLimits caps the memory (in bytes), the number of CPUs and the
+number of processes available to the container.
+*/
+func WithResourceLimits(memory int64, cpus float64, pids int64) ExecOption {
+	return func(config *execConfig) {
+		config.host.Memory = memory
+		config.host.NanoCPUs = int64(cpus * 1e9)
+		config.host.PidsLimit = &pids
+	}
+}
+
+/*
+WithNetworkDisabled runs the container without any network access.
+*/
+func WithNetworkDisabled() ExecOption {
+	return func(config *execConfig) {
+		config.host.NetworkMode = "none"
+	}
+}
+
+/*
+WithVolumes mounts host directories, or named volumes, into the container,
+each given as host:container or host:container:ro, so files persist across
+execs and containers. Sensitive host paths, such as /etc or the docker
+socket, are rejected unless they are allowed with WithAllowedHostPaths.
+*/
+func WithVolumes(volumes ...string) ExecOption {
+	return func(config *execConfig) {
+		config.volumes = append(config.volumes, volumes...)
+	}
+}
+
+/*
+WithAllowedHostPaths allows WithVolumes to mount the given host paths, and
+anything below them, even when they are sensitive.
+*/
+func WithAllowedHostPaths(paths ...string) ExecOption {
+	return func(config *execConfig) {
+		config.allowedPaths = append(config.allowedPaths, paths...)
+	}
+}
+
+/*
+WithWorkingDir runs the container, and every command in it, in the given
+directory.
+*/
+func WithWorkingDir(dir string) ExecOption {
+	return func(config *execConfig) {
+		config.workingDir = dir
+	}
+}
+
+/*
+WithUser runs the container, and every command in it, as the given user,
+instead of the agent user of the a2a-go image.
+*/
+func WithUser(user string) ExecOption {
+	return func(config *execConfig) {
+		config.user = user
+	}
+}
+
 func NewEnvironment() (*Environment, error) {
 	client, err := client.NewClientWithOpts(
 		client.FromEnv, client.WithAPIVersionNegotiation(),
@@ -41,18 +193,110 @@ func NewEnvironment() (*Environment, error) {
 	}, nil
 }
 
+func (env *Environment) Ping(ctx context.Context) error {
+	_, err := env.client.Ping(ctx)
+	return err
+}
+
+/*
+LogFunc receives the output of a command line by line while it runs. The
+stream is either "stdout" or "stderr".
+*/
+type LogFunc func(stream string, line string)
+
+/*
+Exec runs the command in the named container, creating the container when it
+does not exist yet, and returns its output once it finished.
+*/
 func (env *Environment) Exec(
-	ctx context.Context, cmd string, containerName string,
+	ctx context.Context, cmd string, containerName string, options ...ExecOption,
+) (Result, error) {
+	return env.ExecStream(ctx, cmd, containerName, nil, options...)
+}
+
+/*
+RunOnce runs the command like Exec, in a new container of its own, named
+after the prefix, which is removed once the command finished. Nothing the
+command leaves behind reaches a later one, and the options always apply, as
+the container never exists beforehand.
+*/
+func (env *Environment) RunOnce(
+	ctx context.Context, cmd string, prefix string, options ...ExecOption,
 ) (Result, error) {
+	env.containerID = ""
+	defer env.remove()
+
+	return env.Exec(ctx, cmd, prefix+"-"+uuid.NewString(), options...)
+}
+
+/*
+remove force removes the container of the environment. The command may have
+ended with its context, so the container is removed with a context of its
+own.
+*/
+func (env *Environment) remove() {
+	if env.containerID == "" {
+		return
+	}
+
+	removeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := env.client.ContainerRemove(removeCtx, env.containerID, container.RemoveOptions{Force: true}); err != nil {
+		log.Error("Failed to remove container", "containerID", env.containerID, "error", err)
+	}
+
+	env.containerID = ""
+}
+
+/*
+ExecStream runs the command like Exec, and passes every line of its output to
+onLog as soon as it arrives, so a long running command can be followed. When
+ctx is done before the command finished, the container is killed, as an exec
+cannot be stopped on its own, and the output so far is returned with the
+error of ctx. A killed container is started again by the next call.
+*/
+func (env *Environment) ExecStream(
+	ctx context.Context, cmd string, containerName string, onLog LogFunc, options ...ExecOption,
+) (Result, error) {
+	config, err := newExecConfig(options)
+
+	if err != nil {
+		return Result{}, err
+	}
+
 	containers, err := env.client.ContainerList(ctx, container.ListOptions{All: true})
 
 	if err != nil {
 		return Result{}, err
 	}
 
-	for _, container := range containers {
-		if slices.Contains(container.Names, "/"+containerName) {
-			env.containerID = container.ID
+	for _, existing := range containers {
+		if slices.Contains(existing.Names, "/"+containerName) {
+			inspect, err := env.client.ContainerInspect(ctx, existing.ID)
+
+			if err != nil {
+				return Result{}, err
+			}
+
+			if missing := config.mismatches(inspect.HostConfig); len(missing) > 0 {
+				return Result{}, fmt.Errorf(
+					"%w: container %s was created without %s",
+					ErrContainerMismatch, containerName, strings.Join(missing, ", "),
+				)
+			}
+
+			env.containerID = existing.ID
+
+			// The container may have been killed by an interrupted exec.
+			if existing.State != "running" {
+				log.Info("Starting stopped container", "containerID", env.containerID, "state", existing.State)
+
+				if err := env.client.ContainerStart(ctx, env.containerID, container.StartOptions{}); err != nil {
+					return Result{}, err
+				}
+			}
+
 			break
 		}
 	}
@@ -64,11 +308,13 @@ func (env *Environment) Exec(
 
 		resp, err := env.client.ContainerCreate(ctx,
 			&container.Config{
-				Image: containerName,
-				Cmd:   []string{"/bin/bash"},
-				Tty:   true,
+				Image:      "a2a-go",
+				Cmd:        []string{"/bin/bash"},
+				Tty:        true,
+				WorkingDir: config.workingDir,
+				User:       config.user,
 			},
-			nil, nil, nil, containerName,
+			config.host, nil, nil, containerName,
 		)
 
 		if err != nil {
@@ -76,6 +322,10 @@ func (env *Environment) Exec(
 		}
 
 		env.containerID = resp.ID
+
+		if err := env.client.ContainerStart(ctx, env.containerID, container.StartOptions{}); err != nil {
+			return Result{}, err
+		}
 	}
 
 	log.Info("Creating exec", "containerID", env.containerID)
@@ -83,7 +333,8 @@ func (env *Environment) Exec(
 		ctx,
 		env.containerID,
 		container.ExecOptions{
-			User:         "agent",
+			User:         cmp.Or(config.user, "agent"),
+			WorkingDir:   config.workingDir,
 			Cmd:          []string{"/bin/sh", "-c", cmd},
 			AttachStdout: true,
 			AttachStderr: true,
@@ -112,36 +363,60 @@ func (env *Environment) Exec(
 		return Result{}, err
 	}
 
-	// Create buffers to capture output
 	var result Result
 	result.Stdout = &bytes.Buffer{}
 	result.Stderr = &bytes.Buffer{}
 
-	mw := io.MultiWriter(result.Stdout)
-	mwErr := io.MultiWriter(result.Stderr)
-
 	// Copy output using the demultiplexer since we're not in TTY mode
 	errCh := make(chan error, 1)
 	go func() {
-		errCh <- demultiplexDockerStream(resp.Reader, mw, mwErr)
+		errCh <- streamOutput(resp.Reader, result, onLog)
 	}()
 
+	// Closing the connection ends the copying, so the output is no longer
+	// written to once it is returned.
+	interrupted := func() (Result, error) {
+		err := env.kill(ctx)
+		resp.Close()
+		<-errCh
+
+		return result, err
+	}
+
 	// Wait for the command to complete
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
 	for {
 		inspectResp, err := env.client.ContainerExecInspect(ctx, exec.ID)
+
+		if ctx.Err() != nil {
+			return interrupted()
+		}
+
 		if err != nil {
 			return Result{}, err
 		}
+
 		if !inspectResp.Running {
 			break
 		}
-		time.Sleep(100 * time.Millisecond)
+
+		select {
+		case <-ctx.Done():
+			return interrupted()
+		case <-ticker.C:
+		}
 	}
 
 	// Wait for output copying to complete
-	copyErr := <-errCh
-	if copyErr != nil && copyErr != io.EOF {
-		return Result{}, copyErr
+	select {
+	case copyErr := <-errCh:
+		if copyErr != nil && copyErr != io.EOF {
+			return Result{}, copyErr
+		}
+	case <-ctx.Done():
+		return interrupted()
 	}
 
 	// Check if this was an EOF during input read
@@ -155,6 +430,24 @@ func (env *Environment) Exec(
 	return result, nil
 }
 
+/*
+kill stops the container of a command whose context is done, and returns the
+error of the context. The context is done, so the container is killed with
+a context of its own.
+*/
+func (env *Environment) kill(ctx context.Context) error {
+	log.Warn("Killing container of interrupted exec", "containerID", env.containerID, "error", ctx.Err())
+
+	killCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := env.client.ContainerKill(killCtx, env.containerID, "KILL"); err != nil {
+		log.Error("Failed to kill container", "containerID", env.containerID, "error", err)
+	}
+
+	return ctx.Err()
+}
+
 /*
 BuildImage builds a Docker image from a Dockerfile.
 
@@ -255,6 +548,65 @@ func (env *Environment) print(reader io.Reader) error {
 	}
 }
 
+/*
+streamOutput copies the multiplexed output of a command into the result,
+passing every complete line to onLog, if any, as it arrives. A last line
+without a newline is passed on once the output ends.
+*/
+func streamOutput(reader io.Reader, result Result, onLog LogFunc) error {
+	if onLog == nil {
+		return demultiplexDockerStream(reader, result.Stdout, result.Stderr)
+	}
+
+	stdout := &lineWriter{stream: "stdout", onLog: onLog}
+	stderr := &lineWriter{stream: "stderr", onLog: onLog}
+
+	err := demultiplexDockerStream(
+		reader,
+		io.MultiWriter(result.Stdout, stdout),
+		io.MultiWriter(result.Stderr, stderr),
+	)
+
+	stdout.flush()
+	stderr.flush()
+
+	return err
+}
+
+/*
+lineWriter splits what is written to it into lines for a LogFunc, holding
+back a partial line until the rest of it is written.
+*/
+type lineWriter struct {
+	stream  string
+	onLog   LogFunc
+	pending []byte
+}
+
+func (writer *lineWriter) Write(p []byte) (int, error) {
+	writer.pending = append(writer.pending, p...)
+
+	for {
+		idx := bytes.IndexByte(writer.pending, '\n')
+
+		if idx < 0 {
+			return len(p), nil
+		}
+
+		writer.onLog(writer.stream, strings.TrimSuffix(string(writer.pending[:idx]), "\r"))
+		writer.pending = writer.pending[idx+1:]
+	}
+}
+
+func (writer *lineWriter) flush() {
+	if len(writer.pending) == 0 {
+		return
+	}
+
+	writer.onLog(writer.stream, string(writer.pending))
+	writer.pending = nil
+}
+
 /*
 demultiplexDockerStream processes a Docker multiplexed stream.
 
@@ -0,0 +1,256 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+frame multiplexes a chunk of output the way the docker daemon sends it
+for an exec without a TTY.
+*/
+func frame(stream byte, data string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(data)))
+
+	return append(header, data...)
+}
+
+type logLine struct {
+	stream string
+	line   string
+}
+
+func TestStreamOutput(t *testing.T) {
+	Convey("Given the output of a command that prints three lines with delays", t, func() {
+		reader, writer := io.Pipe()
+		lines := make(chan logLine, 10)
+		result := Result{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+		done := make(chan error, 1)
+
+		go func() {
+			done <- streamOutput(reader, result, func(stream, line string) {
+				lines <- logLine{stream, line}
+			})
+		}()
+
+		Convey("Then every line should reach the callback before the next one is printed", func() {
+			chunks := [][]byte{
+				frame(1, "line 1\n"),
+				frame(2, "warning\n"),
+				// The third line arrives in two chunks.
+				frame(1, "line"),
+				frame(1, " 3\n"),
+			}
+			expected := []logLine{{"stdout", "line 1"}, {"stderr", "warning"}, {}, {"stdout", "line 3"}}
+
+			for i, chunk := range chunks {
+				_, err := writer.Write(chunk)
+				So(err, ShouldBeNil)
+
+				if expected[i].line == "" {
+					So(lines, ShouldBeEmpty)
+					continue
+				}
+
+				select {
+				case line := <-lines:
+					So(line, ShouldResemble, expected[i])
+				case <-time.After(time.Second):
+					t.Fatalf("line %d did not reach the callback", i+1)
+				}
+
+				time.Sleep(20 * time.Millisecond)
+			}
+
+			So(writer.Close(), ShouldBeNil)
+			So(<-done, ShouldBeNil)
+
+			So(result.Stdout.String(), ShouldEqual, "line 1\nline 3\n")
+			So(result.Stderr.String(), ShouldEqual, "warning\n")
+		})
+
+		Convey("Then a last line without a newline should be passed on at the end", func() {
+			_, err := writer.Write(frame(1, "no newline"))
+			So(err, ShouldBeNil)
+			So(writer.Close(), ShouldBeNil)
+			So(<-done, ShouldBeNil)
+
+			So(<-lines, ShouldResemble, logLine{"stdout", "no newline"})
+		})
+	})
+}
+
+func TestExecConfigMismatches(t *testing.T) {
+	Convey("Given an existing container with a volume and no network", t, func() {
+		dir := t.TempDir()
+		config, err := newExecConfig([]ExecOption{WithVolumes(dir + ":/workspace"), WithNetworkDisabled()})
+		So(err, ShouldBeNil)
+
+		existing := &container.HostConfig{Binds: config.host.Binds, NetworkMode: "none"}
+
+		Convey("Then the options it was created with should match", func() {
+			So(config.mismatches(existing), ShouldBeEmpty)
+		})
+
+		Convey("Then no options at all should match", func() {
+			config, err := newExecConfig(nil)
+			So(err, ShouldBeNil)
+			So(config.mismatches(existing), ShouldBeEmpty)
+		})
+
+		Convey("Then a volume or limit it lacks should not match", func() {
+			config, err := newExecConfig([]ExecOption{
+				WithVolumes(t.TempDir() + ":/data"),
+				WithResourceLimits(256*1024*1024, 1, 64),
+			})
+			So(err, ShouldBeNil)
+
+			missing := config.mismatches(existing)
+			So(missing, ShouldHaveLength, 4)
+			So(missing[0], ShouldStartWith, "volume ")
+		})
+	})
+}
+
+func TestExecStream(t *testing.T) {
+	home, _ := os.UserHomeDir()
+
+	if _, err := os.Stat(path.Join(home, ".a2a-go", "Dockerfile")); err != nil {
+		t.Skip("no Dockerfile for the a2a-go image")
+	}
+
+	env, err := NewEnvironment()
+
+	if err != nil {
+		t.Skip("no docker client: ", err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := env.Ping(pingCtx); err != nil {
+		t.Skip("no docker daemon: ", err)
+	}
+
+	Convey("Given a command that prints three lines with delays", t, func() {
+		var (
+			lines []string
+			times []time.Time
+		)
+
+		result, err := env.ExecStream(
+			context.Background(),
+			"for i in 1 2 3; do echo line $i; sleep 0.5; done",
+			"a2a-go-test",
+			func(stream, line string) {
+				lines = append(lines, line)
+				times = append(times, time.Now())
+			},
+		)
+
+		Convey("Then the callback should fire for each line as it is printed", func() {
+			So(err, ShouldBeNil)
+			So(lines, ShouldResemble, []string{"line 1", "line 2", "line 3"})
+			So(times[2].Sub(times[0]), ShouldBeGreaterThan, 500*time.Millisecond)
+			So(result.Stdout.String(), ShouldEqual, "line 1\nline 2\nline 3\n")
+		})
+	})
+}
+
+func TestExecVolumes(t *testing.T) {
+	home, _ := os.UserHomeDir()
+
+	if _, err := os.Stat(path.Join(home, ".a2a-go", "Dockerfile")); err != nil {
+		t.Skip("no Dockerfile for the a2a-go image")
+	}
+
+	env, err := NewEnvironment()
+
+	if err != nil {
+		t.Skip("no docker client: ", err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := env.Ping(pingCtx); err != nil {
+		t.Skip("no docker daemon: ", err)
+	}
+
+	Convey("Given a container with a host directory mounted as its working directory", t, func() {
+		dir := t.TempDir()
+		So(os.Chmod(dir, 0o777), ShouldBeNil)
+
+		options := []ExecOption{WithVolumes(dir + ":/workspace"), WithWorkingDir("/workspace")}
+
+		defer func() {
+			if env.containerID != "" {
+				env.client.ContainerRemove(context.Background(), env.containerID, container.RemoveOptions{Force: true})
+				env.containerID = ""
+			}
+		}()
+
+		Convey("When one exec writes a file and a second one reads it", func() {
+			_, err := env.Exec(context.Background(), "echo persisted > notes.txt", "a2a-go-test-volumes", options...)
+			So(err, ShouldBeNil)
+
+			result, err := env.Exec(context.Background(), "cat /workspace/notes.txt", "a2a-go-test-volumes", options...)
+
+			Convey("Then the second exec should read what the first one wrote", func() {
+				So(err, ShouldBeNil)
+				So(result.Stdout.String(), ShouldEqual, "persisted\n")
+
+				onHost, err := os.ReadFile(path.Join(dir, "notes.txt"))
+				So(err, ShouldBeNil)
+				So(string(onHost), ShouldEqual, "persisted\n")
+			})
+		})
+	})
+}
+
+func TestRunOnce(t *testing.T) {
+	home, _ := os.UserHomeDir()
+
+	if _, err := os.Stat(path.Join(home, ".a2a-go", "Dockerfile")); err != nil {
+		t.Skip("no Dockerfile for the a2a-go image")
+	}
+
+	env, err := NewEnvironment()
+
+	if err != nil {
+		t.Skip("no docker client: ", err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := env.Ping(pingCtx); err != nil {
+		t.Skip("no docker daemon: ", err)
+	}
+
+	Convey("Given a command that leaves a file behind", t, func() {
+		_, err := env.RunOnce(context.Background(), "echo leaked > /tmp/state.txt", "a2a-go-test-once")
+		So(err, ShouldBeNil)
+
+		Convey("When a second command looks for it", func() {
+			result, err := env.RunOnce(context.Background(), "cat /tmp/state.txt || echo clean", "a2a-go-test-once")
+
+			Convey("Then it should run in a clean container", func() {
+				So(err, ShouldBeNil)
+				So(result.Stdout.String(), ShouldEqual, "clean\n")
+				So(env.containerID, ShouldBeEmpty)
+			})
+		})
+	})
+}
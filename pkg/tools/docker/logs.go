@@ -0,0 +1,60 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/theapemachine/a2a-go/pkg/a2a"
+	"github.com/theapemachine/a2a-go/pkg/jsonrpc"
+)
+
+type logFuncKey struct{}
+
+/*
+WithLogFunc returns a context that has the docker tool pass the output of
+the commands it runs to fn while they run. The tool handler has no other way
+to learn about the task it runs for.
+*/
+func WithLogFunc(ctx context.Context, fn LogFunc) context.Context {
+	return context.WithValue(ctx, logFuncKey{}, fn)
+}
+
+/*
+LogFuncFrom returns the LogFunc set with WithLogFunc, or nil.
+*/
+func LogFuncFrom(ctx context.Context) LogFunc {
+	fn, _ := ctx.Value(logFuncKey{}).(LogFunc)
+	return fn
+}
+
+/*
+TaskLogs returns a LogFunc that sends every line of output to out as a
+TaskArtifactUpdateEvent of the task, appending to its "docker_logs"
+artifact, so clients of a streamed task see a command's output as it
+arrives. Lines are dropped once ctx is done, rather than block the command.
+*/
+func TaskLogs(ctx context.Context, taskID string, out chan<- jsonrpc.Response) LogFunc {
+	name := "docker_logs"
+	description := "Output of the docker tool, as it arrives."
+	started := false
+
+	return func(stream string, line string) {
+		appendChunk := started
+		started = true
+
+		chunk := jsonrpc.Response{Result: a2a.TaskArtifactUpdateEvent{
+			ID: taskID,
+			Artifact: a2a.Artifact{
+				Name:        &name,
+				Description: &description,
+				Parts:       []a2a.Part{a2a.NewTextPart(line + "\n")},
+				Metadata:    map[string]any{"stream": stream},
+				Append:      &appendChunk,
+			},
+		}}
+
+		select {
+		case out <- chunk:
+		case <-ctx.Done():
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/*
+sensitiveHostPaths may not be mounted into a container, nor may anything
+below them, or any directory holding them, unless they are allowed with
+WithAllowedHostPaths. Paths starting with ~ are below the home directory.
+*/
+var sensitiveHostPaths = []string{
+	"/etc",
+	"/proc",
+	"/sys",
+	"/dev",
+	"/boot",
+	"/root",
+	"/run",
+	"/var/run",
+	"/var/lib/docker",
+	"/usr",
+	"/bin",
+	"/sbin",
+	"/lib",
+	"/lib64",
+	"~/.ssh",
+	"~/.aws",
+	"~/.kube",
+	"~/.docker",
+	"~/.gnupg",
+	"~/.config",
+}
+
+var namedVolumePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+/*
+validateVolume checks a mount given as host:container or
+host:container:mode, and returns it as the bind docker expects. The host
+side is either an absolute path, which must not be sensitive, or the name of
+a volume. Host paths are resolved through their symlinks before they are
+checked, so a link cannot smuggle in a sensitive path.
+*/
+func validateVolume(spec string, allowed []string) (string, error) {
+	parts := strings.Split(spec, ":")
+
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", fmt.Errorf("invalid volume %q: expected host:container or host:container:mode", spec)
+	}
+
+	hostPath, containerPath := parts[0], parts[1]
+
+	if !strings.HasPrefix(containerPath, "/") {
+		return "", fmt.Errorf("invalid volume %q: container path must be absolute", spec)
+	}
+
+	if len(parts) == 3 && parts[2] != "ro" && parts[2] != "rw" {
+		return "", fmt.Errorf("invalid volume %q: mode must be ro or rw", spec)
+	}
+
+	if !filepath.IsAbs(hostPath) {
+		if !namedVolumePattern.MatchString(hostPath) {
+			return "", fmt.Errorf("invalid volume %q: host path must be absolute, or the name of a volume", spec)
+		}
+
+		return spec, nil
+	}
+
+	resolved := filepath.Clean(hostPath)
+
+	if real, err := filepath.EvalSymlinks(resolved); err == nil {
+		resolved = real
+	}
+
+	if isAllowedHostPath(resolved, allowed) {
+		parts[0] = resolved
+		return strings.Join(parts, ":"), nil
+	}
+
+	if resolved == "/" {
+		return "", fmt.Errorf("invalid volume %q: the host root may not be mounted", spec)
+	}
+
+	for _, sensitive := range expandHostPaths(sensitiveHostPaths) {
+		if isWithin(resolved, sensitive) || isWithin(sensitive, resolved) {
+			return "", fmt.Errorf("invalid volume %q: %s is a sensitive host path", spec, sensitive)
+		}
+	}
+
+	parts[0] = resolved
+	return strings.Join(parts, ":"), nil
+}
+
+func isAllowedHostPath(path string, allowed []string) bool {
+	for _, allow := range expandHostPaths(allowed) {
+		if isWithin(path, allow) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+expandHostPaths resolves ~ to the home directory, leaving out the paths
+below it when there is none.
+*/
+func expandHostPaths(paths []string) []string {
+	home, _ := os.UserHomeDir()
+	expanded := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		if rest, ok := strings.CutPrefix(path, "~"); ok {
+			if home == "" {
+				continue
+			}
+
+			path = home + rest
+		}
+
+		expanded = append(expanded, filepath.Clean(path))
+	}
+
+	return expanded
+}
+
+/*
+isWithin reports whether path is dir or lies below it.
+*/
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateVolume(t *testing.T) {
+	Convey("Given volumes to mount into a container", t, func() {
+		dir := t.TempDir()
+
+		Convey("Then a host directory or a named volume should be mounted", func() {
+			bind, err := validateVolume(dir+":/workspace", nil)
+			So(err, ShouldBeNil)
+			So(bind, ShouldEndWith, ":/workspace")
+
+			bind, err = validateVolume("cache:/cache:ro", nil)
+			So(err, ShouldBeNil)
+			So(bind, ShouldEqual, "cache:/cache:ro")
+		})
+
+		Convey("Then malformed specs should be rejected", func() {
+			for _, spec := range []string{
+				"/tmp",
+				dir + ":workspace",
+				dir + ":/workspace:rwx",
+				"relative/dir:/workspace",
+				"a:b:c:d",
+			} {
+				_, err := validateVolume(spec, nil)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("Then sensitive host paths should be rejected", func() {
+			for _, spec := range []string{
+				"/:/host",
+				"/etc:/etc",
+				"/var/run/docker.sock:/var/run/docker.sock",
+				"/var:/var",
+			} {
+				_, err := validateVolume(spec, nil)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("Then a symlink to a sensitive host path should be rejected", func() {
+			link := filepath.Join(dir, "etc")
+			So(os.Symlink("/etc", link), ShouldBeNil)
+
+			_, err := validateVolume(link+":/config", nil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Then an allowed sensitive host path should be mounted", func() {
+			bind, err := validateVolume("/var/run/docker.sock:/var/run/docker.sock", []string{"/var/run/docker.sock"})
+			So(err, ShouldBeNil)
+			So(bind, ShouldEndWith, ":/var/run/docker.sock")
+		})
+	})
+}
@@ -0,0 +1,345 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/viper"
+)
+
+// defaultMaxFileSize bounds the files fs_read and fs_write handle, unless
+// tools.filesystem.maxFileSize is set.
+const defaultMaxFileSize = 1024 * 1024
+
+/*
+ErrOutsideRoot is returned for a path that resolves outside the root of the
+filesystem tools, whether through "..", an absolute path or a symlink.
+*/
+var ErrOutsideRoot = errors.New("path is outside the root directory")
+
+/*
+FileInfo describes a file or directory, as fs_list and fs_stat return it.
+*/
+type FileInfo struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	IsDir   bool      `json:"isDir"`
+	ModTime time.Time `json:"modTime"`
+}
+
+/*
+FilesystemTool serves the fs_read, fs_write, fs_list and fs_stat tools, which
+only reach the files below their root directory.
+*/
+type FilesystemTool struct {
+	root        string
+	maxFileSize int64
+}
+
+/*
+NewFilesystemTool returns the filesystem tools for the root directory, which
+must exist. Files larger than tools.filesystem.maxFileSize bytes, 1MB by
+default, are neither read nor written.
+*/
+func NewFilesystemTool(root string) (*FilesystemTool, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Paths are checked against the real root, so a root that is a symlink
+	// itself does not make everything below it look like an escape.
+	real, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root directory: %w", err)
+	}
+
+	info, err := os.Stat(real)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root directory: %w", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid root directory: %s is not a directory", root)
+	}
+
+	maxFileSize := viper.GetViper().GetInt64("tools.filesystem.maxFileSize")
+
+	if maxFileSize <= 0 {
+		maxFileSize = defaultMaxFileSize
+	}
+
+	return &FilesystemTool{root: real, maxFileSize: maxFileSize}, nil
+}
+
+/*
+RegisterFilesystemTool adds the filesystem tools for the root directory to
+the MCP server, as a native replacement for the filesystem MCP server.
+*/
+func RegisterFilesystemTool(srv *server.MCPServer, root string) error {
+	ft, err := NewFilesystemTool(root)
+	if err != nil {
+		return err
+	}
+
+	srv.AddTool(mcp.NewTool(
+		"fs_read",
+		mcp.WithDescription("Read a text file below the root directory."),
+		mcp.WithString("path",
+			mcp.Description("Path of the file, relative to the root directory."),
+			mcp.Required(),
+		),
+	), ft.HandleRead)
+
+	srv.AddTool(mcp.NewTool(
+		"fs_write",
+		mcp.WithDescription("Write a text file below the root directory, creating it and its parent directories when needed, or replacing it."),
+		mcp.WithString("path",
+			mcp.Description("Path of the file, relative to the root directory."),
+			mcp.Required(),
+		),
+		mcp.WithString("content",
+			mcp.Description("The content to write."),
+			mcp.Required(),
+		),
+	), ft.HandleWrite)
+
+	srv.AddTool(mcp.NewTool(
+		"fs_list",
+		mcp.WithDescription("List the entries of a directory below the root directory, with their name, size, whether they are a directory and when they were modified."),
+		mcp.WithString("path",
+			mcp.Description("Path of the directory, relative to the root directory. Default: the root directory."),
+		),
+	), ft.HandleList)
+
+	srv.AddTool(mcp.NewTool(
+		"fs_stat",
+		mcp.WithDescription("Describe a file or directory below the root directory."),
+		mcp.WithString("path",
+			mcp.Description("Path of the file or directory, relative to the root directory."),
+			mcp.Required(),
+		),
+	), ft.HandleStat)
+
+	return nil
+}
+
+func (ft *FilesystemTool) HandleRead(
+	ctx context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	path, errResult := ft.pathArg(req, true)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot read %q: %v", req.GetArguments()["path"], ft.relative(err))), nil
+	}
+
+	if info.IsDir() {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot read %q: it is a directory, use fs_list", req.GetArguments()["path"])), nil
+	}
+
+	if info.Size() > ft.maxFileSize {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot read %q: %d bytes exceeds the limit of %d bytes", req.GetArguments()["path"], info.Size(), ft.maxFileSize)), nil
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot read %q: %v", req.GetArguments()["path"], ft.relative(err))), nil
+	}
+
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+func (ft *FilesystemTool) HandleWrite(
+	ctx context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	path, errResult := ft.pathArg(req, true)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	content, ok := req.GetArguments()["content"].(string)
+	if !ok {
+		return mcp.NewToolResultError("content parameter is required"), nil
+	}
+
+	if int64(len(content)) > ft.maxFileSize {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot write %q: %d bytes exceeds the limit of %d bytes", req.GetArguments()["path"], len(content), ft.maxFileSize)), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot write %q: %v", req.GetArguments()["path"], ft.relative(err))), nil
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot write %q: %v", req.GetArguments()["path"], ft.relative(err))), nil
+	}
+
+	log.Info("fs_write wrote file", "path", path, "size", len(content))
+
+	return ft.jsonResult(map[string]any{"path": req.GetArguments()["path"], "size": len(content)})
+}
+
+func (ft *FilesystemTool) HandleList(
+	ctx context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	path, errResult := ft.pathArg(req, false)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot list %q: %v", req.GetArguments()["path"], ft.relative(err))), nil
+	}
+
+	listing := make([]FileInfo, 0, len(entries))
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			// The entry was removed since the directory was read.
+			continue
+		}
+
+		listing = append(listing, newFileInfo(info))
+	}
+
+	return ft.jsonResult(listing)
+}
+
+func (ft *FilesystemTool) HandleStat(
+	ctx context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	path, errResult := ft.pathArg(req, true)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("cannot stat %q: %v", req.GetArguments()["path"], ft.relative(err))), nil
+	}
+
+	return ft.jsonResult(newFileInfo(info))
+}
+
+/*
+pathArg resolves the path argument of a request, answering with a tool error
+when it is missing but required, or leads outside the root. A missing path
+that is not required is the root itself.
+*/
+func (ft *FilesystemTool) pathArg(req mcp.CallToolRequest, required bool) (string, *mcp.CallToolResult) {
+	path, _ := req.GetArguments()["path"].(string)
+
+	if path == "" && required {
+		return "", mcp.NewToolResultError("path parameter is required")
+	}
+
+	resolved, err := ft.resolve(path)
+	if err != nil {
+		log.Warn("filesystem tool rejected path", "path", path, "error", err)
+		return "", mcp.NewToolResultError(fmt.Sprintf("invalid path %q: %v", path, ft.relative(err)))
+	}
+
+	return resolved, nil
+}
+
+/*
+resolve turns a path relative to the root into the real path it leads to,
+following symlinks, and rejects it with ErrOutsideRoot when that is not
+below the root. Paths holding ".." are rejected outright, and absolute
+paths only pass when they lie below the root. Files that do not exist yet
+resolve through their nearest existing parent directory, so fs_write cannot
+escape through a symlinked directory either, and a dangling symlink, which
+could point anywhere once written through, is rejected.
+*/
+func (ft *FilesystemTool) resolve(path string) (string, error) {
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == filepath.Separator }) {
+		if part == ".." {
+			return "", ErrOutsideRoot
+		}
+	}
+
+	joined := path
+
+	if !filepath.IsAbs(path) {
+		joined = filepath.Join(ft.root, path)
+	}
+
+	existing, missing := filepath.Clean(joined), ""
+
+	for {
+		real, err := filepath.EvalSymlinks(existing)
+
+		if err == nil {
+			resolved := filepath.Join(real, missing)
+
+			if !ft.within(resolved) {
+				return "", ErrOutsideRoot
+			}
+
+			return resolved, nil
+		}
+
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if _, lerr := os.Lstat(existing); lerr == nil {
+			return "", fmt.Errorf("%w: dangling symlink", ErrOutsideRoot)
+		}
+
+		parent := filepath.Dir(existing)
+
+		if parent == existing {
+			return "", ErrOutsideRoot
+		}
+
+		missing = filepath.Join(filepath.Base(existing), missing)
+		existing = parent
+	}
+}
+
+func (ft *FilesystemTool) within(path string) bool {
+	rel, err := filepath.Rel(ft.root, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+/*
+relative keeps the real root out of the errors the model gets to see.
+*/
+func (ft *FilesystemTool) relative(err error) string {
+	return strings.ReplaceAll(err.Error(), ft.root+string(filepath.Separator), "")
+}
+
+func (ft *FilesystemTool) jsonResult(value any) (*mcp.CallToolResult, error) {
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+func newFileInfo(info os.FileInfo) FileInfo {
+	return FileInfo{
+		Name:    info.Name(),
+		Size:    info.Size(),
+		IsDir:   info.IsDir(),
+		ModTime: info.ModTime().UTC(),
+	}
+}
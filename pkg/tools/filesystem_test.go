@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilesystemTool(t *testing.T) {
+	Convey("Given the filesystem tools rooted in a directory", t, func() {
+		root := t.TempDir()
+		outside := t.TempDir()
+
+		So(os.MkdirAll(filepath.Join(root, "notes"), 0o755), ShouldBeNil)
+		So(os.WriteFile(filepath.Join(root, "notes", "todo.txt"), []byte("ship it"), 0o644), ShouldBeNil)
+		So(os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("hunter2"), 0o644), ShouldBeNil)
+
+		ft, err := NewFilesystemTool(root)
+		So(err, ShouldBeNil)
+
+		request := func(arguments map[string]any) mcp.CallToolRequest {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = arguments
+			return req
+		}
+
+		Convey("When reading a file below the root", func() {
+			result, err := ft.HandleRead(context.Background(), request(map[string]any{"path": "notes/todo.txt"}))
+
+			Convey("Then its content should be returned", func() {
+				So(err, ShouldBeNil)
+				So(result.IsError, ShouldBeFalse)
+				So(result.Content[0].(mcp.TextContent).Text, ShouldEqual, "ship it")
+			})
+		})
+
+		Convey("When a path tries to leave the root", func() {
+			So(os.Symlink(outside, filepath.Join(root, "escape")), ShouldBeNil)
+
+			// The labels stay the same on every pass, unlike the temp paths.
+			for _, escape := range []struct{ label, path string }{
+				{"a path through the parent directory", "../" + filepath.Base(outside) + "/secret.txt"},
+				{"a path climbing out of a subdirectory", "notes/../../secret.txt"},
+				{"an absolute path", filepath.Join(outside, "secret.txt")},
+				{"a path through a symlink", "escape/secret.txt"},
+			} {
+				result, err := ft.HandleRead(context.Background(), request(map[string]any{"path": escape.path}))
+
+				Convey("Then "+escape.label+" should be rejected", func() {
+					So(err, ShouldBeNil)
+					So(result.IsError, ShouldBeTrue)
+					So(result.Content[0].(mcp.TextContent).Text, ShouldContainSubstring, ErrOutsideRoot.Error())
+				})
+			}
+
+			Convey("Then a write through a symlinked directory should be rejected", func() {
+				result, err := ft.HandleWrite(context.Background(), request(map[string]any{
+					"path": "escape/new.txt", "content": "owned",
+				}))
+
+				So(err, ShouldBeNil)
+				So(result.IsError, ShouldBeTrue)
+
+				_, statErr := os.Stat(filepath.Join(outside, "new.txt"))
+				So(os.IsNotExist(statErr), ShouldBeTrue)
+			})
+		})
+
+		Convey("When writing more than the maximum file size", func() {
+			result, err := ft.HandleWrite(context.Background(), request(map[string]any{
+				"path":    "big.txt",
+				"content": strings.Repeat("x", defaultMaxFileSize+1),
+			}))
+
+			Convey("Then the write should be rejected and nothing written", func() {
+				So(err, ShouldBeNil)
+				So(result.IsError, ShouldBeTrue)
+				So(result.Content[0].(mcp.TextContent).Text, ShouldContainSubstring, "exceeds the limit")
+
+				_, statErr := os.Stat(filepath.Join(root, "big.txt"))
+				So(os.IsNotExist(statErr), ShouldBeTrue)
+			})
+		})
+
+		Convey("When listing a directory", func() {
+			result, err := ft.HandleList(context.Background(), request(map[string]any{}))
+			So(err, ShouldBeNil)
+
+			Convey("Then its entries should be returned as JSON", func() {
+				var listing []FileInfo
+				So(json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &listing), ShouldBeNil)
+				So(listing, ShouldHaveLength, 1)
+				So(listing[0].Name, ShouldEqual, "notes")
+				So(listing[0].IsDir, ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/viper"
 )
 
@@ -22,12 +23,18 @@ func Acquire(id string) (*mcp.Tool, error) {
 		return NewDockerTool(), nil
 	case "web-browsing", "browser":
 		return NewBrowserTool(), nil
+	case "web_summarize":
+		return NewWebSummarizeTool(), nil
 	case "catalog":
 		return NewCatalogTool(), nil
 	case "evaluation", "evaluate_output":
 		return NewEvaluateTool(), nil
 	case "management", "delegate_task", "communication":
 		return NewDelegateTool(), nil
+	case "calculator":
+		return NewCalculatorTool(), nil
+	case "python_eval":
+		return NewPythonEvalTool(), nil
 	case "azure_get_sprints":
 		return NewAzureGetSprintsTool(), nil
 	case "azure_create_sprint":
@@ -110,6 +117,13 @@ func NewExecutor(
 		return resultString, nil
 	}
 
+	switch name {
+	case "calculator":
+		return executeLocal(ctx, name, args, (&CalculatorTool{}).Handle)
+	case "python_eval":
+		return executeLocal(ctx, name, args, (&PythonEvalTool{}).Handle)
+	}
+
 	endpointKey := "endpoints." + name + "tool"
 	url := viper.GetViper().GetString(endpointKey)
 	if url == "" {
@@ -195,3 +209,43 @@ func NewExecutor(
 	log.Info("client shutting down after tool call")
 	return resultString, nil
 }
+
+/*
+executeLocal runs a built-in tool in-process instead of through an MCP
+server and returns its text result.
+*/
+func executeLocal(
+	ctx context.Context, name, args string, handle server.ToolHandlerFunc,
+) (string, error) {
+	log.Info("executing tool locally", "toolName", name)
+
+	arguments := map[string]any{}
+
+	if err := json.Unmarshal([]byte(args), &arguments); err != nil {
+		return "", fmt.Errorf("failed to unmarshal tool arguments for %s '%s': %w", name, args, err)
+	}
+
+	callToolRequest := mcp.CallToolRequest{}
+	callToolRequest.Params.Name = name
+	callToolRequest.Params.Arguments = arguments
+
+	result, err := handle(ctx, callToolRequest)
+
+	if err != nil {
+		return "", err
+	}
+
+	var resultString string
+
+	if len(result.Content) > 0 {
+		if textContent, ok := result.Content[0].(mcp.TextContent); ok {
+			resultString = textContent.Text
+		}
+	}
+
+	if result.IsError {
+		return "", fmt.Errorf("%s", resultString)
+	}
+
+	return resultString, nil
+}
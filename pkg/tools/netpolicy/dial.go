@@ -0,0 +1,69 @@
+package netpolicy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+/*
+DialContext dials address once the current policy allows it. The host is
+matched against the allowed and denied hosts before it is resolved, and the
+address the connection is actually made to is checked right before the
+connect, so neither a redirect nor a DNS answer that changed since an
+earlier Check can lead a tool to a host the policy rejects.
+*/
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	policy := Current()
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+
+	host = normalizeHost(host)
+
+	if err := policy.checkHost(host); err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			ip, _, err := net.SplitHostPort(address)
+
+			if err != nil {
+				return err
+			}
+
+			return policy.checkIP(host, net.ParseIP(ip))
+		},
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
+
+/*
+Transport returns an HTTP transport that dials through DialContext. It
+connects directly, as a proxy would resolve and reach the destination out of
+the policy's sight.
+*/
+func Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = DialContext
+
+	return transport
+}
+
+/*
+Client returns an HTTP client whose every connection, including the ones of
+the redirects it follows, is checked against the current policy.
+*/
+func Client() *http.Client {
+	return &http.Client{Transport: Transport()}
+}
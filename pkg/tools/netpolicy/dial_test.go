@@ -0,0 +1,63 @@
+package netpolicy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient(t *testing.T) {
+	Convey("Given a public page that redirects to a denied host", t, func() {
+		var (
+			reached atomic.Bool
+			ts      *httptest.Server
+		)
+
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/secret" {
+				reached.Store(true)
+				return
+			}
+
+			// The same server, under a name the policy denies.
+			target := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1) + "/secret"
+			http.Redirect(w, r, target, http.StatusFound)
+		}))
+		defer ts.Close()
+
+		Set(&NetworkPolicy{AllowedHosts: []string{"127.0.0.1", "localhost"}, DeniedHosts: []string{"localhost"}})
+		defer Set(nil)
+
+		Convey("When the page is fetched with the policy client", func() {
+			_, err := Client().Get(ts.URL)
+
+			Convey("Then the redirect should be rejected before the denied host is reached", func() {
+				So(errors.Is(err, ErrNotAllowed), ShouldBeTrue)
+				So(err.Error(), ShouldContainSubstring, `host "localhost" is denied`)
+				So(reached.Load(), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a policy blocking private addresses", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer ts.Close()
+
+		Set(&NetworkPolicy{BlockPrivate: true})
+		defer Set(nil)
+
+		Convey("When a host is dialed that resolves to a private address", func() {
+			_, err := Client().Get(strings.Replace(ts.URL, "127.0.0.1", "localhost", 1))
+
+			Convey("Then the connection should be refused at dial time", func() {
+				So(errors.Is(err, ErrNotAllowed), ShouldBeTrue)
+				So(err.Error(), ShouldContainSubstring, "private address")
+			})
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package netpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+/*
+ErrNotAllowed is wrapped by the errors of requests the policy rejects.
+*/
+var ErrNotAllowed = errors.New("destination not allowed by the network policy")
+
+/*
+NetworkPolicy decides which hosts the network tools may reach. Hosts are
+matched case-insensitively, either exactly or, for patterns starting with
+"*.", as the domain or any of its subdomains. DeniedHosts wins over AllowedHosts, and an empty
+AllowedHosts allows every host that is not denied. BlockPrivate rejects
+hosts that are, or resolve to, loopback, private, link-local or unspecified
+addresses, which keeps tools away from the services next to the agent.
+*/
+type NetworkPolicy struct {
+	AllowedHosts []string
+	DeniedHosts  []string
+	BlockPrivate bool
+}
+
+var (
+	mu     sync.RWMutex
+	policy *NetworkPolicy
+)
+
+/*
+Set makes the policy the one every network tool checks, in place of the
+one configured under tools.network. A nil policy goes back to the
+configuration.
+*/
+func Set(override *NetworkPolicy) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	policy = override
+}
+
+/*
+Current returns the policy set with Set, or else the one configured with
+the tools.network.allowedHosts, tools.network.deniedHosts and
+tools.network.blockPrivate keys.
+*/
+func Current() NetworkPolicy {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if policy != nil {
+		return *policy
+	}
+
+	v := viper.GetViper()
+
+	return NetworkPolicy{
+		AllowedHosts: v.GetStringSlice("tools.network.allowedHosts"),
+		DeniedHosts:  v.GetStringSlice("tools.network.deniedHosts"),
+		BlockPrivate: v.GetBool("tools.network.blockPrivate"),
+	}
+}
+
+/*
+Check validates a URL against the current policy, see NetworkPolicy.Check.
+*/
+func Check(ctx context.Context, rawURL string) error {
+	return Current().Check(ctx, rawURL)
+}
+
+/*
+Check returns an error wrapping ErrNotAllowed when the host of rawURL may
+not be reached. URLs without a host, such as data: URLs, do not leave the
+process and always pass.
+*/
+func (p NetworkPolicy) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+
+	host := normalizeHost(u.Hostname())
+
+	if host == "" {
+		return nil
+	}
+
+	if err := p.checkHost(host); err != nil {
+		return err
+	}
+
+	if !p.BlockPrivate {
+		return nil
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+
+	if ips[0] == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+
+		if err != nil {
+			return fmt.Errorf("failed to resolve host %q: %w", host, err)
+		}
+
+		ips = ips[:0]
+
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if err := p.checkIP(host, ip); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+checkHost matches a normalized host against the denied and allowed hosts.
+*/
+func (p NetworkPolicy) checkHost(host string) error {
+	if matchAny(host, p.DeniedHosts) {
+		return fmt.Errorf("%w: host %q is denied", ErrNotAllowed, host)
+	}
+
+	if len(p.AllowedHosts) > 0 && !matchAny(host, p.AllowedHosts) {
+		return fmt.Errorf("%w: host %q is not in the allowed hosts", ErrNotAllowed, host)
+	}
+
+	return nil
+}
+
+/*
+checkIP rejects an address the host resolved to when it is private and the
+policy blocks private addresses.
+*/
+func (p NetworkPolicy) checkIP(host string, ip net.IP) error {
+	if p.BlockPrivate && isPrivate(ip) {
+		return fmt.Errorf("%w: host %q resolves to the private address %s", ErrNotAllowed, host, ip)
+	}
+
+	return nil
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+func matchAny(host string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(pattern)), ".")
+
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if host == suffix || strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+
+			continue
+		}
+
+		if host == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
@@ -0,0 +1,71 @@
+package netpolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNetworkPolicy(t *testing.T) {
+	Convey("Given a policy allowing example.com and its subdomains", t, func() {
+		ctx := context.Background()
+		policy := NetworkPolicy{
+			AllowedHosts: []string{"*.example.com", "93.184.216.34"},
+			DeniedHosts:  []string{"admin.example.com"},
+		}
+
+		Convey("Then allowed hosts should pass", func() {
+			So(policy.Check(ctx, "https://example.com/otters"), ShouldBeNil)
+			So(policy.Check(ctx, "http://93.184.216.34:8080/"), ShouldBeNil)
+		})
+
+		Convey("Then denied hosts should be blocked, even when allowed", func() {
+			err := policy.Check(ctx, "https://ADMIN.example.com/")
+			So(errors.Is(err, ErrNotAllowed), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, `host "admin.example.com" is denied`)
+		})
+
+		Convey("Then hosts outside the allowed hosts should be blocked", func() {
+			err := policy.Check(ctx, "https://example.org/")
+			So(errors.Is(err, ErrNotAllowed), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "not in the allowed hosts")
+		})
+
+		Convey("Then URLs without a host should pass", func() {
+			So(policy.Check(ctx, "data:text/plain,otters"), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a policy blocking private addresses", t, func() {
+		ctx := context.Background()
+		policy := NetworkPolicy{BlockPrivate: true}
+
+		Convey("Then loopback, private and link-local hosts should be blocked", func() {
+			for _, target := range []string{
+				"http://127.0.0.1:3210/rpc",
+				"http://10.0.0.8/",
+				"http://192.168.1.1/",
+				"http://169.254.169.254/latest/meta-data",
+				"http://[::1]/",
+			} {
+				So(errors.Is(policy.Check(ctx, target), ErrNotAllowed), ShouldBeTrue)
+			}
+		})
+
+		Convey("Then public addresses should pass", func() {
+			So(policy.Check(ctx, "http://93.184.216.34/"), ShouldBeNil)
+		})
+	})
+
+	Convey("Given an overriding policy", t, func() {
+		Set(&NetworkPolicy{DeniedHosts: []string{"example.com"}})
+		defer Set(nil)
+
+		Convey("Then Check should use it", func() {
+			So(errors.Is(Check(context.Background(), "https://example.com"), ErrNotAllowed), ShouldBeTrue)
+			So(Check(context.Background(), "https://example.org"), ShouldBeNil)
+		})
+	})
+}
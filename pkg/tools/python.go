@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	dkr "github.com/theapemachine/a2a-go/pkg/tools/docker"
+)
+
+const (
+	// pythonSandbox prefixes the names of the containers python_eval snippets
+	// run in, a new one for every snippet, removed once it finished.
+	pythonSandbox = "a2a-go-python-sandbox"
+	// pythonTimeout bounds a single snippet, in seconds.
+	pythonTimeout = 30
+)
+
+/*
+pythonSandboxOptions are the limits every python_eval container is created
+with: no network, 256MB of memory, half a CPU and a handful of processes.
+*/
+var pythonSandboxOptions = []dkr.ExecOption{
+	dkr.WithNetworkDisabled(),
+	dkr.WithResourceLimits(256*1024*1024, 0.5, 64),
+}
+
+type PythonEvalTool struct {
+	tool *mcp.Tool
+}
+
+func NewPythonEvalTool() *mcp.Tool {
+	tool := mcp.NewTool(
+		"python_eval",
+		mcp.WithDescription("Run a short Python 3 snippet in a sandbox without network access and return what it prints. Use it for computations the calculator cannot express."),
+		mcp.WithString("code",
+			mcp.Description("The Python code to run. Print the values you want returned."),
+			mcp.Required(),
+		),
+	)
+
+	return &tool
+}
+
+func (pt *PythonEvalTool) RegisterPythonEvalTools(srv *server.MCPServer) {
+	srv.AddTool(*pt.tool, pt.Handle)
+}
+
+func (pt *PythonEvalTool) Handle(
+	ctx context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	code, ok := req.GetArguments()["code"].(string)
+
+	if !ok || strings.TrimSpace(code) == "" {
+		return mcp.NewToolResultError("code parameter is required"), nil
+	}
+
+	env, err := dkr.NewEnvironment()
+
+	if err != nil {
+		log.Error("python_eval error", "error", err)
+		return nil, err
+	}
+
+	// The snippet is passed base64 encoded so no shell quoting can break out of it.
+	cmd := fmt.Sprintf(
+		"echo %s | base64 -d | timeout %d python3 -",
+		base64.StdEncoding.EncodeToString([]byte(code)), pythonTimeout,
+	)
+
+	res, err := env.RunOnce(ctx, cmd, pythonSandbox, pythonSandboxOptions...)
+
+	if err != nil {
+		log.Error("python_eval error", "error", err)
+		return nil, err
+	}
+
+	buf, err := json.Marshal(map[string]string{
+		"stdout": strings.TrimSpace(res.Stdout.String()),
+		"stderr": strings.TrimSpace(res.Stderr.String()),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(string(buf)), nil
+}
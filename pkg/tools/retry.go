@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+/*
+RetriableError marks a tool failure as transient, such as a network blip or
+a rate limit, so the same call may succeed when it is simply tried again.
+*/
+type RetriableError struct {
+	Err error
+}
+
+/*
+Retriable wraps err so callers know the tool call can be retried as is.
+*/
+func Retriable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &RetriableError{Err: err}
+}
+
+func (err *RetriableError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *RetriableError) Unwrap() error {
+	return err.Err
+}
+
+/*
+IsRetriable reports whether a tool call failed in a way that is worth
+retrying. That is either an error wrapped with Retriable, or a result that
+is a structured error payload, a JSON object with an "error" field, that
+sets "retriable" to true, which is how remote tools signal it.
+*/
+func IsRetriable(result string, err error) bool {
+	if err != nil {
+		var retriable *RetriableError
+		return errors.As(err, &retriable)
+	}
+
+	payload := map[string]any{}
+
+	if json.Unmarshal([]byte(result), &payload) != nil {
+		return false
+	}
+
+	retriable, _ := payload["retriable"].(bool)
+	_, failed := payload["error"]
+
+	return retriable && failed
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+/*
+DefaultToolTimeout bounds a tool call when neither the tool nor the
+configuration sets a timeout of its own.
+*/
+const DefaultToolTimeout = 2 * time.Minute
+
+var toolTimeouts sync.Map
+
+/*
+RegisterTimeout overrides the execution timeout of a single tool. A zero
+or negative timeout removes the override again.
+*/
+func RegisterTimeout(name string, timeout time.Duration) {
+	if timeout <= 0 {
+		toolTimeouts.Delete(name)
+		return
+	}
+
+	toolTimeouts.Store(name, timeout)
+}
+
+/*
+TimeoutFor returns the execution timeout of the named tool. A timeout set
+with RegisterTimeout wins, followed by the tools.<name>.timeout and
+tools.timeout configuration keys, and finally DefaultToolTimeout.
+*/
+func TimeoutFor(name string) time.Duration {
+	if timeout, ok := toolTimeouts.Load(name); ok {
+		return timeout.(time.Duration)
+	}
+
+	v := viper.GetViper()
+
+	for _, key := range []string{"tools." + name + ".timeout", "tools.timeout"} {
+		if timeout := v.GetDuration(key); timeout > 0 {
+			return timeout
+		}
+	}
+
+	return DefaultToolTimeout
+}
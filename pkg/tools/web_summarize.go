@@ -0,0 +1,278 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/theapemachine/a2a-go/pkg/tools/browser"
+)
+
+/*
+DefaultSummaryContentLimit caps the page text handed to the summarizer, in
+bytes, so a long page cannot blow the model's context.
+*/
+const DefaultSummaryContentLimit = 12000
+
+/*
+Summarizer answers a summarization prompt with the model's reply. The
+provider behind it is injected, so the tool works with whichever model the
+agent is configured with.
+*/
+type Summarizer func(ctx context.Context, prompt string) (string, error)
+
+/*
+PageFetcher loads a page and returns its title and visible text.
+*/
+type PageFetcher func(ctx context.Context, pageURL string) (*browser.Result, error)
+
+/*
+WebSummary is the structured result of the web_summarize tool.
+*/
+type WebSummary struct {
+	Title     string   `json:"title"`
+	URL       string   `json:"url"`
+	KeyPoints []string `json:"key_points"`
+	Links     []string `json:"links"`
+}
+
+/*
+ErrNoSummarizer is returned when web_summarize is called without a
+summarizer to send the page to.
+*/
+var ErrNoSummarizer = errors.New("web_summarize: no summarizer configured")
+
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+
+type WebSummarizeTool struct {
+	tool         *mcp.Tool
+	fetch        PageFetcher
+	summarize    Summarizer
+	contentLimit int
+}
+
+type WebSummarizeOption func(*WebSummarizeTool)
+
+func NewWebSummarizeTool() *mcp.Tool {
+	tool := mcp.NewTool(
+		"web_summarize",
+		mcp.WithDescription("Fetch a web page and summarize its main content into a title, key points and links, returned as JSON."),
+		mcp.WithString("url",
+			mcp.Description("The URL of the page to summarize"),
+			mcp.Required(),
+		),
+	)
+
+	return &tool
+}
+
+/*
+NewWebSummarizer returns the handler of the web_summarize tool, sending pages
+to summarize. Pages are fetched with the headless browser unless
+WithPageFetcher says otherwise.
+*/
+func NewWebSummarizer(summarize Summarizer, options ...WebSummarizeOption) *WebSummarizeTool {
+	tool := &WebSummarizeTool{
+		tool:      NewWebSummarizeTool(),
+		summarize: summarize,
+		fetch: func(ctx context.Context, pageURL string) (*browser.Result, error) {
+			return browser.NewBrowser().Fetch(ctx, pageURL, browser.FetchOptions{})
+		},
+		contentLimit: DefaultSummaryContentLimit,
+	}
+
+	for _, option := range options {
+		option(tool)
+	}
+
+	return tool
+}
+
+/*
+WithPageFetcher replaces the headless browser the page is fetched with.
+*/
+func WithPageFetcher(fetch PageFetcher) WebSummarizeOption {
+	return func(tool *WebSummarizeTool) {
+		tool.fetch = fetch
+	}
+}
+
+/*
+WithSummaryContentLimit caps the bytes of page text sent to the
+summarizer.
+*/
+func WithSummaryContentLimit(limit int) WebSummarizeOption {
+	return func(tool *WebSummarizeTool) {
+		tool.contentLimit = limit
+	}
+}
+
+func (wt *WebSummarizeTool) RegisterWebSummarizeTools(srv *server.MCPServer) {
+	srv.AddTool(*wt.tool, wt.Handle)
+}
+
+func (wt *WebSummarizeTool) Handle(
+	ctx context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	pageURL, ok := req.GetArguments()["url"].(string)
+
+	if !ok || pageURL == "" {
+		return mcp.NewToolResultError("url parameter is required"), nil
+	}
+
+	if wt.summarize == nil {
+		return nil, ErrNoSummarizer
+	}
+
+	log.Info("summarizing page", "url", pageURL)
+
+	page, err := wt.fetch(ctx, pageURL)
+
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to fetch %s: %v", pageURL, err)), nil
+	}
+
+	content := stripBoilerplate(page.Text)
+
+	if wt.contentLimit > 0 && len(content) > wt.contentLimit {
+		content = truncateUTF8(content, wt.contentLimit)
+	}
+
+	reply, err := wt.summarize(ctx, summaryPrompt(page.Title, content))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize %s: %w", pageURL, err)
+	}
+
+	summary, err := parseWebSummary(reply)
+
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("summarizer returned no valid summary: %v", err)), nil
+	}
+
+	if summary.Title == "" {
+		summary.Title = page.Title
+	}
+
+	summary.URL = page.URL
+	if summary.URL == "" {
+		summary.URL = pageURL
+	}
+
+	summary.Links = mergeLinks(summary.Links, linkPattern.FindAllString(content, -1))
+
+	buf, err := json.Marshal(summary)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+/*
+summaryPrompt asks for the summary in the shape of WebSummary.
+*/
+func summaryPrompt(title, content string) string {
+	return fmt.Sprintf(`Summarize the main content of the web page below.
+
+Respond only with JSON of this shape, without any other text:
+{"title": "the page title", "key_points": ["at most seven short key points"], "links": ["the most relevant links mentioned in the content"]}
+
+TITLE: %s
+
+CONTENT:
+%s`, title, content)
+}
+
+/*
+parseWebSummary reads the summarizer's reply, which models tend to wrap in
+a Markdown code fence.
+*/
+func parseWebSummary(reply string) (WebSummary, error) {
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimPrefix(reply, "```json")
+	reply = strings.TrimPrefix(reply, "```")
+	reply = strings.TrimSuffix(reply, "```")
+
+	var summary WebSummary
+
+	if err := json.Unmarshal([]byte(strings.TrimSpace(reply)), &summary); err != nil {
+		return WebSummary{}, err
+	}
+
+	if summary.KeyPoints == nil {
+		summary.KeyPoints = []string{}
+	}
+
+	return summary, nil
+}
+
+/*
+stripBoilerplate drops what pages repeat around their main content: lines
+seen before, such as repeated navigation, and short lines that are no
+sentence, such as menu entries and buttons.
+*/
+func stripBoilerplate(text string) string {
+	seen := map[string]bool{}
+	lines := make([]string, 0)
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+
+		if line == "" || seen[line] {
+			continue
+		}
+
+		seen[line] = true
+
+		if len(strings.Fields(line)) < 4 && !strings.ContainsAny(line, ".!?:") {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+/*
+truncateUTF8 cuts text to at most limit bytes without splitting a character.
+*/
+func truncateUTF8(text string, limit int) string {
+	for limit > 0 && limit < len(text) && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+
+	return text[:limit]
+}
+
+/*
+mergeLinks returns the links of both lists, in order and without duplicates.
+*/
+func mergeLinks(lists ...[]string) []string {
+	seen := map[string]bool{}
+	links := make([]string, 0)
+
+	for _, list := range lists {
+		for _, link := range list {
+			link = strings.TrimRight(link, ".,;:")
+
+			if link == "" || seen[link] {
+				continue
+			}
+
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/a2a-go/pkg/tools/browser"
+)
+
+/*
+fetchDataURL reads a plain text data: URL the way the browser shows it,
+without launching one.
+*/
+func fetchDataURL(ctx context.Context, pageURL string) (*browser.Result, error) {
+	_, data, _ := strings.Cut(pageURL, ",")
+	text, err := url.PathUnescape(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &browser.Result{Title: "Otters", URL: pageURL, Text: text}, nil
+}
+
+func TestWebSummarize(t *testing.T) {
+	Convey("Given a page with navigation around its main content", t, func() {
+		page := strings.Join([]string{
+			"Home",
+			"About us",
+			"Sea otters hold hands while they sleep so they do not drift apart.",
+			"They use rocks as tools to open shellfish, see https://example.com/otters.",
+			"Home",
+			"Cookie settings",
+		}, "\n")
+		pageURL := "data:text/plain," + url.PathEscape(page)
+
+		var prompt string
+
+		summarizer := func(ctx context.Context, p string) (string, error) {
+			prompt = p
+			return "```json\n" + `{"title":"Sea otters","key_points":["They hold hands asleep","They use tools"],"links":[]}` + "\n```", nil
+		}
+
+		call := func(tool *WebSummarizeTool) *mcp.CallToolResult {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "web_summarize"
+			req.Params.Arguments = map[string]any{"url": pageURL}
+
+			result, err := tool.Handle(context.Background(), req)
+			So(err, ShouldBeNil)
+			return result
+		}
+
+		Convey("When the page is summarized", func() {
+			result := call(NewWebSummarizer(summarizer, WithPageFetcher(fetchDataURL)))
+
+			So(result.IsError, ShouldBeFalse)
+
+			var summary WebSummary
+			So(json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &summary), ShouldBeNil)
+
+			Convey("Then it should return a structured summary", func() {
+				So(summary.Title, ShouldEqual, "Sea otters")
+				So(summary.URL, ShouldEqual, pageURL)
+				So(summary.KeyPoints, ShouldResemble, []string{"They hold hands asleep", "They use tools"})
+				So(summary.Links, ShouldResemble, []string{"https://example.com/otters"})
+			})
+
+			Convey("Then only the main content should be sent to the summarizer", func() {
+				So(prompt, ShouldContainSubstring, "Sea otters hold hands")
+				So(prompt, ShouldNotContainSubstring, "About us")
+				So(prompt, ShouldNotContainSubstring, "Cookie settings")
+			})
+		})
+
+		Convey("When the content is longer than the limit", func() {
+			call(NewWebSummarizer(summarizer, WithPageFetcher(fetchDataURL), WithSummaryContentLimit(20)))
+
+			Convey("Then it should be cut before summarizing", func() {
+				So(prompt, ShouldContainSubstring, "Sea otters hold hand")
+				So(prompt, ShouldNotContainSubstring, "shellfish")
+			})
+		})
+
+		Convey("When the summarizer does not answer with JSON", func() {
+			result := call(NewWebSummarizer(func(ctx context.Context, p string) (string, error) {
+				return "Otters are nice.", nil
+			}, WithPageFetcher(fetchDataURL)))
+
+			Convey("Then the tool should report an error", func() {
+				So(result.IsError, ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -33,6 +33,33 @@ func SkillsToTools(skills []a2a.AgentSkill) []*mcp.Tool {
 	return mcpTools
 }
 
+/*
+HealthySkillsToTools is SkillsToTools for agents that should only advertise
+tools that can actually run. Skills whose capability group fails its
+prerequisite checks are skipped, with a log line explaining why.
+*/
+func HealthySkillsToTools(skills []a2a.AgentSkill) []*mcp.Tool {
+	ids := make([]string, 0, len(skills))
+
+	for _, skill := range skills {
+		ids = append(ids, skill.ID)
+	}
+
+	unavailable := tools.UnavailableTools(ids...)
+	healthy := make([]a2a.AgentSkill, 0, len(skills))
+
+	for _, skill := range skills {
+		if err, skip := unavailable[skill.ID]; skip {
+			log.Warn("skipping unavailable tool", "skill_id", skill.ID, "reason", err)
+			continue
+		}
+
+		healthy = append(healthy, skill)
+	}
+
+	return SkillsToTools(healthy)
+}
+
 func ToMCPTool(skill a2a.AgentSkill) (*mcp.Tool, error) {
 	return tools.Acquire(skill.ID)
 }